@@ -66,6 +66,10 @@ const (
 	// v4 metadata endpoint
 	MetadataURIEnvVarNameV4 = "ECS_CONTAINER_METADATA_URI_V4"
 
+	// MetadataAuthTokenEnvVarName defines the name of the environment variable in containers' config,
+	// which holds the token to present to the v3 and v4 metadata endpoints when they require authentication
+	MetadataAuthTokenEnvVarName = "ECS_CONTAINER_METADATA_TOKEN"
+
 	// MetadataURIFormatV4 defines the URI format for v4 metadata endpoint
 	MetadataURIFormatV4 = "http://169.254.170.2/v4/%s"
 
@@ -79,21 +83,87 @@ const (
 	// SecretProviderSSM is to show secret provider being SSM
 	SecretProviderSSM = "ssm"
 
+	// SecretSSMPathPrefix marks an SSM secret valueFrom as a parameter hierarchy
+	// path whose parameters are all expanded into environment variables
+	SecretSSMPathPrefix = "ssm:path:"
+
 	// SecretProviderASM is to show secret provider being ASM
 	SecretProviderASM = "asm"
 
+	// SecretProviderAppConfig is to show secret provider being AWS AppConfig
+	SecretProviderAppConfig = "appconfig"
+
 	// SecretTypeEnv is to show secret type being ENVIRONMENT_VARIABLE
 	SecretTypeEnv = "ENVIRONMENT_VARIABLE"
 
+	// SecretTypeMount is to show secret type being MOUNT_POINT, where the secret is delivered as a
+	// read-only file at the secret's container path
+	SecretTypeMount = "MOUNT_POINT"
+
 	// SecretTargetLogDriver is to show secret target being "LOG_DRIVER", the default will be "CONTAINER"
 	SecretTargetLogDriver = "LOG_DRIVER"
 
+	// EnvironmentSourceSecret is the source of environment variables populated from secrets
+	EnvironmentSourceSecret = "secret"
+
+	// EnvironmentSourceTaskDef is the source of environment variables set in the task definition
+	EnvironmentSourceTaskDef = "taskdef"
+
+	// EnvironmentSourceEnvfile is the source of environment variables populated from environment files
+	EnvironmentSourceEnvfile = "envfile"
+
+	// EnvironmentSourceAgent is the source of environment variables injected by the agent, such as
+	// the metadata endpoints and the task credentials endpoint
+	EnvironmentSourceAgent = "agent"
+
+	// LogDeliveryStatusOK means the container's log driver is delivering logs to the log backend
+	LogDeliveryStatusOK = "ok"
+
+	// LogDeliveryStatusThrottled means the container's log driver was throttled by the log backend
+	LogDeliveryStatusThrottled = "throttled"
+
+	// LogDeliveryStatusBlocked means the container's log driver is unable to deliver logs to the log backend
+	LogDeliveryStatusBlocked = "blocked"
+
+	// RestartPolicyStateEligible means the container is restarted by its restart policy when it exits
+	RestartPolicyStateEligible = "eligible"
+
+	// RestartPolicyStateExhausted means the container stopped without being restarted by its restart policy
+	RestartPolicyStateExhausted = "exhausted"
+
+	// RestartPolicyStateDisabled means the container has no enabled restart policy
+	RestartPolicyStateDisabled = "disabled"
+
+	// MaxResizeHistoryLength is the number of in-place resource updates kept in a container's resize history
+	MaxResizeHistoryLength = 10
+
+	// SELinuxRelabelShared relabels the content of a bind mount with a label shared by all containers
+	SELinuxRelabelShared = "shared"
+
+	// SELinuxRelabelPrivate relabels the content of a bind mount with a label private to the container
+	SELinuxRelabelPrivate = "private"
+
 	// neuronVisibleDevicesEnvVar is the env which indicates that the container wants to use inferentia devices.
 	neuronVisibleDevicesEnvVar = "AWS_NEURON_VISIBLE_DEVICES"
 
 	credentialSpecPrefix = "credentialspec"
 
 	credentialSpecDomainlessPrefix = credentialSpecPrefix + "domainless"
+
+	// selinuxLabelSecurityOpt is the docker security option setting a component of the SELinux process label
+	selinuxLabelSecurityOpt = "label"
+
+	// selinuxLabelDisable is the value of the label security option that turns SELinux labeling off
+	selinuxLabelDisable = "disable"
+
+	// appArmorSecurityOpt is the docker security option selecting the AppArmor profile of a container
+	appArmorSecurityOpt = "apparmor"
+
+	// appArmorProfileUnconfined runs the container without an AppArmor profile
+	appArmorProfileUnconfined = "unconfined"
+
+	// appArmorProfileDockerDefault is the AppArmor profile docker applies when none is selected
+	appArmorProfileDockerDefault = "docker-default"
 )
 
 var (
@@ -124,6 +194,8 @@ type HealthStatus struct {
 	ExitCode int `json:"exitCode,omitempty"`
 	// Output is the output of health check
 	Output string `json:"output,omitempty"`
+	// FailingStreak is the number of consecutive failed health checks
+	FailingStreak int `json:"failingStreak,omitempty"`
 }
 
 type ManagedAgentState struct {
@@ -190,6 +262,9 @@ type Container struct {
 	VolumesFrom []VolumeFrom `json:"volumesFrom"`
 	// MountPoints contains a list of volume mount paths
 	MountPoints []MountPoint `json:"mountPoints"`
+	// WritablePaths contains the paths the container declares as writable. If the container has a
+	// read-only root filesystem, each of these must be backed by a volume or tmpfs mount
+	WritablePaths []string `json:"writablePaths,omitempty"`
 	// Ports contains a list of ports binding configuration
 	Ports []PortBinding `json:"portMappings"`
 	// Secrets contains a list of secret
@@ -202,6 +277,10 @@ type Container struct {
 	Environment map[string]string `json:"environment"`
 	// EnvironmentFiles is the list of environmentFile used to populate environment variables
 	EnvironmentFiles []EnvironmentFile `json:"environmentFiles"`
+	// EnvironmentSourcesUnsafe records the source of environment variables that were populated
+	// from secrets or environment files. Variables without an entry come from the task definition.
+	// NOTE: Do not access EnvironmentSourcesUnsafe directly. Instead, use `GetEnvironmentSources`.
+	EnvironmentSourcesUnsafe map[string]string `json:"environmentSources,omitempty"`
 	// Overrides contains the configuration to override of a container
 	Overrides ContainerOverrides `json:"overrides"`
 	// DockerConfig is the configuration used to create the container
@@ -334,6 +413,46 @@ type Container struct {
 	setStartedAtOnce sync.Once
 	finishedAt       time.Time
 
+	// PullStartedAtUnsafe and PullStoppedAtUnsafe record when the agent started and finished pulling
+	// the container's image. They're zero if the image was never pulled, e.g. because it was cached.
+	// NOTE: Do not access these directly. Instead, use `SetPullStartedAt`, `SetPullStoppedAt` and
+	// `GetImagePullDuration`.
+	PullStartedAtUnsafe time.Time `json:"pullStartedAt,omitempty"`
+	PullStoppedAtUnsafe time.Time `json:"pullStoppedAt,omitempty"`
+
+	// LayerCacheStatsUnsafe counts the layers of the container's image that were found in the layer cache
+	// and the ones that were downloaded when the agent pulled it. It's nil if the image wasn't pulled.
+	// NOTE: Do not access LayerCacheStatsUnsafe directly. Instead, use `SetLayerCacheStats` and
+	// `GetLayerCacheStats`.
+	LayerCacheStatsUnsafe *LayerCacheStats `json:"layerCacheStats,omitempty"`
+
+	// ImageCompressionUnsafe is the compression of the container's image layers, gzip or zstd, as told by
+	// the layer media types of the image manifest pulled from the registry. It's empty if the compression
+	// isn't known.
+	// NOTE: Do not access ImageCompressionUnsafe directly. Instead, use `SetImageCompression` and
+	// `GetImageCompression`.
+	ImageCompressionUnsafe string `json:"imageCompression,omitempty"`
+
+	// LogDeliveryStatusUnsafe is the last known state of the container's log driver, one of
+	// LogDeliveryStatusOK, LogDeliveryStatusThrottled or LogDeliveryStatusBlocked. It's empty
+	// until the agent has tried to start the container.
+	// NOTE: Do not access LogDeliveryStatusUnsafe directly. Instead, use `SetLogDeliveryStatus`
+	// and `GetLogDeliveryStatus`.
+	LogDeliveryStatusUnsafe string `json:"logDeliveryStatus,omitempty"`
+
+	// ResizedCPUUnsafe and ResizedMemoryUnsafe are the CPU and memory limits of the container after they were
+	// last updated in place. They are zero if the limit was never updated. CPU and Memory are left as they are
+	// in the task definition, since host resource accounting relies on them.
+	// NOTE: Do not access ResizedCPUUnsafe and ResizedMemoryUnsafe directly. Instead, use `SetResources` and
+	// `GetResources`.
+	ResizedCPUUnsafe    uint `json:"resizedCpu,omitempty"`
+	ResizedMemoryUnsafe uint `json:"resizedMemory,omitempty"`
+
+	// ResizeHistoryUnsafe records the most recent in-place updates of the container's CPU and memory
+	// limits, oldest first, bounded by MaxResizeHistoryLength.
+	// NOTE: Do not access ResizeHistoryUnsafe directly. Instead, use `SetResources` and `GetResizeHistory`.
+	ResizeHistoryUnsafe []ResizeEvent `json:"resizeHistory,omitempty"`
+
 	labels map[string]string
 
 	// ContainerHasPortRange is set to true when the container has at least 1 port range requested.
@@ -367,6 +486,20 @@ type ContainerRestartAggregationDataForStats struct {
 	LastStatBeforeLastRestart types.StatsJSON `json:"LastStatBeforeLastRestart,omitempty"`
 }
 
+// ResizeEvent records the CPU and memory limits of a container after an in-place update of its resources
+type ResizeEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	CPU       uint      `json:"cpu"`
+	Memory    uint      `json:"memory"`
+}
+
+// LayerCacheStats counts the layers of an image pull that were found in the layer cache (hits) and the
+// ones that had to be downloaded (misses)
+type LayerCacheStats struct {
+	HitLayers  int `json:"hitLayers"`
+	MissLayers int `json:"missLayers"`
+}
+
 // DockerContainer is a mapping between containers-as-docker-knows-them and
 // containers-as-we-know-them.
 // This is primarily used in DockerState, but lives here such that tasks and
@@ -389,6 +522,9 @@ type MountPoint struct {
 	SourceVolume  string `json:"sourceVolume"`
 	ContainerPath string `json:"containerPath"`
 	ReadOnly      bool   `json:"readOnly"`
+	// SELinuxRelabel is either SELinuxRelabelShared or SELinuxRelabelPrivate when the
+	// content of the mount has to be relabeled for the container, or empty otherwise.
+	SELinuxRelabel string `json:"selinuxRelabel,omitempty"`
 }
 
 // FirelensConfig describes the type and options of a Firelens container.
@@ -420,6 +556,17 @@ func (s *Secret) GetSecretResourceCacheKey() string {
 	return s.ValueFrom + "_" + s.Region
 }
 
+// IsSSMPathReference returns true if the secret references an SSM parameter
+// hierarchy rather than a single parameter
+func (s *Secret) IsSSMPathReference() bool {
+	return s.Provider == SecretProviderSSM && strings.HasPrefix(s.ValueFrom, SecretSSMPathPrefix)
+}
+
+// GetSSMPath returns the SSM parameter hierarchy path of a path reference
+func (s *Secret) GetSSMPath() string {
+	return strings.TrimPrefix(s.ValueFrom, SecretSSMPathPrefix)
+}
+
 // String returns a human-readable string representation of DockerContainer
 func (dc *DockerContainer) String() string {
 	if dc == nil {
@@ -663,6 +810,23 @@ func (c *Container) RestartPolicyEnabled() bool {
 	return c.RestartPolicy.Enabled
 }
 
+// GetRestartPolicyState returns the evaluation state of the container's restart policy, one of
+// RestartPolicyStateEligible, RestartPolicyStateExhausted or RestartPolicyStateDisabled. A container that's
+// restarted never transitions to STOPPED, so a stopped container with a restart policy is one the policy
+// declined to restart.
+func (c *Container) GetRestartPolicyState() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.RestartPolicy == nil || !c.RestartPolicy.Enabled {
+		return RestartPolicyStateDisabled
+	}
+	if c.KnownStatusUnsafe == apicontainerstatus.ContainerStopped {
+		return RestartPolicyStateExhausted
+	}
+	return RestartPolicyStateEligible
+}
+
 // AWSLogAuthExecutionRole returns true if the auth is by execution role
 func (c *Container) AWSLogAuthExecutionRole() bool {
 	return c.LogsAuthStrategy == awslogsAuthExecutionRole
@@ -707,6 +871,70 @@ func (c *Container) SetFinishedAt(finishedAt time.Time) {
 	c.finishedAt = finishedAt
 }
 
+// SetPullStartedAt sets the timestamp for when the container's image pull started
+func (c *Container) SetPullStartedAt(pullStartedAt time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.PullStartedAtUnsafe = pullStartedAt
+}
+
+// SetPullStoppedAt sets the timestamp for when the container's image pull finished
+func (c *Container) SetPullStoppedAt(pullStoppedAt time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.PullStoppedAtUnsafe = pullStoppedAt
+}
+
+// GetImagePullDuration returns how long the container's image pull took. The second return value
+// is false if the pull start and stop times haven't both been recorded.
+func (c *Container) GetImagePullDuration() (time.Duration, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.PullStartedAtUnsafe.IsZero() || c.PullStoppedAtUnsafe.Before(c.PullStartedAtUnsafe) {
+		return 0, false
+	}
+	return c.PullStoppedAtUnsafe.Sub(c.PullStartedAtUnsafe), true
+}
+
+// SetLayerCacheStats sets the layer cache stats of the container's image pull
+func (c *Container) SetLayerCacheStats(stats LayerCacheStats) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.LayerCacheStatsUnsafe = &stats
+}
+
+// GetLayerCacheStats returns the layer cache stats of the container's image pull. The second return value
+// is false if they haven't been recorded.
+func (c *Container) GetLayerCacheStats() (LayerCacheStats, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.LayerCacheStatsUnsafe == nil {
+		return LayerCacheStats{}, false
+	}
+	return *c.LayerCacheStatsUnsafe, true
+}
+
+// SetLogDeliveryStatus sets the last known state of the container's log driver
+func (c *Container) SetLogDeliveryStatus(status string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.LogDeliveryStatusUnsafe = status
+}
+
+// GetLogDeliveryStatus returns the last known state of the container's log driver
+func (c *Container) GetLogDeliveryStatus() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.LogDeliveryStatusUnsafe
+}
+
 // GetCreatedAt sets the timestamp for container's creation time
 func (c *Container) GetCreatedAt() time.Time {
 	c.lock.RLock()
@@ -739,6 +967,63 @@ func (c *Container) SetLabels(labels map[string]string) {
 	c.labels = labels
 }
 
+// SetResources records the CPU and memory limits of a container after they have been updated in place,
+// and adds the resulting limits to the container's resize history. A zero value leaves the corresponding
+// limit unchanged. The CPU and memory of the container definition are not modified.
+func (c *Container) SetResources(cpu, memory uint) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cpu != 0 {
+		c.ResizedCPUUnsafe = cpu
+	}
+	if memory != 0 {
+		c.ResizedMemoryUnsafe = memory
+	}
+	currentCPU, currentMemory := c.getResourcesUnsafe()
+	c.ResizeHistoryUnsafe = append(c.ResizeHistoryUnsafe, ResizeEvent{
+		Timestamp: time.Now(),
+		CPU:       currentCPU,
+		Memory:    currentMemory,
+	})
+	if overflow := len(c.ResizeHistoryUnsafe) - MaxResizeHistoryLength; overflow > 0 {
+		c.ResizeHistoryUnsafe = c.ResizeHistoryUnsafe[overflow:]
+	}
+}
+
+// GetResources returns the current CPU and memory limits of the container, which are the limits it was last
+// updated to in place, or the limits of the container definition if they were never updated.
+func (c *Container) GetResources() (uint, uint) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.getResourcesUnsafe()
+}
+
+func (c *Container) getResourcesUnsafe() (uint, uint) {
+	cpu, memory := c.CPU, c.Memory
+	if c.ResizedCPUUnsafe != 0 {
+		cpu = c.ResizedCPUUnsafe
+	}
+	if c.ResizedMemoryUnsafe != 0 {
+		memory = c.ResizedMemoryUnsafe
+	}
+	return cpu, memory
+}
+
+// GetResizeHistory returns the most recent in-place updates of the container's resources, oldest first
+func (c *Container) GetResizeHistory() []ResizeEvent {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if len(c.ResizeHistoryUnsafe) == 0 {
+		return nil
+	}
+	history := make([]ResizeEvent, len(c.ResizeHistoryUnsafe))
+	copy(history, c.ResizeHistoryUnsafe)
+	return history
+}
+
 // SetRuntimeID sets the DockerID for a container
 func (c *Container) SetRuntimeID(RuntimeID string) {
 	c.lock.Lock()
@@ -771,6 +1056,23 @@ func (c *Container) GetImageDigest() string {
 	return c.ImageDigest
 }
 
+// SetImageCompression sets the compression of the container's image layers
+func (c *Container) SetImageCompression(compression string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.ImageCompressionUnsafe = compression
+}
+
+// GetImageCompression returns the compression of the container's image layers, or an empty string if
+// it isn't known
+func (c *Container) GetImageCompression() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.ImageCompressionUnsafe
+}
+
 // GetLabels gets the labels for a container
 func (c *Container) GetLabels() map[string]string {
 	c.lock.RLock()
@@ -779,12 +1081,45 @@ func (c *Container) GetLabels() map[string]string {
 	return c.labels
 }
 
-// SetKnownPortBindings sets the ports for a container
+// SetKnownPortBindings sets the ports for a container. Bindings are named after the
+// port mapping in the container definition with the same container port and protocol.
 func (c *Container) SetKnownPortBindings(ports []PortBinding) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	c.KnownPortBindingsUnsafe = ports
+	if len(ports) == 0 {
+		c.KnownPortBindingsUnsafe = ports
+		return
+	}
+	// copy the bindings as they may be shared with another container, e.g. the pause container
+	namedPorts := make([]PortBinding, len(ports))
+	copy(namedPorts, ports)
+	for i := range namedPorts {
+		if namedPorts[i].Name == "" {
+			namedPorts[i].Name = c.getPortMappingNameUnsafe(namedPorts[i].ContainerPort, namedPorts[i].Protocol)
+		}
+	}
+	c.KnownPortBindingsUnsafe = namedPorts
+}
+
+// GetPortMappingName returns the name of the port mapping in the container definition
+// for the container port and protocol, or an empty string if there is no such named mapping.
+func (c *Container) GetPortMappingName(containerPort uint16, protocol TransportProtocol) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.getPortMappingNameUnsafe(containerPort, protocol)
+}
+
+// getPortMappingNameUnsafe returns the name of the port mapping in the container definition
+// for the container port and protocol, or an empty string if there is no such named mapping.
+func (c *Container) getPortMappingNameUnsafe(containerPort uint16, protocol TransportProtocol) string {
+	for _, port := range c.Ports {
+		if port.ContainerPort == containerPort && port.Protocol == protocol {
+			return port.Name
+		}
+	}
+	return ""
 }
 
 // GetKnownPortBindings gets the ports for a container
@@ -861,6 +1196,8 @@ func (c *Container) SetHealthStatus(health HealthStatus) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	// The failing streak changes between health checks even if the health status doesn't
+	c.Health.FailingStreak = health.FailingStreak
 	if c.Health.Status == health.Status {
 		return
 	}
@@ -1009,6 +1346,21 @@ func (c *Container) InjectV3MetadataEndpoint() {
 
 	c.Environment[MetadataURIEnvironmentVariableName] =
 		fmt.Sprintf(MetadataURIFormat, c.V3EndpointID)
+	c.setEnvironmentSource(MetadataURIEnvironmentVariableName, EnvironmentSourceAgent)
+}
+
+// InjectMetadataAuthToken injects the token for the metadata endpoints as an environment variable for a container
+func (c *Container) InjectMetadataAuthToken(token string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// don't assume that the environment variable map has been initialized by others
+	if c.Environment == nil {
+		c.Environment = make(map[string]string)
+	}
+
+	c.Environment[MetadataAuthTokenEnvVarName] = token
+	c.setEnvironmentSource(MetadataAuthTokenEnvVarName, EnvironmentSourceAgent)
 }
 
 // InjectV4MetadataEndpoint injects the v4 metadata endpoint as an environment variable for a container
@@ -1023,6 +1375,7 @@ func (c *Container) InjectV4MetadataEndpoint() {
 
 	c.Environment[MetadataURIEnvVarNameV4] =
 		fmt.Sprintf(MetadataURIFormatV4, c.V3EndpointID)
+	c.setEnvironmentSource(MetadataURIEnvVarNameV4, EnvironmentSourceAgent)
 }
 
 // InjectV1AgentAPIEndpoint injects the v1 Agent API endpoint into the container
@@ -1032,6 +1385,7 @@ func (c *Container) InjectV1AgentAPIEndpoint() {
 	defer c.lock.Unlock()
 	c.ensureEnvironmentIsInitialized()
 	c.Environment[AgentURIEnvVarName] = fmt.Sprintf(AgentURIFormat, c.V3EndpointID)
+	c.setEnvironmentSource(AgentURIEnvVarName, EnvironmentSourceAgent)
 }
 
 // Initializes Environment Map if it is nil
@@ -1079,6 +1433,25 @@ func (c *Container) ShouldCreateWithASMSecret() bool {
 	return false
 }
 
+// ShouldCreateWithAppConfigSecret returns true if this container needs to get secret
+// value from AWS AppConfig
+func (c *Container) ShouldCreateWithAppConfigSecret() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	// Secrets field will be nil if there is no secrets for container
+	if c.Secrets == nil {
+		return false
+	}
+
+	for _, secret := range c.Secrets {
+		if secret.Provider == SecretProviderAppConfig {
+			return true
+		}
+	}
+	return false
+}
+
 // ShouldCreateWithEnvFiles returns true if this container needs to
 // retrieve environment variable files
 func (c *Container) ShouldCreateWithEnvFiles() bool {
@@ -1093,7 +1466,9 @@ func (c *Container) ShouldCreateWithEnvFiles() bool {
 }
 
 // MergeEnvironmentVariables appends additional envVarName:envVarValue pairs to
-// the the container's environment values structure
+// the the container's environment values structure. Existing values with the
+// same name are overwritten, which is what gives secrets precedence over
+// environment variables from the task definition
 func (c *Container) MergeEnvironmentVariables(envVars map[string]string) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -1107,9 +1482,68 @@ func (c *Container) MergeEnvironmentVariables(envVars map[string]string) {
 	}
 }
 
+// MergeEnvironmentVariablesFromSecrets merges environment variables populated from
+// secrets into the container's environment values, overwriting existing values
+// with the same name, and records secrets as their source
+func (c *Container) MergeEnvironmentVariablesFromSecrets(envVars map[string]string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.ensureEnvironmentIsInitialized()
+	for k, v := range envVars {
+		c.Environment[k] = v
+		c.setEnvironmentSource(k, EnvironmentSourceSecret)
+	}
+}
+
+// MergeEnvironmentVariablesFromAgent merges environment variables injected by the
+// agent into the container's environment values, overwriting existing values with
+// the same name, and records the agent as their source
+func (c *Container) MergeEnvironmentVariablesFromAgent(envVars map[string]string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.ensureEnvironmentIsInitialized()
+	for k, v := range envVars {
+		c.Environment[k] = v
+		c.setEnvironmentSource(k, EnvironmentSourceAgent)
+	}
+}
+
+// setEnvironmentSource records the source of an environment variable. It
+// must be called with the container lock held.
+func (c *Container) setEnvironmentSource(name, source string) {
+	if c.EnvironmentSourcesUnsafe == nil {
+		c.EnvironmentSourcesUnsafe = make(map[string]string)
+	}
+	c.EnvironmentSourcesUnsafe[name] = source
+}
+
+// GetEnvironmentSources returns a map of each of the container's environment
+// variable names to the source whose value won. Values are never included.
+func (c *Container) GetEnvironmentSources() map[string]string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if len(c.Environment) == 0 {
+		return nil
+	}
+	sources := make(map[string]string, len(c.Environment))
+	for k := range c.Environment {
+		source, ok := c.EnvironmentSourcesUnsafe[k]
+		if !ok {
+			source = EnvironmentSourceTaskDef
+		}
+		sources[k] = source
+	}
+	return sources
+}
+
 // MergeEnvironmentVariablesFromEnvfiles appends environment variable pairs from
 // the retrieved envfiles to the container's environment values list
-// envvars from envfiles will have lower precedence than existing envvars
+// envvars from envfiles will have lower precedence than existing envvars, and
+// envvars from an earlier envfile take precedence over those from later ones.
+// The resulting precedence is: secrets > task definition > envfiles
 func (c *Container) MergeEnvironmentVariablesFromEnvfiles(envVarsList []map[string]string) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -1128,6 +1562,7 @@ func (c *Container) MergeEnvironmentVariablesFromEnvfiles(envVarsList []map[stri
 			// only set the env var if key does not already exist
 			if _, ok := c.Environment[k]; !ok {
 				c.Environment[k] = v
+				c.setEnvironmentSource(k, EnvironmentSourceEnvfile)
 			}
 		}
 	}
@@ -1272,6 +1707,26 @@ func (c *Container) GetNetworkModeFromHostConfig() string {
 	return hostConfig.NetworkMode.NetworkName()
 }
 
+// GetStopSignal returns the signal docker sends to the container when stopping it, as configured in the
+// container's docker config. An empty string means docker uses the image's stop signal, or SIGTERM.
+func (c *Container) GetStopSignal() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.DockerConfig.Config == nil {
+		return ""
+	}
+
+	config := &dockercontainer.Config{}
+	err := json.Unmarshal([]byte(*c.DockerConfig.Config), config)
+	if err != nil {
+		seelog.Warnf("Encountered error when trying to get stop signal for container %s: %v", c.RuntimeID, err)
+		return ""
+	}
+
+	return config.StopSignal
+}
+
 // GetHostConfig returns the container's host config.
 func (c *Container) GetHostConfig() *string {
 	c.lock.RLock()
@@ -1460,6 +1915,96 @@ func (c *Container) getCredentialSpecFromHostConfig() (string, error) {
 	return "", errors.New("unable to obtain credentialspec")
 }
 
+// GetSELinuxLabel returns the SELinux process label components (e.g. "type:svirt_apache_t level:s0:c100")
+// set through the container's security options, separated by spaces. It returns an empty string when the
+// container has no SELinux label or when labeling is disabled.
+func (c *Container) GetSELinuxLabel() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.DockerConfig.HostConfig == nil {
+		return ""
+	}
+
+	hostConfig := &dockercontainer.HostConfig{}
+	if err := json.Unmarshal([]byte(*c.DockerConfig.HostConfig), hostConfig); err != nil {
+		return ""
+	}
+
+	var components []string
+	for _, opt := range hostConfig.SecurityOpt {
+		// docker accepts both label=<value> and the deprecated label:<value> forms
+		if len(opt) <= len(selinuxLabelSecurityOpt) || !strings.HasPrefix(opt, selinuxLabelSecurityOpt) {
+			continue
+		}
+		if sep := opt[len(selinuxLabelSecurityOpt)]; sep != '=' && sep != ':' {
+			continue
+		}
+		value := opt[len(selinuxLabelSecurityOpt)+1:]
+		if value == selinuxLabelDisable {
+			return ""
+		}
+		components = append(components, value)
+	}
+	return strings.Join(components, " ")
+}
+
+// GetAppArmorProfile returns the name of the AppArmor profile selected through the container's security
+// options. It returns an empty string when the container runs with docker's default profile or unconfined.
+func (c *Container) GetAppArmorProfile() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.DockerConfig.HostConfig == nil {
+		return ""
+	}
+
+	hostConfig := &dockercontainer.HostConfig{}
+	if err := json.Unmarshal([]byte(*c.DockerConfig.HostConfig), hostConfig); err != nil {
+		return ""
+	}
+	return AppArmorProfileFromSecurityOpts(hostConfig.SecurityOpt)
+}
+
+// AppArmorProfileFromSecurityOpts returns the name of the AppArmor profile selected by the given docker
+// security options. It returns an empty string when they select docker's default profile, unconfined,
+// or no profile at all.
+func AppArmorProfileFromSecurityOpts(securityOpts []string) string {
+	profile := ""
+	for _, opt := range securityOpts {
+		// docker accepts both apparmor=<profile> and the deprecated apparmor:<profile> forms,
+		// the last option wins
+		if len(opt) <= len(appArmorSecurityOpt) || !strings.HasPrefix(opt, appArmorSecurityOpt) {
+			continue
+		}
+		if sep := opt[len(appArmorSecurityOpt)]; sep != '=' && sep != ':' {
+			continue
+		}
+		profile = opt[len(appArmorSecurityOpt)+1:]
+	}
+	if profile == appArmorProfileUnconfined || profile == appArmorProfileDockerDefault {
+		return ""
+	}
+	return profile
+}
+
+// GetHostConfigResources returns the CPU shares, memory limit and memory reservation (in bytes) set in the
+// container's host config. Values that aren't set, or can't be read, are returned as zero.
+func (c *Container) GetHostConfigResources() (cpuShares int64, memory int64, memoryReservation int64) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.DockerConfig.HostConfig == nil {
+		return 0, 0, 0
+	}
+
+	hostConfig := &dockercontainer.HostConfig{}
+	if err := json.Unmarshal([]byte(*c.DockerConfig.HostConfig), hostConfig); err != nil {
+		return 0, 0, 0
+	}
+	return hostConfig.CPUShares, hostConfig.Memory, hostConfig.MemoryReservation
+}
+
 func (c *Container) getCredentialSpecFromCredentialSpecsContainerField() (string, error) {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
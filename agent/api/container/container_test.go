@@ -242,6 +242,11 @@ func TestSetHealthStatus(t *testing.T) {
 	assert.Equal(t, health2.Status, apicontainerstatus.ContainerHealthy)
 	assert.Equal(t, health2.Since, health.Since)
 
+	// a failed health check updates the failing streak even though the container is still healthy
+	container.SetHealthStatus(HealthStatus{Status: apicontainerstatus.ContainerHealthy, FailingStreak: 1})
+	assert.Equal(t, 1, container.GetHealthStatus().FailingStreak)
+	assert.Equal(t, health.Since, container.GetHealthStatus().Since)
+
 	// the sleep is to ensure the different of the two timestamp returned by time.Now()
 	// is big enough to pass asser.NotEqual
 	time.Sleep(10 * time.Millisecond)
@@ -473,6 +478,43 @@ func TestShouldCreateWithASMSecret(t *testing.T) {
 	}
 }
 
+func TestShouldCreateWithAppConfigSecret(t *testing.T) {
+	cases := []struct {
+		in  *Container
+		out bool
+	}{
+		{&Container{
+			Name:  "myName",
+			Image: "image:tag",
+			Secrets: []Secret{
+				{
+					Provider:  SecretProviderAppConfig,
+					Name:      "secret",
+					ValueFrom: "my-app/prod/flags",
+				}},
+		}, true},
+		{&Container{
+			Name:    "myName",
+			Image:   "image:tag",
+			Secrets: nil,
+		}, false},
+		{&Container{
+			Name:  "myName",
+			Image: "image:tag",
+			Secrets: []Secret{
+				{
+					Provider:  "ssm",
+					Name:      "secret",
+					ValueFrom: "/test/secretName",
+				}},
+		}, false},
+	}
+
+	for _, test := range cases {
+		assert.Equal(t, test.out, test.in.ShouldCreateWithAppConfigSecret())
+	}
+}
+
 func TestHasSecret(t *testing.T) {
 	isEnvOrLogDriverSecret := func(s Secret) bool {
 		return s.Type == SecretTypeEnv || s.Target == SecretTargetLogDriver
@@ -585,6 +627,68 @@ func TestSetRuntimeIDInContainer(t *testing.T) {
 	assert.Equal(t, "asdfghjkl1234", container.GetRuntimeID())
 }
 
+func TestSetResources(t *testing.T) {
+	container := Container{CPU: 256, Memory: 512}
+	container.SetResources(1024, 0)
+	cpu, memory := container.GetResources()
+	assert.Equal(t, uint(1024), cpu)
+	assert.Equal(t, uint(512), memory, "a zero memory should leave the limit unchanged")
+
+	container.SetResources(0, 2048)
+	cpu, memory = container.GetResources()
+	assert.Equal(t, uint(1024), cpu, "a zero cpu should leave the limit unchanged")
+	assert.Equal(t, uint(2048), memory)
+	assert.Equal(t, uint(256), container.CPU, "the cpu used for host resource accounting should not change")
+	assert.Equal(t, uint(512), container.Memory, "the memory used for host resource accounting should not change")
+
+	history := container.GetResizeHistory()
+	require.Len(t, history, 2)
+	assert.Equal(t, uint(1024), history[0].CPU)
+	assert.Equal(t, uint(512), history[0].Memory)
+	assert.Equal(t, uint(1024), history[1].CPU)
+	assert.Equal(t, uint(2048), history[1].Memory)
+}
+
+func TestResizeHistoryBounded(t *testing.T) {
+	container := Container{}
+	for i := 1; i <= MaxResizeHistoryLength+5; i++ {
+		container.SetResources(uint(i), 0)
+	}
+
+	history := container.GetResizeHistory()
+	require.Len(t, history, MaxResizeHistoryLength)
+	assert.Equal(t, uint(6), history[0].CPU, "the oldest events should be dropped first")
+	assert.Equal(t, uint(MaxResizeHistoryLength+5), history[MaxResizeHistoryLength-1].CPU)
+}
+
+func TestSetKnownPortBindingsNamesPorts(t *testing.T) {
+	container := &Container{
+		Ports: []PortBinding{
+			{ContainerPort: 80, Protocol: TransportProtocolTCP, Name: "http"},
+			{ContainerPort: 53, Protocol: TransportProtocolUDP, Name: "dns"},
+			{ContainerPort: 8080, Protocol: TransportProtocolTCP},
+		},
+	}
+	knownPortBindings := []PortBinding{
+		{ContainerPort: 80, HostPort: 32768, Protocol: TransportProtocolTCP},
+		{ContainerPort: 53, HostPort: 32769, Protocol: TransportProtocolTCP},
+		{ContainerPort: 8080, HostPort: 32770, Protocol: TransportProtocolTCP},
+	}
+
+	container.SetKnownPortBindings(knownPortBindings)
+	assert.Equal(t, []PortBinding{
+		{ContainerPort: 80, HostPort: 32768, Protocol: TransportProtocolTCP, Name: "http"},
+		// protocol does not match the named port mapping
+		{ContainerPort: 53, HostPort: 32769, Protocol: TransportProtocolTCP},
+		{ContainerPort: 8080, HostPort: 32770, Protocol: TransportProtocolTCP},
+	}, container.GetKnownPortBindings())
+	// the bindings passed in should not be modified
+	assert.Empty(t, knownPortBindings[0].Name)
+
+	container.SetKnownPortBindings(nil)
+	assert.Nil(t, container.GetKnownPortBindings())
+}
+
 func TestGetManagedAgents(t *testing.T) {
 	container := Container{}
 	assert.Nil(t, container.GetManagedAgents())
@@ -751,6 +855,49 @@ func TestGetNetworkModeFromHostConfig(t *testing.T) {
 	}
 }
 
+func TestGetStopSignal(t *testing.T) {
+	getContainer := func(config string) *Container {
+		c := &Container{
+			Name: "c",
+		}
+		c.DockerConfig.Config = &config
+		return c
+	}
+
+	testCases := []struct {
+		name           string
+		container      *Container
+		expectedOutput string
+	}{
+		{
+			name:           "stop signal configured",
+			container:      getContainer(`{"StopSignal":"SIGQUIT"}`),
+			expectedOutput: "SIGQUIT",
+		},
+		{
+			name:           "stop signal not configured",
+			container:      getContainer(`{"User":"nobody"}`),
+			expectedOutput: "",
+		},
+		{
+			name:           "no docker config",
+			container:      &Container{Name: "c"},
+			expectedOutput: "",
+		},
+		{
+			name:           "invalid case",
+			container:      getContainer("invalid"),
+			expectedOutput: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, tc.container.GetStopSignal())
+		})
+	}
+}
+
 func TestShouldCreateWithEnvfiles(t *testing.T) {
 	cases := []struct {
 		in  Container
@@ -835,6 +982,91 @@ func TestMergeEnvironmentVariablesFromEnvfiles(t *testing.T) {
 	}
 }
 
+func TestEnvironmentVariablePrecedence(t *testing.T) {
+	taskDefEnv := map[string]string{
+		"SHARED":   "taskdef",
+		"TASK_DEF": "taskdef",
+	}
+	secretEnv := map[string]string{
+		"SHARED":  "secret",
+		"ENVFILE": "secret",
+	}
+	envfiles := []map[string]string{
+		{"SHARED": "envfile1", "TASK_DEF": "envfile1", "ENVFILE": "envfile1", "ONLY_ENVFILE": "envfile1"},
+		{"ONLY_ENVFILE": "envfile2", "SECOND_ENVFILE": "envfile2"},
+	}
+	expected := map[string]string{
+		"SHARED":         "secret",
+		"TASK_DEF":       "taskdef",
+		"ENVFILE":        "secret",
+		"ONLY_ENVFILE":   "envfile1",
+		"SECOND_ENVFILE": "envfile2",
+	}
+
+	copyEnv := func(env map[string]string) map[string]string {
+		copied := make(map[string]string)
+		for k, v := range env {
+			copied[k] = v
+		}
+		return copied
+	}
+
+	t.Run("secrets merged before envfiles", func(t *testing.T) {
+		container := &Container{Environment: copyEnv(taskDefEnv)}
+		container.MergeEnvironmentVariables(secretEnv)
+		require.NoError(t, container.MergeEnvironmentVariablesFromEnvfiles(envfiles))
+		assert.Equal(t, expected, container.Environment)
+	})
+
+	t.Run("envfiles merged before secrets", func(t *testing.T) {
+		container := &Container{Environment: copyEnv(taskDefEnv)}
+		require.NoError(t, container.MergeEnvironmentVariablesFromEnvfiles(envfiles))
+		container.MergeEnvironmentVariables(secretEnv)
+		assert.Equal(t, expected, container.Environment)
+	})
+}
+
+func TestGetEnvironmentSources(t *testing.T) {
+	container := &Container{
+		Environment: map[string]string{
+			"SHARED":   "taskdef",
+			"TASK_DEF": "taskdef",
+		},
+	}
+	assert.Equal(t, map[string]string{
+		"SHARED":   EnvironmentSourceTaskDef,
+		"TASK_DEF": EnvironmentSourceTaskDef,
+	}, container.GetEnvironmentSources())
+
+	require.NoError(t, container.MergeEnvironmentVariablesFromEnvfiles([]map[string]string{
+		{"TASK_DEF": "envfile", "ENVFILE": "envfile", "OVERRIDDEN": "envfile"},
+	}))
+	container.MergeEnvironmentVariablesFromSecrets(map[string]string{
+		"SHARED":     "secret",
+		"OVERRIDDEN": "secret",
+	})
+	assert.Equal(t, map[string]string{
+		"SHARED":     EnvironmentSourceSecret,
+		"TASK_DEF":   EnvironmentSourceTaskDef,
+		"ENVFILE":    EnvironmentSourceEnvfile,
+		"OVERRIDDEN": EnvironmentSourceSecret,
+	}, container.GetEnvironmentSources())
+
+	container.V3EndpointID = "endpoint-id"
+	container.InjectV3MetadataEndpoint()
+	container.InjectV4MetadataEndpoint()
+	container.MergeEnvironmentVariablesFromAgent(map[string]string{
+		"AWS_CONTAINER_CREDENTIALS_RELATIVE_URI": "/v2/credentials/id",
+	})
+	sources := container.GetEnvironmentSources()
+	assert.Equal(t, EnvironmentSourceAgent, sources[MetadataURIEnvironmentVariableName])
+	assert.Equal(t, EnvironmentSourceAgent, sources[MetadataURIEnvVarNameV4])
+	assert.Equal(t, EnvironmentSourceAgent, sources["AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"])
+	assert.Equal(t, EnvironmentSourceTaskDef, sources["TASK_DEF"])
+
+	assert.Nil(t, (&Container{}).GetEnvironmentSources())
+}
+
 func TestRequireNeuronRuntime(t *testing.T) {
 	c := &Container{
 		Environment: map[string]string{neuronVisibleDevicesEnvVar: "all"},
@@ -1449,6 +1681,169 @@ func TestGetAndSetRestartAggregationDataForStats(t *testing.T) {
 	require.Equal(t, testRestartAggregationDataForStats, c.GetRestartAggregationDataForStats())
 }
 
+func TestGetSELinuxLabel(t *testing.T) {
+	testCases := []struct {
+		name          string
+		container     *Container
+		expectedLabel string
+	}{
+		{
+			name:          "hostconfig_nil",
+			container:     &Container{},
+			expectedLabel: "",
+		},
+		{
+			name:          "no_label_sec_opt",
+			container:     getContainer(`{"SecurityOpt": ["no-new-privileges"]}`, nil),
+			expectedLabel: "",
+		},
+		{
+			name:          "label_components",
+			container:     getContainer(`{"SecurityOpt": ["label=type:svirt_apache_t", "label:level:s0:c100,c200"]}`, nil),
+			expectedLabel: "type:svirt_apache_t level:s0:c100,c200",
+		},
+		{
+			name:          "labeling_disabled",
+			container:     getContainer(`{"SecurityOpt": ["label=type:svirt_apache_t", "label=disable"]}`, nil),
+			expectedLabel: "",
+		},
+		{
+			name:          "similar_prefix",
+			container:     getContainer(`{"SecurityOpt": ["labels=type:svirt_apache_t"]}`, nil),
+			expectedLabel: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedLabel, tc.container.GetSELinuxLabel())
+		})
+	}
+}
+
+func TestGetAppArmorProfile(t *testing.T) {
+	testCases := []struct {
+		name            string
+		container       *Container
+		expectedProfile string
+	}{
+		{
+			name:            "hostconfig_nil",
+			container:       &Container{},
+			expectedProfile: "",
+		},
+		{
+			name:            "no_apparmor_sec_opt",
+			container:       getContainer(`{"SecurityOpt": ["label=type:svirt_apache_t"]}`, nil),
+			expectedProfile: "",
+		},
+		{
+			name:            "named_profile",
+			container:       getContainer(`{"SecurityOpt": ["apparmor=ecs-custom"]}`, nil),
+			expectedProfile: "ecs-custom",
+		},
+		{
+			name:            "named_profile_deprecated_form",
+			container:       getContainer(`{"SecurityOpt": ["apparmor:ecs-custom"]}`, nil),
+			expectedProfile: "ecs-custom",
+		},
+		{
+			name:            "unconfined",
+			container:       getContainer(`{"SecurityOpt": ["apparmor=unconfined"]}`, nil),
+			expectedProfile: "",
+		},
+		{
+			name:            "docker_default",
+			container:       getContainer(`{"SecurityOpt": ["apparmor=docker-default"]}`, nil),
+			expectedProfile: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedProfile, tc.container.GetAppArmorProfile())
+		})
+	}
+}
+
+func TestGetRestartPolicyState(t *testing.T) {
+	testCases := []struct {
+		name          string
+		restartPolicy *restart.RestartPolicy
+		knownStatus   apicontainerstatus.ContainerStatus
+		expectedState string
+	}{
+		{
+			name:          "no restart policy",
+			knownStatus:   apicontainerstatus.ContainerRunning,
+			expectedState: RestartPolicyStateDisabled,
+		},
+		{
+			name:          "restart policy not enabled",
+			restartPolicy: &restart.RestartPolicy{Enabled: false},
+			knownStatus:   apicontainerstatus.ContainerStopped,
+			expectedState: RestartPolicyStateDisabled,
+		},
+		{
+			name:          "running container",
+			restartPolicy: &restart.RestartPolicy{Enabled: true},
+			knownStatus:   apicontainerstatus.ContainerRunning,
+			expectedState: RestartPolicyStateEligible,
+		},
+		{
+			name:          "stopped container",
+			restartPolicy: &restart.RestartPolicy{Enabled: true},
+			knownStatus:   apicontainerstatus.ContainerStopped,
+			expectedState: RestartPolicyStateExhausted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			container := &Container{
+				RestartPolicy:     tc.restartPolicy,
+				KnownStatusUnsafe: tc.knownStatus,
+			}
+			assert.Equal(t, tc.expectedState, container.GetRestartPolicyState())
+		})
+	}
+}
+
+func TestGetHostConfigResources(t *testing.T) {
+	testCases := []struct {
+		name                      string
+		container                 *Container
+		expectedCPUShares         int64
+		expectedMemory            int64
+		expectedMemoryReservation int64
+	}{
+		{
+			name:      "hostconfig_nil",
+			container: &Container{},
+		},
+		{
+			name:      "invalid_hostconfig",
+			container: getContainer(`{"CpuShares": "invalid"}`, nil),
+		},
+		{
+			name:                      "resources_set",
+			container:                 getContainer(`{"CpuShares": 256, "Memory": 536870912, "MemoryReservation": 268435456}`, nil),
+			expectedCPUShares:         256,
+			expectedMemory:            536870912,
+			expectedMemoryReservation: 268435456,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cpuShares, memory, memoryReservation := tc.container.GetHostConfigResources()
+			assert.Equal(t, tc.expectedCPUShares, cpuShares)
+			assert.Equal(t, tc.expectedMemory, memory)
+			assert.Equal(t, tc.expectedMemoryReservation, memoryReservation)
+		})
+	}
+}
+
 func getContainer(hostConfig string, credentialSpecs []string) *Container {
 	c := &Container{
 		Name: "c",
@@ -40,6 +40,9 @@ type PortBinding struct {
 	BindIP string `json:"BindIp"`
 	// Protocol is the protocol of the port
 	Protocol TransportProtocol
+	// Name is the name of the port mapping in the container definition, used by
+	// Service Connect to refer to the port
+	Name string `json:"Name,omitempty"`
 }
 
 // PortBindingFromDockerPortBinding constructs a PortBinding slice from a docker
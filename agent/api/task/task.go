@@ -15,10 +15,14 @@ package task
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -30,6 +34,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/appconfigsecret"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/asmauth"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/asmsecret"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/credentialspec"
@@ -59,7 +64,9 @@ import (
 	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
 	"github.com/docker/docker/api/types"
 	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 )
 
@@ -75,6 +82,9 @@ const (
 
 	emptyHostVolumeName = "~internal~ecs-emptyvolume-source"
 
+	// metadataAuthTokenLength is the number of random bytes in the metadata endpoint token of a task
+	metadataAuthTokenLength = 32
+
 	// awsSDKCredentialsRelativeURIPathEnvironmentVariableName defines the name of the environment
 	// variable in containers' config, which will be used by the AWS SDK to fetch
 	// credentials.
@@ -99,6 +109,14 @@ const (
 	// awslogsCredsEndpointOpt is the awslogs option that is used to pass in a
 	// http endpoint for authentication
 	awslogsCredsEndpointOpt = "awslogs-credentials-endpoint"
+	// These constants identify the awslogs options validated by the agent
+	awslogsGroupOpt            = "awslogs-group"
+	awslogsCreateGroupOpt      = "awslogs-create-group"
+	awslogsDatetimeFormatOpt   = "awslogs-datetime-format"
+	awslogsMultilinePatternOpt = "awslogs-multiline-pattern"
+	logModeOpt                 = "mode"
+	logModeBlocking            = "blocking"
+	logModeNonBlocking         = "non-blocking"
 	// These contants identify the docker flag options
 	pidModeHost     = "host"
 	pidModeTask     = "task"
@@ -121,6 +139,14 @@ const (
 	// firelensSocketBindFormat specifies the format for firelens container's socket directory bind mount.
 	// First placeholder is host data dir, second placeholder is taskID.
 	firelensSocketBindFormat = "%s/data/firelens/%s/socket/:/var/run/"
+
+	// secretFilesDir is the directory of the data dir under which the files of secrets delivered as
+	// files are written, in a sub directory per task and container.
+	secretFilesDir = "secrets"
+	// SecretFileMode is the mode of the files of secrets delivered as files. They're readable by any
+	// user of the container since the container user isn't known when the file is written, and they're
+	// only visible to the container they're mounted into.
+	SecretFileMode os.FileMode = 0444
 	// firelensDriverName is the log driver name for containers that want to use the firelens container to send logs.
 	firelensDriverName = "awsfirelens"
 	// FirelensLogDriverBufferLimitOption is the option for customers who want to specify the buffer limit size in FireLens.
@@ -239,6 +265,10 @@ type Task struct {
 	// perform some action at the task level, such as pulling image from ECR
 	ExecutionCredentialsID string `json:"executionCredentialsID"`
 
+	// MetadataAuthTokenUnsafe is the token the containers of the task must present to access the v3 and
+	// v4 task metadata endpoints, when metadata endpoint authentication is enabled
+	MetadataAuthTokenUnsafe string `json:"metadataAuthToken,omitempty"`
+
 	// credentialsID is used to set the CredentialsId field for the
 	// IAMRoleCredentials object associated with the task. This id can be
 	// used to look up the credentials for task in the credentials manager
@@ -293,9 +323,25 @@ type Task struct {
 
 	ServiceConnectConnectionDrainingUnsafe bool `json:"ServiceConnectConnectionDraining,omitempty"`
 
+	// HealthGatingEnabledUnsafe specifies whether the task only transitions to RUNNING once all of its
+	// essential containers with a health check are healthy. This field should be accessed via
+	// IsHealthGatingEnabled and SetHealthGatingEnabled.
+	HealthGatingEnabledUnsafe bool `json:"HealthGatingEnabled,omitempty"`
+
 	NetworkMode string `json:"NetworkMode,omitempty"`
 
 	IsInternal bool `json:"IsInternal,omitempty"`
+
+	// Ulimits are the task level ulimits, applied to every container of the task that doesn't
+	// set a ulimit with the same name itself
+	Ulimits []Ulimit `json:"Ulimits,omitempty"`
+}
+
+// Ulimit is a resource limit set at the task level
+type Ulimit struct {
+	Name      string `json:"name"`
+	SoftLimit int64  `json:"softLimit"`
+	HardLimit int64  `json:"hardLimit"`
 }
 
 // TaskFromACS translates ecsacs.Task to apitask.Task by first marshaling the received
@@ -392,6 +438,30 @@ func (task *Task) PostUnmarshalTask(cfg *config.Config,
 		return apierrors.NewResourceInitError(task.Arn, err)
 	}
 
+	if err := task.validateSecretProviders(cfg); err != nil {
+		logger.Error("Unsupported secret provider for container", logger.Fields{
+			field.TaskID: task.GetID(),
+			field.Error:  err,
+		})
+		return err
+	}
+
+	if err := task.validateReadonlyRootfsWritablePaths(); err != nil {
+		logger.Error("Invalid writable paths for container with read-only root filesystem", logger.Fields{
+			field.TaskID: task.GetID(),
+			field.Error:  err,
+		})
+		return err
+	}
+
+	if err := task.validateLogConfigurations(); err != nil {
+		logger.Error("Invalid log configuration for container", logger.Fields{
+			field.TaskID: task.GetID(),
+			field.Error:  err,
+		})
+		return err
+	}
+
 	if err := task.initializeContainerOrdering(); err != nil {
 		logger.Error("Could not initialize dependency for container", logger.Fields{
 			field.TaskID: task.GetID(),
@@ -421,6 +491,15 @@ func (task *Task) PostUnmarshalTask(cfg *config.Config,
 	task.initializeContainersV3MetadataEndpoint(utils.NewDynamicUUIDProvider())
 	task.initializeContainersV4MetadataEndpoint(utils.NewDynamicUUIDProvider())
 	task.initializeContainersV1AgentAPIEndpoint(utils.NewDynamicUUIDProvider())
+	if cfg.TMDSAuthEnabled.Enabled() {
+		if err := task.initializeMetadataAuthToken(); err != nil {
+			logger.Error("Could not issue metadata endpoint token", logger.Fields{
+				field.TaskID: task.GetID(),
+				field.Error:  err,
+			})
+			return apierrors.NewResourceInitError(task.Arn, err)
+		}
+	}
 	if err := task.addNetworkResourceProvisioningDependency(cfg); err != nil {
 		logger.Error("Could not provision network resource", logger.Fields{
 			field.TaskID: task.GetID(),
@@ -467,6 +546,8 @@ func (task *Task) PostUnmarshalTask(cfg *config.Config,
 
 	task.initRestartTrackers()
 
+	task.SetHealthGatingEnabled(cfg.TaskHealthGatingEnabled.Enabled())
+
 	for _, opt := range options {
 		if err := opt(task); err != nil {
 			logger.Error("Could not apply task option", logger.Fields{
@@ -648,6 +729,10 @@ func (task *Task) initSecretResources(credentialsManager credentials.Manager,
 	if task.requiresASMSecret() {
 		task.initializeASMSecretResource(credentialsManager, resourceFields)
 	}
+
+	if task.requiresAppConfigSecret() {
+		task.initializeAppConfigSecretResource(credentialsManager, resourceFields)
+	}
 }
 
 func (task *Task) applyFirelensSetup(cfg *config.Config, resourceFields *taskresource.ResourceFields,
@@ -708,7 +793,7 @@ func (task *Task) populateGPUEnvironmentVariables() {
 			gpuList := strings.Join(container.GPUIDs, ",")
 			envVars := make(map[string]string)
 			envVars[NvidiaVisibleDevicesEnvVar] = gpuList
-			container.MergeEnvironmentVariables(envVars)
+			container.MergeEnvironmentVariablesFromAgent(envVars)
 		}
 	}
 }
@@ -720,6 +805,7 @@ func (task *Task) shouldRequireNvidiaRuntime(container *apicontainer.Container)
 
 func (task *Task) initializeDockerLocalVolumes(dockerClient dockerapi.DockerClient, ctx context.Context) error {
 	var requiredLocalVolumes []string
+	seenLocalVolumes := make(map[string]struct{})
 	for _, container := range task.Containers {
 		for _, mountPoint := range container.MountPoints {
 			vol, ok := task.HostVolumeByName(mountPoint.SourceVolume)
@@ -727,10 +813,16 @@ func (task *Task) initializeDockerLocalVolumes(dockerClient dockerapi.DockerClie
 				continue
 			}
 			if localVolume, ok := vol.(*taskresourcevolume.LocalDockerVolume); ok {
-				localVolume.HostPath = task.volumeName(mountPoint.SourceVolume)
 				container.BuildResourceDependency(mountPoint.SourceVolume,
 					resourcestatus.ResourceStatus(taskresourcevolume.VolumeCreated),
 					apicontainerstatus.ContainerPulled)
+				// The same empty volume may be mounted by several containers as a shared
+				// scratch space; provision it only once so that they all see one volume.
+				if _, seen := seenLocalVolumes[mountPoint.SourceVolume]; seen {
+					continue
+				}
+				seenLocalVolumes[mountPoint.SourceVolume] = struct{}{}
+				localVolume.HostPath = task.volumeName(mountPoint.SourceVolume)
 				requiredLocalVolumes = append(requiredLocalVolumes, mountPoint.SourceVolume)
 			}
 		}
@@ -990,13 +1082,9 @@ func (task *Task) initializeCredentialsEndpoint(credentialsManager credentials.M
 
 	credentialsEndpointRelativeURI := taskCredentials.IAMRoleCredentials.GenerateCredentialsEndpointRelativeURI()
 	for _, container := range task.Containers {
-		// container.Environment map would not be initialized if there are
-		// no environment variables to be set or overridden in the container
-		// config. Check if that's the case and initialize if needed
-		if container.Environment == nil {
-			container.Environment = make(map[string]string)
-		}
-		container.Environment[awsSDKCredentialsRelativeURIPathEnvironmentVariableName] = credentialsEndpointRelativeURI
+		container.MergeEnvironmentVariablesFromAgent(map[string]string{
+			awsSDKCredentialsRelativeURIPathEnvironmentVariableName: credentialsEndpointRelativeURI,
+		})
 	}
 
 	task.SetCredentialsRelativeURI(credentialsEndpointRelativeURI)
@@ -1021,6 +1109,27 @@ func (task *Task) initializeContainersV4MetadataEndpoint(uuidProvider utils.UUID
 	}
 }
 
+// initializeMetadataAuthToken issues the token that the containers of the task must present to access
+// the v3 and v4 metadata endpoints, and injects it as an environment variable. A token restored from
+// the agent state is kept, so that running containers can still use theirs.
+func (task *Task) initializeMetadataAuthToken() error {
+	token := task.GetMetadataAuthToken()
+	if token == "" {
+		tokenBytes := make([]byte, metadataAuthTokenLength)
+		if _, err := rand.Read(tokenBytes); err != nil {
+			return errors.Wrap(err, "unable to generate metadata endpoint token")
+		}
+		token = hex.EncodeToString(tokenBytes)
+		task.lock.Lock()
+		task.MetadataAuthTokenUnsafe = token
+		task.lock.Unlock()
+	}
+	for _, container := range task.Containers {
+		container.InjectMetadataAuthToken(token)
+	}
+	return nil
+}
+
 // For each container of the task, initializeContainersV1AgentAPIEndpoint initializes
 // its V3EndpointID (if not already initialized), and injects V1 Agent API Endpoint
 // into the container.
@@ -1149,6 +1258,50 @@ func (task *Task) getAllSSMSecretRequirements() map[string][]apicontainer.Secret
 	return reqs
 }
 
+// requiresAppConfigSecret returns true if at least one container in the task
+// needs to retrieve secret from AWS AppConfig
+func (task *Task) requiresAppConfigSecret() bool {
+	for _, container := range task.Containers {
+		if container.ShouldCreateWithAppConfigSecret() {
+			return true
+		}
+	}
+	return false
+}
+
+// initializeAppConfigSecretResource builds the resource dependency map for the appconfigsecret resource
+func (task *Task) initializeAppConfigSecretResource(credentialsManager credentials.Manager,
+	resourceFields *taskresource.ResourceFields) {
+	appConfigSecretResource := appconfigsecret.NewAppConfigSecretResource(task.Arn,
+		task.getAllAppConfigSecretRequirements(), task.ExecutionCredentialsID, credentialsManager,
+		resourceFields.AppConfigClientCreator)
+	task.AddResource(appconfigsecret.ResourceName, appConfigSecretResource)
+
+	// for every container that needs appconfig secret vending as env, it needs to wait all secrets got retrieved
+	for _, container := range task.Containers {
+		if container.ShouldCreateWithAppConfigSecret() {
+			container.BuildResourceDependency(appConfigSecretResource.GetName(),
+				resourcestatus.ResourceStatus(appconfigsecret.AppConfigSecretCreated),
+				apicontainerstatus.ContainerCreated)
+		}
+	}
+}
+
+// getAllAppConfigSecretRequirements stores all appconfig secrets in a map whose key is region and
+// value is all secrets in that region
+func (task *Task) getAllAppConfigSecretRequirements() map[string][]apicontainer.Secret {
+	reqs := make(map[string][]apicontainer.Secret)
+
+	for _, container := range task.Containers {
+		for _, secret := range container.Secrets {
+			if secret.Provider == apicontainer.SecretProviderAppConfig {
+				reqs[secret.Region] = append(reqs[secret.Region], secret)
+			}
+		}
+	}
+	return reqs
+}
+
 // requiresASMSecret returns true if at least one container in the task
 // needs to retrieve secret from AWS Secrets Manager
 func (task *Task) requiresASMSecret() bool {
@@ -1252,6 +1405,7 @@ func (task *Task) initializeFirelensResource(config *config.Config, resourceFiel
 			if err != nil {
 				return errors.Wrap(err, "unable to initialize firelens resource")
 			}
+			firelensResource.SetLogRateLimit(config.LogRateLimit)
 			task.AddResource(firelens.ResourceName, firelensResource)
 			container.BuildResourceDependency(firelensResource.GetName(), resourcestatus.ResourceCreated,
 				apicontainerstatus.ContainerCreated)
@@ -1414,6 +1568,72 @@ func (task *Task) AddFirelensContainerBindMounts(firelensConfig *apicontainer.Fi
 	return nil
 }
 
+// AddSecretFileMounts writes the secrets of the container delivered as files to the task's secret
+// files directory and mounts each of them read-only at the container path of the secret.
+func (task *Task) AddSecretFileMounts(hostConfig *dockercontainer.HostConfig, container *apicontainer.Container,
+	cfg *config.Config) *apierrors.HostConfigError {
+	var asmRes *asmsecret.ASMSecretResource
+	if container.ShouldCreateWithASMSecret() {
+		resource, ok := task.getASMSecretsResource()
+		if !ok {
+			return &apierrors.HostConfigError{Msg: "task secret data: unable to fetch ASM Secrets resource"}
+		}
+		asmRes = resource[0].(*asmsecret.ASMSecretResource)
+	}
+
+	taskID := task.GetID()
+	containerDir := filepath.Join(task.GetSecretFilesDir(cfg.DataDir), container.Name)
+	for _, secret := range container.Secrets {
+		if secret.Type != apicontainer.SecretTypeMount {
+			continue
+		}
+		if secret.Provider != apicontainer.SecretProviderASM {
+			return &apierrors.HostConfigError{Msg: fmt.Sprintf(
+				"secret %s from %s can't be delivered as a file", secret.Name, secret.Provider)}
+		}
+		if secret.ContainerPath == "" || strings.ContainsAny(secret.Name, `/\`) {
+			return &apierrors.HostConfigError{Msg: fmt.Sprintf(
+				"secret %s has an invalid name or container path for file delivery", secret.Name)}
+		}
+		secretValue, ok := asmRes.GetCachedSecretValue(secret.GetSecretResourceCacheKey())
+		if !ok {
+			return &apierrors.HostConfigError{Msg: fmt.Sprintf("unable to find the value of secret %s", secret.Name)}
+		}
+		if err := os.MkdirAll(containerDir, 0700); err != nil {
+			return &apierrors.HostConfigError{Msg: fmt.Sprintf("unable to create secret files directory: %v", err)}
+		}
+		if err := os.WriteFile(filepath.Join(containerDir, secret.Name), []byte(secretValue), SecretFileMode); err != nil {
+			return &apierrors.HostConfigError{Msg: fmt.Sprintf("unable to write file of secret %s: %v", secret.Name, err)}
+		}
+		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   filepath.Join(cfg.DataDirOnHost, "data", secretFilesDir, taskID, container.Name, secret.Name),
+			Target:   secret.ContainerPath,
+			ReadOnly: true,
+		})
+	}
+	return nil
+}
+
+// HasSecretFiles returns true if a container of the task has a secret delivered as a file.
+func (task *Task) HasSecretFiles() bool {
+	isSecretAsFile := func(s apicontainer.Secret) bool {
+		return s.Type == apicontainer.SecretTypeMount
+	}
+	for _, container := range task.Containers {
+		if container.HasSecret(isSecretAsFile) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSecretFilesDir returns the directory of the data dir holding the files of the task's secrets
+// delivered as files.
+func (task *Task) GetSecretFilesDir(dataDir string) string {
+	return filepath.Join(dataDir, secretFilesDir, task.GetID())
+}
+
 // IsNetworkModeAWSVPC checks if the task is configured to use the AWSVPC task networking feature.
 func (task *Task) IsNetworkModeAWSVPC() bool {
 	return task.NetworkMode == AWSVPCNetworkMode
@@ -1692,6 +1912,15 @@ func (task *Task) updateTaskKnownStatus() (newStatus apitaskstatus.TaskStatus) {
 	// defined. Instead, we should get the task status for all containers' known
 	// statuses and compute the min of this
 	earliestKnownTaskStatus := task.getEarliestKnownTaskStatusForContainers()
+	// With health gating, the task stays at the status preceding RUNNING until all of its
+	// essential containers with a health check are healthy
+	if earliestKnownTaskStatus == apitaskstatus.TaskRunning && task.IsHealthGatingEnabled() &&
+		task.GetHealthStatus() != apicontainerstatus.ContainerHealthy {
+		logger.Debug("Essential container is not healthy yet, not updating task status to running", logger.Fields{
+			field.TaskID: task.GetID(),
+		})
+		earliestKnownTaskStatus = apitaskstatus.TaskCreated
+	}
 	if task.GetKnownStatus() < earliestKnownTaskStatus {
 		task.SetKnownStatus(earliestKnownTaskStatus)
 		logger.Info("Container change also resulted in task change", logger.Fields{
@@ -1925,6 +2154,8 @@ func (task *Task) dockerHostConfig(container *apicontainer.Container, dockerCont
 		}
 	}
 
+	task.enforceReadOnlyVolumesFrom(container, dockerContainerMap, hostConfig)
+
 	if err := task.platformHostConfigOverride(hostConfig); err != nil {
 		return nil, &apierrors.HostConfigError{Msg: err.Error()}
 	}
@@ -1953,10 +2184,36 @@ func (task *Task) dockerHostConfig(container *apicontainer.Container, dockerCont
 
 	task.pidModeOverride(container, dockerContainerMap, hostConfig)
 	task.ipcModeOverride(container, dockerContainerMap, hostConfig)
+	task.ulimitsOverride(container, hostConfig)
 
 	return hostConfig, nil
 }
 
+// ulimitsOverride adds the task level ulimits to the host config of the container. Ulimits set by
+// the container itself take precedence over task level ulimits with the same name.
+func (task *Task) ulimitsOverride(container *apicontainer.Container, hostConfig *dockercontainer.HostConfig) {
+	if container.IsInternal() || len(task.Ulimits) == 0 {
+		return
+	}
+
+	containerUlimits := make(map[string]struct{}, len(hostConfig.Ulimits))
+	for _, ulimit := range hostConfig.Ulimits {
+		if ulimit != nil {
+			containerUlimits[ulimit.Name] = struct{}{}
+		}
+	}
+	for _, ulimit := range task.Ulimits {
+		if _, ok := containerUlimits[ulimit.Name]; ok {
+			continue
+		}
+		hostConfig.Ulimits = append(hostConfig.Ulimits, &units.Ulimit{
+			Name: ulimit.Name,
+			Soft: ulimit.SoftLimit,
+			Hard: ulimit.HardLimit,
+		})
+	}
+}
+
 // overrideContainerRuntime overrides the runtime for the container in host config if needed.
 func (task *Task) overrideContainerRuntime(container *apicontainer.Container, hostCfg *dockercontainer.HostConfig,
 	cfg *config.Config) *apierrors.HostConfigError {
@@ -2118,8 +2375,10 @@ func (task *Task) overrideDNS(hostConfig *dockercontainer.HostConfig) *dockercon
 		return hostConfig
 	}
 
-	hostConfig.DNS = eni.DomainNameServers
-	hostConfig.DNSSearch = eni.DomainNameSearchList
+	// The name servers are tried in order, so the order the ENI lists them in must be kept. Copy them
+	// so that changes to the host config can't reorder the ENI's lists.
+	hostConfig.DNS = append([]string(nil), eni.DomainNameServers...)
+	hostConfig.DNSSearch = append([]string(nil), eni.DomainNameSearchList...)
 
 	return hostConfig
 }
@@ -2283,6 +2542,156 @@ func (task *Task) ipcModeOverride(container *apicontainer.Container, dockerConta
 	}
 }
 
+// validateReadonlyRootfsWritablePaths checks that every writable path declared by a container
+// with a read-only root filesystem is backed by a writable volume mount or a tmpfs mount, since
+// writes anywhere else would fail at runtime.
+// validateLogConfigurations validates the log options of the containers using the awslogs or awsfirelens
+// log drivers, so that a task with invalid options fails before any of its containers is started.
+func (task *Task) validateLogConfigurations() error {
+	firelensConfigType := ""
+	if firelensContainer := task.GetFirelensContainer(); firelensContainer != nil {
+		firelensConfigType = firelensContainer.GetFirelensConfig().Type
+	}
+
+	for _, container := range task.Containers {
+		if container.DockerConfig.HostConfig == nil {
+			continue
+		}
+		hostConfig := &dockercontainer.HostConfig{}
+		if err := json.Unmarshal([]byte(*container.DockerConfig.HostConfig), hostConfig); err != nil {
+			return fmt.Errorf("unable to decode host config of container %s: %w", container.Name, err)
+		}
+
+		var err error
+		switch hostConfig.LogConfig.Type {
+		case string(dockerclient.AWSLogsDriver):
+			err = validateAWSLogsOptions(hostConfig.LogConfig.Config)
+		case firelensDriverName:
+			err = validateFirelensLogOptions(container, firelensConfigType, hostConfig.LogConfig.Config)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid log configuration for container %s: %w", container.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateAWSLogsOptions validates the options of the awslogs log driver the same way the docker daemon would
+// when the container is started.
+func validateAWSLogsOptions(options map[string]string) error {
+	if options[awslogsGroupOpt] == "" {
+		return fmt.Errorf("missing required option %s", awslogsGroupOpt)
+	}
+	if createGroup, ok := options[awslogsCreateGroupOpt]; ok {
+		if _, err := strconv.ParseBool(createGroup); err != nil {
+			return fmt.Errorf("option %s must be a boolean: %w", awslogsCreateGroupOpt, err)
+		}
+	}
+	if mode, ok := options[logModeOpt]; ok && mode != logModeBlocking && mode != logModeNonBlocking {
+		return fmt.Errorf("option %s must be either %s or %s", logModeOpt, logModeBlocking, logModeNonBlocking)
+	}
+	_, hasDatetimeFormat := options[awslogsDatetimeFormatOpt]
+	multilinePattern, hasMultilinePattern := options[awslogsMultilinePatternOpt]
+	if hasDatetimeFormat && hasMultilinePattern {
+		return fmt.Errorf("options %s and %s are mutually exclusive", awslogsDatetimeFormatOpt,
+			awslogsMultilinePatternOpt)
+	}
+	if hasMultilinePattern {
+		if _, err := regexp.Compile(multilinePattern); err != nil {
+			return fmt.Errorf("option %s must be a valid regular expression: %w", awslogsMultilinePatternOpt, err)
+		}
+	}
+	return nil
+}
+
+// validateFirelensLogOptions validates that the log options of a container using the awsfirelens log driver,
+// including its log driver secrets, form a valid output configuration for the task's firelens container. The
+// options aren't validated if the task has no firelens container, which fails the task separately.
+func validateFirelensLogOptions(container *apicontainer.Container, firelensConfigType string,
+	options map[string]string) error {
+	if firelensConfigType == "" {
+		return nil
+	}
+	logOptions := make(map[string]string)
+	for k, v := range options {
+		if k == FirelensLogDriverBufferLimitOption {
+			continue
+		}
+		logOptions[k] = v
+	}
+	for _, secret := range container.Secrets {
+		if secret.Target == apicontainer.SecretTargetLogDriver {
+			logOptions[secret.Name] = ""
+		}
+	}
+	return firelens.ValidateLogOptions(firelensConfigType, logOptions)
+}
+
+// validateSecretProviders returns an error if a container sources its environment from
+// AWS AppConfig while the agent isn't configured to be AppConfig capable.
+func (task *Task) validateSecretProviders(cfg *config.Config) error {
+	if cfg.AppConfigCapable.Enabled() {
+		return nil
+	}
+	for _, container := range task.Containers {
+		for _, secret := range container.Secrets {
+			if secret.Provider == apicontainer.SecretProviderAppConfig {
+				return fmt.Errorf("container %s sources secret %s from %s, which is not enabled on this instance",
+					container.Name, secret.Name, apicontainer.SecretProviderAppConfig)
+			}
+		}
+	}
+	return nil
+}
+
+// validateReadonlyRootfsWritablePaths checks that every writable path declared by a container
+// with a read-only root filesystem is backed by a writable volume mount or a tmpfs mount, since
+// writes anywhere else would fail at runtime.
+func (task *Task) validateReadonlyRootfsWritablePaths() error {
+	for _, container := range task.Containers {
+		if len(container.WritablePaths) == 0 || container.DockerConfig.HostConfig == nil {
+			continue
+		}
+		hostConfig := &dockercontainer.HostConfig{}
+		if err := json.Unmarshal([]byte(*container.DockerConfig.HostConfig), hostConfig); err != nil {
+			return fmt.Errorf("unable to decode host config of container %s: %w", container.Name, err)
+		}
+		if !hostConfig.ReadonlyRootfs {
+			continue
+		}
+
+		var mountPaths []string
+		for _, mountPoint := range container.MountPoints {
+			if !mountPoint.ReadOnly {
+				mountPaths = append(mountPaths, mountPoint.ContainerPath)
+			}
+		}
+		for tmpfsPath := range hostConfig.Tmpfs {
+			mountPaths = append(mountPaths, tmpfsPath)
+		}
+
+		for _, writablePath := range container.WritablePaths {
+			if !isPathUnderAny(writablePath, mountPaths) {
+				return fmt.Errorf("container %s has a read-only root filesystem but writable path %s "+
+					"is not backed by a volume or tmpfs mount", container.Name, writablePath)
+			}
+		}
+	}
+	return nil
+}
+
+// isPathUnderAny returns true if the given path is, or is nested under, one of the mount paths
+func isPathUnderAny(path string, mountPaths []string) bool {
+	path = filepath.Clean(path)
+	for _, mountPath := range mountPaths {
+		mountPath = filepath.Clean(mountPath)
+		if path == mountPath || strings.HasPrefix(path, strings.TrimSuffix(mountPath, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (task *Task) initializeContainerOrdering() error {
 	// Handle ordering for Service Connect
 	if task.IsServiceConnectEnabled() {
@@ -2579,6 +2988,33 @@ func (task *Task) dockerVolumesFrom(container *apicontainer.Container,
 	return volumesFrom, nil
 }
 
+// enforceReadOnlyVolumesFrom makes sure that volumes shared from other containers as read-only
+// remain read-only, even if the docker host config override in the task definition specifies
+// the same source container without the read-only flag.
+func (task *Task) enforceReadOnlyVolumesFrom(container *apicontainer.Container,
+	dockerContainerMap map[string]*apicontainer.DockerContainer, hostConfig *dockercontainer.HostConfig) {
+	for _, volume := range container.VolumesFrom {
+		if !volume.ReadOnly {
+			continue
+		}
+		targetContainer, ok := dockerContainerMap[volume.SourceContainer]
+		if !ok {
+			continue
+		}
+		readOnlyVolume := targetContainer.DockerName + ":ro"
+		found := false
+		for i, existing := range hostConfig.VolumesFrom {
+			if strings.SplitN(existing, ":", 2)[0] == targetContainer.DockerName {
+				hostConfig.VolumesFrom[i] = readOnlyVolume
+				found = true
+			}
+		}
+		if !found {
+			hostConfig.VolumesFrom = append(hostConfig.VolumesFrom, readOnlyVolume)
+		}
+	}
+}
+
 func (task *Task) dockerHostBinds(container *apicontainer.Container) ([]string, error) {
 	if container.Name == emptyHostVolumeName {
 		// emptyHostVolumes are handled as a special case in config, not
@@ -2605,9 +3041,24 @@ func (task *Task) dockerHostBinds(container *apicontainer.Container) ([]string,
 				container.Name, mountPoint.SourceVolume, hv.Source(), mountPoint.ContainerPath)
 		}
 
-		bind := hv.Source() + ":" + mountPoint.ContainerPath
+		var options []string
 		if mountPoint.ReadOnly {
-			bind += ":ro"
+			options = append(options, "ro")
+		}
+		switch mountPoint.SELinuxRelabel {
+		case "":
+		case apicontainer.SELinuxRelabelShared:
+			options = append(options, "z")
+		case apicontainer.SELinuxRelabelPrivate:
+			options = append(options, "Z")
+		default:
+			return []string{}, errors.Errorf("Invalid selinux relabel option for volume %s of container %s: %s",
+				mountPoint.SourceVolume, container.Name, mountPoint.SELinuxRelabel)
+		}
+
+		bind := hv.Source() + ":" + mountPoint.ContainerPath
+		if len(options) > 0 {
+			bind += ":" + strings.Join(options, ",")
 		}
 		binds[i] = bind
 	}
@@ -2769,6 +3220,14 @@ func (task *Task) GetExecutionCredentialsID() string {
 	return task.ExecutionCredentialsID
 }
 
+// GetMetadataAuthToken gets the token issued to the task for the metadata endpoints
+func (task *Task) GetMetadataAuthToken() string {
+	task.lock.RLock()
+	defer task.lock.RUnlock()
+
+	return task.MetadataAuthTokenUnsafe
+}
+
 // GetDesiredStatus gets the desired status of the task
 func (task *Task) GetDesiredStatus() apitaskstatus.TaskStatus {
 	task.lock.RLock()
@@ -3099,10 +3558,20 @@ func (task *Task) getSSMSecretsResource() ([]taskresource.TaskResource, bool) {
 	return res, ok
 }
 
+// getAppConfigSecretsResource retrieves appconfigsecret resource from resource map
+func (task *Task) getAppConfigSecretsResource() ([]taskresource.TaskResource, bool) {
+	task.lock.RLock()
+	defer task.lock.RUnlock()
+
+	res, ok := task.ResourcesMapUnsafe[appconfigsecret.ResourceName]
+	return res, ok
+}
+
 // PopulateSecrets appends secrets to container's env var map and hostconfig section
 func (task *Task) PopulateSecrets(hostConfig *dockercontainer.HostConfig, container *apicontainer.Container) *apierrors.DockerClientConfigError {
 	var ssmRes *ssmsecret.SSMSecretResource
 	var asmRes *asmsecret.ASMSecretResource
+	var appConfigRes *appconfigsecret.AppConfigSecretResource
 
 	if container.ShouldCreateWithSSMSecret() {
 		resource, ok := task.getSSMSecretsResource()
@@ -3120,12 +3589,40 @@ func (task *Task) PopulateSecrets(hostConfig *dockercontainer.HostConfig, contai
 		asmRes = resource[0].(*asmsecret.ASMSecretResource)
 	}
 
-	populateContainerSecrets(hostConfig, container, ssmRes, asmRes)
+	if container.ShouldCreateWithAppConfigSecret() {
+		resource, ok := task.getAppConfigSecretsResource()
+		if !ok {
+			return &apierrors.DockerClientConfigError{Msg: "task secret data: unable to fetch AppConfig Secrets resource"}
+		}
+		appConfigRes = resource[0].(*appconfigsecret.AppConfigSecretResource)
+	}
+
+	populateContainerSecrets(hostConfig, container, ssmRes, asmRes, appConfigRes)
 	return nil
 }
 
+// expandSSMPathSecret adds one environment variable per parameter of an SSM parameter
+// hierarchy. Variable names are the secret name followed by the parameter name relative
+// to the hierarchy path, with path separators replaced by underscores.
+func expandSSMPathSecret(envVars map[string]string, prefix string, secretVal string) {
+	if secretVal == "" {
+		return
+	}
+	var parameters map[string]string
+	if err := json.Unmarshal([]byte(secretVal), &parameters); err != nil {
+		logger.Warn("Unable to decode SSM parameter hierarchy secret", logger.Fields{
+			field.Error: err,
+		})
+		return
+	}
+	for name, value := range parameters {
+		envVars[prefix+strings.ReplaceAll(name, "/", "_")] = value
+	}
+}
+
 func populateContainerSecrets(hostConfig *dockercontainer.HostConfig, container *apicontainer.Container,
-	ssmRes *ssmsecret.SSMSecretResource, asmRes *asmsecret.ASMSecretResource) {
+	ssmRes *ssmsecret.SSMSecretResource, asmRes *asmsecret.ASMSecretResource,
+	appConfigRes *appconfigsecret.AppConfigSecretResource) {
 	envVars := make(map[string]string)
 
 	logDriverTokenName := ""
@@ -3148,6 +3645,18 @@ func populateContainerSecrets(hostConfig *dockercontainer.HostConfig, container
 			}
 		}
 
+		if secret.Provider == apicontainer.SecretProviderAppConfig {
+			k := secret.GetSecretResourceCacheKey()
+			if secretValue, ok := appConfigRes.GetCachedSecretValue(k); ok {
+				secretVal = secretValue
+			}
+		}
+
+		if secret.Type == apicontainer.SecretTypeEnv && secret.IsSSMPathReference() {
+			expandSSMPathSecret(envVars, secret.Name, secretVal)
+			continue
+		}
+
 		if secret.Type == apicontainer.SecretTypeEnv {
 			envVars[secret.Name] = secretVal
 			continue
@@ -3174,7 +3683,7 @@ func populateContainerSecrets(hostConfig *dockercontainer.HostConfig, container
 		}
 	}
 
-	container.MergeEnvironmentVariables(envVars)
+	container.MergeEnvironmentVariablesFromSecrets(envVars)
 }
 
 // PopulateSecretLogOptionsToFirelensContainer collects secret log option values for awsfirelens log driver from task
@@ -3558,6 +4067,76 @@ func (task *Task) IsServiceConnectConnectionDraining() bool {
 	return task.ServiceConnectConnectionDrainingUnsafe
 }
 
+// SetHealthGatingEnabled sets whether the task gates its RUNNING status on the health of its essential containers.
+func (task *Task) SetHealthGatingEnabled(enabled bool) {
+	task.lock.Lock()
+	defer task.lock.Unlock()
+	task.HealthGatingEnabledUnsafe = enabled
+}
+
+// IsHealthGatingEnabled returns whether the task gates its RUNNING status on the health of its essential containers.
+func (task *Task) IsHealthGatingEnabled() bool {
+	task.lock.RLock()
+	defer task.lock.RUnlock()
+	return task.HealthGatingEnabledUnsafe
+}
+
+// IsHeldByHealthGating returns whether all of the task's containers are running but the task is held back from
+// RUNNING because its essential containers with a health check are not healthy yet.
+func (task *Task) IsHeldByHealthGating() bool {
+	return task.IsHealthGatingEnabled() && task.GetKnownStatus() < apitaskstatus.TaskRunning &&
+		task.getEarliestKnownTaskStatusForContainers() == apitaskstatus.TaskRunning &&
+		task.GetHealthStatus() != apicontainerstatus.ContainerHealthy
+}
+
+// IsHealthGatingTimedOut returns whether the task has been held back from RUNNING by health gating for longer
+// than the given timeout, counted from the time its last essential container with a health check started.
+func (task *Task) IsHealthGatingTimedOut(timeout time.Duration, now time.Time) bool {
+	if !task.IsHeldByHealthGating() {
+		return false
+	}
+	var lastStartedAt time.Time
+	for _, container := range task.Containers {
+		if !container.Essential || !container.HealthStatusShouldBeReported() {
+			continue
+		}
+		if startedAt := container.GetStartedAt(); startedAt.After(lastStartedAt) {
+			lastStartedAt = startedAt
+		}
+	}
+	return !lastStartedAt.IsZero() && now.Sub(lastStartedAt) > timeout
+}
+
+// HealthStatusShouldBeReported returns true if any of the task's essential containers has a health check
+// defined in the task definition.
+func (task *Task) HealthStatusShouldBeReported() bool {
+	for _, container := range task.Containers {
+		if container.Essential && container.HealthStatusShouldBeReported() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetHealthStatus returns the health of the task based on its essential containers that have a health check.
+// The task is unhealthy if any of those containers is unhealthy, unknown if any of them has not reported
+// its health yet, and healthy otherwise.
+func (task *Task) GetHealthStatus() apicontainerstatus.ContainerHealthStatus {
+	taskHealth := apicontainerstatus.ContainerHealthy
+	for _, container := range task.Containers {
+		if !container.Essential || !container.HealthStatusShouldBeReported() {
+			continue
+		}
+		switch container.GetHealthStatus().Status {
+		case apicontainerstatus.ContainerUnhealthy:
+			return apicontainerstatus.ContainerUnhealthy
+		case apicontainerstatus.ContainerHealthUnknown:
+			taskHealth = apicontainerstatus.ContainerHealthUnknown
+		}
+	}
+	return taskHealth
+}
+
 func (task *Task) IsLaunchTypeFargate() bool {
 	return strings.ToUpper(task.LaunchType) == "FARGATE"
 }
@@ -756,6 +756,8 @@ func TestPostUnmarshalWithFirelensContainer(t *testing.T) {
 	task := getFirelensTask(t)
 	task.Containers[1].FirelensConfig.Options["config-file-type"] = "file"
 	task.Containers[1].FirelensConfig.Options["config-file-value"] = "/tmp/file"
+	// the output plugin is required for the plugin specific log options to be valid
+	setFirelensLogOption(t, task.Containers[0], "@type", "cloudwatch")
 
 	resourceFields := &taskresource.ResourceFields{
 		ResourceFieldsCommon: &taskresource.ResourceFieldsCommon{
@@ -1317,6 +1319,33 @@ func TestCollectLogDriverSecretData(t *testing.T) {
 }
 
 // getFirelensTask returns a sample firelens task.
+func TestValidateLogConfigurationsFirelens(t *testing.T) {
+	task := getFirelensTask(t)
+	// plugin specific log options require the output plugin
+	assert.Error(t, task.validateLogConfigurations())
+
+	setFirelensLogOption(t, task.Containers[0], "@type", "cloudwatch")
+	assert.NoError(t, task.validateLogConfigurations())
+
+	// each additional output requires its own output plugin
+	task.Containers[1].FirelensConfig.Type = firelens.FirelensConfigTypeFluentbit
+	setFirelensLogOption(t, task.Containers[0], "Name", "cloudwatch")
+	setFirelensLogOption(t, task.Containers[0], "output.s3.bucket", "my-bucket")
+	assert.Error(t, task.validateLogConfigurations())
+
+	setFirelensLogOption(t, task.Containers[0], "output.s3.Name", "s3")
+	assert.NoError(t, task.validateLogConfigurations())
+}
+
+func setFirelensLogOption(t *testing.T, container *apicontainer.Container, key, value string) {
+	hostConfig := &dockercontainer.HostConfig{}
+	require.NoError(t, json.Unmarshal([]byte(*container.DockerConfig.HostConfig), hostConfig))
+	hostConfig.LogConfig.Config[key] = value
+	rawHostConfig, err := json.Marshal(hostConfig)
+	require.NoError(t, err)
+	container.DockerConfig.HostConfig = strptr(string(rawHostConfig))
+}
+
 func getFirelensTask(t *testing.T) *Task {
 	rawHostConfigInput := dockercontainer.HostConfig{
 		LogConfig: dockercontainer.LogConfig{
@@ -19,6 +19,8 @@ package task
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"strconv"
@@ -59,11 +61,13 @@ import (
 	commonutils "github.com/aws/amazon-ecs-agent/ecs-agent/utils"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
 
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/appconfigsecret"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/asmsecret"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/envFiles"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/ssmsecret"
 	"github.com/aws/aws-sdk-go/aws"
 	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/go-units"
 	"github.com/golang/mock/gomock"
@@ -701,6 +705,133 @@ func TestDockerHostConfigVolumesFrom(t *testing.T) {
 	}
 }
 
+func TestDockerHostConfigVolumesFromReadOnly(t *testing.T) {
+	testCases := []struct {
+		name                string
+		hostConfigOverride  *string
+		expectedVolumesFrom []string
+	}{
+		{
+			name:                "no override",
+			expectedVolumesFrom: []string{"dockername-c1:ro", "dockername-c2"},
+		},
+		{
+			name:                "override drops read-only flag",
+			hostConfigOverride:  strptr(`{"VolumesFrom":["dockername-c1","dockername-c2"]}`),
+			expectedVolumesFrom: []string{"dockername-c1:ro", "dockername-c2"},
+		},
+		{
+			name:                "override sets read-write flag",
+			hostConfigOverride:  strptr(`{"VolumesFrom":["dockername-c1:rw"]}`),
+			expectedVolumesFrom: []string{"dockername-c1:ro"},
+		},
+		{
+			name:                "override omits read-only source",
+			hostConfigOverride:  strptr(`{"VolumesFrom":["dockername-c2"]}`),
+			expectedVolumesFrom: []string{"dockername-c2", "dockername-c1:ro"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testTask := &Task{
+				Containers: []*apicontainer.Container{
+					{
+						Name: "c1",
+					},
+					{
+						Name: "c2",
+					},
+					{
+						Name: "c3",
+						VolumesFrom: []apicontainer.VolumeFrom{
+							{SourceContainer: "c1", ReadOnly: true},
+							{SourceContainer: "c2"},
+						},
+						DockerConfig: apicontainer.DockerConfig{
+							HostConfig: tc.hostConfigOverride,
+						},
+					},
+				},
+			}
+
+			hostConfig, err := testTask.DockerHostConfig(testTask.Containers[2], dockerMap(testTask),
+				defaultDockerClientAPIVersion, &config.Config{})
+			require.Nil(t, err)
+			assert.Equal(t, tc.expectedVolumesFrom, hostConfig.VolumesFrom)
+		})
+	}
+}
+
+func TestDockerHostBindsSELinuxRelabel(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mountPoint   apicontainer.MountPoint
+		expectedBind string
+		expectError  bool
+	}{
+		{
+			name:         "no relabel",
+			mountPoint:   apicontainer.MountPoint{SourceVolume: "vol", ContainerPath: "/data"},
+			expectedBind: "/host/path:/data",
+		},
+		{
+			name: "shared relabel",
+			mountPoint: apicontainer.MountPoint{SourceVolume: "vol", ContainerPath: "/data",
+				SELinuxRelabel: apicontainer.SELinuxRelabelShared},
+			expectedBind: "/host/path:/data:z",
+		},
+		{
+			name: "private relabel",
+			mountPoint: apicontainer.MountPoint{SourceVolume: "vol", ContainerPath: "/data",
+				SELinuxRelabel: apicontainer.SELinuxRelabelPrivate},
+			expectedBind: "/host/path:/data:Z",
+		},
+		{
+			name: "read-only with private relabel",
+			mountPoint: apicontainer.MountPoint{SourceVolume: "vol", ContainerPath: "/data", ReadOnly: true,
+				SELinuxRelabel: apicontainer.SELinuxRelabelPrivate},
+			expectedBind: "/host/path:/data:ro,Z",
+		},
+		{
+			name: "invalid relabel",
+			mountPoint: apicontainer.MountPoint{SourceVolume: "vol", ContainerPath: "/data",
+				SELinuxRelabel: "unknown"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testTask := &Task{
+				Containers: []*apicontainer.Container{
+					{
+						Name:        "c1",
+						MountPoints: []apicontainer.MountPoint{tc.mountPoint},
+					},
+				},
+				Volumes: []TaskVolume{
+					{
+						Name: "vol",
+						Type: HostVolumeType,
+						Volume: &taskresourcevolume.FSHostVolume{
+							FSSourcePath: "/host/path",
+						},
+					},
+				},
+			}
+
+			binds, err := testTask.dockerHostBinds(testTask.Containers[0])
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{tc.expectedBind}, binds)
+		})
+	}
+}
+
 func TestDockerHostConfigRawConfig(t *testing.T) {
 	rawHostConfigInput := dockercontainer.HostConfig{
 		Privileged:     true,
@@ -751,6 +882,103 @@ func TestDockerHostConfigRawConfig(t *testing.T) {
 	assertSetStructFieldsEqual(t, expectedOutput, *config)
 }
 
+func TestDockerHostConfigDNSOrder(t *testing.T) {
+	dnsServers := []string{"10.0.0.3", "10.0.0.2", "8.8.8.8"}
+	rawHostConfig, err := json.Marshal(&dockercontainer.HostConfig{DNS: dnsServers})
+	require.NoError(t, err)
+
+	testTask := &Task{
+		Containers: []*apicontainer.Container{
+			{
+				Name: "c1",
+				DockerConfig: apicontainer.DockerConfig{
+					HostConfig: strptr(string(rawHostConfig)),
+				},
+			},
+		},
+	}
+
+	cfg, configErr := testTask.DockerHostConfig(testTask.Containers[0], dockerMap(testTask), defaultDockerClientAPIVersion,
+		&config.Config{})
+	require.Nil(t, configErr)
+	assert.Equal(t, dnsServers, cfg.DNS)
+}
+
+func TestDockerHostConfigTaskUlimits(t *testing.T) {
+	rawHostConfig, err := json.Marshal(&dockercontainer.HostConfig{
+		Resources: dockercontainer.Resources{
+			Ulimits: []*units.Ulimit{{Name: "nofile", Soft: 2048, Hard: 4096}},
+		},
+	})
+	require.NoError(t, err)
+
+	testTask := &Task{
+		Ulimits: []Ulimit{
+			{Name: "nofile", SoftLimit: 1024, HardLimit: 1024},
+			{Name: "nproc", SoftLimit: 512, HardLimit: 1024},
+		},
+		Containers: []*apicontainer.Container{
+			{
+				Name: "c1",
+				DockerConfig: apicontainer.DockerConfig{
+					HostConfig: strptr(string(rawHostConfig)),
+				},
+			},
+			{
+				Name: "c2",
+			},
+		},
+	}
+
+	cfg, configErr := testTask.DockerHostConfig(testTask.Containers[0], dockerMap(testTask), defaultDockerClientAPIVersion,
+		&config.Config{})
+	require.Nil(t, configErr)
+	// the container's own nofile ulimit takes precedence over the task level one
+	assert.Equal(t, []*units.Ulimit{
+		{Name: "nofile", Soft: 2048, Hard: 4096},
+		{Name: "nproc", Soft: 512, Hard: 1024},
+	}, cfg.Ulimits)
+
+	cfg, configErr = testTask.DockerHostConfig(testTask.Containers[1], dockerMap(testTask), defaultDockerClientAPIVersion,
+		&config.Config{})
+	require.Nil(t, configErr)
+	assert.Equal(t, []*units.Ulimit{
+		{Name: "nofile", Soft: 1024, Hard: 1024},
+		{Name: "nproc", Soft: 512, Hard: 1024},
+	}, cfg.Ulimits)
+}
+
+func TestTaskFromACSUlimits(t *testing.T) {
+	taskFromACS := ecsacs.Task{
+		Ulimits: []*ecsacs.Ulimit{
+			{Name: strptr("nofile"), SoftLimit: aws.Int64(1024), HardLimit: aws.Int64(2048)},
+		},
+	}
+	seqNum := int64(42)
+	task, err := TaskFromACS(&taskFromACS, &ecsacs.PayloadMessage{SeqNum: &seqNum})
+	require.NoError(t, err)
+	assert.Equal(t, []Ulimit{{Name: "nofile", SoftLimit: 1024, HardLimit: 2048}}, task.Ulimits)
+}
+
+func TestOverrideDNSOrder(t *testing.T) {
+	eniDNSServers := []string{"10.0.0.3", "10.0.0.2", "169.254.169.253"}
+	testTask := &Task{
+		ENIs: []*ni.NetworkInterface{
+			{
+				ID:                "eniID",
+				DomainNameServers: eniDNSServers,
+			},
+		},
+	}
+
+	hostConfig := testTask.overrideDNS(&dockercontainer.HostConfig{})
+	assert.Equal(t, eniDNSServers, hostConfig.DNS)
+
+	// Changes to the host config must not reorder the ENI's name servers
+	hostConfig.DNS[0], hostConfig.DNS[1] = hostConfig.DNS[1], hostConfig.DNS[0]
+	assert.Equal(t, []string{"10.0.0.3", "10.0.0.2", "169.254.169.253"}, testTask.ENIs[0].DomainNameServers)
+}
+
 func TestDockerHostConfigPauseContainer(t *testing.T) {
 	testTask := &Task{
 		ENIs: []*ni.NetworkInterface{
@@ -1375,6 +1603,33 @@ func TestInitializeContainersV4MetadataEndpoint(t *testing.T) {
 		fmt.Sprintf(apicontainer.MetadataURIFormatV4, "new-uuid"))
 }
 
+func TestInitializeMetadataAuthToken(t *testing.T) {
+	task := Task{
+		Containers: []*apicontainer.Container{
+			{Name: "c1"},
+			{Name: "c2"},
+		},
+	}
+
+	require.NoError(t, task.initializeMetadataAuthToken())
+
+	// every container of the task gets the same token
+	token := task.GetMetadataAuthToken()
+	assert.Len(t, token, 2*metadataAuthTokenLength)
+	for _, container := range task.Containers {
+		assert.Equal(t, token, container.Environment[apicontainer.MetadataAuthTokenEnvVarName])
+	}
+
+	// a token restored from the agent state is kept
+	require.NoError(t, task.initializeMetadataAuthToken())
+	assert.Equal(t, token, task.GetMetadataAuthToken())
+
+	// tokens are issued per task
+	otherTask := Task{Containers: []*apicontainer.Container{{Name: "c1"}}}
+	require.NoError(t, otherTask.initializeMetadataAuthToken())
+	assert.NotEqual(t, token, otherTask.GetMetadataAuthToken())
+}
+
 // Tests that task.initializeContainersV1AgentAPIEndpoint method initializes
 // V3EndpointID for all containers of the task and injects v1 Agent API Endpoint
 // as an environment variable into each container.
@@ -1730,6 +1985,7 @@ func TestTaskFromACS(t *testing.T) {
 						HostPort:      intptr(800),
 						ContainerPort: intptr(900),
 						Protocol:      strptr("udp"),
+						Name:          strptr("dns"),
 					},
 					{
 						ContainerPortRange: strptr("99-199"),
@@ -1742,6 +1998,7 @@ func TestTaskFromACS(t *testing.T) {
 						SourceContainer: strptr("volumeLink"),
 					},
 				},
+				WritablePaths: []*string{strptr("/container/path/data")},
 				DockerConfig: &ecsacs.DockerConfig{
 					Config:     strptr("config json"),
 					HostConfig: strptr("hostconfig json"),
@@ -1833,6 +2090,7 @@ func TestTaskFromACS(t *testing.T) {
 						HostPort:      800,
 						ContainerPort: 900,
 						Protocol:      apicontainer.TransportProtocolUDP,
+						Name:          "dns",
 					},
 					{
 						ContainerPortRange: "99-199",
@@ -1845,6 +2103,7 @@ func TestTaskFromACS(t *testing.T) {
 						SourceContainer: "volumeLink",
 					},
 				},
+				WritablePaths: []string{"/container/path/data"},
 				DockerConfig: apicontainer.DockerConfig{
 					Config:     strptr("config json"),
 					HostConfig: strptr("hostconfig json"),
@@ -1959,6 +2218,197 @@ func TestTaskUpdateKnownStatusNotChangeToRunningWithEssentialContainerStopped(t
 	assert.Equal(t, apitaskstatus.TaskCreated, testTask.GetKnownStatus(), "task status should not move to running if essential container is stopped")
 }
 
+// TestTaskUpdateKnownStatusHealthGating tests that, with health gating enabled, the task only moves to
+// running once all of its essential containers with a health check are healthy
+func TestTaskUpdateKnownStatusHealthGating(t *testing.T) {
+	testCases := []struct {
+		name                string
+		healthGatingEnabled bool
+		healthStatus        apicontainerstatus.ContainerHealthStatus
+		expectedStatus      apitaskstatus.TaskStatus
+	}{
+		{
+			name:                "gating disabled",
+			healthGatingEnabled: false,
+			healthStatus:        apicontainerstatus.ContainerHealthUnknown,
+			expectedStatus:      apitaskstatus.TaskRunning,
+		},
+		{
+			name:                "essential container health unknown",
+			healthGatingEnabled: true,
+			healthStatus:        apicontainerstatus.ContainerHealthUnknown,
+			expectedStatus:      apitaskstatus.TaskCreated,
+		},
+		{
+			name:                "essential container unhealthy",
+			healthGatingEnabled: true,
+			healthStatus:        apicontainerstatus.ContainerUnhealthy,
+			expectedStatus:      apitaskstatus.TaskCreated,
+		},
+		{
+			name:                "essential container healthy",
+			healthGatingEnabled: true,
+			healthStatus:        apicontainerstatus.ContainerHealthy,
+			expectedStatus:      apitaskstatus.TaskRunning,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			essential := &apicontainer.Container{
+				KnownStatusUnsafe: apicontainerstatus.ContainerRunning,
+				Essential:         true,
+				HealthCheckType:   apicontainer.DockerHealthCheckType,
+			}
+			essential.SetHealthStatus(apicontainer.HealthStatus{Status: tc.healthStatus})
+			nonEssential := &apicontainer.Container{
+				KnownStatusUnsafe: apicontainerstatus.ContainerRunning,
+				HealthCheckType:   apicontainer.DockerHealthCheckType,
+			}
+			nonEssential.SetHealthStatus(apicontainer.HealthStatus{Status: apicontainerstatus.ContainerUnhealthy})
+			testTask := &Task{
+				KnownStatusUnsafe: apitaskstatus.TaskCreated,
+				Containers:        []*apicontainer.Container{essential, nonEssential},
+			}
+			testTask.SetHealthGatingEnabled(tc.healthGatingEnabled)
+
+			testTask.updateTaskKnownStatus()
+			assert.Equal(t, tc.expectedStatus, testTask.GetKnownStatus())
+		})
+	}
+}
+
+// TestIsHealthGatingTimedOut tests that a task whose essential container never becomes healthy is reported as
+// timed out once the health gating timeout expired after the container started
+func TestIsHealthGatingTimedOut(t *testing.T) {
+	startedAt := time.Now()
+	timeout := 5 * time.Minute
+	testCases := []struct {
+		name                string
+		healthGatingEnabled bool
+		healthStatus        apicontainerstatus.ContainerHealthStatus
+		elapsed             time.Duration
+		expectedHeld        bool
+		expectedTimedOut    bool
+	}{
+		{
+			name:                "gating disabled",
+			healthGatingEnabled: false,
+			healthStatus:        apicontainerstatus.ContainerHealthUnknown,
+			elapsed:             2 * timeout,
+		},
+		{
+			name:                "never healthy within timeout",
+			healthGatingEnabled: true,
+			healthStatus:        apicontainerstatus.ContainerHealthUnknown,
+			elapsed:             timeout / 2,
+			expectedHeld:        true,
+		},
+		{
+			name:                "never healthy after timeout",
+			healthGatingEnabled: true,
+			healthStatus:        apicontainerstatus.ContainerHealthUnknown,
+			elapsed:             2 * timeout,
+			expectedHeld:        true,
+			expectedTimedOut:    true,
+		},
+		{
+			name:                "unhealthy after timeout",
+			healthGatingEnabled: true,
+			healthStatus:        apicontainerstatus.ContainerUnhealthy,
+			elapsed:             2 * timeout,
+			expectedHeld:        true,
+			expectedTimedOut:    true,
+		},
+		{
+			name:                "healthy after timeout",
+			healthGatingEnabled: true,
+			healthStatus:        apicontainerstatus.ContainerHealthy,
+			elapsed:             2 * timeout,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			essential := &apicontainer.Container{
+				KnownStatusUnsafe: apicontainerstatus.ContainerRunning,
+				Essential:         true,
+				HealthCheckType:   apicontainer.DockerHealthCheckType,
+			}
+			essential.SetHealthStatus(apicontainer.HealthStatus{Status: tc.healthStatus})
+			essential.SetStartedAt(startedAt)
+			testTask := &Task{
+				KnownStatusUnsafe: apitaskstatus.TaskCreated,
+				Containers:        []*apicontainer.Container{essential},
+			}
+			testTask.SetHealthGatingEnabled(tc.healthGatingEnabled)
+
+			testTask.updateTaskKnownStatus()
+			assert.Equal(t, tc.expectedHeld, testTask.IsHeldByHealthGating())
+			assert.Equal(t, tc.expectedTimedOut, testTask.IsHealthGatingTimedOut(timeout, startedAt.Add(tc.elapsed)))
+		})
+	}
+}
+
+func TestGetHealthStatus(t *testing.T) {
+	newContainer := func(essential bool, status apicontainerstatus.ContainerHealthStatus) *apicontainer.Container {
+		container := &apicontainer.Container{
+			Essential:       essential,
+			HealthCheckType: apicontainer.DockerHealthCheckType,
+		}
+		container.SetHealthStatus(apicontainer.HealthStatus{Status: status})
+		return container
+	}
+
+	testCases := []struct {
+		name             string
+		containers       []*apicontainer.Container
+		expected         apicontainerstatus.ContainerHealthStatus
+		expectedReported bool
+	}{
+		{
+			name:       "no health checks",
+			containers: []*apicontainer.Container{{Essential: true}},
+			expected:   apicontainerstatus.ContainerHealthy,
+		},
+		{
+			name: "all essential healthy",
+			containers: []*apicontainer.Container{
+				newContainer(true, apicontainerstatus.ContainerHealthy),
+				newContainer(false, apicontainerstatus.ContainerUnhealthy),
+			},
+			expected:         apicontainerstatus.ContainerHealthy,
+			expectedReported: true,
+		},
+		{
+			name: "essential unknown",
+			containers: []*apicontainer.Container{
+				newContainer(true, apicontainerstatus.ContainerHealthy),
+				newContainer(true, apicontainerstatus.ContainerHealthUnknown),
+			},
+			expected:         apicontainerstatus.ContainerHealthUnknown,
+			expectedReported: true,
+		},
+		{
+			name: "essential unhealthy",
+			containers: []*apicontainer.Container{
+				newContainer(true, apicontainerstatus.ContainerHealthUnknown),
+				newContainer(true, apicontainerstatus.ContainerUnhealthy),
+			},
+			expected:         apicontainerstatus.ContainerUnhealthy,
+			expectedReported: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := &Task{Containers: tc.containers}
+			assert.Equal(t, tc.expected, task.GetHealthStatus())
+			assert.Equal(t, tc.expectedReported, task.HealthStatusShouldBeReported())
+		})
+	}
+}
+
 // TestTaskUpdateKnownStatusToPendingWithEssentialContainerStopped tests when there is one essential container
 // is stopped while other container status are prior to Running, the task status should be updated.
 func TestTaskUpdateKnownStatusToPendingWithEssentialContainerStopped(t *testing.T) {
@@ -3364,6 +3814,127 @@ func TestPopulateSecretsNoConfigInHostConfig(t *testing.T) {
 	assert.Equal(t, "secretValue1", hostConfig.LogConfig.Config["splunk-token"])
 }
 
+func TestAddSecretFileMounts(t *testing.T) {
+	secret1 := apicontainer.Secret{
+		Provider:      "asm",
+		Name:          "secret1",
+		Region:        "us-west-2",
+		Type:          apicontainer.SecretTypeMount,
+		ValueFrom:     "arn:aws:secretsmanager:us-west-2:11111:secret:/test/secretName",
+		ContainerPath: "/run/secrets/secret1",
+	}
+
+	secret2 := apicontainer.Secret{
+		Provider:  "asm",
+		Name:      "secret2",
+		Region:    "us-west-1",
+		Type:      apicontainer.SecretTypeEnv,
+		ValueFrom: "/test/secretName1",
+	}
+
+	container := &apicontainer.Container{
+		Name:    "myName",
+		Image:   "image:tag",
+		Secrets: []apicontainer.Secret{secret1, secret2},
+	}
+
+	task := &Task{
+		Arn:                "arn:aws:ecs:us-west-2:123456789012:task/cluster/taskID",
+		ResourcesMapUnsafe: make(map[string][]taskresource.TaskResource),
+		Containers:         []*apicontainer.Container{container},
+	}
+	require.True(t, task.HasSecretFiles())
+
+	cfg := &config.Config{
+		DataDir:       t.TempDir(),
+		DataDirOnHost: "/var/lib/ecs",
+	}
+	hostConfig := &dockercontainer.HostConfig{}
+	assert.NotNil(t, task.AddSecretFileMounts(hostConfig, container, cfg),
+		"secrets as files need the asmsecret resource")
+
+	asmRes := &asmsecret.ASMSecretResource{}
+	asmRes.SetCachedSecretValue(asmSecretKeyWest1, "secretValue1")
+	task.AddResource(asmsecret.ResourceName, asmRes)
+
+	require.Nil(t, task.AddSecretFileMounts(hostConfig, container, cfg))
+	assert.Equal(t, []mount.Mount{
+		{
+			Type:     mount.TypeBind,
+			Source:   filepath.Join("/var/lib/ecs", "data", "secrets", "taskID", "myName", "secret1"),
+			Target:   "/run/secrets/secret1",
+			ReadOnly: true,
+		},
+	}, hostConfig.Mounts)
+
+	secretFile := filepath.Join(task.GetSecretFilesDir(cfg.DataDir), "myName", "secret1")
+	content, err := os.ReadFile(secretFile)
+	require.NoError(t, err)
+	assert.Equal(t, "secretValue1", string(content))
+	info, err := os.Stat(secretFile)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, SecretFileMode, info.Mode().Perm())
+	}
+}
+
+func TestAddSecretFileMountsInvalidSecret(t *testing.T) {
+	testCases := []struct {
+		name   string
+		secret apicontainer.Secret
+	}{
+		{
+			name: "provider without file delivery",
+			secret: apicontainer.Secret{
+				Provider:      "ssm",
+				Name:          "secret1",
+				Type:          apicontainer.SecretTypeMount,
+				ValueFrom:     "/test/secretName",
+				ContainerPath: "/run/secrets/secret1",
+			},
+		},
+		{
+			name: "no container path",
+			secret: apicontainer.Secret{
+				Provider:  "asm",
+				Name:      "secret1",
+				Type:      apicontainer.SecretTypeMount,
+				ValueFrom: "/test/secretName",
+			},
+		},
+		{
+			name: "name with a path separator",
+			secret: apicontainer.Secret{
+				Provider:      "asm",
+				Name:          "../secret1",
+				Type:          apicontainer.SecretTypeMount,
+				ValueFrom:     "/test/secretName",
+				ContainerPath: "/run/secrets/secret1",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			container := &apicontainer.Container{
+				Name:    "myName",
+				Secrets: []apicontainer.Secret{tc.secret},
+			}
+			task := &Task{
+				Arn:                "arn:aws:ecs:us-west-2:123456789012:task/cluster/taskID",
+				ResourcesMapUnsafe: make(map[string][]taskresource.TaskResource),
+				Containers:         []*apicontainer.Container{container},
+			}
+			task.AddResource(asmsecret.ResourceName, &asmsecret.ASMSecretResource{})
+
+			cfg := &config.Config{DataDir: t.TempDir()}
+			hostConfig := &dockercontainer.HostConfig{}
+			assert.NotNil(t, task.AddSecretFileMounts(hostConfig, container, cfg))
+			assert.Empty(t, hostConfig.Mounts)
+		})
+	}
+}
+
 func TestPopulateSecretsAsEnvOnlySSM(t *testing.T) {
 	secret1 := apicontainer.Secret{
 		Provider:  "asm",
@@ -3420,6 +3991,145 @@ func TestPopulateSecretsAsEnvOnlySSM(t *testing.T) {
 	assert.Equal(t, 1, len(container.Environment))
 }
 
+func TestInitializeAndGetAppConfigSecretResource(t *testing.T) {
+	secret := apicontainer.Secret{
+		Provider:  apicontainer.SecretProviderAppConfig,
+		Name:      "FEATURE_FLAGS",
+		Region:    "us-west-2",
+		Type:      apicontainer.SecretTypeEnv,
+		ValueFrom: "my-app/prod/flags",
+	}
+
+	container := &apicontainer.Container{
+		Name:                      "myName",
+		Image:                     "image:tag",
+		Secrets:                   []apicontainer.Secret{secret},
+		TransitionDependenciesMap: make(map[apicontainerstatus.ContainerStatus]apicontainer.TransitionDependencySet),
+	}
+
+	container1 := &apicontainer.Container{
+		Name:                      "myName",
+		Image:                     "image:tag",
+		Secrets:                   nil,
+		TransitionDependenciesMap: make(map[apicontainerstatus.ContainerStatus]apicontainer.TransitionDependencySet),
+	}
+
+	task := &Task{
+		Arn:                "test",
+		ResourcesMapUnsafe: make(map[string][]taskresource.TaskResource),
+		Containers:         []*apicontainer.Container{container, container1},
+	}
+	require.True(t, task.requiresAppConfigSecret())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	credentialsManager := mock_credentials.NewMockManager(ctrl)
+	resFields := &taskresource.ResourceFields{
+		ResourceFieldsCommon: &taskresource.ResourceFieldsCommon{
+			CredentialsManager: credentialsManager,
+		},
+	}
+
+	task.initSecretResources(credentialsManager, resFields)
+
+	resourceDep := apicontainer.ResourceDependency{
+		Name:           appconfigsecret.ResourceName,
+		RequiredStatus: resourcestatus.ResourceStatus(appconfigsecret.AppConfigSecretCreated),
+	}
+
+	assert.Equal(t, resourceDep, task.Containers[0].TransitionDependenciesMap[apicontainerstatus.ContainerCreated].ResourceDependencies[0])
+	assert.Equal(t, 0, len(task.Containers[1].TransitionDependenciesMap))
+
+	_, ok := task.getAppConfigSecretsResource()
+	assert.True(t, ok)
+	_, ok = task.getSSMSecretsResource()
+	assert.False(t, ok)
+}
+
+func TestPopulateSecretsAppConfig(t *testing.T) {
+	secret1 := apicontainer.Secret{
+		Provider:  apicontainer.SecretProviderAppConfig,
+		Name:      "FEATURE_FLAGS",
+		Region:    "us-west-2",
+		Type:      apicontainer.SecretTypeEnv,
+		ValueFrom: "my-app/prod/flags",
+	}
+
+	secret2 := apicontainer.Secret{
+		Provider:  "ssm",
+		Name:      "secret2",
+		Region:    "us-west-2",
+		Type:      apicontainer.SecretTypeEnv,
+		ValueFrom: "/test/secretName",
+	}
+
+	container := &apicontainer.Container{
+		Name:                      "myName",
+		Image:                     "image:tag",
+		Secrets:                   []apicontainer.Secret{secret1, secret2},
+		TransitionDependenciesMap: make(map[apicontainerstatus.ContainerStatus]apicontainer.TransitionDependencySet),
+	}
+
+	task := &Task{
+		Arn:                "test",
+		ResourcesMapUnsafe: make(map[string][]taskresource.TaskResource),
+		Containers:         []*apicontainer.Container{container},
+	}
+
+	hostConfig := &dockercontainer.HostConfig{}
+	assert.NotNil(t, task.PopulateSecrets(hostConfig, container), "appconfig secrets need the appconfigsecret resource")
+
+	appConfigRes := &appconfigsecret.AppConfigSecretResource{}
+	appConfigRes.SetCachedSecretValue(secret1.GetSecretResourceCacheKey(), `{"feature":true}`)
+	ssmRes := &ssmsecret.SSMSecretResource{}
+	ssmRes.SetCachedSecretValue(secretKeyWest1, "secretValue2")
+
+	task.AddResource(appconfigsecret.ResourceName, appConfigRes)
+	task.AddResource(ssmsecret.ResourceName, ssmRes)
+
+	assert.Nil(t, task.PopulateSecrets(hostConfig, container))
+	assert.Equal(t, `{"feature":true}`, container.Environment["FEATURE_FLAGS"])
+	assert.Equal(t, "secretValue2", container.Environment["secret2"])
+	assert.Equal(t, 2, len(container.Environment))
+}
+
+func TestPopulateSecretsSSMPathReference(t *testing.T) {
+	secret := apicontainer.Secret{
+		Provider:  "ssm",
+		Name:      "APP_",
+		Region:    "us-west-2",
+		Type:      "ENVIRONMENT_VARIABLE",
+		ValueFrom: "ssm:path:/app/prod",
+	}
+
+	container := &apicontainer.Container{
+		Name:                      "myName",
+		Image:                     "image:tag",
+		Secrets:                   []apicontainer.Secret{secret},
+		TransitionDependenciesMap: make(map[apicontainerstatus.ContainerStatus]apicontainer.TransitionDependencySet),
+	}
+
+	task := &Task{
+		Arn:                "test",
+		ResourcesMapUnsafe: make(map[string][]taskresource.TaskResource),
+		Containers:         []*apicontainer.Container{container},
+	}
+
+	ssmRes := &ssmsecret.SSMSecretResource{}
+	ssmRes.SetCachedSecretValue(secret.GetSecretResourceCacheKey(),
+		`{"DB_HOST":"db.example.com","db/PASSWORD":"secretValue"}`)
+	task.AddResource(ssmsecret.ResourceName, ssmRes)
+
+	hostConfig := &dockercontainer.HostConfig{}
+
+	task.PopulateSecrets(hostConfig, container)
+
+	assert.Equal(t, "db.example.com", container.Environment["APP_DB_HOST"])
+	assert.Equal(t, "secretValue", container.Environment["APP_db_PASSWORD"])
+	assert.Equal(t, 2, len(container.Environment))
+}
+
 func TestAddGPUResource(t *testing.T) {
 	container := &apicontainer.Container{
 		Name:  "myName",
@@ -3817,6 +4527,171 @@ func TestInitializeContainerOrderingWithError(t *testing.T) {
 	assert.Error(t, errLink2)
 }
 
+func TestValidateAWSLogsOptions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		options     map[string]string
+		expectError bool
+	}{
+		{
+			name: "valid options",
+			options: map[string]string{
+				"awslogs-group":             "my-group",
+				"awslogs-region":            "us-west-2",
+				"awslogs-create-group":      "true",
+				"awslogs-multiline-pattern": "^INFO",
+				"mode":                      "non-blocking",
+			},
+		},
+		{
+			name:        "missing group",
+			options:     map[string]string{"awslogs-region": "us-west-2"},
+			expectError: true,
+		},
+		{
+			name:        "create group is not a boolean",
+			options:     map[string]string{"awslogs-group": "my-group", "awslogs-create-group": "yes please"},
+			expectError: true,
+		},
+		{
+			name:        "unknown mode",
+			options:     map[string]string{"awslogs-group": "my-group", "mode": "async"},
+			expectError: true,
+		},
+		{
+			name: "datetime format and multiline pattern",
+			options: map[string]string{
+				"awslogs-group":             "my-group",
+				"awslogs-datetime-format":   "%Y-%m-%d",
+				"awslogs-multiline-pattern": "^INFO",
+			},
+			expectError: true,
+		},
+		{
+			name:        "invalid multiline pattern",
+			options:     map[string]string{"awslogs-group": "my-group", "awslogs-multiline-pattern": "(INFO"},
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAWSLogsOptions(tc.options)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateLogConfigurationsInvalidHostConfig(t *testing.T) {
+	task := &Task{
+		Containers: []*apicontainer.Container{
+			{
+				Name:         "myName",
+				DockerConfig: apicontainer.DockerConfig{HostConfig: strptr(`{"LogConfig":`)},
+			},
+		},
+	}
+	assert.Error(t, task.validateLogConfigurations())
+}
+
+func TestValidateSecretProviders(t *testing.T) {
+	task := &Task{
+		Arn: "test",
+		Containers: []*apicontainer.Container{
+			{
+				Name: "myName",
+				Secrets: []apicontainer.Secret{
+					{
+						Provider:  apicontainer.SecretProviderAppConfig,
+						Name:      "FEATURE_FLAGS",
+						Type:      apicontainer.SecretTypeEnv,
+						ValueFrom: "my-app/prod/flags",
+					},
+				},
+			},
+		},
+	}
+
+	cfg := &config.Config{}
+	err := task.validateSecretProviders(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not enabled on this instance")
+
+	cfg.AppConfigCapable = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+	assert.NoError(t, task.validateSecretProviders(cfg))
+
+	cfg.AppConfigCapable = config.BooleanDefaultFalse{}
+	task.Containers[0].Secrets[0].Provider = apicontainer.SecretProviderSSM
+	assert.NoError(t, task.validateSecretProviders(cfg))
+}
+
+func TestValidateReadonlyRootfsWritablePaths(t *testing.T) {
+	testCases := []struct {
+		name          string
+		hostConfig    string
+		mountPoints   []apicontainer.MountPoint
+		writablePaths []string
+		expectError   bool
+	}{
+		{
+			name:          "writable paths backed by volume and tmpfs",
+			hostConfig:    `{"ReadonlyRootfs":true,"Tmpfs":{"/tmp":"rw"}}`,
+			mountPoints:   []apicontainer.MountPoint{{SourceVolume: "data", ContainerPath: "/data"}},
+			writablePaths: []string{"/data", "/data/cache/", "/tmp/scratch"},
+		},
+		{
+			name:          "writable root filesystem is not validated",
+			hostConfig:    `{"ReadonlyRootfs":false}`,
+			writablePaths: []string{"/var/log"},
+		},
+		{
+			name:          "writable path not backed by a mount",
+			hostConfig:    `{"ReadonlyRootfs":true,"Tmpfs":{"/tmp":"rw"}}`,
+			writablePaths: []string{"/var/log"},
+			expectError:   true,
+		},
+		{
+			name:          "writable path backed by a read-only volume",
+			hostConfig:    `{"ReadonlyRootfs":true}`,
+			mountPoints:   []apicontainer.MountPoint{{SourceVolume: "data", ContainerPath: "/data", ReadOnly: true}},
+			writablePaths: []string{"/data"},
+			expectError:   true,
+		},
+		{
+			name:          "sibling path with a common prefix is not backed",
+			hostConfig:    `{"ReadonlyRootfs":true}`,
+			mountPoints:   []apicontainer.MountPoint{{SourceVolume: "data", ContainerPath: "/data"}},
+			writablePaths: []string{"/database"},
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := &Task{
+				Arn: "test",
+				Containers: []*apicontainer.Container{
+					{
+						Name:          "c1",
+						MountPoints:   tc.mountPoints,
+						WritablePaths: tc.writablePaths,
+						DockerConfig:  apicontainer.DockerConfig{HostConfig: strptr(tc.hostConfig)},
+					},
+				},
+			}
+			err := task.validateReadonlyRootfsWritablePaths()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestTaskFromACSPerContainerTimeouts(t *testing.T) {
 	modelTimeout := int64(10)
 	expectedTimeout := uint(modelTimeout)
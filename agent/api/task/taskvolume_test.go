@@ -394,6 +394,48 @@ func TestInitializeLocalDockerVolume(t *testing.T) {
 	assert.Len(t, testTask.Containers[0].TransitionDependenciesMap, 1, "expect a volume resource as the container dependency")
 }
 
+func TestInitializeLocalDockerVolumeSharedByContainers(t *testing.T) {
+	newContainer := func(name string) *apicontainer.Container {
+		return &apicontainer.Container{
+			Name: name,
+			MountPoints: []apicontainer.MountPoint{
+				{
+					SourceVolume:  "scratch",
+					ContainerPath: "/scratch",
+				},
+			},
+			TransitionDependenciesMap: make(map[apicontainerstatus.ContainerStatus]apicontainer.TransitionDependencySet),
+		}
+	}
+	scratchVolume := &taskresourcevolume.LocalDockerVolume{}
+	testTask := &Task{
+		Family:             "family",
+		Version:            "1",
+		ResourcesMapUnsafe: make(map[string][]taskresource.TaskResource),
+		Containers:         []*apicontainer.Container{newContainer("c1"), newContainer("c2")},
+		Volumes: []TaskVolume{
+			{
+				Name:   "scratch",
+				Type:   "docker",
+				Volume: scratchVolume,
+			},
+		},
+	}
+
+	require.NoError(t, testTask.initializeDockerLocalVolumes(nil, nil))
+
+	volumeResources := testTask.ResourcesMapUnsafe["dockerVolume"]
+	require.Len(t, volumeResources, 1, "expect a single volume resource for the shared empty volume")
+	volumeResource, ok := volumeResources[0].(*taskresourcevolume.VolumeResource)
+	require.True(t, ok)
+	assert.Equal(t, scratchVolume.HostPath, volumeResource.VolumeConfig.DockerVolumeName)
+	assert.Equal(t, taskresourcevolume.TaskScope, volumeResource.VolumeConfig.Scope)
+	assert.Equal(t, taskresourcevolume.DockerLocalVolumeDriver, volumeResource.VolumeConfig.Driver)
+	for _, container := range testTask.Containers {
+		assert.Len(t, container.TransitionDependenciesMap, 1, "expect the shared volume resource as the container dependency")
+	}
+}
+
 func TestInitializeSharedProvisionedVolume(t *testing.T) {
 	sharedVolumeMatchFullConfig := config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
 	ctrl := gomock.NewController(t)
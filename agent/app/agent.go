@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	agentacs "github.com/aws/amazon-ecs-agent/agent/acs/session"
@@ -364,6 +365,11 @@ func (agent *ecsAgent) doStart(containerChangeEventStream *eventstream.EventStre
 		return exitcodes.ExitTerminal
 	}
 
+	// Reload the awslogs endpoint for new containers on SIGHUP
+	if setter, ok := taskEngine.(sighandlers.AWSLogsEndpointSetter); ok {
+		sighandlers.StartLogEndpointReloadHandler(setter)
+	}
+
 	// Start termination handler in goroutine
 	go agent.terminationHandler(state, agent.dataClient, taskEngine, agent.cancel)
 
@@ -447,6 +453,9 @@ func (agent *ecsAgent) doStart(containerChangeEventStream *eventstream.EventStre
 	// Load Managed Daemon images asynchronously
 	agent.loadManagedDaemonImagesAsync(imageManager)
 
+	// Pre-pull configured images asynchronously
+	agent.prePullImagesAsync(imageManager)
+
 	scManager := agent.serviceconnectManager
 	scManager.SetECSClient(client, agent.containerInstanceARN)
 	if loaded, _ := scManager.IsLoaded(agent.dockerClient); loaded {
@@ -776,6 +785,33 @@ func (agent *ecsAgent) loadManagedDaemonImage(dm dm.DaemonManager, imageManager
 	imageManager.AddImageToCleanUpExclusionList(imageRef)
 }
 
+// Pulls the images configured to be pre-pulled in the background. Successfully pulled
+// images are added to imageManager's cleanup exclusion list so that they stay warm.
+func (agent *ecsAgent) prePullImagesAsync(imageManager engine.ImageManager) {
+	for _, image := range agent.cfg.PrePullImages {
+		go agent.prePullImage(image, imageManager)
+	}
+}
+
+// Pulls an image and adds it to image cleanup exclusion list upon success.
+func (agent *ecsAgent) prePullImage(image string, imageManager engine.ImageManager) {
+	logger.Info("Starting to pre-pull image", logger.Fields{
+		field.Image: image,
+	})
+	metadata := agent.dockerClient.PullImage(agent.ctx, image, nil, agent.cfg.ImagePullTimeout)
+	if metadata.Error != nil {
+		logger.Error("Failed to pre-pull image", logger.Fields{
+			field.Image: image,
+			field.Error: metadata.Error,
+		})
+		return
+	}
+	logger.Info("Successfully pre-pulled image", logger.Fields{
+		field.Image: image,
+	})
+	imageManager.AddImageToCleanUpExclusionList(image)
+}
+
 // registerContainerInstance registers the container instance ID for the ECS Agent
 func (agent *ecsAgent) registerContainerInstance(
 	client ecs.ECSClient,
@@ -790,6 +826,13 @@ func (agent *ecsAgent) registerContainerInstance(
 		return err
 	}
 	capabilities := append(agentCapabilities, additionalAttributes...)
+	var attributeNames []string
+	for _, attr := range AttributesOnly(agentCapabilities) {
+		attributeNames = append(attributeNames, aws.StringValue(attr.Name))
+	}
+	logger.Debug("Registering agent capability attributes", logger.Fields{
+		"attributes": strings.Join(attributeNames, ","),
+	})
 
 	// Get the tags of this container instance defined in config file
 	tags := utils.MapToTags(agent.cfg.ContainerInstanceTags)
@@ -913,7 +956,7 @@ func (agent *ecsAgent) startAsyncRoutines(
 	}
 
 	// Agent introspection api
-	go handlers.ServeIntrospectionHTTPEndpoint(agent.ctx, &agent.containerInstanceARN, taskEngine, agent.cfg)
+	go handlers.ServeIntrospectionHTTPEndpoint(agent.ctx, &agent.containerInstanceARN, taskEngine, agent.dockerClient, agent.cfg)
 
 	telemetryMessages := make(chan ecstcs.TelemetryMessage, telemetryChannelDefaultBufferSize)
 	healthMessages := make(chan ecstcs.HealthMessage, telemetryChannelDefaultBufferSize)
@@ -923,9 +966,9 @@ func (agent *ecsAgent) startAsyncRoutines(
 	// Start serving the endpoint to fetch IAM Role credentials and other task metadata
 	if agent.cfg.TaskMetadataAZDisabled {
 		// send empty availability zone
-		go handlers.ServeTaskHTTPEndpoint(agent.ctx, credentialsManager, state, client, agent.containerInstanceARN, agent.cfg, statsEngine, "", agent.vpc)
+		go handlers.ServeTaskHTTPEndpoint(agent.ctx, credentialsManager, state, client, agent.containerInstanceARN, agent.cfg, statsEngine, agent.dockerClient, "", agent.vpc)
 	} else {
-		go handlers.ServeTaskHTTPEndpoint(agent.ctx, credentialsManager, state, client, agent.containerInstanceARN, agent.cfg, statsEngine, agent.availabilityZone, agent.vpc)
+		go handlers.ServeTaskHTTPEndpoint(agent.ctx, credentialsManager, state, client, agent.containerInstanceARN, agent.cfg, statsEngine, agent.dockerClient, agent.availabilityZone, agent.vpc)
 	}
 
 	// Start sending events to the backend
@@ -14,22 +14,34 @@
 package app
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
 	dm "github.com/aws/amazon-ecs-agent/agent/engine/daemonmanager"
+	"github.com/aws/amazon-ecs-agent/agent/handlers"
+	"github.com/aws/amazon-ecs-agent/agent/utils"
+	"github.com/aws/amazon-ecs-agent/agent/version"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs/model/ecs"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
 	md "github.com/aws/amazon-ecs-agent/ecs-agent/manageddaemon"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/cihub/seelog"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -47,14 +59,33 @@ const (
 	capabilityIncreasedTaskCPULimit                        = "increased-task-cpu-limit"
 	capabilityDockerPluginInfix                            = "docker-plugin."
 	attributeSeparator                                     = "."
+	capabilityVolumeDriverScopeInfix                       = ".scope."
+	volumeDriverScopeLocal                                 = "local"
+	volumeDriverScopeGlobal                                = "global"
 	capabilityPrivateRegistryAuthASM                       = "private-registry-authentication.secretsmanager"
 	capabilitySecretEnvSSM                                 = "secrets.ssm.environment-variables"
 	capabilitySecretEnvASM                                 = "secrets.asm.environment-variables"
 	capabilitySecretLogDriverSSM                           = "secrets.ssm.bootstrap.log-driver"
 	capabilitySecretLogDriverASM                           = "secrets.asm.bootstrap.log-driver"
+	capabilitySecretEnvFileASM                             = "secrets.asm.mounted-files"
+	capabilityLogEndpointReload                            = "log-endpoint-reload"
+	capabilityContainerInitCustom                          = "container-init.custom"
+	capabilityParallelTaskStop                             = "parallel-task-stop"
+	capabilityCustomStopSignal                             = "custom-stop-signal"
+	capabilityENICountMax                                  = "eni-count-max"
+	capabilityLogRateLimit                                 = "log-rate-limit"
+	capabilitySharedScratch                                = "shared-scratch"
+	capabilityWindowsNamedPipeVolume                       = "windows-named-pipe-volume"
+	capabilityImagePrewarm                                 = "image-prewarm"
+	capabilityContainerStopTimeout                         = "container-stop-timeout"
+	capabilityStopSignalEscalation                         = "stop-signal-escalation"
+	capabilityImagePullBehaviorPrefix                      = "image-pull-behavior."
+	capabilityNetworkOverlay                               = "network.overlay"
+	capabilityDNSOrder                                     = "dns-order"
 	capabiltyPIDAndIPCNamespaceSharing                     = "pid-ipc-namespace-sharing"
 	capabilityNvidiaDriverVersionInfix                     = "nvidia-driver-version."
 	capabilityECREndpoint                                  = "ecr-endpoint"
+	capabilityRegistryAuthECRPublic                        = "registry-auth.ecr-public"
 	capabilityContainerOrdering                            = "container-ordering"
 	taskEIAAttributeSuffix                                 = "task-eia"
 	taskEIAWithOptimizedCPU                                = "task-eia.optimized-cpu"
@@ -66,12 +97,21 @@ const (
 	capabilityFireLensLoggingDriverConfigBufferLimitSuffix = ".log-driver-buffer-limit"
 	capabilityFirelensConfigFile                           = "firelens.options.config.file"
 	capabilityFirelensConfigS3                             = "firelens.options.config.s3"
+	capabilityFirelensOTLP                                 = "firelens.otlp"
+	capabilityFirelensMultiOutput                          = "firelens.multi-output"
+	capabilityLogDeadLetter                                = "log-dead-letter"
+	capabilityAWSLogsNonBlocking                           = "logging-driver.awslogs.non-blocking"
+	capabilityJournaldTag                                  = "logging-driver.journald.tag"
+	capabilityTaskHealthGating                             = "task-health-gating"
 	capabilityFullTaskSync                                 = "full-sync"
 	capabilityGMSA                                         = "gmsa"
 	capabilityGMSADomainless                               = "gmsa-domainless"
+	capabilityGMSACredentialSpecS3                         = "gmsa.credentialspec.s3"
+	capabilityGMSACredentialSpecSSM                        = "gmsa.credentialspec.ssm"
 	capabilityEFS                                          = "efs"
 	capabilityEFSAuth                                      = "efsAuth"
 	capabilityEnvFilesS3                                   = "env-files.s3"
+	capabilityEnvFilesSSM                                  = "env-files.ssm"
 	capabilityFSxWindowsFileServer                         = "fsxWindowsFileServer"
 	capabilityExec                                         = "execute-command"
 	capabilityExecBinRelativePath                          = "bin"
@@ -80,8 +120,55 @@ const (
 	capabilityExternal                                     = "external"
 	capabilityServiceConnect                               = "service-connect-v1"
 	capabilityGpuDriverVersion                             = "gpu-driver-version"
+	capabilityGPU                                          = "gpu"
+	capabilityGPUCount                                     = "gpu.count"
 	capabilityEBSTaskAttach                                = "storage.ebs-task-volume-attach"
 	capabilityContainerRestartPolicy                       = "container-restart-policy"
+	capabilityContainerRestartPolicyJitter                 = "container-restart-policy.jitter"
+	capabilityRegistryMutualTLS                            = "registry-mutual-tls"
+	capabilityPortMappingName                              = "port-mapping.name"
+	capabilityReadonlyRootfsValidation                     = "readonly-rootfs.validation"
+	capabilityEnvPrecedence                                = "env-precedence"
+	capabilityNetworkBandwidthLimit                        = "network-bandwidth-limit"
+	capabilityAgentVersion                                 = "agent-version"
+	capabilityTaskDefinitionVersion                        = "task-definition-version"
+	capabilityIntrospectionVersion                         = "introspection-version"
+	capabilitySELinuxRelabel                               = "selinux-relabel"
+	capabilityAppArmorNamedProfile                         = "apparmor.named-profile"
+	capabilityTmpfs                                        = "tmpfs"
+	capabilityTaskLevelUlimits                             = "task-level-ulimits"
+	capabilityContainerResize                              = "container-resize"
+	capabilityTaskDNS                                      = "task-dns"
+	capabilityFaultInjectionStatus                         = "fault-injection.status"
+	capabilityImageCacheMetrics                            = "image-cache-metrics"
+	capabilitySecretsSSMPath                               = "secrets.ssm.path"
+	capabilityDaemonReconnect                              = "daemon-reconnect"
+	capabilityEnvAppConfig                                 = "env.appconfig"
+	capabilityLogConfigValidation                          = "log-config-validation"
+	capabilityVolumesFromReadOnly                          = "volumes-from.readonly"
+	capabilityContainerSwap                                = "container-swap"
+	capabilityHealthCheckCache                             = "health-check.cache"
+	capabilitySetHash                                      = "set-hash"
+	capabilityLogTeeStdout                                 = "log-tee-stdout"
+	capabilityMetadataAuth                                 = "metadata.auth"
+	capabilityDockerClientAPIVersionNegotiated             = "docker-client-api-version.negotiated"
+	capabilityImagePullZstd                                = "image-pull.zstd"
+
+	// taskDefinitionSchemaVersion is the version of the task definition schema understood by the
+	// agent. Bump it whenever the agent starts honoring new task definition fields.
+	taskDefinitionSchemaVersion = "1.0.0"
+
+	// imagePullZstdDockerVersion selects the docker engine versions that can decompress image layers
+	// compressed with zstd
+	imagePullZstdDockerVersion = ">=23.0.0"
+
+	// cniPluginVersionAttempts bounds the number of times a CNI plugin is invoked to get its version, as
+	// the invocation can fail transiently while the host is still starting up.
+	cniPluginVersionAttempts        = 3
+	cniPluginVersionBackoffMin      = 100 * time.Millisecond
+	cniPluginVersionBackoffMax      = time.Second
+	cniPluginVersionBackoffJitter   = 0.2
+	cniPluginVersionBackoffMultiple = 2
 
 	// network capabilities, going forward, please append "network." prefix to any new networking capability we introduce
 	networkCapabilityPrefix      = "network."
@@ -109,17 +196,54 @@ var (
 		capabilityFullTaskSync,
 		// ecs agent version 1.39.0 supports bulk loading env vars through environmentFiles in S3
 		capabilityEnvFilesS3,
+		// support bulk loading env vars through environmentFiles in SSM parameter store, which is
+		// fetched with the same client as the SSM secrets
+		capabilityEnvFilesSSM,
 		// support container port range in container definition - port mapping field
 		capabilityContainerPortRange,
 		// support container restart policy
 		capabilityContainerRestartPolicy,
+		capabilityContainerRestartPolicyJitter,
+		// support named port mappings in container definition, used by service connect
+		capabilityPortMappingName,
+		// validate that writable paths of read-only root filesystem containers are backed by mounts
+		capabilityReadonlyRootfsValidation,
+		// environment variables are deduplicated with precedence secrets > task definition > environment files
+		capabilityEnvPrecedence,
+		// tasks are stopped in parallel, bounded by ECS_PARALLEL_TASK_STOP_LIMIT
+		capabilityParallelTaskStop,
+		// containers are stopped with their configured stop signal, and killed once their stop timeout has passed
+		capabilityCustomStopSignal,
+		// dns servers are applied in the order they are configured, so later ones are only used for failover
+		capabilityDNSOrder,
+		// layer cache hits and misses are recorded for every image pull
+		capabilityImageCacheMetrics,
+		// ssm:path: secrets expand every parameter under an ssm parameter hierarchy
+		capabilitySecretsSSMPath,
+		// the docker events stream is reopened once the docker daemon is reachable again after a restart
+		capabilityDaemonReconnect,
+		// awslogs and awsfirelens log options are validated before any container of the task is started
+		capabilityLogConfigValidation,
+		// volumes shared read-only through volumesFrom stay read-only even with a docker host config override
+		capabilityVolumesFromReadOnly,
+		// the health result inspected for a health status event is reused for repeated events with the same status
+		capabilityHealthCheckCache,
 	}
 	// use empty struct as value type to simulate set
 	capabilityExecInvalidSsmVersions = map[string]struct{}{}
 
-	pathExists              = defaultPathExists
-	getSubDirectories       = defaultGetSubDirectories
-	isPlatformExecSupported = defaultIsPlatformExecSupported
+	// imagePullBehaviorModes maps the image pull behaviors to the values of ECS_IMAGE_PULL_BEHAVIOR
+	imagePullBehaviorModes = map[config.ImagePullBehaviorType]string{
+		config.ImagePullDefaultBehavior:      "default",
+		config.ImagePullAlwaysBehavior:       "always",
+		config.ImagePullOnceBehavior:         "once",
+		config.ImagePullPreferCachedBehavior: "prefer-cached",
+	}
+
+	pathExists                    = defaultPathExists
+	getSubDirectories             = defaultGetSubDirectories
+	isPlatformExecSupported       = defaultIsPlatformExecSupported
+	findRegistriesWithClientCerts = dockerclient.RegistriesWithClientCerts
 
 	// List of capabilities that are not supported on external capacity.
 	externalUnsupportedCapabilities = []string{
@@ -145,8 +269,8 @@ var (
 	configDir             = filepath.Join(capabilityExecRootDir, capabilityExecConfigRelativePath)
 )
 
-// capabilities returns the supported capabilities of this agent / docker-client pair.
-// Currently, the following capabilities are possible:
+// capabilities returns the supported capabilities of this agent / docker-client pair,
+// sorted by name. Currently, the following capabilities are possible:
 //
 //	com.amazonaws.ecs.capability.privileged-container
 //	com.amazonaws.ecs.capability.docker-remote-api.1.17
@@ -159,24 +283,36 @@ var (
 //	com.amazonaws.ecs.capability.logging-driver.journald
 //	com.amazonaws.ecs.capability.logging-driver.gelf
 //	com.amazonaws.ecs.capability.logging-driver.none
+//	ecs.capability.logging-driver.awslogs.non-blocking
+//	ecs.capability.logging-driver.journald.tag
 //	com.amazonaws.ecs.capability.selinux
 //	com.amazonaws.ecs.capability.apparmor
 //	com.amazonaws.ecs.capability.ecr-auth
 //	com.amazonaws.ecs.capability.task-iam-role
 //	com.amazonaws.ecs.capability.task-iam-role-network-host
 //	ecs.capability.docker-volume-driver.${driverName}
+//	ecs.capability.docker-volume-driver.${driverName}.scope.${scope}
 //	ecs.capability.task-eni
 //	ecs.capability.task-eni-block-instance-metadata
 //	ecs.capability.execution-role-ecr-pull
+//	ecs.capability.registry-auth.ecr-public
 //	ecs.capability.execution-role-awslogs
 //	ecs.capability.container-health-check
+//	ecs.capability.task-health-gating
 //	ecs.capability.private-registry-authentication.secretsmanager
 //	ecs.capability.secrets.ssm.environment-variables
+//	ecs.capability.secrets.ssm.path
+//	ecs.capability.daemon-reconnect
+//	ecs.capability.env.appconfig
+//	ecs.capability.log-config-validation
+//	ecs.capability.volumes-from.readonly
+//	ecs.capability.health-check.cache
 //	ecs.capability.secrets.ssm.bootstrap.log-driver
 //	ecs.capability.pid-ipc-namespace-sharing
 //	ecs.capability.ecr-endpoint
 //	ecs.capability.secrets.asm.environment-variables
 //	ecs.capability.secrets.asm.bootstrap.log-driver
+//	ecs.capability.secrets.asm.mounted-files
 //	ecs.capability.aws-appmesh
 //	ecs.capability.task-eia
 //	ecs.capability.task-eni-trunking
@@ -188,16 +324,61 @@ var (
 //	ecs.capability.logging-driver.awsfirelens.log-driver-buffer-limit
 //	ecs.capability.firelens.options.config.file
 //	ecs.capability.firelens.options.config.s3
+//	ecs.capability.firelens.otlp
+//	ecs.capability.firelens.multi-output
+//	ecs.capability.log-dead-letter
 //	ecs.capability.full-sync
 //	ecs.capability.gmsa
+//	ecs.capability.gmsa.credentialspec.s3
+//	ecs.capability.gmsa.credentialspec.ssm
 //	ecs.capability.efsAuth
 //	ecs.capability.env-files.s3
+//	ecs.capability.env-files.ssm
 //	ecs.capability.fsxWindowsFileServer
 //	ecs.capability.execute-command
 //	ecs.capability.external
 //	ecs.capability.service-connect-v1
 //	ecs.capability.network.container-port-range
 //	ecs.capability.container-restart-policy
+//	ecs.capability.container-restart-policy.jitter
+//	ecs.capability.port-mapping.name
+//	ecs.capability.readonly-rootfs.validation
+//	ecs.capability.env-precedence
+//	ecs.capability.parallel-task-stop
+//	ecs.capability.custom-stop-signal
+//	ecs.capability.agent-version
+//	ecs.capability.task-definition-version
+//	ecs.capability.introspection-version
+//	ecs.capability.selinux-relabel
+//	ecs.capability.apparmor.named-profile
+//	ecs.capability.tmpfs
+//	ecs.capability.task-level-ulimits
+//	ecs.capability.container-resize
+//	ecs.capability.task-dns
+//	ecs.capability.container-swap
+//	ecs.capability.log-endpoint-reload
+//	ecs.capability.container-init.custom
+//	ecs.capability.registry-mutual-tls
+//	ecs.capability.network-bandwidth-limit
+//	ecs.capability.log-rate-limit
+//	ecs.capability.eni-count-max
+//	ecs.capability.shared-scratch
+//	ecs.capability.windows-named-pipe-volume
+//	ecs.capability.image-prewarm
+//	ecs.capability.container-stop-timeout
+//	ecs.capability.stop-signal-escalation
+//	ecs.capability.image-pull-behavior.<mode>
+//	ecs.capability.network.overlay
+//	ecs.capability.image-pull.zstd
+//	ecs.capability.gpu
+//	ecs.capability.gpu.count
+//	ecs.capability.dns-order
+//	ecs.capability.fault-injection.status
+//	ecs.capability.image-cache-metrics
+//	ecs.capability.set-hash
+//	ecs.capability.log-tee-stdout
+//	ecs.capability.metadata.auth
+//	ecs.capability.docker-client-api-version.negotiated
 func (agent *ecsAgent) capabilities() ([]*ecs.Attribute, error) {
 	var capabilities []*ecs.Attribute
 
@@ -209,34 +390,74 @@ func (agent *ecsAgent) capabilities() ([]*ecs.Attribute, error) {
 		capabilities = appendNameOnlyAttribute(capabilities, capabilityPrefix+"privileged-container")
 	}
 
+	// querying docker, executing plugins and inspecting the file system can be slow, so the supported
+	// docker API versions are looked up concurrently with the capabilities that don't depend on them
+	var dockerVersions []dockerclient.DockerVersion
+	probes := append(agent.capabilityProbes(), func() ([]*ecs.Attribute, error) {
+		dockerVersions = dockerclient.SupportedVersionsExtended(agent.dockerClient.SupportedVersions)
+		return nil, nil
+	})
+	probedCapabilities, err := runCapabilityProbes(probes)
+	if err != nil {
+		return nil, err
+	}
+
 	supportedVersions := make(map[dockerclient.DockerVersion]bool)
 	// Determine API versions to report as supported via com.amazonaws.ecs.capability.docker-remote-api.X.XX capabilities
 	// and for determining which features we support that depend on specific docker API versions
-	for _, version := range dockerclient.SupportedVersionsExtended(agent.dockerClient.SupportedVersions) {
+	for _, version := range dockerVersions {
 		capabilities = appendNameOnlyAttribute(capabilities, capabilityPrefix+"docker-remote-api."+string(version))
 		supportedVersions[version] = true
 	}
 
 	capabilities = agent.appendLoggingDriverCapabilities(capabilities, supportedVersions)
+	capabilities = agent.appendAWSLogsNonBlockingCapability(capabilities, supportedVersions)
+	capabilities = agent.appendJournaldTagCapability(capabilities, supportedVersions)
 
 	if agent.cfg.SELinuxCapable.Enabled() {
 		capabilities = appendNameOnlyAttribute(capabilities, capabilityPrefix+"selinux")
+		// bind mounts are relabeled with the z/Z options when requested by the mount point
+		capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilitySELinuxRelabel)
 	}
 	if agent.cfg.AppArmorCapable.Enabled() {
 		capabilities = appendNameOnlyAttribute(capabilities, capabilityPrefix+"apparmor")
 	}
+	if agent.cfg.AppConfigCapable.Enabled() {
+		capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilityEnvAppConfig)
+	}
 
 	capabilities = agent.appendTaskIamRoleCapabilities(capabilities, supportedVersions)
 
-	capabilities, err := agent.appendTaskCPUMemLimitCapabilities(capabilities, supportedVersions)
+	capabilities, err = agent.appendTaskCPUMemLimitCapabilities(capabilities, supportedVersions)
 	if err != nil {
 		return nil, err
 	}
 
 	capabilities = agent.appendIncreasedTaskCPULimitCapability(capabilities)
-	capabilities = agent.appendTaskENICapabilities(capabilities)
-	capabilities = agent.appendENITrunkingCapabilities(capabilities)
 	capabilities = agent.appendDockerDependentCapabilities(capabilities, supportedVersions)
+	capabilities = agent.appendSecretEnvFileASMCapability(capabilities, supportedVersions)
+	capabilities = agent.appendTmpfsCapability(capabilities, supportedVersions)
+	capabilities = agent.appendTaskLevelUlimitsCapability(capabilities, supportedVersions)
+	capabilities = agent.appendContainerResizeCapability(capabilities, supportedVersions)
+	capabilities = agent.appendTaskDNSCapability(capabilities, supportedVersions)
+	capabilities = agent.appendContainerSwapCapability(capabilities, supportedVersions)
+	capabilities = agent.appendWindowsNamedPipeVolumeCapability(capabilities, supportedVersions)
+	capabilities = agent.appendImagePrewarmCapability(capabilities)
+	capabilities = agent.appendContainerStopTimeoutCapability(capabilities)
+	capabilities = agent.appendStopSignalEscalationCapability(capabilities)
+	capabilities = agent.appendImagePullBehaviorCapability(capabilities)
+	capabilities = agent.appendLogEndpointReloadCapability(capabilities)
+	capabilities = agent.appendContainerInitCustomCapability(capabilities)
+	capabilities = agent.appendLogRateLimitCapability(capabilities)
+	capabilities = agent.appendTaskHealthGatingCapability(capabilities)
+	capabilities = agent.appendLogTeeStdoutCapability(capabilities)
+	capabilities = agent.appendMetadataAuthCapability(capabilities)
+	capabilities = agent.appendNetworkBandwidthLimitCapability(capabilities)
+	capabilities = agent.appendENICountMaxCapability(capabilities)
+	capabilities = appendAgentVersionCapability(capabilities)
+	capabilities = appendTaskDefinitionVersionCapability(capabilities)
+	capabilities = appendIntrospectionVersionCapability(capabilities)
+	capabilities = appendFaultInjectionStatusCapability(capabilities)
 
 	// TODO: gate this on docker api version when ecs supported docker includes
 	// credentials endpoint feature from upstream docker
@@ -244,16 +465,11 @@ func (agent *ecsAgent) capabilities() ([]*ecs.Attribute, error) {
 		capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+"execution-role-awslogs")
 	}
 
-	capabilities = agent.appendVolumeDriverCapabilities(capabilities)
-
 	if agent.cfg.GPUSupportEnabled {
 		capabilities = agent.appendNvidiaDriverVersionAttribute(capabilities)
+		capabilities = agent.appendGPUCapabilities(capabilities)
 	}
 
-	// ecs agent version 1.22.0 supports sharing PID namespaces and IPC resource namespaces
-	// with host EC2 instance and among containers within the task
-	capabilities = agent.appendPIDAndIPCNamespaceSharingCapabilities(capabilities)
-
 	// ecs agent version 1.26.0 supports aws-appmesh cni plugin
 	capabilities = agent.appendAppMeshCapabilities(capabilities)
 
@@ -278,12 +494,24 @@ func (agent *ecsAgent) capabilities() ([]*ecs.Attribute, error) {
 	// support external firelens config
 	capabilities = agent.appendFirelensConfigCapabilities(capabilities)
 
+	// support routing firelens logs to an OpenTelemetry collector
+	capabilities = agent.appendFirelensOTLPCapabilities(capabilities)
+
+	// support routing a container's firelens logs to multiple outputs
+	capabilities = agent.appendFirelensMultiOutputCapabilities(capabilities)
+
+	// support keeping firelens logs that can't be delivered after retries in a dead letter path
+	capabilities = agent.appendLogDeadLetterCapabilities(capabilities)
+
 	// support GMSA capabilities
 	capabilities = agent.appendGMSACapabilities(capabilities)
 
 	// support GMSA domainless capabilities
 	capabilities = agent.appendGMSADomainlessCapabilities(capabilities)
 
+	// support fetching GMSA credential specs from S3 and SSM
+	capabilities = agent.appendGMSACredentialSpecSourceCapabilities(capabilities)
+
 	// support efs auth on ecs capabilities
 	for _, cap := range agent.cfg.VolumePluginCapabilities {
 		capabilities = agent.appendEFSVolumePluginCapabilities(capabilities, cap)
@@ -291,18 +519,13 @@ func (agent *ecsAgent) capabilities() ([]*ecs.Attribute, error) {
 
 	// support fsxWindowsFileServer on ecs capabilities
 	capabilities = agent.appendFSxWindowsFileServerCapabilities(capabilities)
+
 	// add ecs-exec capabilities if applicable
-	capabilities, err = agent.appendExecCapabilities(capabilities)
+	capabilities, err = agent.appendExecCapabilities(capabilities, supportedVersions)
 	if err != nil {
 		return nil, err
 	}
-	// add service-connect capabilities if applicable
-	capabilities = agent.appendServiceConnectCapabilities(capabilities)
-
-	if agent.cfg.EBSTASupportEnabled {
-		// add ebs-task-attach attribute if applicable
-		capabilities = agent.appendEBSTaskAttachCapabilities(capabilities)
-	}
+	capabilities = append(capabilities, probedCapabilities...)
 
 	if agent.cfg.External.Enabled() {
 		// Add external specific capability; remove external unsupported capabilities.
@@ -312,14 +535,84 @@ func (agent *ecsAgent) capabilities() ([]*ecs.Attribute, error) {
 		capabilities = removeAttributesByNames(capabilities, externalUnsupportedCapabilities)
 	}
 
+	capabilities = dedupeAttributesByName(capabilities)
+	capabilities = appendSetHashAttribute(capabilities)
+	sortAttributesByName(capabilities)
 	return capabilities, nil
 }
 
+// capabilityProbe computes attributes that depend on potentially slow calls, such
+// as querying docker or executing CNI plugins. Probes must be independent of
+// each other as they are run concurrently.
+type capabilityProbe func() ([]*ecs.Attribute, error)
+
+// capabilityProbes returns the probes for the capabilities that can be computed
+// concurrently, in the order their attributes are merged.
+func (agent *ecsAgent) capabilityProbes() []capabilityProbe {
+	probes := []capabilityProbe{
+		nonFailingCapabilityProbe(agent.appendTaskENICapabilities),
+		nonFailingCapabilityProbe(agent.appendENITrunkingCapabilities),
+		nonFailingCapabilityProbe(agent.appendRegistryMutualTLSCapabilities),
+		nonFailingCapabilityProbe(agent.appendVolumeDriverCapabilities),
+		// ecs agent version 1.22.0 supports sharing PID namespaces and IPC resource namespaces
+		// with host EC2 instance and among containers within the task
+		nonFailingCapabilityProbe(agent.appendPIDAndIPCNamespaceSharingCapabilities),
+		// add service-connect capabilities if applicable
+		nonFailingCapabilityProbe(agent.appendServiceConnectCapabilities),
+		// add overlay network capability if the docker daemon is part of a swarm
+		nonFailingCapabilityProbe(agent.appendNetworkOverlayCapability),
+		// add zstd image layer capability if the docker daemon can decompress them
+		nonFailingCapabilityProbe(agent.appendImagePullZstdCapability),
+		// add named apparmor profile capability if loaded profiles can be verified
+		nonFailingCapabilityProbe(agent.appendAppArmorNamedProfileCapability),
+		// add the docker api version negotiated by the docker client
+		nonFailingCapabilityProbe(agent.appendDockerClientAPIVersionNegotiatedCapability),
+	}
+	if agent.cfg.EBSTASupportEnabled {
+		// add ebs-task-attach attribute if applicable
+		probes = append(probes, nonFailingCapabilityProbe(agent.appendEBSTaskAttachCapabilities))
+	}
+	return probes
+}
+
+func nonFailingCapabilityProbe(appendCapabilities func([]*ecs.Attribute) []*ecs.Attribute) capabilityProbe {
+	return func() ([]*ecs.Attribute, error) {
+		return appendCapabilities(nil), nil
+	}
+}
+
+// runCapabilityProbes runs all the probes concurrently and merges their attributes
+// in probe order once all of them have completed. If any probe fails, the error of
+// the first probe to fail is returned.
+func runCapabilityProbes(probes []capabilityProbe) ([]*ecs.Attribute, error) {
+	// each probe only writes its own result, which is read once all of them have completed
+	results := make([][]*ecs.Attribute, len(probes))
+	var group errgroup.Group
+	for i, probe := range probes {
+		i, probe := i, probe
+		group.Go(func() error {
+			attributes, err := probe()
+			results[i] = attributes
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	var attributes []*ecs.Attribute
+	for _, result := range results {
+		attributes = append(attributes, result...)
+	}
+	return attributes, nil
+}
+
 func (agent *ecsAgent) appendDockerDependentCapabilities(capabilities []*ecs.Attribute,
 	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
 	if _, ok := supportedVersions[dockerclient.Version_1_19]; ok {
 		capabilities = appendNameOnlyAttribute(capabilities, capabilityPrefix+"ecr-auth")
 		capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+"execution-role-ecr-pull")
+		capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilityRegistryAuthECRPublic)
 	}
 	if _, ok := supportedVersions[dockerclient.Version_1_24]; ok && !agent.cfg.DisableDockerHealthCheck.Enabled() {
 		// Docker health check was added in API 1.24
@@ -328,6 +621,148 @@ func (agent *ecsAgent) appendDockerDependentCapabilities(capabilities []*ecs.Att
 	return capabilities
 }
 
+// appendAgentVersionCapability reports the version and commit of the running agent build, so that
+// the agent version of container instances can be audited with DescribeContainerInstances.
+func appendAgentVersionCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return append(capabilities, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilityAgentVersion),
+		Value: aws.String(version.Version + "-" + version.GitShortHash),
+	})
+}
+
+// appendTaskDefinitionVersionCapability reports the task definition schema version supported by the
+// agent, so that the backend can tell which task definition features the agent understands.
+func appendTaskDefinitionVersionCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return append(capabilities, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilityTaskDefinitionVersion),
+		Value: aws.String(taskDefinitionSchemaVersion),
+	})
+}
+
+// appendIntrospectionVersionCapability reports the version of the introspection API served by the agent,
+// so that tooling can tell which introspection handlers are available on the instance.
+func appendIntrospectionVersionCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return append(capabilities, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilityIntrospectionVersion),
+		Value: aws.String(handlers.IntrospectionAPIVersion),
+	})
+}
+
+// appendFaultInjectionStatusCapability advertises that the active faults of a task can be listed through the
+// task metadata fault injection status endpoint, when the endpoint serves that route.
+func appendFaultInjectionStatusCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !handlers.FaultInjectionStatusEnabled {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityFaultInjectionStatus)
+}
+
+// appendTaskHealthGatingCapability advertises that tasks only transition to RUNNING once all of their
+// essential containers with a health check are healthy.
+func (agent *ecsAgent) appendTaskHealthGatingCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !agent.cfg.TaskHealthGatingEnabled.Enabled() {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityTaskHealthGating)
+}
+
+// appendLogTeeStdoutCapability advertises that the logs of containers are also written to the agent's
+// stdout, which is only the case when it's enabled with ECS_LOG_TEE_STDOUT.
+func (agent *ecsAgent) appendLogTeeStdoutCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !agent.cfg.LogTeeStdout.Enabled() {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityLogTeeStdout)
+}
+
+// appendMetadataAuthCapability advertises that v3 and v4 task metadata requests require the token issued to
+// the task, which is only the case when it's enabled with ECS_TMDS_AUTH_ENABLED.
+func (agent *ecsAgent) appendMetadataAuthCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !agent.cfg.TMDSAuthEnabled.Enabled() {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityMetadataAuth)
+}
+
+// appendNetworkBandwidthLimitCapability advertises that per-container network bandwidth limits can be
+// applied, which is only the case when the tc binary was found on the host.
+func (agent *ecsAgent) appendNetworkBandwidthLimitCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !agent.cfg.BandwidthLimitCapable.Enabled() {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityNetworkBandwidthLimit)
+}
+
+// appendENICountMaxCapability advertises the maximum number of task ENIs that can be attached to the
+// instance. The agent can't detect this limit from the instance type, so the attribute is only advertised
+// when it's configured with ECS_MAX_ENIS.
+func (agent *ecsAgent) appendENICountMaxCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if agent.cfg.MaxENIs <= 0 {
+		return capabilities
+	}
+	return append(capabilities, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilityENICountMax),
+		Value: aws.String(strconv.Itoa(agent.cfg.MaxENIs)),
+	})
+}
+
+// appendImagePrewarmCapability advertises that the agent pulls the images configured with
+// ECS_PRE_PULL_IMAGES on startup.
+func (agent *ecsAgent) appendImagePrewarmCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if len(agent.cfg.PrePullImages) == 0 {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityImagePrewarm)
+}
+
+// appendContainerStopTimeoutCapability advertises the container stop timeout configured with
+// ECS_CONTAINER_STOP_TIMEOUT. The attribute isn't advertised when the default timeout is used.
+func (agent *ecsAgent) appendContainerStopTimeoutCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if agent.cfg.DockerStopTimeout == config.DefaultDockerStopTimeout {
+		return capabilities
+	}
+	return append(capabilities, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilityContainerStopTimeout),
+		Value: aws.String(agent.cfg.DockerStopTimeout.String()),
+	})
+}
+
+// appendStopSignalEscalationCapability advertises that containers are sent the sequence of signals configured
+// with ECS_CONTAINER_STOP_SIGNAL_ESCALATION before they're killed.
+func (agent *ecsAgent) appendStopSignalEscalationCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if len(agent.cfg.StopSignalEscalation) == 0 {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityStopSignalEscalation)
+}
+
+// appendImagePullBehaviorCapability advertises the image pull behavior configured with ECS_IMAGE_PULL_BEHAVIOR,
+// so that tasks relying on cached images can be placed on instances that don't always pull.
+func (agent *ecsAgent) appendImagePullBehaviorCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	mode, ok := imagePullBehaviorModes[agent.cfg.ImagePullBehavior]
+	if !ok {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityImagePullBehaviorPrefix+mode)
+}
+
+// appendRegistryMutualTLSCapabilities advertises support for pulling from registries requiring
+// mutual TLS when at least one registry has a client certificate configured.
+func (agent *ecsAgent) appendRegistryMutualTLSCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if agent.cfg.RegistryClientCertsDir == "" {
+		return capabilities
+	}
+	registries, err := findRegistriesWithClientCerts(agent.cfg.RegistryClientCertsDir)
+	if err != nil {
+		seelog.Warnf("Unable to look up registry client certificates in %s: %v", agent.cfg.RegistryClientCertsDir, err)
+		return capabilities
+	}
+	if len(registries) == 0 {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityRegistryMutualTLS)
+}
+
 func (agent *ecsAgent) appendGMSACapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	if agent.cfg.GMSACapable.Enabled() {
 		return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityGMSA)
@@ -344,6 +779,17 @@ func (agent *ecsAgent) appendGMSADomainlessCapabilities(capabilities []*ecs.Attr
 	return capabilities
 }
 
+// appendGMSACredentialSpecSourceCapabilities advertises that gMSA credential specs can be fetched from
+// S3 and SSM when either flavor of gMSA is enabled. The credential spec resource fetches them with the
+// same S3 and SSM clients as environment files and secrets, which have no switch of their own.
+func (agent *ecsAgent) appendGMSACredentialSpecSourceCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !agent.cfg.GMSACapable.Enabled() && !agent.cfg.GMSADomainlessCapable.Enabled() {
+		return capabilities
+	}
+	capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilityGMSACredentialSpecS3)
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityGMSACredentialSpecSSM)
+}
+
 func (agent *ecsAgent) appendLoggingDriverCapabilities(capabilities []*ecs.Attribute, supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
 	for _, loggingDriver := range agent.cfg.AvailableLoggingDrivers {
 		requiredVersion := dockerclient.LoggingDriverMinimumVersion[loggingDriver]
@@ -354,6 +800,34 @@ func (agent *ecsAgent) appendLoggingDriverCapabilities(capabilities []*ecs.Attri
 	return capabilities
 }
 
+// appendAWSLogsNonBlockingCapability advertises support for the non-blocking delivery mode of the
+// awslogs driver, which requires docker to support the `mode` and `max-buffer-size` log options.
+func (agent *ecsAgent) appendAWSLogsNonBlockingCapability(capabilities []*ecs.Attribute, supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	if _, ok := supportedVersions[dockerclient.NonBlockingLogModeMinimumVersion]; !ok {
+		return capabilities
+	}
+	for _, loggingDriver := range agent.cfg.AvailableLoggingDrivers {
+		if loggingDriver == dockerclient.AWSLogsDriver {
+			return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityAWSLogsNonBlocking)
+		}
+	}
+	return capabilities
+}
+
+// appendJournaldTagCapability advertises support for templating the journald syslog identifier with
+// the `tag` log option, which requires docker to support the option for the journald driver.
+func (agent *ecsAgent) appendJournaldTagCapability(capabilities []*ecs.Attribute, supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	if _, ok := supportedVersions[dockerclient.JournaldTagMinimumVersion]; !ok {
+		return capabilities
+	}
+	for _, loggingDriver := range agent.cfg.AvailableLoggingDrivers {
+		if loggingDriver == dockerclient.JournaldDriver {
+			return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityJournaldTag)
+		}
+	}
+	return capabilities
+}
+
 func (agent *ecsAgent) appendTaskIamRoleCapabilities(capabilities []*ecs.Attribute, supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
 	if agent.cfg.TaskIAMRoleEnabled.Enabled() {
 		// The "task-iam-role" capability is supported for docker v1.7.x onwards
@@ -432,7 +906,35 @@ func (agent *ecsAgent) appendTaskENICapabilities(capabilities []*ecs.Attribute)
 	return capabilities
 }
 
-func (agent *ecsAgent) appendExecCapabilities(capabilities []*ecs.Attribute) ([]*ecs.Attribute, error) {
+// getCNIPluginVersion returns the version of the CNI plugin, retrying with a short backoff when invoking
+// the plugin fails. It gives up early if the agent context is canceled.
+func (agent *ecsAgent) getCNIPluginVersion(pluginName string) (string, error) {
+	if err := agent.ctx.Err(); err != nil {
+		return "", err
+	}
+	var version string
+	backoff := retry.NewExponentialBackoff(cniPluginVersionBackoffMin, cniPluginVersionBackoffMax,
+		cniPluginVersionBackoffJitter, cniPluginVersionBackoffMultiple)
+	err := retry.RetryNWithBackoffCtx(agent.ctx, backoff, cniPluginVersionAttempts, func() error {
+		var err error
+		version, err = agent.cniClient.Version(pluginName)
+		if err != nil {
+			seelog.Debugf("Error when getting the version of the plugin '%s': %v", pluginName, err)
+		}
+		return err
+	})
+	return version, err
+}
+
+func (agent *ecsAgent) appendExecCapabilities(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) ([]*ecs.Attribute, error) {
+	if !agent.cfg.ExecCapable.Enabled() {
+		return capabilities, nil
+	}
+
+	if _, ok := supportedVersions[dockerclient.ExecAgentMinimumVersion]; !ok {
+		return capabilities, nil
+	}
 
 	// Only Windows 2019 and above are supported, all Linux supported
 	if platformSupported, err := isPlatformExecSupported(); err != nil || !platformSupported {
@@ -478,6 +980,65 @@ func (agent *ecsAgent) appendExecCapabilities(capabilities []*ecs.Attribute) ([]
 	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityExec), nil
 }
 
+// appendNetworkOverlayCapability advertises support for attachable overlay networks when the docker
+// daemon is an active swarm node. The attribute isn't advertised if the daemon info can't be retrieved.
+func (agent *ecsAgent) appendNetworkOverlayCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	info, err := agent.dockerClient.Info(agent.ctx, dockerclient.InfoTimeout)
+	if err != nil {
+		logger.Warn("Unable to get docker info, overlay network capability will not be advertised", logger.Fields{
+			field.Error: err,
+		})
+		return capabilities
+	}
+	if info.Swarm.LocalNodeState != swarm.LocalNodeStateActive {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityNetworkOverlay)
+}
+
+// appendImagePullZstdCapability advertises support for pulling images with zstd compressed layers when the
+// docker daemon is recent enough to decompress them. The attribute isn't advertised if the daemon info can't
+// be retrieved.
+func (agent *ecsAgent) appendImagePullZstdCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	info, err := agent.dockerClient.Info(agent.ctx, dockerclient.InfoTimeout)
+	if err != nil {
+		logger.Warn("Unable to get docker info, zstd image pull capability will not be advertised", logger.Fields{
+			field.Error: err,
+		})
+		return capabilities
+	}
+	supported, err := utils.Version(info.ServerVersion).Matches(imagePullZstdDockerVersion)
+	if err != nil {
+		logger.Warn("Unable to parse docker server version, zstd image pull capability will not be advertised",
+			logger.Fields{
+				"serverVersion": info.ServerVersion,
+				field.Error:     err,
+			})
+		return capabilities
+	}
+	if !supported {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityImagePullZstd)
+}
+
+// appendDockerClientAPIVersionNegotiatedCapability advertises the docker api version the docker client
+// settled on with the daemon, which may be lower than the versions the agent supports. The attribute
+// isn't advertised if the client version can't be retrieved.
+func (agent *ecsAgent) appendDockerClientAPIVersionNegotiatedCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	apiVersion, err := agent.dockerClient.APIVersion()
+	if err != nil {
+		logger.Warn("Unable to get docker client api version, negotiated version will not be advertised", logger.Fields{
+			field.Error: err,
+		})
+		return capabilities
+	}
+	return append(capabilities, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilityDockerClientAPIVersionNegotiated),
+		Value: aws.String(string(apiVersion)),
+	})
+}
+
 func (agent *ecsAgent) appendServiceConnectCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	if loaded, _ := agent.serviceconnectManager.IsLoaded(agent.dockerClient); !loaded {
 		_, err := agent.serviceconnectManager.LoadImage(agent.ctx, agent.cfg, agent.dockerClient)
@@ -607,12 +1168,97 @@ func defaultPathExists(path string, shouldBeDirectory bool) (bool, error) {
 	return (isDirectory && shouldBeDirectory) || (!isDirectory && !shouldBeDirectory), nil
 }
 
+// appendVolumePluginScopeAttributes looks up the scope reported by the volume plugin and advertises
+// it for each of the plugin's driver attributes. Plugins whose scope can't be determined are skipped.
+func (agent *ecsAgent) appendVolumePluginScopeAttributes(capabilities []*ecs.Attribute, pluginName string,
+	driverAttributes ...string) []*ecs.Attribute {
+	scope, err := agent.mobyPlugins.VolumeDriverScope(pluginName)
+	if err != nil {
+		seelog.Debugf("Unable to determine scope of volume plugin %s: %v", pluginName, err)
+		return capabilities
+	}
+	for _, driverAttribute := range driverAttributes {
+		capabilities = appendVolumeDriverScopeAttribute(capabilities, driverAttribute, scope)
+	}
+	return capabilities
+}
+
+// appendVolumeDriverScopeAttribute advertises the scope of a volume driver as a suffix of its
+// driver attribute. Docker treats any scope other than global as local, and so does this.
+func appendVolumeDriverScopeAttribute(capabilities []*ecs.Attribute, driverAttribute, scope string) []*ecs.Attribute {
+	if strings.ToLower(scope) == volumeDriverScopeGlobal {
+		return appendNameOnlyAttribute(capabilities, driverAttribute+capabilityVolumeDriverScopeInfix+volumeDriverScopeGlobal)
+	}
+	return appendNameOnlyAttribute(capabilities, driverAttribute+capabilityVolumeDriverScopeInfix+volumeDriverScopeLocal)
+}
+
 func appendNameOnlyAttribute(attributes []*ecs.Attribute, name string) []*ecs.Attribute {
 	return append(attributes, &ecs.Attribute{
 		Name: aws.String(name),
 	})
 }
 
+// sortAttributesByName sorts the attributes by name so that the registered
+// capabilities do not depend on the order in which they were computed.
+func sortAttributesByName(attributes []*ecs.Attribute) {
+	sort.SliceStable(attributes, func(i, j int) bool {
+		return aws.StringValue(attributes[i].Name) < aws.StringValue(attributes[j].Name)
+	})
+}
+
+// appendSetHashAttribute adds an attribute whose value is the hex encoded SHA-256 hash of the names and
+// values of the other attributes, sorted by name, so that a change to the capabilities can be detected
+// by comparing a single value. Attributes without a value are hashed the same as ones with an empty value.
+func appendSetHashAttribute(attributes []*ecs.Attribute) []*ecs.Attribute {
+	sorted := removeAttributesByNames(attributes, []string{attributePrefix + capabilitySetHash})
+	sortAttributesByName(sorted)
+
+	hash := sha256.New()
+	for _, attr := range sorted {
+		// each name and value is terminated by a NUL byte so that adjacent fields can't run together
+		fmt.Fprintf(hash, "%s\x00%s\x00", aws.StringValue(attr.Name), aws.StringValue(attr.Value))
+	}
+	return append(sorted, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilitySetHash),
+		Value: aws.String(hex.EncodeToString(hash.Sum(nil))),
+	})
+}
+
+// dedupeAttributesByName drops attributes whose name has already been seen,
+// keeping the value of the first occurrence. ECS rejects registrations that
+// contain the same attribute name more than once.
+func dedupeAttributesByName(attributes []*ecs.Attribute) []*ecs.Attribute {
+	seen := make(map[string]struct{})
+	ret := make([]*ecs.Attribute, 0, len(attributes))
+	for _, attr := range attributes {
+		name := aws.StringValue(attr.Name)
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		ret = append(ret, attr)
+	}
+	return ret
+}
+
+// AttributesOnly returns the subset of capabilities that are attributes, i.e. whose name has the
+// "ecs.capability." prefix, leaving out "com.amazonaws.ecs.capability." entries. It doesn't change
+// the capabilities that are registered with ECS.
+func AttributesOnly(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return filterAttributesByNamePrefix(capabilities, attributePrefix)
+}
+
+// filterAttributesByNamePrefix returns the attributes whose name starts with prefix.
+func filterAttributesByNamePrefix(attributes []*ecs.Attribute, prefix string) []*ecs.Attribute {
+	var ret []*ecs.Attribute
+	for _, attr := range attributes {
+		if strings.HasPrefix(aws.StringValue(attr.Name), prefix) {
+			ret = append(ret, attr)
+		}
+	}
+	return ret
+}
+
 func removeAttributesByNames(attributes []*ecs.Attribute, names []string) []*ecs.Attribute {
 	nameMap := make(map[string]struct{})
 	for _, name := range names {
@@ -21,8 +21,10 @@ import (
 	"errors"
 	"io/ioutil"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	app_mocks "github.com/aws/amazon-ecs-agent/agent/app/mocks"
 	"github.com/aws/amazon-ecs-agent/agent/config"
@@ -32,13 +34,17 @@ import (
 	dm "github.com/aws/amazon-ecs-agent/agent/engine/daemonmanager"
 	mock_daemonmanager "github.com/aws/amazon-ecs-agent/agent/engine/daemonmanager/mock"
 	mock_serviceconnect "github.com/aws/amazon-ecs-agent/agent/engine/serviceconnect/mock"
+	"github.com/aws/amazon-ecs-agent/agent/handlers"
 	mock_loader "github.com/aws/amazon-ecs-agent/agent/utils/loader/mocks"
 	mock_mobypkgwrapper "github.com/aws/amazon-ecs-agent/agent/utils/mobypkgwrapper/mocks"
+	"github.com/aws/amazon-ecs-agent/agent/version"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs/model/ecs"
 	md "github.com/aws/amazon-ecs-agent/ecs-agent/manageddaemon"
 
 	"github.com/aws/aws-sdk-go/aws"
 	aws_credentials "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -86,6 +92,7 @@ func TestCapabilities(t *testing.T) {
 		TaskENIEnabled:             config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
 		AWSVPCBlockInstanceMetdata: config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
 		TaskCleanupWaitDuration:    config.DefaultConfig().TaskCleanupWaitDuration,
+		ExecCapable:                config.BooleanDefaultTrue{Value: config.ExplicitlyEnabled},
 	}
 
 	mockPauseLoader.EXPECT().IsLoaded(gomock.Any()).Return(false, nil).AnyTimes()
@@ -104,19 +111,21 @@ func TestCapabilities(t *testing.T) {
 
 	// Scan() and ListPluginsWithFilters() are tested with
 	// AnyTimes() because they are not called in windows.
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-			dockerclient.Version_1_18,
-			dockerclient.Version_1_19,
-		}),
-		// CNI plugins are platform dependent.
-		// Therefore, for any version query for any plugin return an appropriate version
-		cniClient.EXPECT().Version(gomock.Any()).Return("v1", nil),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	// CNI plugins are platform dependent.
+	// Therefore, for any version query for any plugin return an appropriate version
+	expectAppMeshPluginVersion(cniClient, "v1", nil)
+	cniClient.EXPECT().Version(gomock.Any()).Return("v1", nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+		dockerclient.Version_1_18,
+		dockerclient.Version_1_19,
+		dockerclient.Version_1_25,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	// TODO add capabilityEBSTaskAttach
 	expectedNameOnlyCapabilities := []string{
@@ -127,6 +136,7 @@ func TestCapabilities(t *testing.T) {
 		capabilityPrefix + "logging-driver.syslog",
 		capabilityPrefix + "logging-driver.journald",
 		capabilityPrefix + "selinux",
+		attributePrefix + capabilitySELinuxRelabel,
 		capabilityPrefix + "apparmor",
 		attributePrefix + "docker-plugin.local",
 		attributePrefix + taskENIAttributeSuffix,
@@ -139,11 +149,26 @@ func TestCapabilities(t *testing.T) {
 		attributePrefix + capabilityContainerOrdering,
 		attributePrefix + capabilityFullTaskSync,
 		attributePrefix + capabilityEnvFilesS3,
+		attributePrefix + capabilityEnvFilesSSM,
 		attributePrefix + taskENIBlockInstanceMetadataAttributeSuffix,
 		attributePrefix + capabilityExec,
 		attributePrefix + capabilityServiceConnect,
 		attributePrefix + capabilityContainerPortRange,
 		attributePrefix + capabilityContainerRestartPolicy,
+		attributePrefix + capabilityContainerRestartPolicyJitter,
+		attributePrefix + capabilityPortMappingName,
+		attributePrefix + capabilityReadonlyRootfsValidation,
+		attributePrefix + capabilityEnvPrecedence,
+		attributePrefix + capabilityParallelTaskStop,
+		attributePrefix + capabilityCustomStopSignal,
+		attributePrefix + capabilityDNSOrder,
+		attributePrefix + capabilityFaultInjectionStatus,
+		attributePrefix + capabilityImageCacheMetrics,
+		attributePrefix + capabilitySecretsSSMPath,
+		attributePrefix + capabilityDaemonReconnect,
+		attributePrefix + capabilityLogConfigValidation,
+		attributePrefix + capabilityVolumesFromReadOnly,
+		attributePrefix + capabilityHealthCheckCache,
 	}
 
 	var expectedCapabilities []*ecs.Attribute
@@ -202,6 +227,8 @@ func TestCapabilitiesExternal(t *testing.T) {
 		})
 	}
 	commonCaps := removeAttributesByNames(capsNonExternal, externalUnsupportedCapabilities)
+	// the hash of the capability set differs as the capabilities differ
+	commonCaps = removeAttributesByNames(commonCaps, []string{attributePrefix + capabilitySetHash})
 	for _, cap := range commonCaps {
 		assert.Contains(t, capsExternal, cap)
 	}
@@ -222,6 +249,7 @@ func getCapabilitiesTestConfig() *config.Config {
 		TaskENIEnabled:             config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
 		AWSVPCBlockInstanceMetdata: config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
 		TaskCleanupWaitDuration:    config.DefaultConfig().TaskCleanupWaitDuration,
+		ExecCapable:                config.BooleanDefaultTrue{Value: config.ExplicitlyEnabled},
 	}
 }
 
@@ -247,15 +275,16 @@ func getCapabilitiesWithConfig(cfg *config.Config, t *testing.T) []*ecs.Attribut
 	mockDaemonManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-			dockerclient.Version_1_18,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+		dockerclient.Version_1_18,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 
 	ctx, cancel := context.WithCancel(context.TODO())
@@ -288,6 +317,8 @@ func TestCapabilitiesECR(t *testing.T) {
 		dockerclient.Version_1_19,
 	})
 	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
 	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
@@ -328,6 +359,9 @@ func TestCapabilitiesECR(t *testing.T) {
 
 	_, ok = capMap["ecs.capability.execution-role-ecr-pull"]
 	assert.True(t, ok, "Could not find ECR execution pull capability when expected; got capabilities %v", capabilities)
+
+	_, ok = capMap["ecs.capability.registry-auth.ecr-public"]
+	assert.True(t, ok, "Could not find ECR Public registry auth capability when expected; got capabilities %v", capabilities)
 }
 
 func TestCapabilitiesTaskIAMRoleForSupportedDockerVersion(t *testing.T) {
@@ -344,6 +378,8 @@ func TestCapabilitiesTaskIAMRoleForSupportedDockerVersion(t *testing.T) {
 		dockerclient.Version_1_19,
 	})
 	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
 	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
@@ -398,6 +434,8 @@ func TestCapabilitiesTaskIAMRoleForUnSupportedDockerVersion(t *testing.T) {
 		dockerclient.Version_1_18,
 	})
 	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
 	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
@@ -452,6 +490,8 @@ func TestCapabilitiesTaskIAMRoleNetworkHostForSupportedDockerVersion(t *testing.
 		dockerclient.Version_1_19,
 	})
 	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
 	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
@@ -506,6 +546,8 @@ func TestCapabilitiesTaskIAMRoleNetworkHostForUnSupportedDockerVersion(t *testin
 		dockerclient.Version_1_18,
 	})
 	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
 	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
@@ -573,15 +615,16 @@ func TestAWSVPCBlockInstanceMetadataWhenTaskENIIsDisabled(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-			dockerclient.Version_1_18,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+		dockerclient.Version_1_18,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "privileged-container",
@@ -644,8 +687,12 @@ func TestCapabilitiesExecutionRoleAWSLogs(t *testing.T) {
 	})
 	// CNI plugins are platform dependent.
 	// Therefore, for any version query for any plugin return an error
-	cniClient.EXPECT().Version(gomock.Any()).Return("v1", errors.New("some error happened"))
+	expectAppMeshPluginVersion(cniClient, "v1", errors.New("some error happened"))
+	cniClient.EXPECT().Version(gomock.Any()).Return("v1", errors.New("some error happened")).
+		Times(cniPluginVersionAttempts)
 	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
 	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
@@ -687,6 +734,88 @@ func TestCapabilitiesExecutionRoleAWSLogs(t *testing.T) {
 	assert.True(t, ok, "Could not find AWSLogs execution role capability when expected; got capabilities %v", capabilities)
 }
 
+func TestCapabilitiesCNIPluginVersionRetried(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+	cniClient := mock_ecscni.NewMockCNIClient(ctrl)
+	conf := &config.Config{
+		TaskENIEnabled: config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
+	}
+	mockMobyPlugins := mock_mobypkgwrapper.NewMockPlugins(ctrl)
+
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	// The first invocation of the plugin fails transiently, the second one succeeds
+	expectAppMeshPluginVersion(cniClient, "v1", nil)
+	gomock.InOrder(
+		cniClient.EXPECT().Version(gomock.Any()).Return("", errors.New("some error happened")),
+		cniClient.EXPECT().Version(gomock.Any()).Return("v1", nil),
+	)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
+
+	mockPauseLoader := mock_loader.NewMockLoader(ctrl)
+	mockPauseLoader.EXPECT().IsLoaded(gomock.Any()).Return(false, nil).AnyTimes()
+
+	mockServiceConnectManager := mock_serviceconnect.NewMockManager(ctrl)
+	mockServiceConnectManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
+	mockServiceConnectManager.EXPECT().GetLoadedAppnetVersion().AnyTimes()
+	mockServiceConnectManager.EXPECT().GetCapabilitiesForAppnetInterfaceVersion("").AnyTimes()
+
+	mockDaemonManager := mock_daemonmanager.NewMockDaemonManager(ctrl)
+	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
+	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	// Cancel the context to cancel async routines
+	defer cancel()
+	agent := &ecsAgent{
+		ctx:                   ctx,
+		cfg:                   conf,
+		dockerClient:          client,
+		cniClient:             cniClient,
+		pauseLoader:           mockPauseLoader,
+		mobyPlugins:           mockMobyPlugins,
+		serviceconnectManager: mockServiceConnectManager,
+		daemonManagers:        mockDaemonManagers,
+	}
+
+	capabilities, err := agent.capabilities()
+	require.NoError(t, err)
+
+	var versionAttribute *ecs.Attribute
+	for _, capability := range capabilities {
+		if aws.StringValue(capability.Name) == attributePrefix+cniPluginVersionSuffix {
+			versionAttribute = capability
+		}
+	}
+	require.NotNil(t, versionAttribute, "Could not find the CNI plugin version attribute; got capabilities %v", capabilities)
+	assert.Equal(t, "v1", aws.StringValue(versionAttribute.Value))
+}
+
+func TestGetCNIPluginVersionContextCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cniClient := mock_ecscni.NewMockCNIClient(ctrl)
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	agent := &ecsAgent{
+		ctx:       ctx,
+		cniClient: cniClient,
+	}
+
+	// The plugin must not be invoked once the agent context is canceled
+	_, err := agent.getCNIPluginVersion("plugin")
+	assert.Equal(t, context.Canceled, err)
+}
+
 func TestCapabilitiesTaskResourceLimit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -706,12 +835,13 @@ func TestCapabilitiesTaskResourceLimit(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return(versionList),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return(versionList)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 	ctx, cancel := context.WithCancel(context.TODO())
 	// Cancel the context to cancel async routines
 	defer cancel()
@@ -759,12 +889,13 @@ func TestCapabilitesTaskResourceLimitDisabledByMissingDockerVersion(t *testing.T
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return(versionList),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return(versionList)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 	ctx, cancel := context.WithCancel(context.TODO())
 	// Cancel the context to cancel async routines
 	defer cancel()
@@ -811,9 +942,14 @@ func TestCapabilitesTaskResourceLimitErrorCase(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return(versionList),
-	)
+	// the capabilities that don't depend on the docker version are probed before the error is returned
+	mockMobyPlugins := mock_mobypkgwrapper.NewMockPlugins(ctrl)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return(versionList)
 	ctx, cancel := context.WithCancel(context.TODO())
 	// Cancel the context to cancel async routines
 	defer cancel()
@@ -822,6 +958,7 @@ func TestCapabilitesTaskResourceLimitErrorCase(t *testing.T) {
 		cfg:                   conf,
 		pauseLoader:           mockPauseLoader,
 		dockerClient:          client,
+		mobyPlugins:           mockMobyPlugins,
 		serviceconnectManager: mockServiceConnectManager,
 		daemonManagers:        mockDaemonManagers,
 	}
@@ -880,12 +1017,13 @@ func TestCapabilitiesIncreasedTaskCPULimit(t *testing.T) {
 			mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 			mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-			gomock.InOrder(
-				client.EXPECT().SupportedVersions().Return(versionList),
-				mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-				client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-					gomock.Any()).AnyTimes().Return([]string{}, nil),
-			)
+			client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+			client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+			client.EXPECT().SupportedVersions().Return(versionList)
+			mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+			client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).AnyTimes().Return([]string{}, nil)
 			ctx, cancel := context.WithCancel(context.TODO())
 			// Cancel the context to cancel async routines
 			defer cancel()
@@ -925,6 +1063,8 @@ func TestCapabilitiesContainerHealth(t *testing.T) {
 		dockerclient.Version_1_24,
 	})
 	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
 	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
@@ -975,6 +1115,8 @@ func TestCapabilitiesContainerHealthDisabled(t *testing.T) {
 		dockerclient.Version_1_24,
 	})
 	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
 	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
 		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
@@ -1031,12 +1173,13 @@ func TestCapabilitesListPluginsErrorCase(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return(versionList),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return(nil, errors.New("listPlugins error happened")),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return(versionList)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return(nil, errors.New("listPlugins error happened"))
 	ctx, cancel := context.WithCancel(context.TODO())
 	// Cancel the context to cancel async routines
 	defer cancel()
@@ -1053,11 +1196,17 @@ func TestCapabilitesListPluginsErrorCase(t *testing.T) {
 	capabilities, err := agent.capabilities()
 	require.NoError(t, err)
 
+	var volumeDriverCapabilities []string
 	for _, capability := range capabilities {
-		if strings.HasPrefix(aws.StringValue(capability.Name), "ecs.capability.docker-volume-driver") {
-			assert.Equal(t, aws.StringValue(capability.Name), "ecs.capability.docker-volume-driver.local")
+		if strings.HasPrefix(aws.StringValue(capability.Name), attributePrefix+capabilityDockerPluginInfix) {
+			volumeDriverCapabilities = append(volumeDriverCapabilities, aws.StringValue(capability.Name))
 		}
 	}
+	assert.ElementsMatch(t, []string{
+		attributePrefix + capabilityDockerPluginInfix + "local",
+		attributePrefix + capabilityDockerPluginInfix + "local.scope.local",
+	}, volumeDriverCapabilities)
+	assert.Contains(t, capabilities, &ecs.Attribute{Name: aws.String(attributePrefix + capabilitySharedScratch)})
 }
 
 func TestCapabilitesScanPluginsErrorCase(t *testing.T) {
@@ -1078,12 +1227,13 @@ func TestCapabilitesScanPluginsErrorCase(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return(versionList),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return(nil, errors.New("Scan plugins error happened")),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return(versionList)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return(nil, errors.New("Scan plugins error happened"))
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 	ctx, cancel := context.WithCancel(context.TODO())
 	// Cancel the context to cancel async routines
 	defer cancel()
@@ -1118,6 +1268,8 @@ func TestCapabilitiesExecuteCommand(t *testing.T) {
 		invalidSsmVersions       map[string]struct{}
 		shouldHaveExecCapability bool
 		osPlatformNotSupported   bool
+		execCapableDisabled      bool
+		dockerVersion            dockerclient.DockerVersion
 	}{
 		{
 			name:                     "execute-command capability should not be added if any required file is not found",
@@ -1164,6 +1316,20 @@ func TestCapabilitiesExecuteCommand(t *testing.T) {
 			osPlatformNotSupported:   true,
 			shouldHaveExecCapability: false,
 		},
+		{
+			name:                     "execute-command capability should not be added if disabled in config",
+			pathExists:               func(path string, shouldBeDirectory bool) (bool, error) { return true, nil },
+			getSubDirectories:        func(path string) ([]string, error) { return []string{"3.0.236.0"}, nil },
+			execCapableDisabled:      true,
+			shouldHaveExecCapability: false,
+		},
+		{
+			name:                     "execute-command capability should not be added if docker version is not supported",
+			pathExists:               func(path string, shouldBeDirectory bool) (bool, error) { return true, nil },
+			getSubDirectories:        func(path string) ([]string, error) { return []string{"3.0.236.0"}, nil },
+			dockerVersion:            dockerclient.Version_1_24,
+			shouldHaveExecCapability: false,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1183,7 +1349,11 @@ func TestCapabilitiesExecuteCommand(t *testing.T) {
 
 			mockMobyPlugins := mock_mobypkgwrapper.NewMockPlugins(ctrl)
 			client := mock_dockerapi.NewMockDockerClient(ctrl)
-			versionList := []dockerclient.DockerVersion{dockerclient.Version_1_19}
+			dockerVersion := dockerclient.ExecAgentMinimumVersion
+			if tc.dockerVersion != "" {
+				dockerVersion = tc.dockerVersion
+			}
+			versionList := []dockerclient.DockerVersion{dockerclient.Version_1_19, dockerVersion}
 			mockPauseLoader := mock_loader.NewMockLoader(ctrl)
 			mockPauseLoader.EXPECT().IsLoaded(gomock.Any()).Return(false, nil).AnyTimes()
 			mockServiceConnectManager := mock_serviceconnect.NewMockManager(ctrl)
@@ -1195,18 +1365,23 @@ func TestCapabilitiesExecuteCommand(t *testing.T) {
 			mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 			mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-			gomock.InOrder(
-				client.EXPECT().SupportedVersions().Return(versionList),
-				mockMobyPlugins.EXPECT().Scan().AnyTimes().Return(nil, errors.New("Scan plugins error happened")),
-				client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-					gomock.Any()).AnyTimes().Return([]string{}, nil),
-			)
+			client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+			client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+			client.EXPECT().SupportedVersions().Return(versionList)
+			mockMobyPlugins.EXPECT().Scan().AnyTimes().Return(nil, errors.New("Scan plugins error happened"))
+			client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).AnyTimes().Return([]string{}, nil)
 			ctx, cancel := context.WithCancel(context.TODO())
 			// Cancel the context to cancel async routines
 			defer cancel()
+			cfg := &config.Config{ExecCapable: config.BooleanDefaultTrue{Value: config.NotSet}}
+			if tc.execCapableDisabled {
+				cfg.ExecCapable = config.BooleanDefaultTrue{Value: config.ExplicitlyDisabled}
+			}
 			agent := &ecsAgent{
 				ctx:                   ctx,
-				cfg:                   &config.Config{},
+				cfg:                   cfg,
 				dockerClient:          client,
 				pauseLoader:           mockPauseLoader,
 				mobyPlugins:           mockMobyPlugins,
@@ -1262,6 +1437,7 @@ func TestCapabilitiesNoServiceConnect(t *testing.T) {
 		TaskENIEnabled:             config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
 		AWSVPCBlockInstanceMetdata: config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
 		TaskCleanupWaitDuration:    config.DefaultConfig().TaskCleanupWaitDuration,
+		ExecCapable:                config.BooleanDefaultTrue{Value: config.ExplicitlyEnabled},
 	}
 
 	mockPauseLoader.EXPECT().IsLoaded(gomock.Any()).Return(false, nil).AnyTimes()
@@ -1278,19 +1454,21 @@ func TestCapabilitiesNoServiceConnect(t *testing.T) {
 
 	// Scan() and ListPluginsWithFilters() are tested with
 	// AnyTimes() because they are not called in windows.
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-			dockerclient.Version_1_18,
-			dockerclient.Version_1_19,
-		}),
-		// CNI plugins are platform dependent.
-		// Therefore, for any version query for any plugin return an appropriate version
-		cniClient.EXPECT().Version(gomock.Any()).Return("v1", nil),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	// CNI plugins are platform dependent.
+	// Therefore, for any version query for any plugin return an appropriate version
+	expectAppMeshPluginVersion(cniClient, "v1", nil)
+	cniClient.EXPECT().Version(gomock.Any()).Return("v1", nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+		dockerclient.Version_1_18,
+		dockerclient.Version_1_19,
+		dockerclient.Version_1_25,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	expectedNameOnlyCapabilities := []string{
 		capabilityPrefix + "privileged-container",
@@ -1300,6 +1478,7 @@ func TestCapabilitiesNoServiceConnect(t *testing.T) {
 		capabilityPrefix + "logging-driver.syslog",
 		capabilityPrefix + "logging-driver.journald",
 		capabilityPrefix + "selinux",
+		attributePrefix + capabilitySELinuxRelabel,
 		capabilityPrefix + "apparmor",
 		attributePrefix + "docker-plugin.local",
 		attributePrefix + taskENIAttributeSuffix,
@@ -1312,10 +1491,25 @@ func TestCapabilitiesNoServiceConnect(t *testing.T) {
 		attributePrefix + capabilityContainerOrdering,
 		attributePrefix + capabilityFullTaskSync,
 		attributePrefix + capabilityEnvFilesS3,
+		attributePrefix + capabilityEnvFilesSSM,
 		attributePrefix + taskENIBlockInstanceMetadataAttributeSuffix,
 		attributePrefix + capabilityExec,
 		attributePrefix + capabilityContainerPortRange,
 		attributePrefix + capabilityContainerRestartPolicy,
+		attributePrefix + capabilityContainerRestartPolicyJitter,
+		attributePrefix + capabilityPortMappingName,
+		attributePrefix + capabilityReadonlyRootfsValidation,
+		attributePrefix + capabilityEnvPrecedence,
+		attributePrefix + capabilityParallelTaskStop,
+		attributePrefix + capabilityCustomStopSignal,
+		attributePrefix + capabilityDNSOrder,
+		attributePrefix + capabilityFaultInjectionStatus,
+		attributePrefix + capabilityImageCacheMetrics,
+		attributePrefix + capabilitySecretsSSMPath,
+		attributePrefix + capabilityDaemonReconnect,
+		attributePrefix + capabilityLogConfigValidation,
+		attributePrefix + capabilityVolumesFromReadOnly,
+		attributePrefix + capabilityHealthCheckCache,
 	}
 
 	var expectedCapabilities []*ecs.Attribute
@@ -1489,6 +1683,606 @@ func TestAppendAndRemoveAttributes(t *testing.T) {
 	})
 }
 
+func TestAppendSetHashAttribute(t *testing.T) {
+	setHash := func(attributes []*ecs.Attribute) string {
+		for _, attr := range appendSetHashAttribute(attributes) {
+			if aws.StringValue(attr.Name) == attributePrefix+capabilitySetHash {
+				return aws.StringValue(attr.Value)
+			}
+		}
+		require.Fail(t, "set hash attribute not found")
+		return ""
+	}
+	attributes := func() []*ecs.Attribute {
+		return []*ecs.Attribute{
+			{Name: aws.String(attributePrefix + "b")},
+			{Name: aws.String(attributePrefix + "a"), Value: aws.String("1")},
+			{Name: aws.String(capabilityPrefix + "c")},
+		}
+	}
+
+	hash := setHash(attributes())
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, setHash(attributes()), "hash must be stable for the same attributes")
+
+	reordered := attributes()
+	reordered[0], reordered[2] = reordered[2], reordered[0]
+	assert.Equal(t, hash, setHash(reordered), "hash must not depend on the order of the attributes")
+
+	withPreviousHash := appendSetHashAttribute(attributes())
+	assert.Equal(t, hash, setHash(withPreviousHash), "hash must not include a previous hash attribute")
+	assert.Len(t, appendSetHashAttribute(withPreviousHash), len(attributes())+1)
+
+	added := append(attributes(), &ecs.Attribute{Name: aws.String(attributePrefix + "d")})
+	assert.NotEqual(t, hash, setHash(added), "hash must change when an attribute is added")
+
+	removed := attributes()[1:]
+	assert.NotEqual(t, hash, setHash(removed), "hash must change when an attribute is removed")
+
+	changed := attributes()
+	changed[1].Value = aws.String("2")
+	assert.NotEqual(t, hash, setHash(changed), "hash must change when an attribute value changes")
+}
+
+func TestDedupeAttributesByName(t *testing.T) {
+	attrs := []*ecs.Attribute{
+		{Name: aws.String("cap-1"), Value: aws.String("first")},
+		{Name: aws.String("cap-2")},
+		{Name: aws.String("cap-1"), Value: aws.String("second")},
+	}
+
+	attrs = dedupeAttributesByName(attrs)
+	require.Len(t, attrs, 2)
+	assert.Equal(t, "cap-1", aws.StringValue(attrs[0].Name))
+	assert.Equal(t, "first", aws.StringValue(attrs[0].Value))
+	assert.Equal(t, "cap-2", aws.StringValue(attrs[1].Name))
+}
+
+func TestAttributesOnly(t *testing.T) {
+	capabilities := []*ecs.Attribute{
+		{Name: aws.String(capabilityPrefix + "privileged-container")},
+		{Name: aws.String(attributePrefix + capabilityEnvFilesS3)},
+		{Name: aws.String(capabilityPrefix + "logging-driver.awslogs")},
+		{
+			Name:  aws.String(attributePrefix + capabilityAgentVersion),
+			Value: aws.String("1.0.0"),
+		},
+		{Name: aws.String("ecs.os-type")},
+	}
+
+	assert.Equal(t, []*ecs.Attribute{
+		{Name: aws.String(attributePrefix + capabilityEnvFilesS3)},
+		{
+			Name:  aws.String(attributePrefix + capabilityAgentVersion),
+			Value: aws.String("1.0.0"),
+		},
+	}, AttributesOnly(capabilities))
+	assert.Len(t, capabilities, 5, "filtering shouldn't modify the capabilities")
+	assert.Empty(t, AttributesOnly(nil))
+}
+
+// runCapabilityProbesSerially is the serial equivalent of runCapabilityProbes.
+func runCapabilityProbesSerially(probes []capabilityProbe) ([]*ecs.Attribute, error) {
+	var attributes []*ecs.Attribute
+	for _, probe := range probes {
+		probed, err := probe()
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, probed...)
+	}
+	return attributes, nil
+}
+
+// delayedCapabilityProbe returns a probe that sleeps before returning the
+// named attributes, so that probes complete in a different order than started.
+func delayedCapabilityProbe(delay time.Duration, names ...string) capabilityProbe {
+	return func() ([]*ecs.Attribute, error) {
+		time.Sleep(delay)
+		var attributes []*ecs.Attribute
+		for _, name := range names {
+			attributes = appendNameOnlyAttribute(attributes, name)
+		}
+		return attributes, nil
+	}
+}
+
+func TestRunCapabilityProbesMatchesSerial(t *testing.T) {
+	probes := []capabilityProbe{
+		delayedCapabilityProbe(30*time.Millisecond, "cap-1", "cap-2"),
+		delayedCapabilityProbe(20 * time.Millisecond),
+		delayedCapabilityProbe(10*time.Millisecond, "cap-3"),
+		delayedCapabilityProbe(0, "cap-4", "cap-5"),
+	}
+
+	expected, err := runCapabilityProbesSerially(probes)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		attributes, err := runCapabilityProbes(probes)
+		require.NoError(t, err)
+		assert.Equal(t, expected, attributes)
+	}
+}
+
+func TestRunCapabilityProbesError(t *testing.T) {
+	probeErr := errors.New("probe error")
+	probes := []capabilityProbe{
+		delayedCapabilityProbe(10*time.Millisecond, "cap-1"),
+		func() ([]*ecs.Attribute, error) {
+			return nil, probeErr
+		},
+		delayedCapabilityProbe(0, "cap-2"),
+	}
+
+	attributes, err := runCapabilityProbes(probes)
+	assert.Nil(t, attributes)
+	assert.Equal(t, probeErr, err)
+}
+
+func TestCapabilitiesSortedAndStable(t *testing.T) {
+	cfg := getCapabilitiesTestConfig()
+	expected := getCapabilitiesWithConfig(cfg, t)
+	assert.True(t, sort.SliceIsSorted(expected, func(i, j int) bool {
+		return aws.StringValue(expected[i].Name) < aws.StringValue(expected[j].Name)
+	}))
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, expected, getCapabilitiesWithConfig(cfg, t))
+	}
+}
+
+func BenchmarkRunCapabilityProbes(b *testing.B) {
+	var probes []capabilityProbe
+	for i := 0; i < 8; i++ {
+		probes = append(probes, delayedCapabilityProbe(time.Millisecond, "cap"))
+	}
+
+	b.Run("serial", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			runCapabilityProbesSerially(probes)
+		}
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			runCapabilityProbes(probes)
+		}
+	})
+}
+
+func TestAppendRegistryMutualTLSCapabilities(t *testing.T) {
+	defer func() {
+		findRegistriesWithClientCerts = dockerclient.RegistriesWithClientCerts
+	}()
+
+	testCases := []struct {
+		name               string
+		certsDir           string
+		registries         []string
+		findErr            error
+		expectedCapability bool
+	}{
+		{
+			name:               "registry with client cert",
+			certsDir:           "/etc/docker/certs.d",
+			registries:         []string{"registry.example.com"},
+			expectedCapability: true,
+		},
+		{
+			name:     "no registries with client cert",
+			certsDir: "/etc/docker/certs.d",
+		},
+		{
+			name:     "error looking up client certs",
+			certsDir: "/etc/docker/certs.d",
+			findErr:  errors.New("error"),
+		},
+		{
+			name:       "certs dir not configured",
+			registries: []string{"registry.example.com"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			findRegistriesWithClientCerts = func(string) ([]string, error) {
+				return tc.registries, tc.findErr
+			}
+			agent := &ecsAgent{
+				cfg: &config.Config{
+					RegistryClientCertsDir: tc.certsDir,
+				},
+			}
+
+			capabilities := agent.appendRegistryMutualTLSCapabilities(nil)
+
+			if tc.expectedCapability {
+				require.Len(t, capabilities, 1)
+				assert.Equal(t, attributePrefix+capabilityRegistryMutualTLS, aws.StringValue(capabilities[0].Name))
+			} else {
+				assert.Empty(t, capabilities)
+			}
+		})
+	}
+}
+
+func TestAppendAWSLogsNonBlockingCapability(t *testing.T) {
+	testCases := []struct {
+		name               string
+		loggingDrivers     []dockerclient.LoggingDriver
+		supportedVersions  []dockerclient.DockerVersion
+		expectedCapability bool
+	}{
+		{
+			name:               "awslogs available with supported docker version",
+			loggingDrivers:     []dockerclient.LoggingDriver{dockerclient.JSONFileDriver, dockerclient.AWSLogsDriver},
+			supportedVersions:  []dockerclient.DockerVersion{dockerclient.Version_1_21, dockerclient.Version_1_28},
+			expectedCapability: true,
+		},
+		{
+			name:              "awslogs available with unsupported docker version",
+			loggingDrivers:    []dockerclient.LoggingDriver{dockerclient.JSONFileDriver, dockerclient.AWSLogsDriver},
+			supportedVersions: []dockerclient.DockerVersion{dockerclient.Version_1_21, dockerclient.Version_1_27},
+		},
+		{
+			name:              "awslogs not available with supported docker version",
+			loggingDrivers:    []dockerclient.LoggingDriver{dockerclient.JSONFileDriver},
+			supportedVersions: []dockerclient.DockerVersion{dockerclient.Version_1_21, dockerclient.Version_1_28},
+		},
+		{
+			name:              "no logging drivers available",
+			supportedVersions: []dockerclient.DockerVersion{dockerclient.Version_1_28},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			supportedVersions := make(map[dockerclient.DockerVersion]bool)
+			for _, version := range tc.supportedVersions {
+				supportedVersions[version] = true
+			}
+			agent := &ecsAgent{
+				cfg: &config.Config{
+					AvailableLoggingDrivers: tc.loggingDrivers,
+				},
+			}
+
+			capabilities := agent.appendAWSLogsNonBlockingCapability(nil, supportedVersions)
+
+			if tc.expectedCapability {
+				assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityAWSLogsNonBlocking)}}, capabilities)
+			} else {
+				assert.Empty(t, capabilities)
+			}
+		})
+	}
+}
+
+func TestAppendJournaldTagCapability(t *testing.T) {
+	testCases := []struct {
+		name               string
+		loggingDrivers     []dockerclient.LoggingDriver
+		supportedVersions  []dockerclient.DockerVersion
+		expectedCapability bool
+	}{
+		{
+			name:               "journald available with supported docker version",
+			loggingDrivers:     []dockerclient.LoggingDriver{dockerclient.JSONFileDriver, dockerclient.JournaldDriver},
+			supportedVersions:  []dockerclient.DockerVersion{dockerclient.Version_1_19, dockerclient.Version_1_23},
+			expectedCapability: true,
+		},
+		{
+			name:              "journald available with unsupported docker version",
+			loggingDrivers:    []dockerclient.LoggingDriver{dockerclient.JSONFileDriver, dockerclient.JournaldDriver},
+			supportedVersions: []dockerclient.DockerVersion{dockerclient.Version_1_19, dockerclient.Version_1_22},
+		},
+		{
+			name:              "journald not available with supported docker version",
+			loggingDrivers:    []dockerclient.LoggingDriver{dockerclient.JSONFileDriver},
+			supportedVersions: []dockerclient.DockerVersion{dockerclient.Version_1_19, dockerclient.Version_1_23},
+		},
+		{
+			name:              "no logging drivers available",
+			supportedVersions: []dockerclient.DockerVersion{dockerclient.Version_1_23},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			supportedVersions := make(map[dockerclient.DockerVersion]bool)
+			for _, version := range tc.supportedVersions {
+				supportedVersions[version] = true
+			}
+			agent := &ecsAgent{
+				cfg: &config.Config{
+					AvailableLoggingDrivers: tc.loggingDrivers,
+				},
+			}
+
+			capabilities := agent.appendJournaldTagCapability(nil, supportedVersions)
+
+			if tc.expectedCapability {
+				assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityJournaldTag)}}, capabilities)
+			} else {
+				assert.Empty(t, capabilities)
+			}
+		})
+	}
+}
+
+func TestAppendTaskHealthGatingCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{},
+	}
+	assert.Empty(t, agent.appendTaskHealthGatingCapability(nil))
+
+	agent.cfg.TaskHealthGatingEnabled = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityTaskHealthGating)}},
+		agent.appendTaskHealthGatingCapability(nil))
+}
+
+func TestAppendLogTeeStdoutCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{},
+	}
+	assert.Empty(t, agent.appendLogTeeStdoutCapability(nil))
+
+	agent.cfg.LogTeeStdout = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityLogTeeStdout)}},
+		agent.appendLogTeeStdoutCapability(nil))
+}
+
+func TestAppendMetadataAuthCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{},
+	}
+	assert.Empty(t, agent.appendMetadataAuthCapability(nil))
+
+	agent.cfg.TMDSAuthEnabled = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityMetadataAuth)}},
+		agent.appendMetadataAuthCapability(nil))
+}
+
+func TestAppendNetworkBandwidthLimitCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{},
+	}
+	assert.Empty(t, agent.appendNetworkBandwidthLimitCapability(nil))
+
+	agent.cfg.BandwidthLimitCapable = config.BooleanDefaultFalse{Value: config.ExplicitlyDisabled}
+	assert.Empty(t, agent.appendNetworkBandwidthLimitCapability(nil))
+
+	agent.cfg.BandwidthLimitCapable = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityNetworkBandwidthLimit)}},
+		agent.appendNetworkBandwidthLimitCapability(nil))
+}
+
+func TestAppendENICountMaxCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{},
+	}
+	// ECS_MAX_ENIS isn't configured and there's no detection to fall back on
+	assert.Empty(t, agent.appendENICountMaxCapability(nil))
+
+	agent.cfg.MaxENIs = 15
+	assert.Equal(t, []*ecs.Attribute{
+		{
+			Name:  aws.String(attributePrefix + capabilityENICountMax),
+			Value: aws.String("15"),
+		},
+	}, agent.appendENICountMaxCapability(nil))
+}
+
+func TestAppendImagePrewarmCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{},
+	}
+	assert.Empty(t, agent.appendImagePrewarmCapability(nil))
+
+	agent.cfg.PrePullImages = []string{"busybox:1.36"}
+	assert.Equal(t, []*ecs.Attribute{
+		{Name: aws.String(attributePrefix + capabilityImagePrewarm)},
+	}, agent.appendImagePrewarmCapability(nil))
+}
+
+func TestAppendContainerStopTimeoutCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{DockerStopTimeout: config.DefaultDockerStopTimeout},
+	}
+	assert.Empty(t, agent.appendContainerStopTimeoutCapability(nil))
+
+	agent.cfg.DockerStopTimeout = 2 * time.Minute
+	assert.Equal(t, []*ecs.Attribute{
+		{
+			Name:  aws.String(attributePrefix + capabilityContainerStopTimeout),
+			Value: aws.String("2m0s"),
+		},
+	}, agent.appendContainerStopTimeoutCapability(nil))
+}
+
+func TestAppendStopSignalEscalationCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{},
+	}
+	assert.Empty(t, agent.appendStopSignalEscalationCapability(nil))
+
+	agent.cfg.StopSignalEscalation = []string{"SIGTERM", "SIGINT"}
+	assert.Equal(t, []*ecs.Attribute{
+		{Name: aws.String(attributePrefix + capabilityStopSignalEscalation)},
+	}, agent.appendStopSignalEscalationCapability(nil))
+}
+
+func TestAppendImagePullBehaviorCapability(t *testing.T) {
+	testCases := []struct {
+		behavior          config.ImagePullBehaviorType
+		expectedAttribute string
+	}{
+		{config.ImagePullDefaultBehavior, "ecs.capability.image-pull-behavior.default"},
+		{config.ImagePullAlwaysBehavior, "ecs.capability.image-pull-behavior.always"},
+		{config.ImagePullOnceBehavior, "ecs.capability.image-pull-behavior.once"},
+		{config.ImagePullPreferCachedBehavior, "ecs.capability.image-pull-behavior.prefer-cached"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expectedAttribute, func(t *testing.T) {
+			agent := &ecsAgent{
+				cfg: &config.Config{ImagePullBehavior: tc.behavior},
+			}
+			assert.Equal(t, []*ecs.Attribute{
+				{Name: aws.String(tc.expectedAttribute)},
+			}, agent.appendImagePullBehaviorCapability(nil))
+		})
+	}
+}
+
+func TestAppendNetworkOverlayCapability(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		info                 types.Info
+		infoErr              error
+		expectedCapabilities []*ecs.Attribute
+	}{
+		{
+			name: "swarm active",
+			info: types.Info{Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateActive}},
+			expectedCapabilities: []*ecs.Attribute{
+				{Name: aws.String(attributePrefix + capabilityNetworkOverlay)},
+			},
+		},
+		{
+			name: "swarm inactive",
+			info: types.Info{Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateInactive}},
+		},
+		{
+			name:    "info error",
+			infoErr: errors.New("error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mock_dockerapi.NewMockDockerClient(ctrl)
+			client.EXPECT().Info(gomock.Any(), dockerclient.InfoTimeout).Return(tc.info, tc.infoErr)
+			agent := &ecsAgent{
+				ctx:          context.TODO(),
+				dockerClient: client,
+			}
+
+			assert.Equal(t, tc.expectedCapabilities, agent.appendNetworkOverlayCapability(nil))
+		})
+	}
+}
+
+func TestAppendImagePullZstdCapability(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		info                 types.Info
+		infoErr              error
+		expectedCapabilities []*ecs.Attribute
+	}{
+		{
+			name: "zstd supported",
+			info: types.Info{ServerVersion: "24.0.5"},
+			expectedCapabilities: []*ecs.Attribute{
+				{Name: aws.String(attributePrefix + capabilityImagePullZstd)},
+			},
+		},
+		{
+			name: "zstd not supported",
+			info: types.Info{ServerVersion: "20.10.25"},
+		},
+		{
+			name: "unparsable server version",
+			info: types.Info{ServerVersion: "dev"},
+		},
+		{
+			name:    "info error",
+			infoErr: errors.New("error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mock_dockerapi.NewMockDockerClient(ctrl)
+			client.EXPECT().Info(gomock.Any(), dockerclient.InfoTimeout).Return(tc.info, tc.infoErr)
+			agent := &ecsAgent{
+				ctx:          context.TODO(),
+				dockerClient: client,
+			}
+
+			assert.Equal(t, tc.expectedCapabilities, agent.appendImagePullZstdCapability(nil))
+		})
+	}
+}
+
+func TestAppendDockerClientAPIVersionNegotiatedCapability(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		apiVersion           dockerclient.DockerVersion
+		apiVersionErr        error
+		expectedCapabilities []*ecs.Attribute
+	}{
+		{
+			name:       "negotiated version",
+			apiVersion: dockerclient.Version_1_35,
+			expectedCapabilities: []*ecs.Attribute{
+				{
+					Name:  aws.String(attributePrefix + capabilityDockerClientAPIVersionNegotiated),
+					Value: aws.String("1.35"),
+				},
+			},
+		},
+		{
+			name:          "api version error",
+			apiVersionErr: errors.New("error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mock_dockerapi.NewMockDockerClient(ctrl)
+			client.EXPECT().APIVersion().Return(tc.apiVersion, tc.apiVersionErr)
+			agent := &ecsAgent{
+				ctx:          context.TODO(),
+				dockerClient: client,
+			}
+
+			assert.Equal(t, tc.expectedCapabilities, agent.appendDockerClientAPIVersionNegotiatedCapability(nil))
+		})
+	}
+}
+
+func TestAppendAgentVersionCapability(t *testing.T) {
+	capabilities := appendAgentVersionCapability(nil)
+	assert.Equal(t, []*ecs.Attribute{
+		{
+			Name:  aws.String(attributePrefix + capabilityAgentVersion),
+			Value: aws.String(version.Version + "-" + version.GitShortHash),
+		},
+	}, capabilities)
+}
+
+func TestCapabilitiesTaskDefinitionVersion(t *testing.T) {
+	capabilities := getCapabilitiesWithConfig(getCapabilitiesTestConfig(), t)
+	assert.Contains(t, capabilities, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilityTaskDefinitionVersion),
+		Value: aws.String(taskDefinitionSchemaVersion),
+	})
+}
+
+func TestCapabilitiesIntrospectionVersion(t *testing.T) {
+	capabilities := getCapabilitiesWithConfig(getCapabilitiesTestConfig(), t)
+	assert.Contains(t, capabilities, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilityIntrospectionVersion),
+		Value: aws.String(handlers.IntrospectionAPIVersion),
+	})
+}
+
 func TestAppendGMSACapabilities(t *testing.T) {
 	var inputCapabilities []*ecs.Attribute
 	var expectedCapabilities []*ecs.Attribute
@@ -1541,6 +2335,56 @@ func TestAppendGMSADomainlessCapabilities(t *testing.T) {
 	}
 }
 
+func TestAppendGMSACredentialSpecSourceCapabilities(t *testing.T) {
+	testCases := []struct {
+		name               string
+		gmsaCapable        config.BooleanDefaultFalse
+		gmsaDomainless     config.BooleanDefaultFalse
+		expectCapabilities []string
+	}{
+		{
+			name:               "gmsa enabled",
+			gmsaCapable:        config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
+			gmsaDomainless:     config.BooleanDefaultFalse{Value: config.ExplicitlyDisabled},
+			expectCapabilities: []string{capabilityGMSACredentialSpecS3, capabilityGMSACredentialSpecSSM},
+		},
+		{
+			name:               "gmsa domainless enabled",
+			gmsaCapable:        config.BooleanDefaultFalse{Value: config.ExplicitlyDisabled},
+			gmsaDomainless:     config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
+			expectCapabilities: []string{capabilityGMSACredentialSpecS3, capabilityGMSACredentialSpecSSM},
+		},
+		{
+			name:           "gmsa disabled and domainless not set",
+			gmsaCapable:    config.BooleanDefaultFalse{Value: config.ExplicitlyDisabled},
+			gmsaDomainless: config.BooleanDefaultFalse{Value: config.NotSet},
+		},
+		{
+			name:           "gmsa disabled",
+			gmsaCapable:    config.BooleanDefaultFalse{Value: config.ExplicitlyDisabled},
+			gmsaDomainless: config.BooleanDefaultFalse{Value: config.ExplicitlyDisabled},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			agent := &ecsAgent{
+				cfg: &config.Config{
+					GMSACapable:           tc.gmsaCapable,
+					GMSADomainlessCapable: tc.gmsaDomainless,
+				},
+			}
+
+			capabilities := agent.appendGMSACredentialSpecSourceCapabilities(nil)
+
+			require.Len(t, capabilities, len(tc.expectCapabilities))
+			for i, name := range tc.expectCapabilities {
+				assert.Equal(t, attributePrefix+name, aws.StringValue(capabilities[i].Name))
+			}
+		})
+	}
+}
+
 func TestAppendGMSADomainlessCapabilitiesFalse(t *testing.T) {
 	var inputCapabilities []*ecs.Attribute
 	var expectedCapabilities []*ecs.Attribute
@@ -1558,3 +2402,13 @@ func TestAppendGMSADomainlessCapabilitiesFalse(t *testing.T) {
 
 	assert.Equal(t, len(expectedCapabilities), len(capabilities))
 }
+
+func TestCapabilitiesEnvAppConfig(t *testing.T) {
+	appConfigCapability := &ecs.Attribute{Name: aws.String(attributePrefix + capabilityEnvAppConfig)}
+
+	cfg := getCapabilitiesTestConfig()
+	assert.NotContains(t, getCapabilitiesWithConfig(cfg, t), appConfigCapability)
+
+	cfg.AppConfigCapable = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+	assert.Contains(t, getCapabilitiesWithConfig(cfg, t), appConfigCapability)
+}
@@ -17,13 +17,16 @@
 package app
 
 import (
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
 	"github.com/aws/amazon-ecs-agent/agent/ecscni"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/firelens"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/volume"
 	"github.com/aws/amazon-ecs-agent/agent/utils"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs/model/ecs"
@@ -40,6 +43,14 @@ const (
 	capabilityDepsRootDir = "/managed-agents"
 )
 
+const (
+	// swapAccountingCgroupV1Path is only present in the memory cgroup when the kernel accounts for swap usage
+	swapAccountingCgroupV1Path = "/sys/fs/cgroup/memory/memory.memsw.limit_in_bytes"
+	// cgroupV2ControllersPath lists the controllers of the root cgroup, swap is accounted by the memory
+	// controller in cgroup v2
+	cgroupV2ControllersPath = "/sys/fs/cgroup/cgroup.controllers"
+)
+
 var (
 	certsDir                    = filepath.Join(capabilityExecRootDir, capabilityExecCertsRelativePath)
 	capabilityExecRequiredCerts = []string{
@@ -57,11 +68,17 @@ var (
 		configDir: []string{},
 		certsDir:  capabilityExecRequiredCerts,
 	}
+
+	isSwapAccountingEnabled = defaultIsSwapAccountingEnabled
 )
 
 func (agent *ecsAgent) appendVolumeDriverCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	// "local" is default docker driver
-	capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilityDockerPluginInfix+volume.DockerLocalVolumeDriver)
+	localDriverAttribute := attributePrefix + capabilityDockerPluginInfix + volume.DockerLocalVolumeDriver
+	capabilities = appendNameOnlyAttribute(capabilities, localDriverAttribute)
+	capabilities = appendVolumeDriverScopeAttribute(capabilities, localDriverAttribute, volumeDriverScopeLocal)
+	// empty host volumes are provisioned as task scoped "local" volumes shared by the task's containers
+	capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilitySharedScratch)
 
 	// for non-standardized plugins, call docker pkg's plugins.Scan()
 	nonStandardizedPlugins, err := agent.mobyPlugins.Scan()
@@ -72,8 +89,10 @@ func (agent *ecsAgent) appendVolumeDriverCapabilities(capabilities []*ecs.Attrib
 
 	for _, pluginName := range nonStandardizedPlugins {
 		// Replace the ':' to '.' in the plugin name for attributes
-		capabilities = appendNameOnlyAttribute(capabilities,
-			attributePrefix+capabilityDockerPluginInfix+strings.Replace(pluginName, config.DockerTagSeparator, attributeSeparator, -1))
+		driverAttribute := attributePrefix + capabilityDockerPluginInfix +
+			strings.Replace(pluginName, config.DockerTagSeparator, attributeSeparator, -1)
+		capabilities = appendNameOnlyAttribute(capabilities, driverAttribute)
+		capabilities = agent.appendVolumePluginScopeAttributes(capabilities, pluginName, driverAttribute)
 	}
 
 	// for standardized plugins, call docker's plugin ls API
@@ -88,13 +107,18 @@ func (agent *ecsAgent) appendVolumeDriverCapabilities(capabilities []*ecs.Attrib
 	// For plugin with default tag latest, register two attributes with and without the latest tag
 	// as the tag is optional and can be added by docker or customer
 	for _, pluginName := range standardizedPlugins {
+		var driverAttributes []string
 		names := strings.Split(pluginName, config.DockerTagSeparator)
 		if len(names) > 1 && names[len(names)-1] == config.DefaultDockerTag {
-			capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilityDockerPluginInfix+strings.Join(names[:len(names)-1], attributeSeparator))
+			driverAttributes = append(driverAttributes, attributePrefix+capabilityDockerPluginInfix+strings.Join(names[:len(names)-1], attributeSeparator))
 		}
-
-		capabilities = appendNameOnlyAttribute(capabilities,
+		driverAttributes = append(driverAttributes,
 			attributePrefix+capabilityDockerPluginInfix+strings.Replace(pluginName, config.DockerTagSeparator, attributeSeparator, -1))
+
+		for _, driverAttribute := range driverAttributes {
+			capabilities = appendNameOnlyAttribute(capabilities, driverAttribute)
+		}
+		capabilities = agent.appendVolumePluginScopeAttributes(capabilities, pluginName, driverAttributes...)
 	}
 	return capabilities
 }
@@ -113,6 +137,23 @@ func (agent *ecsAgent) appendNvidiaDriverVersionAttribute(capabilities []*ecs.At
 	return capabilities
 }
 
+// appendGPUCapabilities advertises that GPUs can be scheduled on the instance along with the number of
+// NVIDIA devices found by the GPU manager. The attributes aren't advertised when no GPUs were found.
+func (agent *ecsAgent) appendGPUCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if agent.resourceFields == nil || agent.resourceFields.NvidiaGPUManager == nil {
+		return capabilities
+	}
+	devices := agent.resourceFields.NvidiaGPUManager.GetDevices()
+	if len(devices) == 0 {
+		return capabilities
+	}
+	capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilityGPU)
+	return append(capabilities, &ecs.Attribute{
+		Name:  aws.String(attributePrefix + capabilityGPUCount),
+		Value: aws.String(strconv.Itoa(len(devices))),
+	})
+}
+
 func (agent *ecsAgent) appendENITrunkingCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	if !agent.cfg.ENITrunkingEnabled.Enabled() {
 		return capabilities
@@ -122,7 +163,7 @@ func (agent *ecsAgent) appendENITrunkingCapabilities(capabilities []*ecs.Attribu
 }
 
 func (agent *ecsAgent) appendBranchENIPluginVersionAttribute(capabilities []*ecs.Attribute) []*ecs.Attribute {
-	version, err := agent.cniClient.Version(ecscni.ECSBranchENIPluginName)
+	version, err := agent.getCNIPluginVersion(ecscni.ECSBranchENIPluginName)
 	if err != nil {
 		seelog.Warnf(
 			"Unable to determine the version of the plugin '%s': %v",
@@ -145,7 +186,18 @@ func (agent *ecsAgent) appendPIDAndIPCNamespaceSharingCapabilities(capabilities
 	return appendNameOnlyAttribute(capabilities, attributePrefix+capabiltyPIDAndIPCNamespaceSharing)
 }
 
+// appendAppMeshCapabilities advertises that tasks can use an APPMESH proxy configuration, which is only the
+// case when the aws-appmesh plugin is present. The plugin requires awsvpc, so the capability isn't advertised
+// when task ENIs are disabled.
 func (agent *ecsAgent) appendAppMeshCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !agent.cfg.TaskENIEnabled.Enabled() {
+		return capabilities
+	}
+	if _, err := agent.getCNIPluginVersion(ecscni.ECSAppMeshPluginName); err != nil {
+		seelog.Warnf("Unable to determine the version of the plugin '%s', not advertising %s: %v",
+			ecscni.ECSAppMeshPluginName, appMeshAttributeSuffix, err)
+		return capabilities
+	}
 	return appendNameOnlyAttribute(capabilities, attributePrefix+appMeshAttributeSuffix)
 }
 
@@ -204,6 +256,173 @@ func (agent *ecsAgent) appendFirelensConfigCapabilities(capabilities []*ecs.Attr
 	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityFirelensConfigS3)
 }
 
+func (agent *ecsAgent) appendFirelensOTLPCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !firelens.OTLPOutputSupported(firelens.FirelensConfigTypeFluentbit) {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityFirelensOTLP)
+}
+
+func (agent *ecsAgent) appendFirelensMultiOutputCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !firelens.MultipleOutputsSupported(firelens.FirelensConfigTypeFluentbit) {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityFirelensMultiOutput)
+}
+
+func (agent *ecsAgent) appendLogDeadLetterCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !firelens.DeadLetterSupported(firelens.FirelensConfigTypeFluentbit) {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityLogDeadLetter)
+}
+
+// appendSecretEnvFileASMCapability advertises support for delivering secrets from AWS Secrets Manager
+// as files, which are bind mounted read-only through the Mounts field of the container's HostConfig
+// added in docker API 1.25.
+func (agent *ecsAgent) appendSecretEnvFileASMCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	if _, ok := supportedVersions[dockerclient.Version_1_25]; !ok {
+		seelog.Warn("Secrets Manager secrets as files are not supported by the Docker version. API version 1.25 or greater is required.")
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilitySecretEnvFileASM)
+}
+
+// appendTmpfsCapability advertises support for tmpfs mounts, which are set through the Tmpfs field of
+// the container's HostConfig added in docker API 1.22.
+func (agent *ecsAgent) appendTmpfsCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	if _, ok := supportedVersions[dockerclient.Version_1_22]; !ok {
+		seelog.Warn("Tmpfs mounts are not supported by the Docker version. API version 1.22 or greater is required.")
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityTmpfs)
+}
+
+// appendTaskLevelUlimitsCapability advertises that task level ulimits are applied to the containers of
+// the task, which requires the Ulimits field of the container's HostConfig added in docker API 1.18.
+func (agent *ecsAgent) appendTaskLevelUlimitsCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	if _, ok := supportedVersions[dockerclient.Version_1_18]; !ok {
+		seelog.Warn("Task level ulimits are not supported by the Docker version. API version 1.18 or greater is required.")
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityTaskLevelUlimits)
+}
+
+// appendContainerResizeCapability advertises that the CPU and memory limits of a running container can be
+// updated in place through the v3 container resources endpoint, which relies on the container update API
+// accepting memory and CPU changes since docker API 1.29.
+func (agent *ecsAgent) appendContainerResizeCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	if _, ok := supportedVersions[dockerclient.Version_1_29]; !ok {
+		seelog.Warn("Container resize is not supported by the Docker version. API version 1.29 or greater is required.")
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityContainerResize)
+}
+
+// appendTaskDNSCapability advertises that the DNS servers, search domains and options of a task are applied
+// to its containers. They're set through the DNS fields of the container's HostConfig in bridge mode and of
+// the pause container's HostConfig in awsvpc mode, which both need the DnsOptions field added in docker API 1.21.
+func (agent *ecsAgent) appendTaskDNSCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	if _, ok := supportedVersions[dockerclient.Version_1_21]; !ok {
+		seelog.Warn("Task DNS is not supported by the Docker version. API version 1.21 or greater is required.")
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityTaskDNS)
+}
+
+// appendContainerSwapCapability advertises that the swap limit and swappiness of a container are applied,
+// which requires the MemorySwappiness field of the container's HostConfig added in docker API 1.20 and a
+// kernel that accounts for swap usage. Docker silently discards the swap limit otherwise.
+func (agent *ecsAgent) appendContainerSwapCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	if _, ok := supportedVersions[dockerclient.Version_1_20]; !ok {
+		seelog.Warn("Container swap is not supported by the Docker version. API version 1.20 or greater is required.")
+		return capabilities
+	}
+	if !isSwapAccountingEnabled() {
+		seelog.Warn("Swap accounting is disabled in the kernel, not advertising container swap capability.")
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityContainerSwap)
+}
+
+// defaultIsSwapAccountingEnabled checks the cgroup file that shows whether the kernel accounts for swap usage.
+func defaultIsSwapAccountingEnabled() bool {
+	if config.CgroupV2 {
+		return swapAccountingEnabledFromControllers(cgroupV2ControllersPath)
+	}
+	return swapAccountingEnabledFromMemsw(swapAccountingCgroupV1Path)
+}
+
+// swapAccountingEnabledFromMemsw returns whether the cgroup v1 memsw limit file can be read.
+func swapAccountingEnabledFromMemsw(path string) bool {
+	_, err := os.ReadFile(path)
+	return err == nil
+}
+
+// swapAccountingEnabledFromControllers returns whether the memory controller is listed in the given
+// cgroup v2 controllers file.
+func swapAccountingEnabledFromControllers(path string) bool {
+	controllers, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	for _, controller := range strings.Fields(string(controllers)) {
+		if controller == "memory" {
+			return true
+		}
+	}
+	return false
+}
+
+// appendLogEndpointReloadCapability advertises that the awslogs endpoint can be reloaded on SIGHUP
+// and applied to new containers without restarting running tasks.
+func (agent *ecsAgent) appendLogEndpointReloadCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityLogEndpointReload)
+}
+
+// appendContainerInitCustomCapability advertises that containers enabling an init process can run the
+// custom init binary configured on the instance.
+func (agent *ecsAgent) appendContainerInitCustomCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if agent.cfg.ContainerInitPath == "" {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityContainerInitCustom)
+}
+
+// appendAppArmorNamedProfileCapability advertises that containers referring to an AppArmor profile by
+// name are verified to use a profile loaded on the instance, which requires the list of loaded profiles.
+func (agent *ecsAgent) appendAppArmorNamedProfileCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if !agent.cfg.AppArmorCapable.Enabled() {
+		return capabilities
+	}
+	if exists, err := pathExists(utils.AppArmorProfilesFilePath, false); err != nil || !exists {
+		seelog.Warnf("Unable to find loaded apparmor profiles at %s, not advertising %s: %v",
+			utils.AppArmorProfilesFilePath, capabilityAppArmorNamedProfile, err)
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityAppArmorNamedProfile)
+}
+
+func (agent *ecsAgent) appendWindowsNamedPipeVolumeCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+// appendLogRateLimitCapability advertises that logs sent through a firelens log router are rate limited
+// per container.
+func (agent *ecsAgent) appendLogRateLimitCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	if agent.cfg.LogRateLimit <= 0 {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityLogRateLimit)
+}
+
 func (agent *ecsAgent) appendIPv6Capability(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	return appendNameOnlyAttribute(capabilities, attributePrefix+taskENIIPv6AttributeSuffix)
 }
@@ -219,7 +438,7 @@ func (agent *ecsAgent) appendFSxWindowsFileServerCapabilities(capabilities []*ec
 // doesn't contribute to placement decisions and just serves as additional
 // debugging information
 func (agent *ecsAgent) getTaskENIPluginVersionAttribute() (*ecs.Attribute, error) {
-	version, err := agent.cniClient.Version(ecscni.VPCENIPluginName)
+	version, err := agent.getCNIPluginVersion(ecscni.VPCENIPluginName)
 	if err != nil {
 		seelog.Warnf(
 			"Unable to determine the version of the plugin '%s': %v",
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	app_mocks "github.com/aws/amazon-ecs-agent/agent/app/mocks"
@@ -33,6 +34,7 @@ import (
 	mock_daemonmanager "github.com/aws/amazon-ecs-agent/agent/engine/daemonmanager/mock"
 	mock_serviceconnect "github.com/aws/amazon-ecs-agent/agent/engine/serviceconnect/mock"
 	"github.com/aws/amazon-ecs-agent/agent/gpu"
+	mock_gpu "github.com/aws/amazon-ecs-agent/agent/gpu/mocks"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource"
 	"github.com/aws/amazon-ecs-agent/agent/utils"
 	mock_loader "github.com/aws/amazon-ecs-agent/agent/utils/loader/mocks"
@@ -41,8 +43,10 @@ import (
 	md "github.com/aws/amazon-ecs-agent/ecs-agent/manageddaemon"
 	"github.com/aws/aws-sdk-go/aws"
 	aws_credentials "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/docker/docker/api/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -84,18 +88,22 @@ func TestVolumeDriverCapabilitiesUnix(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-			dockerclient.Version_1_18,
-			dockerclient.Version_1_19,
-		}),
-		cniClient.EXPECT().Version(ecscni.VPCENIPluginName).Return("v1", nil),
-		mockMobyPlugins.EXPECT().Scan().Return([]string{"fancyvolumedriver"}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).Return(
-			[]string{"coolvolumedriver", "volumedriver:latest"}, nil),
-	)
+	cniClient.EXPECT().Version(ecscni.ECSAppMeshPluginName).Return("v1", nil)
+	cniClient.EXPECT().Version(ecscni.VPCENIPluginName).Return("v1", nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+		dockerclient.Version_1_18,
+		dockerclient.Version_1_19,
+	})
+	mockMobyPlugins.EXPECT().Scan().Return([]string{"fancyvolumedriver"}, nil)
+	mockMobyPlugins.EXPECT().VolumeDriverScope("fancyvolumedriver").Return("global", nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).Return(
+		[]string{"coolvolumedriver", "volumedriver:latest"}, nil)
+	mockMobyPlugins.EXPECT().VolumeDriverScope("coolvolumedriver").Return("local", nil)
+	mockMobyPlugins.EXPECT().VolumeDriverScope("volumedriver:latest").Return("", errors.New("plugin not found"))
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "privileged-container",
@@ -105,12 +113,16 @@ func TestVolumeDriverCapabilitiesUnix(t *testing.T) {
 		capabilityPrefix + "logging-driver.syslog",
 		capabilityPrefix + "logging-driver.journald",
 		capabilityPrefix + "selinux",
+		attributePrefix + capabilitySELinuxRelabel,
 		capabilityPrefix + "apparmor",
 		attributePrefix + "docker-plugin.local",
 		attributePrefix + "docker-plugin.fancyvolumedriver",
 		attributePrefix + "docker-plugin.coolvolumedriver",
 		attributePrefix + "docker-plugin.volumedriver",
 		attributePrefix + "docker-plugin.volumedriver.latest",
+		attributePrefix + "docker-plugin.local.scope.local",
+		attributePrefix + "docker-plugin.fancyvolumedriver.scope.global",
+		attributePrefix + "docker-plugin.coolvolumedriver.scope.local",
 		attributePrefix + taskENIBlockInstanceMetadataAttributeSuffix,
 	}
 
@@ -150,6 +162,11 @@ func TestVolumeDriverCapabilitiesUnix(t *testing.T) {
 			Value: expected.Value,
 		})
 	}
+	// No scope is advertised for a plugin whose scope can't be determined
+	for _, capability := range capabilities {
+		assert.False(t, strings.HasPrefix(aws.StringValue(capability.Name), attributePrefix+"docker-plugin.volumedriver.scope"))
+		assert.False(t, strings.HasPrefix(aws.StringValue(capability.Name), attributePrefix+"docker-plugin.volumedriver.latest.scope"))
+	}
 }
 
 func TestNvidiaDriverCapabilitiesUnix(t *testing.T) {
@@ -175,14 +192,15 @@ func TestNvidiaDriverCapabilitiesUnix(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	nvidiaDriverVersion := "396.44"
 
@@ -258,14 +276,15 @@ func TestEmptyNvidiaDriverCapabilitiesUnix(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "docker-remote-api.1.17",
@@ -310,6 +329,44 @@ func TestEmptyNvidiaDriverCapabilitiesUnix(t *testing.T) {
 	}
 }
 
+func TestAppendGPUCapabilities(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		devices              []*ecs.PlatformDevice
+		expectedCapabilities []*ecs.Attribute
+	}{
+		{
+			name: "no gpus",
+		},
+		{
+			name: "gpus found",
+			devices: []*ecs.PlatformDevice{
+				{Id: aws.String("gpu1"), Type: aws.String(ecs.PlatformDeviceTypeGpu)},
+				{Id: aws.String("gpu2"), Type: aws.String(ecs.PlatformDeviceTypeGpu)},
+			},
+			expectedCapabilities: []*ecs.Attribute{
+				{Name: aws.String(attributePrefix + capabilityGPU)},
+				{Name: aws.String(attributePrefix + capabilityGPUCount), Value: aws.String("2")},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			gpuManager := mock_gpu.NewMockGPUManager(ctrl)
+			gpuManager.EXPECT().GetDevices().Return(tc.devices)
+			agent := &ecsAgent{
+				resourceFields: &taskresource.ResourceFields{
+					NvidiaGPUManager: gpuManager,
+				},
+			}
+			assert.Equal(t, tc.expectedCapabilities, agent.appendGPUCapabilities(nil))
+		})
+	}
+}
+
 func TestENITrunkingCapabilitiesUnix(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -335,16 +392,17 @@ func TestENITrunkingCapabilitiesUnix(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		cniClient.EXPECT().Version(ecscni.VPCENIPluginName).Return("v1", nil),
-		cniClient.EXPECT().Version(ecscni.ECSBranchENIPluginName).Return("v2", nil),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	cniClient.EXPECT().Version(ecscni.ECSAppMeshPluginName).Return("v1", nil)
+	cniClient.EXPECT().Version(ecscni.VPCENIPluginName).Return("v1", nil)
+	cniClient.EXPECT().Version(ecscni.ECSBranchENIPluginName).Return("v2", nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "docker-remote-api.1.17",
@@ -427,15 +485,16 @@ func TestNoENITrunkingCapabilitiesUnix(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		cniClient.EXPECT().Version(ecscni.VPCENIPluginName).Return("v1", nil),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	cniClient.EXPECT().Version(ecscni.ECSAppMeshPluginName).Return("v1", nil)
+	cniClient.EXPECT().Version(ecscni.VPCENIPluginName).Return("v1", nil)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "docker-remote-api.1.17",
@@ -507,14 +566,15 @@ func TestPIDAndIPCNamespaceSharingCapabilitiesUnix(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "docker-remote-api.1.17",
@@ -528,9 +588,11 @@ func TestPIDAndIPCNamespaceSharingCapabilitiesUnix(t *testing.T) {
 		attributePrefix + capabilityContainerOrdering,
 		attributePrefix + capabilityFullTaskSync,
 		attributePrefix + capabilityEnvFilesS3,
+		attributePrefix + capabilityEnvFilesSSM,
 		attributePrefix + capabiltyPIDAndIPCNamespaceSharing,
 		attributePrefix + capabilityContainerPortRange,
 		attributePrefix + capabilityContainerRestartPolicy,
+		attributePrefix + capabilityContainerRestartPolicyJitter,
 	}
 
 	var expectedCapabilities []*ecs.Attribute
@@ -584,14 +646,15 @@ func TestPIDAndIPCNamespaceSharingCapabilitiesNoPauseContainer(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "docker-remote-api.1.17",
@@ -605,6 +668,7 @@ func TestPIDAndIPCNamespaceSharingCapabilitiesNoPauseContainer(t *testing.T) {
 		attributePrefix + capabilityContainerOrdering,
 		attributePrefix + capabilityFullTaskSync,
 		attributePrefix + capabilityEnvFilesS3,
+		attributePrefix + capabilityEnvFilesSSM,
 	}
 
 	var expectedCapabilities []*ecs.Attribute
@@ -637,82 +701,67 @@ func TestPIDAndIPCNamespaceSharingCapabilitiesNoPauseContainer(t *testing.T) {
 }
 
 func TestAppMeshCapabilitiesUnix(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	client := mock_dockerapi.NewMockDockerClient(ctrl)
-	mockMobyPlugins := mock_mobypkgwrapper.NewMockPlugins(ctrl)
-	mockCredentialsProvider := app_mocks.NewMockProvider(ctrl)
-	mockPauseLoader := mock_loader.NewMockLoader(ctrl)
-	conf := &config.Config{
-		PrivilegedDisabled: config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled},
-	}
-
-	mockPauseLoader.EXPECT().IsLoaded(gomock.Any()).Return(true, nil)
-	mockServiceConnectManager := mock_serviceconnect.NewMockManager(ctrl)
-	mockServiceConnectManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
-	mockServiceConnectManager.EXPECT().GetLoadedAppnetVersion().AnyTimes()
-	mockServiceConnectManager.EXPECT().GetCapabilitiesForAppnetInterfaceVersion("").AnyTimes()
-
-	mockDaemonManager := mock_daemonmanager.NewMockDaemonManager(ctrl)
-	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
-	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
-
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
-
-	expectedCapabilityNames := []string{
-		capabilityPrefix + "docker-remote-api.1.17",
-		attributePrefix + "docker-plugin.local",
-		attributePrefix + capabilityPrivateRegistryAuthASM,
-		attributePrefix + capabilitySecretEnvSSM,
-		attributePrefix + capabilitySecretLogDriverSSM,
-		attributePrefix + capabilityECREndpoint,
-		attributePrefix + capabilitySecretEnvASM,
-		attributePrefix + capabilitySecretLogDriverASM,
-		attributePrefix + capabilityContainerOrdering,
-		attributePrefix + capabilityFullTaskSync,
-		attributePrefix + capabilityEnvFilesS3,
-		attributePrefix + capabiltyPIDAndIPCNamespaceSharing,
-		attributePrefix + appMeshAttributeSuffix,
-		attributePrefix + capabilityContainerPortRange,
-		attributePrefix + capabilityContainerRestartPolicy,
-	}
-
-	var expectedCapabilities []*ecs.Attribute
-	for _, name := range expectedCapabilityNames {
-		expectedCapabilities = append(expectedCapabilities,
-			&ecs.Attribute{Name: aws.String(name)})
+	testCases := []struct {
+		name             string
+		taskENIEnabled   bool
+		pluginVersionErr error
+		expectVersion    bool
+		expectCapability bool
+	}{
+		{
+			name:             "plugin present",
+			taskENIEnabled:   true,
+			expectVersion:    true,
+			expectCapability: true,
+		},
+		{
+			name:             "plugin missing",
+			taskENIEnabled:   true,
+			pluginVersionErr: errors.New("no such file or directory"),
+			expectVersion:    true,
+		},
+		{
+			name: "task eni disabled",
+		},
 	}
 
-	ctx, cancel := context.WithCancel(context.TODO())
-	// Cancel the context to cancel async routines
-	defer cancel()
-	agent := &ecsAgent{
-		ctx:                   ctx,
-		cfg:                   conf,
-		dockerClient:          client,
-		pauseLoader:           mockPauseLoader,
-		credentialProvider:    aws_credentials.NewCredentials(mockCredentialsProvider),
-		mobyPlugins:           mockMobyPlugins,
-		serviceconnectManager: mockServiceConnectManager,
-		daemonManagers:        mockDaemonManagers,
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cniClient := mock_ecscni.NewMockCNIClient(ctrl)
+			if tc.expectVersion {
+				expectAppMeshPluginVersion(cniClient, "v1", tc.pluginVersionErr)
+			}
+
+			conf := &config.Config{}
+			if tc.taskENIEnabled {
+				conf.TaskENIEnabled = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+			}
+			agent := &ecsAgent{
+				ctx:       context.Background(),
+				cfg:       conf,
+				cniClient: cniClient,
+			}
+
+			capabilities := agent.appendAppMeshCapabilities(nil)
+			if tc.expectCapability {
+				assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + appMeshAttributeSuffix)}},
+					capabilities)
+			} else {
+				assert.Empty(t, capabilities)
+			}
+		})
 	}
-	capabilities, err := agent.capabilities()
-	assert.NoError(t, err)
+}
 
-	for _, expected := range expectedCapabilities {
-		assert.Contains(t, capabilities, &ecs.Attribute{
-			Name:  expected.Name,
-			Value: expected.Value,
-		})
+// expectAppMeshPluginVersion sets up the version query of the aws-appmesh plugin, which is only made on linux.
+func expectAppMeshPluginVersion(cniClient *mock_ecscni.MockCNIClient, version string, err error) {
+	times := 1
+	if err != nil {
+		times = cniPluginVersionAttempts
 	}
+	cniClient.EXPECT().Version(ecscni.ECSAppMeshPluginName).Return(version, err).Times(times)
 }
 
 func TestTaskEIACapabilitiesNoOptimizedCPU(t *testing.T) {
@@ -742,14 +791,15 @@ func TestTaskEIACapabilitiesNoOptimizedCPU(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	ctx, cancel := context.WithCancel(context.TODO())
 	// Cancel the context to cancel async routines
@@ -798,14 +848,15 @@ func TestTaskEIACapabilitiesWithOptimizedCPU(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	ctx, cancel := context.WithCancel(context.TODO())
 	// Cancel the context to cancel async routines
@@ -851,14 +902,15 @@ func TestCapabilitiesUnix(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "docker-remote-api.1.17",
@@ -871,7 +923,6 @@ func TestCapabilitiesUnix(t *testing.T) {
 		attributePrefix + capabilitySecretLogDriverASM,
 		attributePrefix + capabilityContainerOrdering,
 		attributePrefix + capabiltyPIDAndIPCNamespaceSharing,
-		attributePrefix + appMeshAttributeSuffix,
 		attributePrefix + taskEIAAttributeSuffix,
 		attributePrefix + capabilityFirelensFluentd,
 		attributePrefix + capabilityFirelensFluentbit,
@@ -879,9 +930,14 @@ func TestCapabilitiesUnix(t *testing.T) {
 		attributePrefix + capabilityEFSAuth,
 		capabilityPrefix + capabilityFirelensLoggingDriver,
 		attributePrefix + capabilityFirelensLoggingDriver + capabilityFireLensLoggingDriverConfigBufferLimitSuffix,
+		attributePrefix + capabilityFirelensOTLP,
+		attributePrefix + capabilityFirelensMultiOutput,
+		attributePrefix + capabilityLogDeadLetter,
 		attributePrefix + capabilityEnvFilesS3,
+		attributePrefix + capabilityEnvFilesSSM,
 		attributePrefix + capabilityContainerPortRange,
 		attributePrefix + capabilityContainerRestartPolicy,
+		attributePrefix + capabilityContainerRestartPolicyJitter,
 	}
 
 	var expectedCapabilities []*ecs.Attribute
@@ -911,6 +967,8 @@ func TestCapabilitiesUnix(t *testing.T) {
 			Value: expected.Value,
 		})
 	}
+	// aws-appmesh requires awsvpc, which isn't enabled
+	assert.NotContains(t, capabilities, &ecs.Attribute{Name: aws.String(attributePrefix + appMeshAttributeSuffix)})
 }
 
 func TestFirelensConfigCapabilitiesUnix(t *testing.T) {
@@ -934,14 +992,15 @@ func TestFirelensConfigCapabilitiesUnix(t *testing.T) {
 	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-		}),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+	})
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 
 	ctx, cancel := context.WithCancel(context.TODO())
 	// Cancel the context to cancel async routines
@@ -960,9 +1019,107 @@ func TestFirelensConfigCapabilitiesUnix(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.Contains(t, capabilities, &ecs.Attribute{Name: aws.String(attributePrefix + capabilityFirelensConfigFile)})
+	// s3 config files are fetched the same way as s3 environment files, so both are advertised together
+	assert.Contains(t, capabilities, &ecs.Attribute{Name: aws.String(attributePrefix + capabilityEnvFilesS3)})
+	assert.Contains(t, capabilities, &ecs.Attribute{Name: aws.String(attributePrefix + capabilityEnvFilesSSM)})
 	assert.Contains(t, capabilities, &ecs.Attribute{Name: aws.String(attributePrefix + capabilityFirelensConfigS3)})
 }
 
+func TestAppendFirelensOTLPCapabilities(t *testing.T) {
+	agent := &ecsAgent{}
+
+	capabilities := agent.appendFirelensOTLPCapabilities(nil)
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityFirelensOTLP)}}, capabilities)
+}
+
+func TestAppendFirelensMultiOutputCapabilities(t *testing.T) {
+	agent := &ecsAgent{}
+
+	capabilities := agent.appendFirelensMultiOutputCapabilities(nil)
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityFirelensMultiOutput)}}, capabilities)
+}
+
+func TestAppendLogDeadLetterCapabilities(t *testing.T) {
+	agent := &ecsAgent{}
+
+	capabilities := agent.appendLogDeadLetterCapabilities(nil)
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityLogDeadLetter)}}, capabilities)
+}
+
+func TestAppendLogEndpointReloadCapability(t *testing.T) {
+	agent := &ecsAgent{}
+
+	capabilities := agent.appendLogEndpointReloadCapability(nil)
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityLogEndpointReload)}}, capabilities)
+}
+
+func TestAppendContainerInitCustomCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{},
+	}
+	assert.Empty(t, agent.appendContainerInitCustomCapability(nil))
+
+	agent.cfg.ContainerInitPath = "/usr/local/bin/tini"
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityContainerInitCustom)}},
+		agent.appendContainerInitCustomCapability(nil))
+}
+
+func TestAppendAppArmorNamedProfileCapability(t *testing.T) {
+	defer mockPathExists(false)
+	testCases := []struct {
+		name             string
+		appArmorCapable  config.Conditional
+		profilesLoaded   bool
+		expectCapability bool
+	}{
+		{
+			name:             "apparmor capable with loaded profiles",
+			appArmorCapable:  config.ExplicitlyEnabled,
+			profilesLoaded:   true,
+			expectCapability: true,
+		},
+		{
+			name:            "apparmor capable without loaded profiles",
+			appArmorCapable: config.ExplicitlyEnabled,
+			profilesLoaded:  false,
+		},
+		{
+			name:            "apparmor not capable",
+			appArmorCapable: config.ExplicitlyDisabled,
+			profilesLoaded:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPathExists(tc.profilesLoaded)
+			agent := &ecsAgent{
+				cfg: &config.Config{
+					AppArmorCapable: config.BooleanDefaultFalse{Value: tc.appArmorCapable},
+				},
+			}
+			capabilities := agent.appendAppArmorNamedProfileCapability(nil)
+			if tc.expectCapability {
+				assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityAppArmorNamedProfile)}},
+					capabilities)
+			} else {
+				assert.Empty(t, capabilities)
+			}
+		})
+	}
+}
+
+func TestAppendLogRateLimitCapability(t *testing.T) {
+	agent := &ecsAgent{
+		cfg: &config.Config{},
+	}
+	assert.Empty(t, agent.appendLogRateLimitCapability(nil))
+
+	agent.cfg.LogRateLimit = 1000
+	assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityLogRateLimit)}},
+		agent.appendLogRateLimitCapability(nil))
+}
+
 func TestAppendFSxWindowsFileServerCapabilities(t *testing.T) {
 	var inputCapabilities []*ecs.Attribute
 
@@ -972,3 +1129,297 @@ func TestAppendFSxWindowsFileServerCapabilities(t *testing.T) {
 	assert.Equal(t, len(inputCapabilities), len(capabilities))
 	assert.EqualValues(t, capabilities, inputCapabilities)
 }
+
+func TestCapabilitiesDeduplicated(t *testing.T) {
+	cfg := getCapabilitiesTestConfig()
+	// Injects the efsAuth attribute twice
+	cfg.VolumePluginCapabilities = []string{capabilityEFSAuth, capabilityEFSAuth}
+	capabilities := getCapabilitiesWithConfig(cfg, t)
+
+	names := make(map[string]struct{})
+	for _, capability := range capabilities {
+		name := aws.StringValue(capability.Name)
+		assert.NotContains(t, names, name, "duplicate capability %s", name)
+		names[name] = struct{}{}
+	}
+	assert.Contains(t, names, attributePrefix+capabilityEFSAuth)
+}
+
+// capabilitiesForDockerVersions returns the names of the capabilities computed for a default config when
+// the docker daemon supports the given API versions.
+func capabilitiesForDockerVersions(t *testing.T, versionList []dockerclient.DockerVersion) map[string]bool {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	conf := &config.Config{}
+
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+	mockMobyPlugins := mock_mobypkgwrapper.NewMockPlugins(ctrl)
+	mockPauseLoader := mock_loader.NewMockLoader(ctrl)
+	mockPauseLoader.EXPECT().IsLoaded(gomock.Any()).Return(false, nil).AnyTimes()
+	mockServiceConnectManager := mock_serviceconnect.NewMockManager(ctrl)
+	mockServiceConnectManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
+	mockServiceConnectManager.EXPECT().GetLoadedAppnetVersion().AnyTimes()
+	mockServiceConnectManager.EXPECT().GetCapabilitiesForAppnetInterfaceVersion("").AnyTimes()
+
+	mockDaemonManager := mock_daemonmanager.NewMockDaemonManager(ctrl)
+	mockDaemonManagers := map[string]dm.DaemonManager{md.EbsCsiDriver: mockDaemonManager}
+	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return(versionList)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	client.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
+	ctx, cancel := context.WithCancel(context.TODO())
+	// Cancel the context to cancel async routines
+	defer cancel()
+	agent := &ecsAgent{
+		ctx:                   ctx,
+		cfg:                   conf,
+		dockerClient:          client,
+		pauseLoader:           mockPauseLoader,
+		mobyPlugins:           mockMobyPlugins,
+		serviceconnectManager: mockServiceConnectManager,
+		daemonManagers:        mockDaemonManagers,
+	}
+
+	capabilities, err := agent.capabilities()
+	require.NoError(t, err)
+
+	capMap := make(map[string]bool)
+	for _, capability := range capabilities {
+		capMap[aws.StringValue(capability.Name)] = true
+	}
+	return capMap
+}
+
+func TestCapabilitiesTmpfs(t *testing.T) {
+	testCases := []struct {
+		name             string
+		versionList      []dockerclient.DockerVersion
+		expectCapability bool
+	}{
+		{
+			name:             "supported docker version",
+			versionList:      []dockerclient.DockerVersion{dockerclient.Version_1_19, dockerclient.Version_1_22},
+			expectCapability: true,
+		},
+		{
+			name:             "unsupported docker version",
+			versionList:      []dockerclient.DockerVersion{dockerclient.Version_1_19},
+			expectCapability: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			capMap := capabilitiesForDockerVersions(t, tc.versionList)
+			assert.Equal(t, tc.expectCapability, capMap[attributePrefix+capabilityTmpfs],
+				"Docker 1.22 is required for tmpfs mounts")
+		})
+	}
+}
+
+func TestCapabilitiesSecretEnvFileASM(t *testing.T) {
+	testCases := []struct {
+		name             string
+		versionList      []dockerclient.DockerVersion
+		expectCapability bool
+	}{
+		{
+			name:             "supported docker version",
+			versionList:      []dockerclient.DockerVersion{dockerclient.Version_1_19, dockerclient.Version_1_25},
+			expectCapability: true,
+		},
+		{
+			name:             "unsupported docker version",
+			versionList:      []dockerclient.DockerVersion{dockerclient.Version_1_19, dockerclient.Version_1_24},
+			expectCapability: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			capMap := capabilitiesForDockerVersions(t, tc.versionList)
+			assert.True(t, capMap[attributePrefix+capabilitySecretEnvASM])
+			assert.Equal(t, tc.expectCapability, capMap[attributePrefix+capabilitySecretEnvFileASM],
+				"Docker 1.25 is required for secrets mounted as files")
+		})
+	}
+}
+
+func TestCapabilitiesTaskLevelUlimits(t *testing.T) {
+	testCases := []struct {
+		name             string
+		versionList      []dockerclient.DockerVersion
+		expectCapability bool
+	}{
+		{
+			name:             "supported docker version",
+			versionList:      []dockerclient.DockerVersion{dockerclient.Version_1_17, dockerclient.Version_1_18},
+			expectCapability: true,
+		},
+		{
+			name:             "unsupported docker version",
+			versionList:      []dockerclient.DockerVersion{dockerclient.Version_1_17},
+			expectCapability: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			capMap := capabilitiesForDockerVersions(t, tc.versionList)
+			assert.Equal(t, tc.expectCapability, capMap[attributePrefix+capabilityTaskLevelUlimits],
+				"Docker 1.18 is required for ulimits")
+		})
+	}
+}
+
+func TestAppendTaskDNSCapability(t *testing.T) {
+	testCases := []struct {
+		name             string
+		versions         map[dockerclient.DockerVersion]bool
+		taskENIEnabled   bool
+		expectCapability bool
+	}{
+		{
+			name:             "supported docker version in bridge mode",
+			versions:         map[dockerclient.DockerVersion]bool{dockerclient.Version_1_20: true, dockerclient.Version_1_21: true},
+			expectCapability: true,
+		},
+		{
+			name:             "supported docker version with task ENI enabled",
+			versions:         map[dockerclient.DockerVersion]bool{dockerclient.Version_1_20: true, dockerclient.Version_1_21: true},
+			taskENIEnabled:   true,
+			expectCapability: true,
+		},
+		{
+			name:             "unsupported docker version in bridge mode",
+			versions:         map[dockerclient.DockerVersion]bool{dockerclient.Version_1_20: true},
+			expectCapability: false,
+		},
+		{
+			name:             "unsupported docker version with task ENI enabled",
+			versions:         map[dockerclient.DockerVersion]bool{dockerclient.Version_1_20: true},
+			taskENIEnabled:   true,
+			expectCapability: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			agent := &ecsAgent{
+				cfg: &config.Config{
+					TaskENIEnabled: config.BooleanDefaultFalse{Value: config.ExplicitlyDisabled},
+				},
+			}
+			if tc.taskENIEnabled {
+				agent.cfg.TaskENIEnabled = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+			}
+
+			capabilities := agent.appendTaskDNSCapability(nil, tc.versions)
+			if tc.expectCapability {
+				assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityTaskDNS)}}, capabilities)
+			} else {
+				assert.Empty(t, capabilities, "Docker 1.21 is required for task DNS")
+			}
+		})
+	}
+}
+
+func TestAppendContainerSwapCapability(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		versions              map[dockerclient.DockerVersion]bool
+		swapAccountingEnabled bool
+		expectCapability      bool
+	}{
+		{
+			name:                  "swap accounting enabled",
+			versions:              map[dockerclient.DockerVersion]bool{dockerclient.Version_1_20: true},
+			swapAccountingEnabled: true,
+			expectCapability:      true,
+		},
+		{
+			name:                  "swap accounting disabled",
+			versions:              map[dockerclient.DockerVersion]bool{dockerclient.Version_1_20: true},
+			swapAccountingEnabled: false,
+			expectCapability:      false,
+		},
+		{
+			name:                  "unsupported docker version",
+			versions:              map[dockerclient.DockerVersion]bool{dockerclient.Version_1_19: true},
+			swapAccountingEnabled: true,
+			expectCapability:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			isSwapAccountingEnabled = func() bool { return tc.swapAccountingEnabled }
+			defer func() {
+				isSwapAccountingEnabled = defaultIsSwapAccountingEnabled
+			}()
+
+			agent := &ecsAgent{cfg: &config.Config{}}
+			capabilities := agent.appendContainerSwapCapability(nil, tc.versions)
+			if tc.expectCapability {
+				assert.Equal(t, []*ecs.Attribute{{Name: aws.String(attributePrefix + capabilityContainerSwap)}}, capabilities)
+			} else {
+				assert.Empty(t, capabilities)
+			}
+		})
+	}
+}
+
+func TestSwapAccountingEnabledFromMemsw(t *testing.T) {
+	dir := t.TempDir()
+	memswPath := filepath.Join(dir, "memory.memsw.limit_in_bytes")
+
+	assert.False(t, swapAccountingEnabledFromMemsw(memswPath), "memsw file is absent when swap accounting is disabled")
+
+	require.NoError(t, os.WriteFile(memswPath, []byte("9223372036854771712\n"), 0644))
+	assert.True(t, swapAccountingEnabledFromMemsw(memswPath))
+}
+
+func TestSwapAccountingEnabledFromControllers(t *testing.T) {
+	dir := t.TempDir()
+	controllersPath := filepath.Join(dir, "cgroup.controllers")
+
+	assert.False(t, swapAccountingEnabledFromControllers(controllersPath))
+
+	require.NoError(t, os.WriteFile(controllersPath, []byte("cpuset cpu io pids\n"), 0644))
+	assert.False(t, swapAccountingEnabledFromControllers(controllersPath))
+
+	require.NoError(t, os.WriteFile(controllersPath, []byte("cpuset cpu io memory pids\n"), 0644))
+	assert.True(t, swapAccountingEnabledFromControllers(controllersPath))
+}
+
+func TestCapabilitiesContainerResize(t *testing.T) {
+	testCases := []struct {
+		name             string
+		versionList      []dockerclient.DockerVersion
+		expectCapability bool
+	}{
+		{
+			name:             "supported docker version",
+			versionList:      []dockerclient.DockerVersion{dockerclient.Version_1_28, dockerclient.Version_1_29},
+			expectCapability: true,
+		},
+		{
+			name:             "unsupported docker version",
+			versionList:      []dockerclient.DockerVersion{dockerclient.Version_1_28},
+			expectCapability: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			capMap := capabilitiesForDockerVersions(t, tc.versionList)
+			assert.Equal(t, tc.expectCapability, capMap[attributePrefix+capabilityContainerResize],
+				"Docker 1.29 is required for container resize")
+		})
+	}
+}
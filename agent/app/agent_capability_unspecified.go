@@ -39,7 +39,11 @@ var (
 
 func (agent *ecsAgent) appendVolumeDriverCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	// "local" is default docker driver
-	capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilityDockerPluginInfix+volume.DockerLocalVolumeDriver)
+	localDriverAttribute := attributePrefix + capabilityDockerPluginInfix + volume.DockerLocalVolumeDriver
+	capabilities = appendNameOnlyAttribute(capabilities, localDriverAttribute)
+	capabilities = appendVolumeDriverScopeAttribute(capabilities, localDriverAttribute, volumeDriverScopeLocal)
+	// empty host volumes are provisioned as task scoped "local" volumes shared by the task's containers
+	capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilitySharedScratch)
 
 	// for non-standardized plugins, call docker pkg's plugins.Scan()
 	nonStandardizedPlugins, err := agent.mobyPlugins.Scan()
@@ -50,8 +54,10 @@ func (agent *ecsAgent) appendVolumeDriverCapabilities(capabilities []*ecs.Attrib
 
 	for _, pluginName := range nonStandardizedPlugins {
 		// Replace the ':' to '.' in the plugin name for attributes
-		capabilities = appendNameOnlyAttribute(capabilities,
-			attributePrefix+capabilityDockerPluginInfix+strings.Replace(pluginName, config.DockerTagSeparator, attributeSeparator, -1))
+		driverAttribute := attributePrefix + capabilityDockerPluginInfix +
+			strings.Replace(pluginName, config.DockerTagSeparator, attributeSeparator, -1)
+		capabilities = appendNameOnlyAttribute(capabilities, driverAttribute)
+		capabilities = agent.appendVolumePluginScopeAttributes(capabilities, pluginName, driverAttribute)
 	}
 
 	// for standardized plugins, call docker's plugin ls API
@@ -66,13 +72,18 @@ func (agent *ecsAgent) appendVolumeDriverCapabilities(capabilities []*ecs.Attrib
 	// For plugin with default tag latest, register two attributes with and without the latest tag
 	// as the tag is optional and can be added by docker or customer
 	for _, pluginName := range standardizedPlugins {
+		var driverAttributes []string
 		names := strings.Split(pluginName, config.DockerTagSeparator)
 		if len(names) > 1 && names[len(names)-1] == config.DefaultDockerTag {
-			capabilities = appendNameOnlyAttribute(capabilities, attributePrefix+capabilityDockerPluginInfix+strings.Join(names[:len(names)-1], attributeSeparator))
+			driverAttributes = append(driverAttributes, attributePrefix+capabilityDockerPluginInfix+strings.Join(names[:len(names)-1], attributeSeparator))
 		}
-
-		capabilities = appendNameOnlyAttribute(capabilities,
+		driverAttributes = append(driverAttributes,
 			attributePrefix+capabilityDockerPluginInfix+strings.Replace(pluginName, config.DockerTagSeparator, attributeSeparator, -1))
+
+		for _, driverAttribute := range driverAttributes {
+			capabilities = appendNameOnlyAttribute(capabilities, driverAttribute)
+		}
+		capabilities = agent.appendVolumePluginScopeAttributes(capabilities, pluginName, driverAttributes...)
 	}
 	return capabilities
 }
@@ -81,6 +92,10 @@ func (agent *ecsAgent) appendNvidiaDriverVersionAttribute(capabilities []*ecs.At
 	return capabilities
 }
 
+func (agent *ecsAgent) appendGPUCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
 func (agent *ecsAgent) appendENITrunkingCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	return capabilities
 }
@@ -121,10 +136,73 @@ func (agent *ecsAgent) appendFirelensConfigCapabilities(capabilities []*ecs.Attr
 	return capabilities
 }
 
+func (agent *ecsAgent) appendFirelensOTLPCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendFirelensMultiOutputCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendLogDeadLetterCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
 func (agent *ecsAgent) appendGMSACapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	return capabilities
 }
 
+func (agent *ecsAgent) appendLogEndpointReloadCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendContainerInitCustomCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendAppArmorNamedProfileCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendSecretEnvFileASMCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendTmpfsCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendTaskLevelUlimitsCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendContainerResizeCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendTaskDNSCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendContainerSwapCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendWindowsNamedPipeVolumeCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendLogRateLimitCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
 func (agent *ecsAgent) appendEFSVolumePluginCapabilities(capabilities []*ecs.Attribute, pluginCapability string) []*ecs.Attribute {
 	return capabilities
 }
@@ -18,8 +18,10 @@ package app
 
 import (
 	"path/filepath"
+	"runtime"
 
 	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
 	"github.com/aws/amazon-ecs-agent/agent/ecscni"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/volume"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs/model/ecs"
@@ -53,13 +55,21 @@ var (
 
 func (agent *ecsAgent) appendVolumeDriverCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	// "local" is default docker driver
-	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityDockerPluginInfix+volume.DockerLocalVolumeDriver)
+	localDriverAttribute := attributePrefix + capabilityDockerPluginInfix + volume.DockerLocalVolumeDriver
+	capabilities = appendNameOnlyAttribute(capabilities, localDriverAttribute)
+	capabilities = appendVolumeDriverScopeAttribute(capabilities, localDriverAttribute, volumeDriverScopeLocal)
+	// empty host volumes are provisioned as task scoped "local" volumes shared by the task's containers
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilitySharedScratch)
 }
 
 func (agent *ecsAgent) appendNvidiaDriverVersionAttribute(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	return capabilities
 }
 
+func (agent *ecsAgent) appendGPUCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
 func (agent *ecsAgent) appendENITrunkingCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
 	return capabilities
 }
@@ -100,6 +110,77 @@ func (agent *ecsAgent) appendFirelensConfigCapabilities(capabilities []*ecs.Attr
 	return capabilities
 }
 
+func (agent *ecsAgent) appendFirelensOTLPCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendFirelensMultiOutputCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendLogDeadLetterCapabilities(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendLogEndpointReloadCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendContainerInitCustomCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendAppArmorNamedProfileCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendSecretEnvFileASMCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendTmpfsCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendTaskLevelUlimitsCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendContainerResizeCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendTaskDNSCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+func (agent *ecsAgent) appendContainerSwapCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	return capabilities
+}
+
+// appendWindowsNamedPipeVolumeCapability advertises support for mounting named pipes into
+// containers, which requires the npipe mount type added in docker API 1.30.
+func (agent *ecsAgent) appendWindowsNamedPipeVolumeCapability(capabilities []*ecs.Attribute,
+	supportedVersions map[dockerclient.DockerVersion]bool) []*ecs.Attribute {
+	if runtime.GOOS != "windows" {
+		return capabilities
+	}
+	if _, ok := supportedVersions[dockerclient.Version_1_30]; !ok {
+		return capabilities
+	}
+	return appendNameOnlyAttribute(capabilities, attributePrefix+capabilityWindowsNamedPipeVolume)
+}
+
+func (agent *ecsAgent) appendLogRateLimitCapability(capabilities []*ecs.Attribute) []*ecs.Attribute {
+	return capabilities
+}
+
 func (agent *ecsAgent) appendEFSVolumePluginCapabilities(capabilities []*ecs.Attribute, pluginCapability string) []*ecs.Attribute {
 	return capabilities
 }
@@ -120,7 +201,7 @@ func (agent *ecsAgent) appendFSxWindowsFileServerCapabilities(capabilities []*ec
 // CNI plugins. It just executes the vpc-eni plugin to get the Version information.
 // Currently, only this plugin is used by ECS Windows for awsvpc mode.
 func (agent *ecsAgent) getTaskENIPluginVersionAttribute() (*ecs.Attribute, error) {
-	version, err := agent.cniClient.Version(ecscni.ECSVPCENIPluginExecutable)
+	version, err := agent.getCNIPluginVersion(ecscni.ECSVPCENIPluginExecutable)
 	if err != nil {
 		seelog.Warnf(
 			"Unable to determine the version of the plugin '%s': %v",
@@ -33,6 +33,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	aws_credentials "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/docker/docker/api/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -65,14 +66,15 @@ func TestVolumeDriverCapabilitiesWindows(t *testing.T) {
 		TaskCleanupWaitDuration:    config.DefaultConfig().TaskCleanupWaitDuration,
 	}
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-			dockerclient.Version_1_18,
-			dockerclient.Version_1_19,
-		}),
-		cniClient.EXPECT().Version(ecscni.ECSVPCENIPluginExecutable).Return("v1", nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+		dockerclient.Version_1_18,
+		dockerclient.Version_1_19,
+	})
+	cniClient.EXPECT().Version(ecscni.ECSVPCENIPluginExecutable).Return("v1", nil)
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "privileged-container",
@@ -83,8 +85,10 @@ func TestVolumeDriverCapabilitiesWindows(t *testing.T) {
 		capabilityPrefix + "logging-driver.syslog",
 		capabilityPrefix + "logging-driver.journald",
 		capabilityPrefix + "selinux",
+		attributePrefix + capabilitySELinuxRelabel,
 		capabilityPrefix + "apparmor",
 		attributePrefix + "docker-plugin.local",
+		attributePrefix + "docker-plugin.local.scope.local",
 		attributePrefix + taskENIAttributeSuffix,
 		attributePrefix + taskENIBlockInstanceMetadataAttributeSuffix,
 	}
@@ -152,14 +156,15 @@ func TestSupportedCapabilitiesWindows(t *testing.T) {
 		TaskCleanupWaitDuration:    config.DefaultConfig().TaskCleanupWaitDuration,
 	}
 
-	gomock.InOrder(
-		client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
-			dockerclient.Version_1_17,
-			dockerclient.Version_1_18,
-			dockerclient.Version_1_19,
-		}),
-		cniClient.EXPECT().Version(ecscni.ECSVPCENIPluginExecutable).Return("v1", nil),
-	)
+	client.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	client.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	client.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{
+		dockerclient.Version_1_17,
+		dockerclient.Version_1_18,
+		dockerclient.Version_1_19,
+	})
+	cniClient.EXPECT().Version(ecscni.ECSVPCENIPluginExecutable).Return("v1", nil)
 
 	expectedCapabilityNames := []string{
 		capabilityPrefix + "privileged-container",
@@ -170,8 +175,10 @@ func TestSupportedCapabilitiesWindows(t *testing.T) {
 		capabilityPrefix + "logging-driver.syslog",
 		capabilityPrefix + "logging-driver.journald",
 		capabilityPrefix + "selinux",
+		attributePrefix + capabilitySELinuxRelabel,
 		capabilityPrefix + "apparmor",
 		attributePrefix + "docker-plugin.local",
+		attributePrefix + "docker-plugin.local.scope.local",
 		attributePrefix + taskENIAttributeSuffix,
 		attributePrefix + capabilityPrivateRegistryAuthASM,
 		attributePrefix + capabilitySecretEnvSSM,
@@ -182,9 +189,11 @@ func TestSupportedCapabilitiesWindows(t *testing.T) {
 		attributePrefix + capabilityContainerOrdering,
 		attributePrefix + capabilityFullTaskSync,
 		attributePrefix + capabilityEnvFilesS3,
+		attributePrefix + capabilityEnvFilesSSM,
 		attributePrefix + taskENIBlockInstanceMetadataAttributeSuffix,
 		attributePrefix + capabilityContainerPortRange,
 		attributePrefix + capabilityContainerRestartPolicy,
+		attributePrefix + capabilityContainerRestartPolicyJitter,
 	}
 
 	var expectedCapabilities []*ecs.Attribute
@@ -286,6 +295,24 @@ func TestAppendFSxWindowsFileServerCapabilitiesFalse(t *testing.T) {
 	assert.Equal(t, len(expectedCapabilities), len(capabilities))
 }
 
+func TestAppendWindowsNamedPipeVolumeCapability(t *testing.T) {
+	agent := &ecsAgent{cfg: &config.Config{}}
+
+	capabilities := agent.appendWindowsNamedPipeVolumeCapability(nil,
+		map[dockerclient.DockerVersion]bool{dockerclient.Version_1_30: true})
+	assert.Equal(t, []*ecs.Attribute{
+		{Name: aws.String(attributePrefix + capabilityWindowsNamedPipeVolume)},
+	}, capabilities)
+}
+
+func TestAppendWindowsNamedPipeVolumeCapabilityUnsupportedDockerVersion(t *testing.T) {
+	agent := &ecsAgent{cfg: &config.Config{}}
+
+	capabilities := agent.appendWindowsNamedPipeVolumeCapability(nil,
+		map[dockerclient.DockerVersion]bool{dockerclient.Version_1_29: true})
+	assert.Empty(t, capabilities)
+}
+
 func TestAppendExecCapabilities(t *testing.T) {
 	var inputCapabilities []*ecs.Attribute
 	var expectedCapabilities []*ecs.Attribute
@@ -330,10 +357,11 @@ func TestAppendExecCapabilities(t *testing.T) {
 				getSubDirectories = defaultGetSubDirectories
 			}()
 			agent := &ecsAgent{
-				cfg: &config.Config{},
+				cfg: &config.Config{ExecCapable: config.BooleanDefaultTrue{Value: config.NotSet}},
 			}
 
-			capabilities, err := agent.appendExecCapabilities(inputCapabilities)
+			capabilities, err := agent.appendExecCapabilities(inputCapabilities,
+				map[dockerclient.DockerVersion]bool{dockerclient.ExecAgentMinimumVersion: true})
 
 			assert.NoError(t, err)
 
@@ -345,3 +373,6 @@ func TestAppendExecCapabilities(t *testing.T) {
 		})
 	}
 }
+
+// expectAppMeshPluginVersion sets up the version query of the aws-appmesh plugin, which is only made on linux.
+func expectAppMeshPluginVersion(cniClient *mock_ecscni.MockCNIClient, version string, err error) {}
@@ -249,12 +249,16 @@ func TestDoStartRegisterContainerInstanceErrorTerminal(t *testing.T) {
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	dockerClient.EXPECT().SupportedVersions().Return(apiVersions).AnyTimes()
 
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	dockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil)
+	mockMobyPlugins.EXPECT().VolumeDriverScope(gomock.Any()).AnyTimes().Return(volumeDriverScopeLocal, nil)
+	dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		client.EXPECT().GetHostResources().Return(testHostResource, nil),
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil),
-		dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any()).Return("", "", apierrors.NewAttributeError("error")),
 	)
@@ -311,12 +315,16 @@ func TestDoStartRegisterContainerInstanceErrorNonTerminal(t *testing.T) {
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	dockerClient.EXPECT().SupportedVersions().Return(apiVersions).AnyTimes()
 
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	dockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil)
+	mockMobyPlugins.EXPECT().VolumeDriverScope(gomock.Any()).AnyTimes().Return(volumeDriverScopeLocal, nil)
+	dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		client.EXPECT().GetHostResources().Return(testHostResource, nil),
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil),
-		dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any()).Return("", "", errors.New("error")),
 	)
@@ -478,12 +486,16 @@ func testDoStartHappyPathWithConditions(t *testing.T, blackholed bool, warmPools
 	client.EXPECT().DiscoverTelemetryEndpoint(gomock.Any()).Return(
 		"tele-endpoint", nil).AnyTimes()
 
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	dockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil)
+	mockMobyPlugins.EXPECT().VolumeDriverScope(gomock.Any()).AnyTimes().Return(volumeDriverScopeLocal, nil)
+	dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		client.EXPECT().GetHostResources().Return(testHostResource, nil),
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil),
-		dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any()).Return(containerInstanceARN, availabilityZone, nil),
 		containermetadata.EXPECT().SetContainerInstanceARN(containerInstanceARN),
@@ -993,12 +1005,16 @@ func TestReregisterContainerInstanceHappyPath(t *testing.T) {
 	mockDaemonManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
+	mockDockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	mockDockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil)
+	mockMobyPlugins.EXPECT().VolumeDriverScope(gomock.Any()).AnyTimes().Return(volumeDriverScopeLocal, nil)
+	mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil),
-		mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
-			gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance(containerInstanceARN, gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any(), gomock.Any()).Return(containerInstanceARN, availabilityZone, nil),
 	)
@@ -1052,12 +1068,16 @@ func TestReregisterContainerInstanceInstanceTypeChanged(t *testing.T) {
 	mockDaemonManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
+	mockDockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	mockDockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil)
+	mockMobyPlugins.EXPECT().VolumeDriverScope(gomock.Any()).AnyTimes().Return(volumeDriverScopeLocal, nil)
+	mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{""}, nil),
-		mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
-			gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance(containerInstanceARN, gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any()).Return("", "", awserr.New("",
 			apierrors.InstanceTypeChangedErrorMessage, errors.New(""))),
@@ -1113,12 +1133,15 @@ func TestReregisterContainerInstanceAttributeError(t *testing.T) {
 	mockDaemonManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
+	mockDockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	mockDockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
-			gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance(containerInstanceARN, gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any()).Return("", "", apierrors.NewAttributeError("error")),
 	)
@@ -1172,12 +1195,15 @@ func TestReregisterContainerInstanceNonTerminalError(t *testing.T) {
 	mockDaemonManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
+	mockDockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	mockDockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
-			gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance(containerInstanceARN, gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any()).Return("", "", errors.New("error")),
 	)
@@ -1232,12 +1258,15 @@ func TestRegisterContainerInstanceWhenContainerInstanceARNIsNotSetHappyPath(t *t
 	mockDaemonManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
+	mockDockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	mockDockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
-			gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance("", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any()).Return(containerInstanceARN, availabilityZone, nil),
 	)
@@ -1290,12 +1319,14 @@ func TestRegisterContainerInstanceWhenContainerInstanceARNIsNotSetCanRetryError(
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	retriableError := apierrors.NewRetriableError(apierrors.NewRetriable(true), errors.New("error"))
+	mockDockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	mockDockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
-			gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance("", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any()).Return("", "", retriableError),
 	)
@@ -1348,12 +1379,14 @@ func TestRegisterContainerInstanceWhenContainerInstanceARNIsNotSetCannotRetryErr
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	cannotRetryError := apierrors.NewRetriableError(apierrors.NewRetriable(false), errors.New("error"))
+	mockDockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+	mockDockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
-			gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance("", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any()).Return("", "", cannotRetryError),
 	)
@@ -1405,12 +1438,15 @@ func TestRegisterContainerInstanceWhenContainerInstanceARNIsNotSetAttributeError
 	mockDaemonManager.EXPECT().IsLoaded(gomock.Any()).Return(true, nil).AnyTimes()
 	mockDaemonManager.EXPECT().LoadImage(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
+	mockDockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	mockDockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockDockerClient.EXPECT().SupportedVersions().Return(apiVersions),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		mockDockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(),
-			gomock.Any(), gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance("", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any()).Return("", "", apierrors.NewAttributeError("error")),
 	)
@@ -1466,12 +1502,15 @@ func TestRegisterContainerInstanceInvalidParameterTerminalError(t *testing.T) {
 
 	dockerClient.EXPECT().SupportedVersions().Return(apiVersions).AnyTimes()
 
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	dockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil)
+	dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).AnyTimes().Return([]string{}, nil)
 	gomock.InOrder(
 		client.EXPECT().GetHostResources().Return(testHostResource, nil),
 		mockCredentialsProvider.EXPECT().Retrieve().Return(aws_credentials.Value{}, nil),
-		mockMobyPlugins.EXPECT().Scan().AnyTimes().Return([]string{}, nil),
-		dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).AnyTimes().Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any()).Return("", "", awserr.New("InvalidParameterException", "", nil)),
 	)
@@ -1901,3 +1940,42 @@ func TestLoadManagedDaemonImage(t *testing.T) {
 		})
 	}
 }
+
+func TestPrePullImage(t *testing.T) {
+	tcs := []struct {
+		name                        string
+		pullError                   apierrors.NamedError
+		setImageManagerExpectations func(*mock_engine.MockImageManager)
+	}{
+		{
+			name:      "no exclusion list update if image pull fails",
+			pullError: dockerapi.CannotPullContainerError{FromError: errors.New("error")},
+		},
+		{
+			name: "exclusion list is updated if image pull succeeds",
+			setImageManagerExpectations: func(mim *mock_engine.MockImageManager) {
+				mim.EXPECT().AddImageToCleanUpExclusionList("busybox:1.36")
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+			imageManager := mock_engine.NewMockImageManager(ctrl)
+
+			cfg := getTestConfig()
+			dockerClient.EXPECT().
+				PullImage(gomock.Any(), "busybox:1.36", nil, cfg.ImagePullTimeout).
+				Return(dockerapi.DockerContainerMetadata{Error: tc.pullError})
+			if tc.setImageManagerExpectations != nil {
+				tc.setImageManagerExpectations(imageManager)
+			}
+
+			agent := &ecsAgent{ctx: context.Background(), cfg: &cfg, dockerClient: dockerClient}
+			agent.prePullImage("busybox:1.36", imageManager)
+		})
+	}
+}
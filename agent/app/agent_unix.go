@@ -19,6 +19,7 @@ package app
 import (
 	"os"
 
+	appconfigfactory "github.com/aws/amazon-ecs-agent/agent/appconfig/factory"
 	asmfactory "github.com/aws/amazon-ecs-agent/agent/asm/factory"
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
@@ -170,12 +171,13 @@ func (agent *ecsAgent) initializeResourceFields(credentialsManager credentials.M
 	agent.resourceFields = &taskresource.ResourceFields{
 		Control: cgroup.New(),
 		ResourceFieldsCommon: &taskresource.ResourceFieldsCommon{
-			IOUtil:             ioutilwrapper.NewIOUtil(),
-			ASMClientCreator:   asmfactory.NewClientCreator(),
-			SSMClientCreator:   ssmfactory.NewSSMClientCreator(),
-			S3ClientCreator:    s3factory.NewS3ClientCreator(),
-			CredentialsManager: credentialsManager,
-			EC2InstanceID:      agent.getEC2InstanceID(),
+			IOUtil:                 ioutilwrapper.NewIOUtil(),
+			ASMClientCreator:       asmfactory.NewClientCreator(),
+			SSMClientCreator:       ssmfactory.NewSSMClientCreator(),
+			AppConfigClientCreator: appconfigfactory.NewAppConfigClientCreator(),
+			S3ClientCreator:        s3factory.NewS3ClientCreator(),
+			CredentialsManager:     credentialsManager,
+			EC2InstanceID:          agent.getEC2InstanceID(),
 		},
 		Ctx:              agent.ctx,
 		DockerClient:     agent.dockerClient,
@@ -26,6 +26,7 @@ import (
 	app_mocks "github.com/aws/amazon-ecs-agent/agent/app/mocks"
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/data"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
 	"github.com/aws/amazon-ecs-agent/agent/ecscni"
 	mock_ecscni "github.com/aws/amazon-ecs-agent/agent/ecscni/mocks"
@@ -50,6 +51,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/docker/docker/api/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -126,6 +128,14 @@ func TestDoStartTaskENIHappyPath(t *testing.T) {
 	mockUdevMonitor.EXPECT().Monitor(gomock.Any()).Return(monitoShutdownEvents).AnyTimes()
 	client.EXPECT().GetHostResources().Return(testHostResource, nil).Times(1)
 
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	dockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	cniClient.EXPECT().Version(ecscni.VPCENIPluginName).Return("v1", nil)
+	cniClient.EXPECT().Version(ecscni.ECSBranchENIPluginName).Return("v2", nil)
+	mockMobyPlugins.EXPECT().Scan().Return([]string{}, nil)
+	dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).Return([]string{}, nil)
 	gomock.InOrder(
 		mockMetadata.EXPECT().PrimaryENIMAC().Return(mac, nil),
 		mockMetadata.EXPECT().VPCID(mac).Return(vpcID, nil),
@@ -136,11 +146,7 @@ func TestDoStartTaskENIHappyPath(t *testing.T) {
 		cniClient.EXPECT().Capabilities(ecscni.ECSAppMeshPluginName).Return(cniCapabilities, nil),
 		cniClient.EXPECT().Capabilities(ecscni.ECSBranchENIPluginName).Return(cniCapabilities, nil),
 		mockCredentialsProvider.EXPECT().Retrieve().Return(credentials.Value{}, nil),
-		cniClient.EXPECT().Version(ecscni.VPCENIPluginName).Return("v1", nil),
-		cniClient.EXPECT().Version(ecscni.ECSBranchENIPluginName).Return("v2", nil),
-		mockMobyPlugins.EXPECT().Scan().Return([]string{}, nil),
-		dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).Return([]string{}, nil),
+		cniClient.EXPECT().Version(ecscni.ECSAppMeshPluginName).Return("v1", nil),
 		client.EXPECT().RegisterContainerInstance(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any()).Do(
 			func(x interface{}, attributes []*ecs.Attribute, y interface{}, z interface{}, w interface{},
@@ -477,12 +483,15 @@ func TestDoStartCgroupInitHappyPath(t *testing.T) {
 	imageManager.EXPECT().AddImageToCleanUpExclusionList(gomock.Eq("service_connect_agent:v1")).Times(1)
 	client.EXPECT().GetHostResources().Return(testHostResource, nil).Times(1)
 
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	dockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockMobyPlugins.EXPECT().Scan().Return([]string{}, nil)
+	dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).Return([]string{}, nil)
 	gomock.InOrder(
 		mockControl.EXPECT().Init().Return(nil),
 		mockCredentialsProvider.EXPECT().Retrieve().Return(credentials.Value{}, nil),
-		mockMobyPlugins.EXPECT().Scan().Return([]string{}, nil),
-		dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).Return([]string{}, nil),
 		client.EXPECT().RegisterContainerInstance(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			gomock.Any(), gomock.Any()).Return("arn", "", nil),
 		imageManager.EXPECT().SetDataClient(gomock.Any()),
@@ -655,12 +664,15 @@ func TestDoStartGPUManagerHappyPath(t *testing.T) {
 	client.EXPECT().GetHostResources().Return(testHostResource, nil).Times(1)
 	mockGPUManager.EXPECT().GetDevices().Return(devices).AnyTimes()
 
+	dockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes().Return(types.Info{}, nil)
+
+	dockerClient.EXPECT().APIVersion().AnyTimes().Return(dockerclient.Version_1_41, nil)
+	mockMobyPlugins.EXPECT().Scan().Return([]string{}, nil)
+	dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any()).Return([]string{}, nil)
 	gomock.InOrder(
 		mockGPUManager.EXPECT().Initialize().Return(nil),
 		mockCredentialsProvider.EXPECT().Retrieve().Return(credentials.Value{}, nil),
-		mockMobyPlugins.EXPECT().Scan().Return([]string{}, nil),
-		dockerClient.EXPECT().ListPluginsWithFilters(gomock.Any(), gomock.Any(), gomock.Any(),
-			gomock.Any()).Return([]string{}, nil),
 		mockGPUManager.EXPECT().GetDriverVersion().Return("396.44"),
 		mockGPUManager.EXPECT().GetDevices().Return(devices).AnyTimes(),
 		client.EXPECT().RegisterContainerInstance(gomock.Any(), gomock.Any(), gomock.Any(),
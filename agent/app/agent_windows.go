@@ -22,6 +22,7 @@ import (
 	"sync"
 	"time"
 
+	appconfigfactory "github.com/aws/amazon-ecs-agent/agent/appconfig/factory"
 	asmfactory "github.com/aws/amazon-ecs-agent/agent/asm/factory"
 	"github.com/aws/amazon-ecs-agent/agent/data"
 	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
@@ -313,11 +314,12 @@ func (t *termHandlerIndicator) wait() uint32 {
 func (agent *ecsAgent) initializeResourceFields(credentialsManager credentials.Manager) {
 	agent.resourceFields = &taskresource.ResourceFields{
 		ResourceFieldsCommon: &taskresource.ResourceFieldsCommon{
-			ASMClientCreator:   asmfactory.NewClientCreator(),
-			SSMClientCreator:   ssmfactory.NewSSMClientCreator(),
-			FSxClientCreator:   fsxfactory.NewFSxClientCreator(),
-			S3ClientCreator:    s3factory.NewS3ClientCreator(),
-			CredentialsManager: credentialsManager,
+			ASMClientCreator:       asmfactory.NewClientCreator(),
+			SSMClientCreator:       ssmfactory.NewSSMClientCreator(),
+			AppConfigClientCreator: appconfigfactory.NewAppConfigClientCreator(),
+			FSxClientCreator:       fsxfactory.NewFSxClientCreator(),
+			S3ClientCreator:        s3factory.NewS3ClientCreator(),
+			CredentialsManager:     credentialsManager,
 		},
 		Ctx:          agent.ctx,
 		DockerClient: agent.dockerClient,
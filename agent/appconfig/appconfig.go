@@ -0,0 +1,68 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package appconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/appconfigdata"
+	"github.com/pkg/errors"
+)
+
+// GetConfiguration returns the content of the latest deployed AppConfig configuration referenced by
+// valueFrom, in the form <application>/<environment>/<configuration profile>. Each part can be either
+// the name or the ID of the AppConfig entity.
+func GetConfiguration(valueFrom string, client AppConfigClient) (string, error) {
+	application, environment, profile, err := ParseConfigurationReference(valueFrom)
+	if err != nil {
+		return "", err
+	}
+
+	session, err := client.StartConfigurationSession(&appconfigdata.StartConfigurationSessionInput{
+		ApplicationIdentifier:          aws.String(application),
+		EnvironmentIdentifier:          aws.String(environment),
+		ConfigurationProfileIdentifier: aws.String(profile),
+	})
+	if err != nil {
+		return "", err
+	}
+	if session == nil || aws.StringValue(session.InitialConfigurationToken) == "" {
+		return "", errors.New("empty configuration session")
+	}
+
+	out, err := client.GetLatestConfiguration(&appconfigdata.GetLatestConfigurationInput{
+		ConfigurationToken: session.InitialConfigurationToken,
+	})
+	if err != nil {
+		return "", err
+	}
+	if out == nil {
+		return "", errors.New("empty response")
+	}
+	return string(out.Configuration), nil
+}
+
+// ParseConfigurationReference splits an AppConfig configuration reference of the form
+// <application>/<environment>/<configuration profile> into its parts.
+func ParseConfigurationReference(valueFrom string) (application, environment, profile string, err error) {
+	parts := strings.Split(valueFrom, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf(
+			"invalid AppConfig configuration reference %q, expected <application>/<environment>/<configuration profile>",
+			valueFrom)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
@@ -0,0 +1,125 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package appconfig
+
+import (
+	"errors"
+	"testing"
+
+	mock_appconfig "github.com/aws/amazon-ecs-agent/agent/appconfig/mocks"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/appconfigdata"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testValueFrom = "my-app/prod/flags"
+	testToken     = "token"
+)
+
+func TestGetConfiguration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock_appconfig.NewMockAppConfigClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().StartConfigurationSession(&appconfigdata.StartConfigurationSessionInput{
+			ApplicationIdentifier:          aws.String("my-app"),
+			EnvironmentIdentifier:          aws.String("prod"),
+			ConfigurationProfileIdentifier: aws.String("flags"),
+		}).Return(&appconfigdata.StartConfigurationSessionOutput{
+			InitialConfigurationToken: aws.String(testToken),
+		}, nil),
+		client.EXPECT().GetLatestConfiguration(&appconfigdata.GetLatestConfigurationInput{
+			ConfigurationToken: aws.String(testToken),
+		}).Return(&appconfigdata.GetLatestConfigurationOutput{
+			Configuration: []byte(`{"feature":true}`),
+		}, nil),
+	)
+
+	configuration, err := GetConfiguration(testValueFrom, client)
+	require.NoError(t, err)
+	assert.Equal(t, `{"feature":true}`, configuration)
+}
+
+func TestGetConfigurationErrors(t *testing.T) {
+	cases := []struct {
+		name      string
+		valueFrom string
+		setup     func(client *mock_appconfig.MockAppConfigClient)
+	}{
+		{
+			name:      "invalid reference",
+			valueFrom: "my-app/prod",
+		},
+		{
+			name:      "session error",
+			valueFrom: testValueFrom,
+			setup: func(client *mock_appconfig.MockAppConfigClient) {
+				client.EXPECT().StartConfigurationSession(gomock.Any()).Return(nil, errors.New("error"))
+			},
+		},
+		{
+			name:      "empty session",
+			valueFrom: testValueFrom,
+			setup: func(client *mock_appconfig.MockAppConfigClient) {
+				client.EXPECT().StartConfigurationSession(gomock.Any()).
+					Return(&appconfigdata.StartConfigurationSessionOutput{}, nil)
+			},
+		},
+		{
+			name:      "configuration error",
+			valueFrom: testValueFrom,
+			setup: func(client *mock_appconfig.MockAppConfigClient) {
+				client.EXPECT().StartConfigurationSession(gomock.Any()).
+					Return(&appconfigdata.StartConfigurationSessionOutput{
+						InitialConfigurationToken: aws.String(testToken),
+					}, nil)
+				client.EXPECT().GetLatestConfiguration(gomock.Any()).Return(nil, errors.New("error"))
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mock_appconfig.NewMockAppConfigClient(ctrl)
+			if tc.setup != nil {
+				tc.setup(client)
+			}
+			_, err := GetConfiguration(tc.valueFrom, client)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseConfigurationReference(t *testing.T) {
+	application, environment, profile, err := ParseConfigurationReference(testValueFrom)
+	require.NoError(t, err)
+	assert.Equal(t, "my-app", application)
+	assert.Equal(t, "prod", environment)
+	assert.Equal(t, "flags", profile)
+
+	for _, invalid := range []string{"", "my-app", "my-app/prod", "my-app//flags", "my-app/prod/flags/extra"} {
+		_, _, _, err := ParseConfigurationReference(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package factory
+
+import (
+	"time"
+
+	appconfigclient "github.com/aws/amazon-ecs-agent/agent/appconfig"
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	agentversion "github.com/aws/amazon-ecs-agent/agent/version"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/httpclient"
+	"github.com/aws/aws-sdk-go/aws"
+	awscreds "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/appconfigdata"
+)
+
+const (
+	roundtripTimeout = 5 * time.Second
+)
+
+type AppConfigClientCreator interface {
+	NewAppConfigClient(region string, creds credentials.IAMRoleCredentials) appconfigclient.AppConfigClient
+}
+
+func NewAppConfigClientCreator() AppConfigClientCreator {
+	return &appConfigClientCreator{}
+}
+
+type appConfigClientCreator struct{}
+
+func (*appConfigClientCreator) NewAppConfigClient(region string,
+	creds credentials.IAMRoleCredentials) appconfigclient.AppConfigClient {
+	cfg := aws.NewConfig().
+		WithHTTPClient(httpclient.New(roundtripTimeout, false, agentversion.String(), config.OSType)).
+		WithRegion(region).
+		WithCredentials(
+			awscreds.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey,
+				creds.SessionToken))
+	sess := session.Must(session.NewSession(cfg))
+	return appconfigdata.New(sess)
+}
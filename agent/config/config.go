@@ -62,8 +62,8 @@ const (
 	// This is only used when PollMetrics is set to true
 	DefaultPollingMetricsWaitDuration = DefaultContainerMetricsPublishInterval / 2
 
-	// defaultDockerStopTimeout specifies the value for container stop timeout duration
-	defaultDockerStopTimeout = 30 * time.Second
+	// DefaultDockerStopTimeout specifies the value for container stop timeout duration
+	DefaultDockerStopTimeout = 30 * time.Second
 
 	// DefaultImageCleanupTimeInterval specifies the default value for image cleanup duration. It is used to
 	// remove the images pulled by agent.
@@ -88,6 +88,10 @@ const (
 	//DefaultImagePullTimeout specifies the timeout for PullImage API.
 	DefaultImagePullTimeout = 2 * time.Hour
 
+	// DefaultTaskHealthGatingTimeout specifies how long a task with health gating enabled waits for its
+	// essential containers to become healthy before it is stopped
+	DefaultTaskHealthGatingTimeout = 10 * time.Minute
+
 	// minimumTaskCleanupWaitDuration specifies the minimum duration to wait before cleaning up
 	// a task's container. This is used to enforce sane values for the config.TaskCleanupWaitDuration field.
 	minimumTaskCleanupWaitDuration = time.Second
@@ -132,6 +136,10 @@ const (
 	// DefaultTaskMetadataBurstRate is set to handle 60 burst requests at once
 	DefaultTaskMetadataBurstRate = 60
 
+	// DefaultTMDSMaxConcurrentRequests is the default number of container metadata requests the
+	// task metadata server serves concurrently before rejecting new ones
+	DefaultTMDSMaxConcurrentRequests = 50
+
 	//Known cached image names
 	CachedImageNameAgentContainer = "amazon/amazon-ecs-agent:latest"
 
@@ -167,6 +175,15 @@ const (
 	ImagePullPreferCachedBehavior
 )
 
+const (
+	// MetadataFieldStyleDefault specifies that metadata responses keep their documented field names.
+	MetadataFieldStyleDefault MetadataFieldStyleType = iota
+
+	// MetadataFieldStyleSnake specifies that the field names of metadata responses are converted
+	// to snake_case.
+	MetadataFieldStyleSnake
+)
+
 const (
 	// When ContainerInstancePropagateTagsFromNoneType is specified, no DescribeTags
 	// API call will be made.
@@ -365,6 +382,26 @@ func (cfg *Config) validateAndOverrideBounds() error {
 		cfg.TaskMetadataBurstRate = DefaultTaskMetadataBurstRate
 	}
 
+	if cfg.ParallelTaskStopLimit < 0 {
+		seelog.Warnf("Invalid value for parallel task stop limit, will be overridden to not limit parallel stops. Parsed value: %d.", cfg.ParallelTaskStopLimit)
+		cfg.ParallelTaskStopLimit = 0
+	}
+
+	if cfg.MaxENIs < 0 {
+		seelog.Warnf("Invalid value for max ENIs, will be ignored. Parsed value: %d.", cfg.MaxENIs)
+		cfg.MaxENIs = 0
+	}
+
+	if cfg.LogRateLimit < 0 {
+		seelog.Warnf("Invalid value for log rate limit, will be overridden to not limit log rate. Parsed value: %d.", cfg.LogRateLimit)
+		cfg.LogRateLimit = 0
+	}
+
+	if cfg.TMDSMaxConcurrentRequests <= 0 {
+		seelog.Warnf("Invalid value for task metadata max concurrent requests, will be overridden with the default value: %d. Parsed value: %d.", DefaultTMDSMaxConcurrentRequests, cfg.TMDSMaxConcurrentRequests)
+		cfg.TMDSMaxConcurrentRequests = DefaultTMDSMaxConcurrentRequests
+	}
+
 	// check the PollMetrics specific configurations
 	cfg.pollMetricsOverrides()
 
@@ -475,6 +512,20 @@ func fileConfig() (Config, error) {
 	return cfg, nil
 }
 
+// LoadAWSLogsEndpoint re-reads the awslogs endpoint configuration. The
+// ECS_AWSLOGS_ENDPOINT environment variable takes precedence over the value
+// in the config file, matching the precedence used by NewConfig.
+func LoadAWSLogsEndpoint() (string, error) {
+	if endpoint := strings.TrimSpace(os.Getenv("ECS_AWSLOGS_ENDPOINT")); endpoint != "" {
+		return endpoint, nil
+	}
+	fcfg, err := fileConfig()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(fcfg.AWSLogsEndpoint), nil
+}
+
 // userDataConfig reads configuration JSON from instance's userdata. It doesn't
 // return any error as it's entirely optional to configure the ECS agent using
 // this method.
@@ -543,9 +594,12 @@ func environmentConfig() (Config, error) {
 		DisableMetrics:                      parseBooleanDefaultFalseConfig("ECS_DISABLE_METRICS"),
 		ReservedMemory:                      parseEnvVariableUint16("ECS_RESERVED_MEMORY"),
 		AvailableLoggingDrivers:             parseAvailableLoggingDrivers(),
+		AWSLogsEndpoint:                     os.Getenv("ECS_AWSLOGS_ENDPOINT"),
+		ContainerInitPath:                   os.Getenv("ECS_CONTAINER_INIT_PATH"),
 		PrivilegedDisabled:                  parseBooleanDefaultFalseConfig("ECS_DISABLE_PRIVILEGED"),
 		SELinuxCapable:                      parseBooleanDefaultFalseConfig("ECS_SELINUX_CAPABLE"),
 		AppArmorCapable:                     parseBooleanDefaultFalseConfig("ECS_APPARMOR_CAPABLE"),
+		AppConfigCapable:                    parseBooleanDefaultFalseConfig("ECS_APPCONFIG_CAPABLE"),
 		TaskCleanupWaitDuration:             parseEnvVariableDuration("ECS_ENGINE_TASK_CLEANUP_WAIT_DURATION"),
 		TaskCleanupWaitDurationJitter:       parseEnvVariableDuration("ECS_ENGINE_TASK_CLEANUP_WAIT_DURATION_JITTER"),
 		TaskENIEnabled:                      parseBooleanDefaultFalseConfig("ECS_ENABLE_TASK_ENI"),
@@ -553,12 +607,17 @@ func environmentConfig() (Config, error) {
 		DeleteNonECSImagesEnabled:           parseBooleanDefaultFalseConfig("ECS_ENABLE_UNTRACKED_IMAGE_CLEANUP"),
 		TaskCPUMemLimit:                     parseBooleanDefaultTrueConfig("ECS_ENABLE_TASK_CPU_MEM_LIMIT"),
 		DockerStopTimeout:                   parseDockerStopTimeout(),
+		StopSignalEscalation:                parseStopSignalEscalation(),
+		ParallelTaskStopLimit:               parseParallelTaskStopLimit(),
+		LogRateLimit:                        parseLogRateLimit(),
+		MaxENIs:                             parseMaxENIs(),
 		ManifestPullTimeout:                 parseManifestPullTimeout(),
 		ContainerStartTimeout:               parseContainerStartTimeout(),
 		ContainerCreateTimeout:              parseContainerCreateTimeout(),
 		DependentContainersPullUpfront:      parseBooleanDefaultFalseConfig("ECS_PULL_DEPENDENT_CONTAINERS_UPFRONT"),
 		ImagePullInactivityTimeout:          parseImagePullInactivityTimeout(),
 		ImagePullTimeout:                    parseEnvVariableDuration("ECS_IMAGE_PULL_TIMEOUT"),
+		RegistryClientCertsDir:              os.Getenv("ECS_REGISTRY_CLIENT_CERTS_DIR"),
 		CredentialsAuditLogFile:             os.Getenv("ECS_AUDIT_LOGFILE"),
 		CredentialsAuditLogDisabled:         utils.ParseBool(os.Getenv("ECS_AUDIT_LOGFILE_DISABLED"), false),
 		TaskIAMRoleEnabledForNetworkHost:    utils.ParseBool(os.Getenv("ECS_ENABLE_TASK_IAM_ROLE_NETWORK_HOST"), false),
@@ -570,6 +629,7 @@ func environmentConfig() (Config, error) {
 		NumNonECSContainersToDeletePerCycle: parseNumNonECSContainersToDeletePerCycle(),
 		ImagePullBehavior:                   parseImagePullBehavior(),
 		ImageCleanupExclusionList:           parseImageCleanupExclusionList("ECS_EXCLUDE_UNTRACKED_IMAGE"),
+		PrePullImages:                       parsePrePullImages(),
 		InstanceAttributes:                  instanceAttributes,
 		CNIPluginsPath:                      os.Getenv("ECS_CNI_PLUGINS_PATH"),
 		AWSVPCBlockInstanceMetdata:          parseBooleanDefaultFalseConfig("ECS_AWSVPC_BLOCK_IMDS"),
@@ -580,12 +640,19 @@ func environmentConfig() (Config, error) {
 		CgroupPath:                          os.Getenv("ECS_CGROUP_PATH"),
 		TaskMetadataSteadyStateRate:         steadyStateRate,
 		TaskMetadataBurstRate:               burstRate,
+		TMDSMaxConcurrentRequests:           parseTMDSMaxConcurrentRequests(),
+		TMDSSocketPath:                      os.Getenv("ECS_TMDS_SOCKET"),
+		TMDSAuthEnabled:                     parseBooleanDefaultFalseConfig("ECS_TMDS_AUTH_ENABLED"),
+		MetadataFieldStyle:                  parseMetadataFieldStyle(),
 		SharedVolumeMatchFullConfig:         parseBooleanDefaultFalseConfig("ECS_SHARED_VOLUME_MATCH_FULL_CONFIG"),
 		ContainerInstanceTags:               containerInstanceTags,
 		ContainerInstancePropagateTagsFrom:  parseContainerInstancePropagateTagsFrom(),
 		PollMetrics:                         parseBooleanDefaultFalseConfig("ECS_POLL_METRICS"),
 		PollingMetricsWaitDuration:          parseEnvVariableDuration("ECS_POLLING_METRICS_WAIT_DURATION"),
 		DisableDockerHealthCheck:            parseBooleanDefaultFalseConfig("ECS_DISABLE_DOCKER_HEALTH_CHECK"),
+		TaskHealthGatingEnabled:             parseBooleanDefaultFalseConfig("ECS_ENABLE_TASK_HEALTH_GATING"),
+		TaskHealthGatingTimeout:             parseEnvVariableDuration("ECS_TASK_HEALTH_GATING_TIMEOUT"),
+		LogTeeStdout:                        parseBooleanDefaultFalseConfig("ECS_LOG_TEE_STDOUT"),
 		GPUSupportEnabled:                   utils.ParseBool(os.Getenv("ECS_ENABLE_GPU_SUPPORT"), false),
 		EBSTASupportEnabled:                 utils.ParseBool(os.Getenv("ECS_EBSTA_SUPPORTED"), true),
 		InferentiaSupportEnabled:            utils.ParseBool(os.Getenv("ECS_ENABLE_INF_SUPPORT"), false),
@@ -596,10 +663,12 @@ func environmentConfig() (Config, error) {
 		GMSACapable:                         parseGMSACapability(),
 		GMSADomainlessCapable:               parseGMSADomainlessCapability(),
 		VolumePluginCapabilities:            parseVolumePluginCapabilities(),
+		BandwidthLimitCapable:               parseBandwidthLimitCapability(),
 		FSxWindowsFileServerCapable:         parseFSxWindowsFileServerCapability(),
 		External:                            parseBooleanDefaultFalseConfig("ECS_EXTERNAL"),
 		EnableRuntimeStats:                  parseBooleanDefaultFalseConfig("ECS_ENABLE_RUNTIME_STATS"),
 		ShouldExcludeIPv6PortBinding:        parseBooleanDefaultTrueConfig("ECS_EXCLUDE_IPV6_PORTBINDING"),
+		ExecCapable:                         parseBooleanDefaultTrueConfig("ECS_EXEC_CAPABLE"),
 		WarmPoolsSupport:                    parseBooleanDefaultFalseConfig("ECS_WARM_POOLS_CHECK"),
 		DynamicHostPortRange:                parseDynamicHostPortRange("ECS_DYNAMIC_HOST_PORT_RANGE"),
 		TaskPidsLimit:                       parseTaskPidsLimit(),
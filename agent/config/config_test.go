@@ -133,8 +133,11 @@ func TestEnvironmentConfig(t *testing.T) {
 	defer setTestEnv("ECS_CONTAINER_CREATE_TIMEOUT", "4m")()
 	defer setTestEnv("ECS_IMAGE_PULL_INACTIVITY_TIMEOUT", "10m")()
 	defer setTestEnv("ECS_AVAILABLE_LOGGING_DRIVERS", "[\""+string(dockerclient.SyslogDriver)+"\"]")()
+	defer setTestEnv("ECS_AWSLOGS_ENDPOINT", "https://logs.example.com")()
+	defer setTestEnv("ECS_CONTAINER_INIT_PATH", "/usr/local/bin/tini")()
 	defer setTestEnv("ECS_SELINUX_CAPABLE", "true")()
 	defer setTestEnv("ECS_APPARMOR_CAPABLE", "true")()
+	defer setTestEnv("ECS_APPCONFIG_CAPABLE", "true")()
 	defer setTestEnv("ECS_DISABLE_PRIVILEGED", "true")()
 	defer setTestEnv("ECS_ENGINE_TASK_CLEANUP_WAIT_DURATION", testTaskCleanupWaitDurationStr)()
 	defer setTestEnv("ECS_ENGINE_TASK_CLEANUP_WAIT_DURATION_JITTER", testTaskCleanupWaitDurationJitterStr)()
@@ -151,17 +154,22 @@ func TestEnvironmentConfig(t *testing.T) {
 	defer setTestEnv("ECS_CONTAINER_INSTANCE_TAGS", `{"my_tag": "testing"}`)()
 	defer setTestEnv("ECS_ENABLE_TASK_ENI", "true")()
 	defer setTestEnv("ECS_TASK_METADATA_RPS_LIMIT", "1000,1100")()
+	defer setTestEnv("ECS_TMDS_MAX_CONCURRENT_REQUESTS", "80")()
+	defer setTestEnv("ECS_TMDS_SOCKET", "/var/run/ecs/tmds.sock")()
+	defer setTestEnv("ECS_TMDS_AUTH_ENABLED", "true")()
 	defer setTestEnv("ECS_SHARED_VOLUME_MATCH_FULL_CONFIG", "true")()
 	defer setTestEnv("ECS_ENABLE_GPU_SUPPORT", "true")()
 	defer setTestEnv("ECS_DISABLE_TASK_METADATA_AZ", "true")()
 	defer setTestEnv("ECS_NVIDIA_RUNTIME", "nvidia")()
 	defer setTestEnv("ECS_POLL_METRICS", "true")()
 	defer setTestEnv("ECS_POLLING_METRICS_WAIT_DURATION", "10s")()
+	defer setTestEnv("ECS_TASK_HEALTH_GATING_TIMEOUT", "3m")()
 	defer setTestEnv("ECS_CGROUP_CPU_PERIOD", "")
 	defer setTestEnv("ECS_PULL_DEPENDENT_CONTAINERS_UPFRONT", "true")()
 	defer setTestEnv("ECS_ENABLE_RUNTIME_STATS", "true")()
 	defer setTestEnv("ECS_EXCLUDE_IPV6_PORTBINDING", "true")()
 	defer setTestEnv("ECS_WARM_POOLS_CHECK", "false")()
+	defer setTestEnv("ECS_EXEC_CAPABLE", "false")()
 	defer setTestEnv("ECS_DYNAMIC_HOST_PORT_RANGE", "200-300")()
 	additionalLocalRoutesJSON := `["1.2.3.4/22","5.6.7.8/32"]`
 	setTestEnv("ECS_AWSVPC_ADDITIONAL_LOCAL_ROUTES", additionalLocalRoutesJSON)
@@ -178,6 +186,7 @@ func TestEnvironmentConfig(t *testing.T) {
 	assert.Contains(t, conf.ReservedPortsUDP, uint16(99))
 	assert.Equal(t, uint16(20), conf.ReservedMemory)
 	assert.Equal(t, 85*time.Second, conf.ManifestPullTimeout)
+	assert.Equal(t, 3*time.Minute, conf.TaskHealthGatingTimeout)
 	expectedDurationDockerStopTimeout, _ := time.ParseDuration("60s")
 	assert.Equal(t, expectedDurationDockerStopTimeout, conf.DockerStopTimeout)
 	expectedDurationContainerStartTimeout, _ := time.ParseDuration("5m")
@@ -185,9 +194,12 @@ func TestEnvironmentConfig(t *testing.T) {
 	expectedDurationContainerCreateTimeout, _ := time.ParseDuration("4m")
 	assert.Equal(t, expectedDurationContainerCreateTimeout, conf.ContainerCreateTimeout)
 	assert.Equal(t, []dockerclient.LoggingDriver{dockerclient.SyslogDriver}, conf.AvailableLoggingDrivers)
+	assert.Equal(t, "https://logs.example.com", conf.AWSLogsEndpoint)
+	assert.Equal(t, "/usr/local/bin/tini", conf.ContainerInitPath)
 	assert.True(t, conf.PrivilegedDisabled.Enabled())
 	assert.True(t, conf.SELinuxCapable.Enabled(), "Wrong value for SELinuxCapable")
 	assert.True(t, conf.AppArmorCapable.Enabled(), "Wrong value for AppArmorCapable")
+	assert.True(t, conf.AppConfigCapable.Enabled(), "Wrong value for AppConfigCapable")
 	assert.True(t, conf.TaskIAMRoleEnabled.Enabled(), "Wrong value for TaskIAMRoleEnabled")
 	assert.Equal(t, ExplicitlyEnabled, conf.DeleteNonECSImagesEnabled.Value, "Wrong value for DeleteNonECSImagesEnabled")
 	assert.True(t, conf.TaskIAMRoleEnabledForNetworkHost, "Wrong value for TaskIAMRoleEnabledForNetworkHost")
@@ -212,6 +224,9 @@ func TestEnvironmentConfig(t *testing.T) {
 	assert.True(t, conf.ContainerMetadataEnabled.Enabled(), "Wrong value for ContainerMetadataEnabled")
 	assert.Equal(t, 1000, conf.TaskMetadataSteadyStateRate)
 	assert.Equal(t, 1100, conf.TaskMetadataBurstRate)
+	assert.Equal(t, 80, conf.TMDSMaxConcurrentRequests)
+	assert.Equal(t, "/var/run/ecs/tmds.sock", conf.TMDSSocketPath)
+	assert.True(t, conf.TMDSAuthEnabled.Enabled(), "Wrong value for TMDSAuthEnabled")
 	assert.True(t, conf.SharedVolumeMatchFullConfig.Enabled(), "Wrong value for SharedVolumeMatchFullConfig")
 	assert.True(t, conf.GPUSupportEnabled, "Wrong value for GPUSupportEnabled")
 	assert.Equal(t, "nvidia", conf.NvidiaRuntime)
@@ -223,6 +238,7 @@ func TestEnvironmentConfig(t *testing.T) {
 	assert.True(t, conf.EnableRuntimeStats.Enabled(), "Wrong value for EnableRuntimeStats")
 	assert.True(t, conf.ShouldExcludeIPv6PortBinding.Enabled(), "Wrong value for ShouldExcludeIPv6PortBinding")
 	assert.False(t, conf.WarmPoolsSupport.Enabled(), "Wrong value for WarmPoolsSupport")
+	assert.False(t, conf.ExecCapable.Enabled(), "Wrong value for ExecCapable")
 	assert.Equal(t, "200-300", conf.DynamicHostPortRange)
 }
 
@@ -253,11 +269,15 @@ func TestConfigBoolean(t *testing.T) {
 	defer setTestEnv("ECS_DISABLE_DOCKER_HEALTH_CHECK", "true")()
 	defer setTestEnv("ECS_DISABLE_METRICS", "true")()
 	defer setTestEnv("ECS_ENABLE_SPOT_INSTANCE_DRAINING", "true")()
+	defer setTestEnv("ECS_ENABLE_TASK_HEALTH_GATING", "true")()
+	defer setTestEnv("ECS_LOG_TEE_STDOUT", "true")()
 	cfg, err := NewConfig(ec2.NewBlackholeEC2MetadataClient())
 	assert.NoError(t, err)
 	assert.True(t, cfg.DisableMetrics.Enabled())
 	assert.True(t, cfg.DisableDockerHealthCheck.Enabled())
 	assert.True(t, cfg.SpotInstanceDrainingEnabled.Enabled())
+	assert.True(t, cfg.TaskHealthGatingEnabled.Enabled())
+	assert.True(t, cfg.LogTeeStdout.Enabled())
 }
 
 func TestBadLoggingDriverSerialization(t *testing.T) {
@@ -328,7 +348,7 @@ func TestInvalidFormatDockerStopTimeout(t *testing.T) {
 	defer setTestEnv("ECS_CONTAINER_STOP_TIMEOUT", "invalid")()
 	conf, err := NewConfig(ec2.NewBlackholeEC2MetadataClient())
 	assert.NoError(t, err)
-	assert.Equal(t, conf.DockerStopTimeout, defaultDockerStopTimeout, "Wrong value for DockerStopTimeout")
+	assert.Equal(t, conf.DockerStopTimeout, DefaultDockerStopTimeout, "Wrong value for DockerStopTimeout")
 }
 
 func TestZeroValueDockerStopTimeout(t *testing.T) {
@@ -336,7 +356,7 @@ func TestZeroValueDockerStopTimeout(t *testing.T) {
 	defer setTestEnv("ECS_CONTAINER_STOP_TIMEOUT", "0s")()
 	conf, err := NewConfig(ec2.NewBlackholeEC2MetadataClient())
 	assert.NoError(t, err)
-	assert.Equal(t, defaultDockerStopTimeout, conf.DockerStopTimeout, "Wrong value for DockerStopTimeout")
+	assert.Equal(t, DefaultDockerStopTimeout, conf.DockerStopTimeout, "Wrong value for DockerStopTimeout")
 }
 
 func TestInvalidValueDockerStopTimeout(t *testing.T) {
@@ -516,6 +536,62 @@ func TestValidForImagesCleanupExclusion(t *testing.T) {
 	assert.Equal(t, expectedImages, imagesNotDelete, "unexpected imageCleanupExclusionList")
 }
 
+func TestPrePullImages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		envValue string
+		expected []string
+	}{
+		{
+			name:     "not set",
+			envValue: "",
+			expected: nil,
+		},
+		{
+			name:     "multiple images",
+			envValue: "amazonlinux:2, busybox:1.36 ,",
+			expected: []string{"amazonlinux:2", "busybox:1.36"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setTestRegion()()
+			defer setTestEnv("ECS_PRE_PULL_IMAGES", tc.envValue)()
+			conf, err := environmentConfig()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, conf.PrePullImages)
+		})
+	}
+}
+
+func TestStopSignalEscalation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		envValue string
+		expected []string
+	}{
+		{
+			name:     "not set",
+			envValue: "",
+			expected: nil,
+		},
+		{
+			name:     "multiple signals",
+			envValue: "SIGTERM, SIGINT ,",
+			expected: []string{"SIGTERM", "SIGINT"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setTestRegion()()
+			defer setTestEnv("ECS_CONTAINER_STOP_SIGNAL_ESCALATION", tc.envValue)()
+			conf, err := environmentConfig()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, conf.StopSignalEscalation)
+		})
+	}
+}
+
 func TestValidFormatParseEnvVariableDuration(t *testing.T) {
 	defer setTestRegion()()
 	setTestEnv("FOO", "1s")
@@ -845,6 +921,195 @@ func TestTaskMetadataRPSLimits(t *testing.T) {
 	}
 }
 
+func TestTMDSMaxConcurrentRequests(t *testing.T) {
+	testCases := []struct {
+		name      string
+		envVarVal string
+		expected  int
+	}{
+		{
+			name:      "unset",
+			envVarVal: "",
+			expected:  DefaultTMDSMaxConcurrentRequests,
+		},
+		{
+			name:      "valid value",
+			envVarVal: "10",
+			expected:  10,
+		},
+		{
+			name:      "invalid value",
+			envVarVal: "ten",
+			expected:  DefaultTMDSMaxConcurrentRequests,
+		},
+		{
+			name:      "non positive value",
+			envVarVal: "0",
+			expected:  DefaultTMDSMaxConcurrentRequests,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setTestEnv("ECS_TMDS_MAX_CONCURRENT_REQUESTS", tc.envVarVal)()
+			defer setTestRegion()()
+			cfg, err := NewConfig(ec2.NewBlackholeEC2MetadataClient())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg.TMDSMaxConcurrentRequests)
+		})
+	}
+}
+
+func TestParseMetadataFieldStyle(t *testing.T) {
+	testcases := []struct {
+		name                       string
+		envVarVal                  string
+		expectedMetadataFieldStyle MetadataFieldStyleType
+	}{
+		{
+			name:                       "unset",
+			envVarVal:                  "",
+			expectedMetadataFieldStyle: MetadataFieldStyleDefault,
+		},
+		{
+			name:                       "default",
+			envVarVal:                  "default",
+			expectedMetadataFieldStyle: MetadataFieldStyleDefault,
+		},
+		{
+			name:                       "snake",
+			envVarVal:                  "snake",
+			expectedMetadataFieldStyle: MetadataFieldStyleSnake,
+		},
+		{
+			name:                       "invalid value",
+			envVarVal:                  "camel",
+			expectedMetadataFieldStyle: MetadataFieldStyleDefault,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setTestRegion()()
+			defer setTestEnv("ECS_METADATA_FIELD_STYLE", tc.envVarVal)()
+			assert.Equal(t, tc.expectedMetadataFieldStyle, parseMetadataFieldStyle(), "Wrong value for MetadataFieldStyle")
+		})
+	}
+}
+
+func TestParallelTaskStopLimit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		envVarVal string
+		expected  int
+	}{
+		{
+			name:      "unset",
+			envVarVal: "",
+			expected:  0,
+		},
+		{
+			name:      "valid value",
+			envVarVal: "10",
+			expected:  10,
+		},
+		{
+			name:      "invalid value",
+			envVarVal: "ten",
+			expected:  0,
+		},
+		{
+			name:      "negative value",
+			envVarVal: "-1",
+			expected:  0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setTestEnv("ECS_PARALLEL_TASK_STOP_LIMIT", tc.envVarVal)()
+			defer setTestRegion()()
+			cfg, err := NewConfig(ec2.NewBlackholeEC2MetadataClient())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg.ParallelTaskStopLimit)
+		})
+	}
+}
+
+func TestMaxENIs(t *testing.T) {
+	testCases := []struct {
+		name      string
+		envVarVal string
+		expected  int
+	}{
+		{
+			name:      "unset",
+			envVarVal: "",
+			expected:  0,
+		},
+		{
+			name:      "valid value",
+			envVarVal: "15",
+			expected:  15,
+		},
+		{
+			name:      "invalid value",
+			envVarVal: "many",
+			expected:  0,
+		},
+		{
+			name:      "negative value",
+			envVarVal: "-2",
+			expected:  0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setTestEnv("ECS_MAX_ENIS", tc.envVarVal)()
+			defer setTestRegion()()
+			cfg, err := NewConfig(ec2.NewBlackholeEC2MetadataClient())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg.MaxENIs)
+		})
+	}
+}
+
+func TestLogRateLimit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		envVarVal string
+		expected  int
+	}{
+		{
+			name:      "unset",
+			envVarVal: "",
+			expected:  0,
+		},
+		{
+			name:      "valid value",
+			envVarVal: "1000",
+			expected:  1000,
+		},
+		{
+			name:      "invalid value",
+			envVarVal: "fast",
+			expected:  0,
+		},
+		{
+			name:      "negative value",
+			envVarVal: "-5",
+			expected:  0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer setTestEnv("ECS_LOG_RATE_LIMIT", tc.envVarVal)()
+			defer setTestRegion()()
+			cfg, err := NewConfig(ec2.NewBlackholeEC2MetadataClient())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg.LogRateLimit)
+		})
+	}
+}
+
 func TestUserDataConfig(t *testing.T) {
 	testcases := []struct {
 		name                      string
@@ -60,6 +60,8 @@ const (
 	minimumContainerCreateTimeout = 1 * time.Minute
 	// default docker inactivity time is extra time needed on container extraction
 	defaultImagePullInactivityTimeout = 1 * time.Minute
+	// defaultRegistryClientCertsDir is the directory docker reads registry client certificates from
+	defaultRegistryClientCertsDir = "/etc/docker/certs.d"
 )
 
 // DefaultConfig returns the default configuration for Linux
@@ -75,7 +77,7 @@ func DefaultConfig() Config {
 		AvailableLoggingDrivers:             []dockerclient.LoggingDriver{dockerclient.JSONFileDriver, dockerclient.NoneDriver},
 		TaskCleanupWaitDuration:             DefaultTaskCleanupWaitDuration,
 		ManifestPullTimeout:                 defaultManifestPullTimeout,
-		DockerStopTimeout:                   defaultDockerStopTimeout,
+		DockerStopTimeout:                   DefaultDockerStopTimeout,
 		ContainerStartTimeout:               defaultContainerStartTimeout,
 		ContainerCreateTimeout:              defaultContainerCreateTimeout,
 		DependentContainersPullUpfront:      BooleanDefaultFalse{Value: ExplicitlyDisabled},
@@ -87,6 +89,8 @@ func DefaultConfig() Config {
 		ImageCleanupInterval:                DefaultImageCleanupTimeInterval,
 		ImagePullInactivityTimeout:          defaultImagePullInactivityTimeout,
 		ImagePullTimeout:                    DefaultImagePullTimeout,
+		TaskHealthGatingTimeout:             DefaultTaskHealthGatingTimeout,
+		RegistryClientCertsDir:              defaultRegistryClientCertsDir,
 		NumImagesToDeletePerCycle:           DefaultNumImagesToDeletePerCycle,
 		NumNonECSContainersToDeletePerCycle: DefaultNumNonECSContainersToDeletePerCycle,
 		CNIPluginsPath:                      defaultCNIPluginsPath,
@@ -99,6 +103,7 @@ func DefaultConfig() Config {
 		CgroupPath:                          defaultCgroupPath,
 		TaskMetadataSteadyStateRate:         DefaultTaskMetadataSteadyStateRate,
 		TaskMetadataBurstRate:               DefaultTaskMetadataBurstRate,
+		TMDSMaxConcurrentRequests:           DefaultTMDSMaxConcurrentRequests,
 		SharedVolumeMatchFullConfig:         BooleanDefaultFalse{Value: ExplicitlyDisabled}, // only requiring shared volumes to match on name, which is default docker behavior
 		ContainerInstancePropagateTagsFrom:  ContainerInstancePropagateTagsFromNoneType,
 		PrometheusMetricsEnabled:            false,
@@ -112,6 +117,7 @@ func DefaultConfig() Config {
 		RuntimeStatsLogFile:                 defaultRuntimeStatsLogFile,
 		EnableRuntimeStats:                  BooleanDefaultFalse{Value: NotSet},
 		ShouldExcludeIPv6PortBinding:        BooleanDefaultTrue{Value: ExplicitlyEnabled},
+		ExecCapable:                         BooleanDefaultTrue{Value: NotSet},
 	}
 }
 
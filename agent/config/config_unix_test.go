@@ -69,13 +69,17 @@ func TestConfigDefault(t *testing.T) {
 		"Default TaskMetadataSteadyStateRate is set incorrectly")
 	assert.Equal(t, DefaultTaskMetadataBurstRate, cfg.TaskMetadataBurstRate,
 		"Default TaskMetadataBurstRate is set incorrectly")
+	assert.Equal(t, DefaultTMDSMaxConcurrentRequests, cfg.TMDSMaxConcurrentRequests,
+		"Default TMDSMaxConcurrentRequests is set incorrectly")
 	assert.False(t, cfg.SharedVolumeMatchFullConfig.Enabled(), "Default SharedVolumeMatchFullConfig set incorrectly")
 	assert.Equal(t, defaultCgroupCPUPeriod, cfg.CgroupCPUPeriod, "CFS cpu period set incorrectly")
 	assert.Equal(t, DefaultImagePullTimeout, cfg.ImagePullTimeout, "Default ImagePullTimeout set incorrectly")
+	assert.Equal(t, DefaultTaskHealthGatingTimeout, cfg.TaskHealthGatingTimeout, "Default TaskHealthGatingTimeout set incorrectly")
 	assert.False(t, cfg.DependentContainersPullUpfront.Enabled(), "Default DependentContainersPullUpfront set incorrectly")
 	assert.False(t, cfg.PollMetrics.Enabled(), "ECS_POLL_METRICS default should be false")
 	assert.False(t, cfg.EnableRuntimeStats.Enabled(), "Default EnableRuntimeStats set incorrectly")
 	assert.True(t, cfg.ShouldExcludeIPv6PortBinding.Enabled(), "Default ShouldExcludeIPv6PortBinding set incorrectly")
+	assert.True(t, cfg.ExecCapable.Enabled(), "Default ExecCapable set incorrectly")
 	assert.False(t, cfg.FSxWindowsFileServerCapable.Enabled(), "Default FSxWindowsFileServerCapable set incorrectly")
 }
 
@@ -176,6 +180,29 @@ func TestDockerAuthMergeFromFile(t *testing.T) {
 	assert.Equal(t, map[string]string{"tag1": "value1"}, cfg.ContainerInstanceTags)
 }
 
+// TestLoadAWSLogsEndpoint tests that the awslogs endpoint is re-read from the
+// config file, and that the environment variable takes precedence over it
+func TestLoadAWSLogsEndpoint(t *testing.T) {
+	filePath := setupFileConfiguration(t, `{"AWSLogsEndpoint": "https://logs.file.example.com"}`)
+	defer os.Remove(filePath)
+	defer setTestEnv("ECS_AGENT_CONFIG_FILE_PATH", filePath)()
+
+	endpoint, err := LoadAWSLogsEndpoint()
+	require.NoError(t, err)
+	assert.Equal(t, "https://logs.file.example.com", endpoint)
+
+	// Rewrite the file to simulate an endpoint change and reload
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"AWSLogsEndpoint": "https://logs.new.example.com"}`), 0644))
+	endpoint, err = LoadAWSLogsEndpoint()
+	require.NoError(t, err)
+	assert.Equal(t, "https://logs.new.example.com", endpoint)
+
+	defer setTestEnv("ECS_AWSLOGS_ENDPOINT", "https://logs.env.example.com")()
+	endpoint, err = LoadAWSLogsEndpoint()
+	require.NoError(t, err)
+	assert.Equal(t, "https://logs.env.example.com", endpoint)
+}
+
 func TestBadFileContent(t *testing.T) {
 	content := `{
 	"AWSRegion": "not-real-1",
@@ -87,7 +87,7 @@ func parseDockerStopTimeout() time.Duration {
 		// if the configured ECS_CONTAINER_STOP_TIMEOUT is smaller than minimumDockerStopTimeout,
 		// DockerStopTimeout will be set to minimumDockerStopTimeout
 		// if the ECS_CONTAINER_STOP_TIMEOUT is 0, empty or an invalid value, then DockerStopTimeout
-		// will be set to defaultDockerStopTimeout during the config merge operation
+		// will be set to DefaultDockerStopTimeout during the config merge operation
 		dockerStopTimeout = minimumDockerStopTimeout
 		seelog.Warnf("Discarded invalid value for docker stop timeout, parsed as: %v", parsedStopTimeout)
 	}
@@ -310,6 +310,53 @@ func parseTaskMetadataThrottles() (int, int) {
 	return steadyStateRate, burstRate
 }
 
+func parseTMDSMaxConcurrentRequests() int {
+	maxConcurrentRequestsEnvVal := os.Getenv("ECS_TMDS_MAX_CONCURRENT_REQUESTS")
+	maxConcurrentRequests, err := strconv.Atoi(maxConcurrentRequestsEnvVal)
+	if maxConcurrentRequestsEnvVal != "" && err != nil {
+		seelog.Warnf("Invalid format for \"ECS_TMDS_MAX_CONCURRENT_REQUESTS\", expected an integer. err %v", err)
+	}
+	return maxConcurrentRequests
+}
+
+func parseMetadataFieldStyle() MetadataFieldStyleType {
+	switch os.Getenv("ECS_METADATA_FIELD_STYLE") {
+	case "snake":
+		return MetadataFieldStyleSnake
+	default:
+		// Use the default field style when ECS_METADATA_FIELD_STYLE is
+		// "default" or not valid
+		return MetadataFieldStyleDefault
+	}
+}
+
+func parseParallelTaskStopLimit() int {
+	parallelTaskStopLimitEnvVal := os.Getenv("ECS_PARALLEL_TASK_STOP_LIMIT")
+	parallelTaskStopLimit, err := strconv.Atoi(parallelTaskStopLimitEnvVal)
+	if parallelTaskStopLimitEnvVal != "" && err != nil {
+		seelog.Warnf("Invalid format for \"ECS_PARALLEL_TASK_STOP_LIMIT\", expected an integer. err %v", err)
+	}
+	return parallelTaskStopLimit
+}
+
+func parseMaxENIs() int {
+	maxENIsEnvVal := os.Getenv("ECS_MAX_ENIS")
+	maxENIs, err := strconv.Atoi(maxENIsEnvVal)
+	if maxENIsEnvVal != "" && err != nil {
+		seelog.Warnf("Invalid format for \"ECS_MAX_ENIS\", expected an integer. err %v", err)
+	}
+	return maxENIs
+}
+
+func parseLogRateLimit() int {
+	logRateLimitEnvVal := os.Getenv("ECS_LOG_RATE_LIMIT")
+	logRateLimit, err := strconv.Atoi(logRateLimitEnvVal)
+	if logRateLimitEnvVal != "" && err != nil {
+		seelog.Warnf("Invalid format for \"ECS_LOG_RATE_LIMIT\", expected an integer. err %v", err)
+	}
+	return logRateLimit
+}
+
 func parseContainerInstanceTags(errs []error) (map[string]string, []error) {
 	var containerInstanceTags map[string]string
 	containerInstanceTagsConfigString := os.Getenv("ECS_CONTAINER_INSTANCE_TAGS")
@@ -385,6 +432,26 @@ func parseImageCleanupExclusionList(envVar string) []string {
 	return imageCleanupExclusionList
 }
 
+func parsePrePullImages() []string {
+	var prePullImages []string
+	for _, image := range strings.Split(os.Getenv("ECS_PRE_PULL_IMAGES"), ",") {
+		if image = strings.TrimSpace(image); image != "" {
+			prePullImages = append(prePullImages, image)
+		}
+	}
+	return prePullImages
+}
+
+func parseStopSignalEscalation() []string {
+	var signals []string
+	for _, signal := range strings.Split(os.Getenv("ECS_CONTAINER_STOP_SIGNAL_ESCALATION"), ",") {
+		if signal = strings.TrimSpace(signal); signal != "" {
+			signals = append(signals, signal)
+		}
+	}
+	return signals
+}
+
 func parseCgroupCPUPeriod() time.Duration {
 	duration := parseEnvVariableDuration("ECS_CGROUP_CPU_PERIOD")
 
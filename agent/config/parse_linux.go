@@ -20,6 +20,7 @@ import (
 	"errors"
 	"io/fs"
 	"os"
+	"os/exec"
 	"strconv"
 	"strings"
 
@@ -27,6 +28,13 @@ import (
 	"github.com/cihub/seelog"
 )
 
+const tcBinaryName = "tc"
+
+var (
+	// fnLookPath is used to probe for binaries on the host and can be overridden in tests
+	fnLookPath = exec.LookPath
+)
+
 func parseGMSACapability() BooleanDefaultFalse {
 	envStatus := utils.ParseBool(os.Getenv(envGmsaEcsSupport), false)
 	if envStatus {
@@ -74,6 +82,16 @@ func parseFSxWindowsFileServerCapability() BooleanDefaultTrue {
 	return BooleanDefaultTrue{Value: ExplicitlyDisabled}
 }
 
+// parseBandwidthLimitCapability is used to determine if network bandwidth limiting can be supported,
+// which requires the tc binary to be present on the host
+func parseBandwidthLimitCapability() BooleanDefaultFalse {
+	if _, err := fnLookPath(tcBinaryName); err != nil {
+		seelog.Debugf("Unable to find %s binary, network bandwidth limiting is not supported: %v", tcBinaryName, err)
+		return BooleanDefaultFalse{Value: ExplicitlyDisabled}
+	}
+	return BooleanDefaultFalse{Value: ExplicitlyEnabled}
+}
+
 // parseGMSADomainlessCapability is used to determine if gMSA domainless support can be enabled
 func parseGMSADomainlessCapability() BooleanDefaultFalse {
 	envStatus := utils.ParseBool(os.Getenv(envGmsaEcsSupport), false)
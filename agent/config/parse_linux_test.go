@@ -17,6 +17,8 @@
 package config
 
 import (
+	"errors"
+	"os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -109,3 +111,24 @@ func TestParseTaskPidsLimit(t *testing.T) {
 func TestParseTaskPidsLimit_Unset(t *testing.T) {
 	assert.Equal(t, 0, parseTaskPidsLimit())
 }
+
+func TestParseBandwidthLimitCapability(t *testing.T) {
+	defer func() {
+		fnLookPath = exec.LookPath
+	}()
+
+	t.Run("tc present", func(t *testing.T) {
+		fnLookPath = func(file string) (string, error) {
+			assert.Equal(t, tcBinaryName, file)
+			return "/usr/sbin/tc", nil
+		}
+		assert.True(t, parseBandwidthLimitCapability().Enabled())
+	})
+
+	t.Run("tc absent", func(t *testing.T) {
+		fnLookPath = func(file string) (string, error) {
+			return "", errors.New("executable file not found in $PATH")
+		}
+		assert.False(t, parseBandwidthLimitCapability().Enabled())
+	})
+}
@@ -29,6 +29,10 @@ func parseFSxWindowsFileServerCapability() BooleanDefaultTrue {
 	return BooleanDefaultTrue{Value: ExplicitlyDisabled}
 }
 
+func parseBandwidthLimitCapability() BooleanDefaultFalse {
+	return BooleanDefaultFalse{Value: ExplicitlyDisabled}
+}
+
 func parseGMSADomainlessCapability() BooleanDefaultFalse {
 	return BooleanDefaultFalse{Value: ExplicitlyDisabled}
 }
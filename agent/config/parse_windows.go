@@ -67,6 +67,11 @@ func parseGMSADomainlessCapability() BooleanDefaultFalse {
 	return BooleanDefaultFalse{Value: ExplicitlyDisabled}
 }
 
+// parseBandwidthLimitCapability returns disabled as network bandwidth limiting via tc is not supported on Windows
+func parseBandwidthLimitCapability() BooleanDefaultFalse {
+	return BooleanDefaultFalse{Value: ExplicitlyDisabled}
+}
+
 // parseFSxWindowsFileServerCapability is used to determine if fsxWindowsFileServer support can be enabled
 func parseFSxWindowsFileServerCapability() BooleanDefaultTrue {
 	// fsxwindowsfileserver is not supported on Windows 2016.
@@ -25,6 +25,10 @@ import (
 // behaviors including default, always, never and once.
 type ImagePullBehaviorType int8
 
+// MetadataFieldStyleType is an enum variable type corresponding to the styles of the JSON field
+// names in task metadata responses, including default and snake.
+type MetadataFieldStyleType int8
+
 // ContainerInstancePropagateTagsFromType is an enum variable type corresponding to different
 // ways to propagate tags, it includes none (default) and ec2_instance.
 type ContainerInstancePropagateTagsFromType int8
@@ -103,6 +107,14 @@ type Config struct {
 	// on the instance
 	DisableDockerHealthCheck BooleanDefaultFalse
 
+	// TaskHealthGatingEnabled configures whether a task only transitions to RUNNING once all of its
+	// essential containers with a health check are healthy
+	TaskHealthGatingEnabled BooleanDefaultFalse
+
+	// TaskHealthGatingTimeout is the amount of time a task with health gating enabled waits for its
+	// essential containers to become healthy once they are running, before the task is stopped
+	TaskHealthGatingTimeout time.Duration
+
 	// ReservedMemory specifies Reduction, in MiB, of the memory capacity of the instance
 	// that is reported to Amazon ECS. Used by Amazon ECS when placing tasks on container instances.
 	// This doesn't reserve memory usage on the instance
@@ -115,6 +127,27 @@ type Config struct {
 	// containers managed by ECS
 	DockerStopTimeout time.Duration
 
+	// StopSignalEscalation is the sequence of signals sent to containers when they're stopped. Each signal is
+	// followed by an equal share of the stop timeout before the next one, and SIGKILL is sent once the last
+	// share has passed. Docker's own stop behavior is used when it's empty.
+	StopSignalEscalation []string
+
+	// ParallelTaskStopLimit bounds how many containers the agent stops concurrently, for example
+	// while many tasks are stopped during a drain. A value of 0 means there's no limit.
+	ParallelTaskStopLimit int
+
+	// MaxENIs overrides the maximum number of task ENIs that can be attached to the instance. A value of 0
+	// means it isn't configured.
+	MaxENIs int
+
+	// LogRateLimit is the maximum number of log records per second that each container is allowed to
+	// send through a firelens log router. A value of 0 means there's no limit.
+	LogRateLimit int
+
+	// LogTeeStdout configures whether the logs of the containers started by the agent are also written
+	// to the agent's stdout, which is meant for local debugging
+	LogTeeStdout BooleanDefaultFalse
+
 	// ContainerStartTimeout specifies the amount of time to wait to start a container
 	ContainerStartTimeout time.Duration
 
@@ -131,10 +164,26 @@ type Config struct {
 	//ImagePullTimeout is here to override the timeout for PullImage API
 	ImagePullTimeout time.Duration
 
+	// RegistryClientCertsDir is the directory holding client certificates for registries
+	// that require mutual TLS. It follows docker's certs.d layout, i.e.
+	// <RegistryClientCertsDir>/<registry host>/client.cert and client.key.
+	RegistryClientCertsDir string
+
 	// AvailableLoggingDrivers specifies the logging drivers available for use
 	// with Docker.  If not set, it defaults to ["json-file","none"].
 	AvailableLoggingDrivers []dockerclient.LoggingDriver
 
+	// AWSLogsEndpoint specifies the CloudWatch Logs endpoint applied to new
+	// containers using the awslogs driver that do not set 'awslogs-endpoint'
+	// themselves. It can be reloaded without restarting the agent by sending
+	// it a SIGHUP.
+	AWSLogsEndpoint string `trim:"true"`
+
+	// ContainerInitPath specifies the path on the host of a custom init binary. When set,
+	// containers that enable an init process run this binary instead of Docker's built-in
+	// init. Only supported on Linux.
+	ContainerInitPath string `trim:"true"`
+
 	// PrivilegedDisabled specified whether the Agent is capable of launching
 	// tasks with privileged containers
 	PrivilegedDisabled BooleanDefaultFalse
@@ -147,6 +196,10 @@ type Config struct {
 	// security options
 	AppArmorCapable BooleanDefaultFalse
 
+	// AppConfigCapable specifies whether the Agent is capable of populating
+	// container environment variables from AWS AppConfig
+	AppConfigCapable BooleanDefaultFalse
+
 	// TaskCleanupWaitDuration specifies the time to wait after a task is stopped
 	// until cleanup of task resources is started.
 	TaskCleanupWaitDuration time.Duration
@@ -279,6 +332,23 @@ type Config struct {
 	// TaskMetadataBurstRate specifies the burst rate throttle for the task metadata endpoint
 	TaskMetadataBurstRate int
 
+	// TMDSMaxConcurrentRequests specifies the maximum number of container metadata requests
+	// served concurrently by the task metadata endpoint. Requests beyond this are rejected
+	// with a 503 and a Retry-After header
+	TMDSMaxConcurrentRequests int
+
+	// TMDSSocketPath specifies the path of a Unix domain socket the task metadata endpoint
+	// is served on. When set, the endpoint isn't served on the link-local address
+	TMDSSocketPath string
+
+	// TMDSAuthEnabled specifies whether v3 and v4 task metadata requests must carry the token
+	// issued to the task of the requesting container
+	TMDSAuthEnabled BooleanDefaultFalse
+
+	// MetadataFieldStyle specifies the style of the JSON field names in container metadata
+	// responses. When set to snake, fields are served in snake_case instead of PascalCase
+	MetadataFieldStyle MetadataFieldStyleType
+
 	// SharedVolumeMatchFullConfig is config option used to short-circuit volume validation against a
 	// provisioned volume, if false (default). If true, we perform deep comparison including driver options
 	// and labels. For comparing shared volume across 2 instances, this should be set to false as docker's
@@ -315,6 +385,10 @@ type Config struct {
 	// ImageCleanupExclusionList is the list of image names customers want to keep for their own use and delete automatically
 	ImageCleanupExclusionList []string
 
+	// PrePullImages is the list of images that the agent pulls on startup so that tasks using them start faster.
+	// Pre-pulled images are excluded from image cleanup.
+	PrePullImages []string
+
 	// NvidiaRuntime is the runtime to be used for passing Nvidia GPU devices to containers
 	NvidiaRuntime string `trim:"true"`
 
@@ -344,6 +418,10 @@ type Config struct {
 	// It should be enabled by if the container instance has a plugin to support active directory authentication.
 	GMSADomainlessCapable BooleanDefaultFalse
 
+	// BandwidthLimitCapable indicates whether the tooling needed to apply per-container network
+	// bandwidth limits (the tc binary) is present on the host. It is detected at startup on Linux.
+	BandwidthLimitCapable BooleanDefaultFalse
+
 	// VolumePluginCapabilities specifies the capabilities of the ecs volume plugin.
 	VolumePluginCapabilities []string
 
@@ -365,6 +443,11 @@ type Config struct {
 	// is set to false and can be overridden by means of the ECS_ENABLE_RUNTIME_STATS environment variable.
 	EnableRuntimeStats BooleanDefaultFalse
 
+	// ExecCapable specifies whether the Agent should advertise the ECS Exec capability when the exec
+	// agent dependencies are present. It is enabled by default and can be overridden by the
+	// ECS_EXEC_CAPABLE environment variable.
+	ExecCapable BooleanDefaultTrue
+
 	// ShouldExcludeIPv6PortBinding specifies whether agent should exclude IPv6 port bindings reported from docker. This configuration
 	// is set to true by default, and can be overridden by the ECS_EXCLUDE_IPV6_PORTBINDING environment variable. This is a workaround
 	// for docker's bug as detailed in https://github.com/aws/amazon-ecs-agent/issues/2870.
@@ -15,10 +15,12 @@ package containermetadata
 
 import (
 	"fmt"
+	"net"
 
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 
+	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
 	tmdsresponse "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/response"
 	"github.com/cihub/seelog"
 	"github.com/docker/docker/api/types"
@@ -52,7 +54,12 @@ func (manager *metadataManager) parseMetadataAtContainerCreate(task *apitask.Tas
 // Since we accept incomplete metadata fields, we should not return
 // errors here and handle them at this or the above stage.
 func (manager *metadataManager) parseMetadata(dockerContainer *types.ContainerJSON, task *apitask.Task, containerName string) Metadata {
-	dockerMD := parseDockerContainerMetadata(task.Arn, containerName, dockerContainer)
+	dockerMD := parseDockerContainerMetadata(task.Arn, containerName, dockerContainer, task.GetPrimaryENI())
+	if container, ok := task.ContainerByName(containerName); ok {
+		for i, port := range dockerMD.ports {
+			dockerMD.ports[i].Name = container.GetPortMappingName(port.ContainerPort, port.Protocol)
+		}
+	}
 	return Metadata{
 		cluster: manager.cluster,
 		taskMetadata: TaskMetadata{
@@ -74,7 +81,8 @@ func (manager *metadataManager) parseMetadata(dockerContainer *types.ContainerJS
 // and packages this data for JSON marshaling
 // Since we accept incomplete metadata fields, we should not return
 // errors here and handle them at this stage.
-func parseDockerContainerMetadata(taskARN string, containerName string, dockerContainer *types.ContainerJSON) DockerContainerMetadata {
+func parseDockerContainerMetadata(taskARN string, containerName string, dockerContainer *types.ContainerJSON,
+	eni *ni.NetworkInterface) DockerContainerMetadata {
 	if dockerContainer == nil {
 		seelog.Warnf("Failed to parse container metadata for task %s container %s: container metadata not available or does not exist", taskARN, containerName)
 		return DockerContainerMetadata{}
@@ -96,7 +104,7 @@ func parseDockerContainerMetadata(taskARN string, containerName string, dockerCo
 			imageName: imageNameFromConfig,
 		}
 	}
-	networkMetadata, err := parseNetworkMetadata(dockerContainer.NetworkSettings, dockerContainer.HostConfig)
+	networkMetadata, err := parseNetworkMetadata(dockerContainer.NetworkSettings, dockerContainer.HostConfig, eni)
 
 	if err != nil {
 		seelog.Warnf("Failed to parse container metadata for task %s container %s: %v", taskARN, containerName, err)
@@ -120,10 +128,12 @@ func parseDockerContainerMetadata(taskARN string, containerName string, dockerCo
 }
 
 // parseNetworkMetadata parses the docker.NetworkSettings struct and
-// packages the desired metadata for JSON marshaling
+// packages the desired metadata for JSON marshaling. The MAC address is
+// only populated when the container is attached to the task's ENI.
 // Since we accept incomplete metadata fields, we should not return
 // errors here and handle them at this stage.
-func parseNetworkMetadata(settings *types.NetworkSettings, hostConfig *dockercontainer.HostConfig) (NetworkMetadata, error) {
+func parseNetworkMetadata(settings *types.NetworkSettings, hostConfig *dockercontainer.HostConfig,
+	eni *ni.NetworkInterface) (NetworkMetadata, error) {
 	// Network settings and Host configuration should not be missing except due to errors
 	if settings == nil {
 		err := fmt.Errorf("parse network metadata: could not find network settings")
@@ -143,21 +153,57 @@ func parseNetworkMetadata(settings *types.NetworkSettings, hostConfig *dockercon
 
 	// Extensive Network information is not available for Docker API versions 1.17-1.20
 	// Instead we only get the details of the first network
-	networkList := make([]tmdsresponse.Network, 0)
+	networkList := make([]Network, 0)
 	if len(settings.Networks) > 0 {
 		for modeFromSettings, containerNetwork := range settings.Networks {
 			networkMode := modeFromSettings
 			ipv4Addresses := []string{containerNetwork.IPAddress}
-			network := tmdsresponse.Network{NetworkMode: networkMode, IPv4Addresses: ipv4Addresses}
+			network := Network{
+				Network:    tmdsresponse.Network{NetworkMode: networkMode, IPv4Addresses: ipv4Addresses},
+				Gateway:    containerNetwork.Gateway,
+				SubnetCIDR: subnetCIDR(containerNetwork.IPAddress, containerNetwork.IPPrefixLen),
+			}
 			networkList = append(networkList, network)
 		}
 	} else {
 		ipv4Addresses := []string{ipv4AddressFromSettings}
-		network := tmdsresponse.Network{NetworkMode: networkModeFromHostConfig, IPv4Addresses: ipv4Addresses}
+		network := Network{
+			Network:    tmdsresponse.Network{NetworkMode: networkModeFromHostConfig, IPv4Addresses: ipv4Addresses},
+			Gateway:    settings.Gateway,
+			SubnetCIDR: subnetCIDR(ipv4AddressFromSettings, settings.IPPrefixLen),
+		}
 		networkList = append(networkList, network)
 	}
 
+	// Interface details come from the ENI when the container is attached to one, otherwise
+	// the DNS servers are the ones docker was configured with for the container
+	dnsServerAddresses := hostConfig.DNS
+	macAddress := ""
+	if eni != nil {
+		macAddress = eni.MacAddress
+		if len(eni.DomainNameServers) > 0 {
+			dnsServerAddresses = eni.DomainNameServers
+		}
+	}
+	for i := range networkList {
+		networkList[i].MACAddress = macAddress
+		networkList[i].DNSServerAddresses = dnsServerAddresses
+	}
+
 	return NetworkMetadata{
 		networks: networkList,
 	}, nil
 }
+
+// subnetCIDR returns the CIDR of the subnet the address belongs to, given the prefix
+// length docker reports for it. It returns an empty string if either is unknown.
+func subnetCIDR(ipAddress string, prefixLen int) string {
+	if ipAddress == "" || prefixLen == 0 {
+		return ""
+	}
+	_, subnet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", ipAddress, prefixLen))
+	if err != nil {
+		return ""
+	}
+	return subnet.String()
+}
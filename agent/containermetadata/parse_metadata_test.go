@@ -17,15 +17,19 @@
 package containermetadata
 
 import (
+	"encoding/json"
 	"testing"
 
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
 
 	"github.com/docker/docker/api/types"
 	dockercontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -203,6 +207,47 @@ func TestParseHasNetworkSettingsPortBindings(t *testing.T) {
 	assert.Equal(t, uint16(80), metadata.dockerContainerMetadata.ports[0].ContainerPort, "Expected nonempty ContainerPort field")
 	assert.Equal(t, uint16(8080), metadata.dockerContainerMetadata.ports[0].HostPort, "Expected nonempty HostPort field")
 	assert.Equal(t, "0.0.0.0", metadata.dockerContainerMetadata.ports[0].BindIP, "Expected nonempty HostIP field")
+	assert.Empty(t, metadata.dockerContainerMetadata.ports[0].Name, "Expected no name for an unnamed port mapping")
+}
+
+func TestParseNamedPortBindings(t *testing.T) {
+	mockTask := &apitask.Task{
+		Arn: validTaskARN,
+		Containers: []*apicontainer.Container{
+			{
+				Name: containerName,
+				Ports: []apicontainer.PortBinding{
+					{ContainerPort: 80, Protocol: apicontainer.TransportProtocolTCP, Name: "http"},
+				},
+			},
+		},
+	}
+	mockPorts := nat.PortMap{
+		"80/tcp":   []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "8080"}},
+		"9090/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "9090"}},
+	}
+	mockContainer := &types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			HostConfig: &dockercontainer.HostConfig{NetworkMode: "bridge"},
+		},
+		NetworkSettings: &types.NetworkSettings{
+			NetworkSettingsBase: types.NetworkSettingsBase{
+				Ports: mockPorts,
+			},
+		},
+	}
+
+	newManager := &metadataManager{}
+	metadata := newManager.parseMetadata(mockContainer, mockTask, containerName)
+	portNames := make(map[uint16]string)
+	for _, port := range metadata.dockerContainerMetadata.ports {
+		portNames[port.ContainerPort] = port.Name
+	}
+	assert.Equal(t, map[uint16]string{80: "http", 9090: ""}, portNames)
+
+	metadataJSON, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	assert.Contains(t, string(metadataJSON), `"Name":"http"`)
 }
 
 func TestParseHasNetworkSettingsNetworksEmpty(t *testing.T) {
@@ -293,6 +338,91 @@ func TestParseHasNetworkSettingsNetworksNonEmpty(t *testing.T) {
 	assert.Equal(t, metadata.hostPublicIPv4Address, mockHostPublicIPv4Address, "Expected hostPublicIPv4Address "+hostPublicIPv4Address)
 	assert.Equal(t, string(metadata.metadataStatus), expectedStatus, "Expected status "+expectedStatus)
 	assert.Equal(t, len(metadata.dockerContainerMetadata.networkInfo.networks), 2, "Expected two networks")
+	for _, network := range metadata.dockerContainerMetadata.networkInfo.networks {
+		assert.Empty(t, network.MACAddress, "Expected no MAC address for a container without an ENI")
+	}
+}
+
+func TestParseNetworkMetadataWithENI(t *testing.T) {
+	mockMACAddress := "06:96:9a:ce:a6:ce"
+	mockDNSServers := []string{"169.254.169.253"}
+	mockTask := &apitask.Task{
+		Arn: validTaskARN,
+		ENIs: []*ni.NetworkInterface{
+			{
+				MacAddress:        mockMACAddress,
+				DomainNameServers: mockDNSServers,
+			},
+		},
+	}
+	mockHostConfig := &dockercontainer.HostConfig{
+		NetworkMode: dockercontainer.NetworkMode("container:pause"),
+		DNS:         []string{"10.0.0.2"},
+	}
+	mockNetworks := map[string]*network.EndpointSettings{
+		"awsvpc": {IPAddress: "10.0.0.10"},
+	}
+	mockContainer := &types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			HostConfig: mockHostConfig,
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: mockNetworks,
+		},
+	}
+
+	newManager := &metadataManager{}
+	metadata := newManager.parseMetadata(mockContainer, mockTask, containerName)
+	networks := metadata.dockerContainerMetadata.networkInfo.networks
+	assert.Len(t, networks, 1)
+	assert.Equal(t, "awsvpc", networks[0].NetworkMode)
+	assert.Equal(t, []string{"10.0.0.10"}, networks[0].IPv4Addresses)
+	assert.Equal(t, mockMACAddress, networks[0].MACAddress)
+	assert.Equal(t, mockDNSServers, networks[0].DNSServerAddresses)
+}
+
+func TestParseNetworkMetadataGatewayAndSubnet(t *testing.T) {
+	mockHostConfig := &dockercontainer.HostConfig{NetworkMode: dockercontainer.NetworkMode("bridge")}
+	mockNetworks := map[string]*network.EndpointSettings{
+		"bridge": {
+			IPAddress:   "172.17.0.2",
+			IPPrefixLen: 16,
+			Gateway:     "172.17.0.1",
+		},
+	}
+	mockContainer := &types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			HostConfig: mockHostConfig,
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: mockNetworks,
+		},
+	}
+
+	newManager := &metadataManager{}
+	metadata := newManager.parseMetadata(mockContainer, &apitask.Task{Arn: validTaskARN}, containerName)
+	networks := metadata.dockerContainerMetadata.networkInfo.networks
+	require.Len(t, networks, 1)
+	assert.Equal(t, "bridge", networks[0].NetworkMode)
+	assert.Equal(t, "172.17.0.1", networks[0].Gateway)
+	assert.Equal(t, "172.17.0.0/16", networks[0].SubnetCIDR)
+}
+
+func TestParseNetworkMetadataGatewayAndSubnetFromSettings(t *testing.T) {
+	mockHostConfig := &dockercontainer.HostConfig{NetworkMode: dockercontainer.NetworkMode("bridge")}
+	mockNetworkSettings := &types.NetworkSettings{
+		DefaultNetworkSettings: types.DefaultNetworkSettings{
+			IPAddress:   "172.17.0.2",
+			IPPrefixLen: 16,
+			Gateway:     "172.17.0.1",
+		},
+	}
+
+	networkMetadata, err := parseNetworkMetadata(mockNetworkSettings, mockHostConfig, nil)
+	require.NoError(t, err)
+	require.Len(t, networkMetadata.networks, 1)
+	assert.Equal(t, "172.17.0.1", networkMetadata.networks[0].Gateway)
+	assert.Equal(t, "172.17.0.0/16", networkMetadata.networks[0].SubnetCIDR)
 }
 
 func TestParseHasNoContainerJSONBase(t *testing.T) {
@@ -83,12 +83,24 @@ type DockerMetadataClient interface {
 	InspectContainer(context.Context, string, time.Duration) (*types.ContainerJSON, error)
 }
 
+// Network is the network information written to the metadata file. It extends the
+// task metadata network response with details of the network interface, which are
+// only available when the container is attached to an ENI, and the gateway and subnet
+// docker reports for the network.
+type Network struct {
+	tmdsresponse.Network
+	MACAddress         string   `json:"MACAddress,omitempty"`
+	DNSServerAddresses []string `json:"DNSServerAddresses,omitempty"`
+	Gateway            string   `json:"Gateway,omitempty"`
+	SubnetCIDR         string   `json:"SubnetCIDR,omitempty"`
+}
+
 // NetworkMetadata keeps track of the data we parse from the Network Settings
 // in docker containers. While most information is redundant with the internal
 // Network struct, we keeps this wrapper in case we wish to add data specifically
 // from the NetworkSettings
 type NetworkMetadata struct {
-	networks []tmdsresponse.Network
+	networks []Network
 }
 
 // DockerContainerMetadata keeps track of all metadata acquired from Docker inspection
@@ -141,7 +153,7 @@ type metadataSerializer struct {
 	ImageID                string                     `json:"ImageID,omitempty"`
 	ImageName              string                     `json:"ImageName,omitempty"`
 	Ports                  []apicontainer.PortBinding `json:"PortMappings,omitempty"`
-	Networks               []tmdsresponse.Network     `json:"Networks,omitempty"`
+	Networks               []Network                  `json:"Networks,omitempty"`
 	MetadataFileStatus     MetadataStatus             `json:"MetadataFileStatus,omitempty"`
 	AvailabilityZone       string                     `json:"AvailabilityZone,omitempty"`
 	HostPrivateIPv4Address string                     `json:"HostPrivateIPv4Address,omitempty"`
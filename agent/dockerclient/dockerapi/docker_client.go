@@ -46,6 +46,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/ttime"
 
 	"github.com/cihub/seelog"
+	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	dockercontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/events"
@@ -53,6 +54,8 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/opencontainers/go-digest"
 )
 
 const (
@@ -71,6 +74,10 @@ const (
 	dockerContainerDieEvent = "die"
 	// dockerContainerEventExitCodeAttribute is the attribute name to get exit code from Docker event attribute.
 	dockerContainerEventExitCodeAttribute = "exitCode"
+	// layerAlreadyExistsStatus is the pull status docker reports for an image layer found in the local layer cache
+	layerAlreadyExistsStatus = "Already exists"
+	// layerPullCompleteStatus is the pull status docker reports for an image layer that was downloaded and extracted
+	layerPullCompleteStatus = "Pull complete"
 )
 
 // Timelimits for docker operations enforced above docker
@@ -109,9 +116,29 @@ const (
 	tagImageRetryAttempts = 5
 	tagImageRetryInterval = 100 * time.Millisecond
 
+	// retry settings for reconnecting to the docker daemon after the events
+	// stream is lost, e.g. because the daemon is restarting
+	minimumDaemonReconnectDelay     = 100 * time.Millisecond
+	maximumDaemonReconnectDelay     = 30 * time.Second
+	daemonReconnectDelayMultiplier  = 2
+	daemonReconnectJitterMultiplier = 0.2
+	daemonReconnectPingTimeout      = 5 * time.Second
+
 	// pollStatsTimeout is the timeout for polling Docker Stats API;
 	// keeping it same as streaming stats inactivity timeout
 	pollStatsTimeout = 18 * time.Second
+
+	// healthCheckCacheTTL is how long the health result inspected for a health status event is reused
+	// for subsequent events of the same container reporting the same status
+	healthCheckCacheTTL = 5 * time.Second
+
+	// stopSignalEscalationKillSignal is sent to containers that are still running once every signal
+	// of the stop signal escalation has been sent
+	stopSignalEscalationKillSignal = "SIGKILL"
+
+	// stopSignalEscalationKillTimeout bounds how long the stop signal escalation waits for a container
+	// to exit once it has been killed
+	stopSignalEscalationKillTimeout = 5 * time.Second
 )
 
 // stopContainerTimeoutBuffer is a buffer added to the timeout passed into the docker
@@ -143,6 +170,10 @@ type DockerClient interface {
 	// of the image from the registry.
 	PullImageManifest(context.Context, string, *apicontainer.RegistryAuthenticationData) (registry.DistributionInspect, apierrors.NamedError)
 
+	// PullImageLayerMediaTypes fetches the image manifest with the given digest from the registry and
+	// returns the media types of the layers it references.
+	PullImageLayerMediaTypes(context.Context, string, digest.Digest, *apicontainer.RegistryAuthenticationData) ([]string, error)
+
 	// PullImage pulls an image. authData should contain authentication data provided by the ECS backend.
 	PullImage(context.Context, string, *apicontainer.RegistryAuthenticationData, time.Duration) DockerContainerMetadata
 
@@ -169,6 +200,14 @@ type DockerClient interface {
 	// A timeout value and a context should be provided for the request.
 	RemoveContainer(context.Context, string, time.Duration) error
 
+	// UpdateContainerResources updates the resource limits of the running container identified by the name
+	// provided. A timeout value and a context should be provided for the request.
+	UpdateContainerResources(context.Context, string, dockercontainer.Resources, time.Duration) error
+
+	// ContainerLogs streams the stdout and stderr of the container identified by the name provided, following
+	// the logs until the container stops or the context is canceled. The caller must close the returned stream.
+	ContainerLogs(context.Context, string) (io.ReadCloser, error)
+
 	// InspectContainer returns information about the specified container. A timeout value and a context should be
 	// provided for the request.
 	InspectContainer(context.Context, string, time.Duration) (*types.ContainerJSON, error)
@@ -235,6 +274,9 @@ type DockerClient interface {
 
 	// Info returns the information of the Docker server.
 	Info(context.Context, time.Duration) (types.Info, error)
+
+	// ConnectionStatus returns the health of the connection to the Docker daemon.
+	ConnectionStatus() DockerConnectionStatus
 }
 
 // DockerGoClient wraps the underlying go-dockerclient and docker/docker library.
@@ -267,13 +309,23 @@ type dockerGoClient struct {
 	manifestPullBackoff      retry.Backoff
 	imagePullBackoff         retry.Backoff
 	imageTagBackoff          retry.Backoff
+	daemonReconnectBackoff   retry.Backoff
 	inactivityTimeoutHandler inactivityTimeoutHandlerFunc
 
 	_time     ttime.Time
 	_timeOnce sync.Once
 
-	daemonVersionUnsafe string
-	lock                sync.Mutex
+	daemonVersionUnsafe    string
+	connectionStatusUnsafe DockerConnectionStatus
+	healthCheckCacheUnsafe map[string]cachedHealthCheckResult
+	lock                   sync.Mutex
+}
+
+// cachedHealthCheckResult is the container metadata inspected for the last health status event of a container
+type cachedHealthCheckResult struct {
+	eventStatus string
+	metadata    DockerContainerMetadata
+	inspectedAt time.Time
 }
 
 type ImagePullResponse struct {
@@ -287,17 +339,36 @@ type ImagePullResponse struct {
 	Error    string `json:"error,omitempty"`
 }
 
+// layerCacheStats counts the layers of an image pull that were found in the local layer cache (hits)
+// and the ones that had to be downloaded (misses).
+type layerCacheStats struct {
+	hits   int
+	misses int
+}
+
+// record updates the stats with a pull progress message.
+func (s *layerCacheStats) record(data *ImagePullResponse) {
+	switch data.Status {
+	case layerAlreadyExistsStatus:
+		s.hits++
+	case layerPullCompleteStatus:
+		s.misses++
+	}
+}
+
 func (dg *dockerGoClient) WithVersion(version dockerclient.DockerVersion) (DockerClient, error) {
 	versionedClient := &dockerGoClient{
-		sdkClientFactory:    dg.sdkClientFactory,
-		version:             version,
-		ecrClientFactory:    dg.ecrClientFactory,
-		auth:                dg.auth,
-		ecrTokenCache:       dg.ecrTokenCache,
-		config:              dg.config,
-		context:             dg.context,
-		manifestPullBackoff: dg.manifestPullBackoff,
-		imageTagBackoff:     dg.imageTagBackoff,
+		sdkClientFactory:       dg.sdkClientFactory,
+		version:                version,
+		ecrClientFactory:       dg.ecrClientFactory,
+		auth:                   dg.auth,
+		ecrTokenCache:          dg.ecrTokenCache,
+		config:                 dg.config,
+		context:                dg.context,
+		manifestPullBackoff:    dg.manifestPullBackoff,
+		imageTagBackoff:        dg.imageTagBackoff,
+		daemonReconnectBackoff: dg.daemonReconnectBackoff,
+		connectionStatusUnsafe: DockerConnectionStatus{State: DockerConnectionConnected},
 	}
 	// Check if the version is supported
 	_, err := versionedClient.sdkDockerClient()
@@ -341,8 +412,11 @@ func NewDockerGoClient(sdkclientFactory sdkclientfactory.Factory,
 			pullRetryJitterMultiplier, pullRetryDelayMultiplier),
 		manifestPullBackoff: retry.NewExponentialBackoff(minimumManifestPullRetryDelay,
 			maximumManifestPullRetryDelay, manifestPullRetryJitterMultiplier, manifestPullRetryDelayMultiplier),
-		imageTagBackoff:          retry.NewConstantBackoff(tagImageRetryInterval),
+		imageTagBackoff: retry.NewConstantBackoff(tagImageRetryInterval),
+		daemonReconnectBackoff: retry.NewExponentialBackoff(minimumDaemonReconnectDelay,
+			maximumDaemonReconnectDelay, daemonReconnectJitterMultiplier, daemonReconnectDelayMultiplier),
 		inactivityTimeoutHandler: handleInactivityTimeout,
+		connectionStatusUnsafe:   DockerConnectionStatus{State: DockerConnectionConnected},
 	}, nil
 }
 
@@ -442,15 +516,25 @@ func (dg *dockerGoClient) PullImage(ctx context.Context, image string,
 	defer cancel()
 	response := make(chan DockerContainerMetadata, 1)
 	go func() {
+		var cacheStats *layerCacheStats
 		err := retry.RetryNWithBackoffCtx(ctx, dg.imagePullBackoff, maximumPullRetries,
 			func() error {
-				err := dg.pullImage(ctx, image, authData)
+				stats, err := dg.pullImage(ctx, image, authData)
 				if err != nil {
 					seelog.Errorf("DockerGoClient: failed to pull image %s: [%s] %s", image, err.ErrorName(), err.Error())
+					return err
 				}
-				return err
+				cacheStats = stats
+				return nil
 			})
-		response <- DockerContainerMetadata{Error: wrapPullErrorAsNamedError(image, err)}
+		metadata := DockerContainerMetadata{Error: wrapPullErrorAsNamedError(image, err)}
+		if err == nil && cacheStats != nil {
+			metadata.LayerCacheStats = &apicontainer.LayerCacheStats{
+				HitLayers:  cacheStats.hits,
+				MissLayers: cacheStats.misses,
+			}
+		}
+		response <- metadata
 	}()
 
 	select {
@@ -484,22 +568,25 @@ func wrapPullErrorAsNamedError(image string, err error) apierrors.NamedError {
 }
 
 func (dg *dockerGoClient) pullImage(ctx context.Context, image string,
-	authData *apicontainer.RegistryAuthenticationData) apierrors.NamedError {
+	authData *apicontainer.RegistryAuthenticationData) (*layerCacheStats, apierrors.NamedError) {
 	seelog.Debugf("DockerGoClient: pulling image: %s", image)
 	client, err := dg.sdkDockerClient()
 	if err != nil {
-		return CannotGetDockerClientError{version: dg.version, err: err}
+		return nil, CannotGetDockerClientError{version: dg.version, err: err}
 	}
 
 	sdkAuthConfig, err := dg.getAuthdata(image, authData)
 	if err != nil {
-		return wrapPullErrorAsNamedError(image, err)
+		return nil, wrapPullErrorAsNamedError(image, err)
+	}
+	if err := dg.checkRegistryClientCert(image); err != nil {
+		return nil, CannotPullContainerError{err}
 	}
 	// encode auth data
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(sdkAuthConfig); err != nil {
 		err = redactEcrUrls(image, err)
-		return CannotPullECRContainerError{err}
+		return nil, CannotPullECRContainerError{err}
 	}
 
 	imagePullOpts := types.ImagePullOptions{
@@ -518,6 +605,8 @@ func (dg *dockerGoClient) pullImage(ctx context.Context, image string,
 
 	pullFinished := make(chan error, 1)
 	subCtx, cancelRequest := context.WithCancel(ctx)
+	// cacheStats is only written by the goroutine below and must only be read once it reported a successful pull
+	var cacheStats layerCacheStats
 
 	go func() {
 		defer cancelRequest()
@@ -558,9 +647,12 @@ func (dg *dockerGoClient) pullImage(ctx context.Context, image string,
 			})
 
 			statusDisplayed = dg.filterPullDebugOutput(data, image, statusDisplayed)
+			cacheStats.record(data)
 
 			data = new(ImagePullResponse)
 		}
+		seelog.Infof("DockerGoClient: layer cache stats for image %s: hits: %d, misses: %d",
+			image, cacheStats.hits, cacheStats.misses)
 		pullFinished <- nil
 	}()
 
@@ -570,23 +662,23 @@ func (dg *dockerGoClient) pullImage(ctx context.Context, image string,
 	case pullErr := <-pullFinished:
 		if pullErr != nil {
 			pullErr = redactEcrUrls(image, pullErr)
-			return CannotPullContainerError{pullErr}
+			return nil, CannotPullContainerError{pullErr}
 		}
 		seelog.Debugf("DockerGoClient: pulling image complete: %s", image)
-		return nil
+		return &cacheStats, nil
 	case <-timeout:
-		return &DockerTimeoutError{dockerclient.DockerPullBeginTimeout, "pullBegin"}
+		return nil, &DockerTimeoutError{dockerclient.DockerPullBeginTimeout, "pullBegin"}
 	}
 	seelog.Debugf("DockerGoClient: pull began for image: %s", image)
 
 	err = <-pullFinished
 	if err != nil {
 		err = redactEcrUrls(image, err)
-		return CannotPullContainerError{err}
+		return nil, CannotPullContainerError{err}
 	}
 
 	seelog.Debugf("DockerGoClient: pulling image complete: %s", image)
-	return nil
+	return &cacheStats, nil
 }
 
 func (dg *dockerGoClient) filterPullDebugOutput(data *ImagePullResponse, image string, statusDisplayed time.Time) time.Time {
@@ -605,6 +697,33 @@ func (dg *dockerGoClient) filterPullDebugOutput(data *ImagePullResponse, image s
 	return now
 }
 
+// checkRegistryClientCert verifies the client certificate configured for the image's
+// registry, if any. The agent doesn't present the certificate itself: the Docker daemon
+// does so from its own certs.d directory during the pull. Validating it here lets the
+// pull fail fast with a clear error instead of an opaque TLS handshake failure.
+func (dg *dockerGoClient) checkRegistryClientCert(image string) error {
+	registry := getRegistryHost(image)
+	cert, err := dockerclient.LoadRegistryClientCert(dg.config.RegistryClientCertsDir, registry)
+	if err != nil {
+		return err
+	}
+	if cert != nil {
+		seelog.Debugf("DockerGoClient: validated client certificate for registry %s before pulling image %s",
+			registry, image)
+	}
+	return nil
+}
+
+// getRegistryHost returns the registry host of the image, or an empty string
+// if the image reference cannot be parsed.
+func getRegistryHost(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ""
+	}
+	return reference.Domain(named)
+}
+
 func getRepository(image string) string {
 	repository, tag := utils.ParseRepositoryTag(image)
 	if tag == "" {
@@ -853,11 +972,15 @@ func (dg *dockerGoClient) stopContainer(ctx context.Context, dockerID string, ti
 		return DockerContainerMetadata{Error: CannotGetDockerClientError{version: dg.version, err: err}}
 	}
 
-	timeoutSeconds := int(timeout.Seconds())
-	containerOptions := dockercontainer.StopOptions{
-		Timeout: &timeoutSeconds,
+	if len(dg.config.StopSignalEscalation) > 0 {
+		err = dg.escalateStopSignals(ctx, client, dockerID, timeout)
+	} else {
+		timeoutSeconds := int(timeout.Seconds())
+		containerOptions := dockercontainer.StopOptions{
+			Timeout: &timeoutSeconds,
+		}
+		err = client.ContainerStop(ctx, dockerID, containerOptions)
 	}
-	err = client.ContainerStop(ctx, dockerID, containerOptions)
 	metadata := dg.containerMetadata(ctx, dockerID)
 	if err != nil {
 		seelog.Errorf("DockerGoClient: error stopping container ID=%s: %v", dockerID, err)
@@ -875,6 +998,91 @@ func (dg *dockerGoClient) stopContainer(ctx context.Context, dockerID string, ti
 	return metadata
 }
 
+// escalateStopSignals sends the container's own stop signal, if it has one, followed by each of the configured
+// stop signals to the container in order, giving it an equal share of the stop timeout to exit after each one.
+// The container is killed with SIGKILL if it's still running once the last share has passed. A container that
+// has already exited counts as stopped, as it does for docker's container stop.
+func (dg *dockerGoClient) escalateStopSignals(ctx context.Context, client sdkclient.Client, dockerID string,
+	timeout time.Duration) error {
+	signals := dg.stopSignals(ctx, client, dockerID)
+	interval := timeout / time.Duration(len(signals))
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	exited, waitErr := client.ContainerWait(waitCtx, dockerID, dockercontainer.WaitConditionNotRunning)
+	for _, signal := range signals {
+		seelog.Debugf("DockerGoClient: sending signal %s to container ID=%s", signal, dockerID)
+		if err := client.ContainerKill(ctx, dockerID, signal); err != nil {
+			if isContainerNotRunningError(err) {
+				return nil
+			}
+			return err
+		}
+		timer := dg.time().After(interval)
+		select {
+		case <-exited:
+			return nil
+		case err := <-waitErr:
+			// The container can't be waited on, so it's given the whole interval after each signal
+			seelog.Warnf("DockerGoClient: unable to wait for container ID=%s to exit: %v", dockerID, err)
+			exited, waitErr = nil, nil
+			select {
+			case <-timer:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case <-timer:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	seelog.Debugf("DockerGoClient: container ID=%s still running after stop signal escalation, killing it", dockerID)
+	if err := client.ContainerKill(ctx, dockerID, stopSignalEscalationKillSignal); err != nil {
+		if isContainerNotRunningError(err) {
+			return nil
+		}
+		return err
+	}
+	select {
+	case <-exited:
+	case err := <-waitErr:
+		seelog.Warnf("DockerGoClient: unable to wait for container ID=%s to exit: %v", dockerID, err)
+	case <-dg.time().After(stopSignalEscalationKillTimeout):
+		seelog.Warnf("DockerGoClient: container ID=%s still running %s after it was killed",
+			dockerID, stopSignalEscalationKillTimeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// stopSignals returns the signals to escalate through when stopping the container. The stop signal the
+// container was created with is sent first, followed by the configured stop signals.
+func (dg *dockerGoClient) stopSignals(ctx context.Context, client sdkclient.Client, dockerID string) []string {
+	containerJSON, err := client.ContainerInspect(ctx, dockerID)
+	if err != nil {
+		seelog.Warnf("DockerGoClient: unable to get the stop signal of container ID=%s: %v", dockerID, err)
+		return dg.config.StopSignalEscalation
+	}
+	if containerJSON.Config == nil || containerJSON.Config.StopSignal == "" {
+		return dg.config.StopSignalEscalation
+	}
+
+	stopSignal := containerJSON.Config.StopSignal
+	signals := []string{stopSignal}
+	for _, signal := range dg.config.StopSignalEscalation {
+		if signal != stopSignal {
+			signals = append(signals, signal)
+		}
+	}
+	return signals
+}
+
+// isContainerNotRunningError returns whether the error is the one docker returns when signaling a container
+// that isn't running.
+func isContainerNotRunningError(err error) bool {
+	return errdefs.IsConflict(err) && strings.Contains(err.Error(), "is not running")
+}
+
 func (dg *dockerGoClient) RemoveContainer(ctx context.Context, dockerID string, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -910,6 +1118,57 @@ func (dg *dockerGoClient) removeContainer(ctx context.Context, dockerID string)
 		})
 }
 
+func (dg *dockerGoClient) UpdateContainerResources(ctx context.Context, dockerID string,
+	resources dockercontainer.Resources, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// Buffered channel so in the case of timeout it takes one write, never gets
+	// read, and can still be GC'd
+	response := make(chan error, 1)
+	go func() { response <- dg.updateContainerResources(ctx, dockerID, resources) }()
+	// Wait until we get a response or for the 'done' context channel
+	select {
+	case resp := <-response:
+		return resp
+	case <-ctx.Done():
+		err := ctx.Err()
+		// Context has either expired or canceled. If it has timed out,
+		// send back the DockerTimeoutError
+		if err == context.DeadlineExceeded {
+			return &DockerTimeoutError{timeout, "updating"}
+		}
+		return CannotUpdateContainerError{err}
+	}
+}
+
+func (dg *dockerGoClient) updateContainerResources(ctx context.Context, dockerID string,
+	resources dockercontainer.Resources) error {
+	client, err := dg.sdkDockerClient()
+	if err != nil {
+		return CannotUpdateContainerError{err}
+	}
+	_, err = client.ContainerUpdate(ctx, dockerID, dockercontainer.UpdateConfig{Resources: resources})
+	if err != nil {
+		if strings.Contains(err.Error(), "No such container") {
+			err = NoSuchContainerError{dockerID}
+		}
+		return CannotUpdateContainerError{err}
+	}
+	return nil
+}
+
+func (dg *dockerGoClient) ContainerLogs(ctx context.Context, dockerID string) (io.ReadCloser, error) {
+	client, err := dg.sdkDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.ContainerLogs(ctx, dockerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+}
+
 func (dg *dockerGoClient) containerMetadata(ctx context.Context, id string) DockerContainerMetadata {
 	ctx, cancel := context.WithTimeout(ctx, dockerclient.InspectContainerTimeout)
 	defer cancel()
@@ -1001,6 +1260,7 @@ func getMetadataHealthCheck(dockerContainer *types.ContainerJSON) apicontainer.H
 		}
 		health.Output = output[:size]
 	}
+	health.FailingStreak = dockerContainer.State.Health.FailingStreak
 	switch dockerContainer.State.Health.Status {
 	case healthCheckHealthy:
 		health.Status = apicontainerstatus.ContainerHealthy
@@ -1028,6 +1288,7 @@ func (dg *dockerGoClient) ContainerEvents(ctx context.Context) (<-chan DockerCon
 	buffer := NewInfiniteBuffer()
 
 	derivedCtx, cancel := context.WithCancel(ctx)
+	streamOpenedAt := time.Now()
 	dockerEvents, eventErr := client.Events(derivedCtx, types.EventsOptions{})
 
 	// Cache the event from docker client. Channel closes when an error is passed to eventErr.
@@ -1047,11 +1308,23 @@ func (dg *dockerGoClient) ContainerEvents(ctx context.Context) (<-chan DockerCon
 					seelog.Infof("DockerGoClient: Docker events stream closed with: %v", err)
 				} else {
 					seelog.Errorf("DockerGoClient: Docker events stream closed with error: %v", err)
+					dg.setConnectionStatus(DockerConnectionReconnecting, err)
+					// The daemon may be restarting, wait for it to come back before reopening the stream.
+					if !dg.waitForDaemon(ctx, client) {
+						return
+					}
+					dg.setConnectionStatus(DockerConnectionConnected, err)
 				}
 
-				// Reopen a new event stream to continue listening.
+				// Reopen a new event stream to continue listening. Events emitted while the stream was
+				// down are replayed from the last one received, so container state changes aren't lost.
+				// The last event itself may be replayed, which is harmless as its status was already applied.
+				since := buffer.LastEventTime()
+				if since.IsZero() {
+					since = streamOpenedAt
+				}
 				nextCtx, nextCancel := context.WithCancel(ctx)
-				dockerEvents, eventErr = client.Events(nextCtx, types.EventsOptions{})
+				dockerEvents, eventErr = client.Events(nextCtx, types.EventsOptions{Since: eventsSince(since)})
 				// Cache the event from docker client.
 				go buffer.StartListening(nextCtx, dockerEvents)
 				// Close previous stream after starting to listen on new one
@@ -1072,6 +1345,33 @@ func (dg *dockerGoClient) ContainerEvents(ctx context.Context) (<-chan DockerCon
 	return changedContainers, nil
 }
 
+// eventsSince formats t as the 'since' filter of the docker events API, which takes
+// seconds since the epoch with an optional fractional part.
+func eventsSince(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
+
+// waitForDaemon pings the docker daemon with backoff until it responds. It returns false
+// if ctx is canceled before the daemon could be reached.
+func (dg *dockerGoClient) waitForDaemon(ctx context.Context, client sdkclient.Client) bool {
+	defer dg.daemonReconnectBackoff.Reset()
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, daemonReconnectPingTimeout)
+		_, err := client.Ping(pingCtx)
+		cancel()
+		if err == nil {
+			return true
+		}
+		delay := dg.daemonReconnectBackoff.Duration()
+		seelog.Warnf("DockerGoClient: unable to reach Docker daemon, retrying in %v: %v", delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
 func (dg *dockerGoClient) handleContainerEvents(ctx context.Context,
 	events <-chan *events.Message,
 	changedContainers chan<- DockerContainerChangeEvent) {
@@ -1121,7 +1421,15 @@ func (dg *dockerGoClient) handleContainerEvents(ctx context.Context,
 			seelog.Debugf("DockerGoClient: unknown status event from docker: %v", event)
 		}
 
-		metadata := dg.containerMetadata(ctx, containerID)
+		var metadata DockerContainerMetadata
+		if eventType == apicontainer.ContainerHealthEvent {
+			metadata = dg.healthCheckMetadata(ctx, containerID, event.Status)
+		} else {
+			if status == apicontainerstatus.ContainerStopped {
+				dg.forgetHealthCheckResult(containerID)
+			}
+			metadata = dg.containerMetadata(ctx, containerID)
+		}
 		// In case when we received a container die event but was not able to inspect the container (e.g. due to timeout),
 		// we will use the exit code from the event, so that the exit code of the container is still reported and
 		// available for customer to see from describing task.
@@ -1135,6 +1443,43 @@ func (dg *dockerGoClient) handleContainerEvents(ctx context.Context,
 	}
 }
 
+// healthCheckMetadata returns the container metadata for a health status event. The container is only inspected
+// again if the last health status event of the container reported a different status or was inspected more than
+// healthCheckCacheTTL ago, so that containers with frequent health probes don't cause an inspect call each time.
+func (dg *dockerGoClient) healthCheckMetadata(ctx context.Context, containerID, eventStatus string) DockerContainerMetadata {
+	dg.lock.Lock()
+	cached, ok := dg.healthCheckCacheUnsafe[containerID]
+	dg.lock.Unlock()
+	if ok && cached.eventStatus == eventStatus && time.Since(cached.inspectedAt) < healthCheckCacheTTL {
+		seelog.Debugf("DockerGoClient: using cached health result for container %s", containerID)
+		return cached.metadata
+	}
+
+	metadata := dg.containerMetadata(ctx, containerID)
+	if metadata.Error != nil {
+		return metadata
+	}
+	dg.lock.Lock()
+	defer dg.lock.Unlock()
+	if dg.healthCheckCacheUnsafe == nil {
+		dg.healthCheckCacheUnsafe = make(map[string]cachedHealthCheckResult)
+	}
+	dg.healthCheckCacheUnsafe[containerID] = cachedHealthCheckResult{
+		eventStatus: eventStatus,
+		metadata:    metadata,
+		inspectedAt: time.Now(),
+	}
+	return metadata
+}
+
+// forgetHealthCheckResult removes the cached health result of a container
+func (dg *dockerGoClient) forgetHealthCheckResult(containerID string) {
+	dg.lock.Lock()
+	defer dg.lock.Unlock()
+
+	delete(dg.healthCheckCacheUnsafe, containerID)
+}
+
 // setExitCodeFromEvent tries to get exit code from event and stores it in metadata, if metadata doesn't
 // contain the exit code already.
 func setExitCodeFromEvent(event *events.Message, metadata *DockerContainerMetadata) {
@@ -1334,6 +1679,24 @@ func (dg *dockerGoClient) setDaemonVersion(version string) {
 	dg.daemonVersionUnsafe = version
 }
 
+// ConnectionStatus returns the health of the connection to the Docker daemon
+func (dg *dockerGoClient) ConnectionStatus() DockerConnectionStatus {
+	dg.lock.Lock()
+	defer dg.lock.Unlock()
+
+	return dg.connectionStatusUnsafe
+}
+
+func (dg *dockerGoClient) setConnectionStatus(state string, lastErr error) {
+	dg.lock.Lock()
+	defer dg.lock.Unlock()
+
+	dg.connectionStatusUnsafe = DockerConnectionStatus{
+		State:     state,
+		LastError: lastErr.Error(),
+	}
+}
+
 func (dg *dockerGoClient) CreateVolume(ctx context.Context, name string,
 	driver string,
 	driverOptions map[string]string,
@@ -19,9 +19,12 @@ package dockerapi
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -54,6 +57,7 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -218,6 +222,117 @@ func TestImagePull(t *testing.T) {
 	assert.NoError(t, metadata.Error, "Expected pull to succeed")
 }
 
+func TestImagePullPartiallyCachedLayerCacheStats(t *testing.T) {
+	mockDockerSDK, client, testTime, _, _, done := dockerClientSetup(t)
+	defer done()
+
+	testTime.EXPECT().After(gomock.Any()).AnyTimes()
+
+	pullOutput := `{"status":"Pulling from library/image","id":"latest"}
+{"status":"Already exists","id":"a1"}
+{"status":"Already exists","id":"b2"}
+{"status":"Pulling fs layer","id":"c3"}
+{"status":"Download complete","id":"c3"}
+{"status":"Pull complete","id":"c3"}
+{"status":"Status: Downloaded newer image for image:latest"}`
+	mockDockerSDK.EXPECT().ImagePull(gomock.Any(), "image:latest", gomock.Any()).Return(
+		mockReadCloser{
+			reader: strings.NewReader(pullOutput),
+		}, nil)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	metadata := client.PullImage(ctx, "image", nil, defaultTestConfig().ImagePullTimeout)
+	require.NoError(t, metadata.Error, "Expected pull to succeed")
+	assert.Equal(t, &apicontainer.LayerCacheStats{HitLayers: 2, MissLayers: 1}, metadata.LayerCacheStats)
+}
+
+func TestLayerCacheStatsRecord(t *testing.T) {
+	pullOutput := []string{
+		`{"status":"Pulling from library/busybox","id":"latest"}`,
+		`{"status":"Already exists","id":"a1"}`,
+		`{"status":"Pulling fs layer","id":"b2"}`,
+		`{"status":"Already exists","id":"c3"}`,
+		`{"status":"Downloading","progressDetail":{"current":100,"total":200},"id":"b2"}`,
+		`{"status":"Download complete","id":"b2"}`,
+		`{"status":"Pull complete","id":"b2"}`,
+		`{"status":"Digest: sha256:0123456789abcdef"}`,
+		`{"status":"Status: Downloaded newer image for busybox:latest"}`,
+	}
+
+	var stats layerCacheStats
+	for _, line := range pullOutput {
+		data := new(ImagePullResponse)
+		require.NoError(t, json.Unmarshal([]byte(line), data))
+		stats.record(data)
+	}
+	assert.Equal(t, 2, stats.hits)
+	assert.Equal(t, 1, stats.misses)
+}
+
+func TestImagePullInvalidRegistryClientCert(t *testing.T) {
+	certsDir := t.TempDir()
+	registryDir := filepath.Join(certsDir, "registry.example.com")
+	require.NoError(t, os.MkdirAll(registryDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, dockerclient.RegistryClientCertFile), []byte("invalid"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, dockerclient.RegistryClientKeyFile), []byte("invalid"), 0600))
+
+	conf := config.DefaultConfig()
+	conf.RegistryClientCertsDir = certsDir
+	_, client, testTime, _, _, done := dockerClientSetupWithConfig(t, conf)
+	defer done()
+
+	testTime.EXPECT().After(gomock.Any()).AnyTimes()
+	// ImagePull is not expected to be called as the client certificate can't be loaded
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	metadata := client.PullImage(ctx, "registry.example.com/image", nil, defaultTestConfig().ImagePullTimeout)
+	require.Error(t, metadata.Error, "Expected pull to fail")
+	assert.Equal(t, "CannotPullContainerError", metadata.Error.(apierrors.NamedError).ErrorName(), "Wrong error type")
+}
+
+func TestImagePullRegistryClientCertOtherRegistry(t *testing.T) {
+	certsDir := t.TempDir()
+	registryDir := filepath.Join(certsDir, "registry.example.com")
+	require.NoError(t, os.MkdirAll(registryDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, dockerclient.RegistryClientCertFile), []byte("invalid"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, dockerclient.RegistryClientKeyFile), []byte("invalid"), 0600))
+
+	conf := config.DefaultConfig()
+	conf.RegistryClientCertsDir = certsDir
+	mockDockerSDK, client, testTime, _, _, done := dockerClientSetupWithConfig(t, conf)
+	defer done()
+
+	testTime.EXPECT().After(gomock.Any()).AnyTimes()
+	mockDockerSDK.EXPECT().ImagePull(gomock.Any(), "other.example.com/image:latest", gomock.Any()).Return(
+		mockReadCloser{
+			reader: strings.NewReader(`{"status":"pull complete"}`),
+		}, nil)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	metadata := client.PullImage(ctx, "other.example.com/image", nil, defaultTestConfig().ImagePullTimeout)
+	assert.NoError(t, metadata.Error, "Expected pull to succeed")
+}
+
+func TestGetRegistryHost(t *testing.T) {
+	testCases := []struct {
+		image    string
+		expected string
+	}{
+		{"image", "docker.io"},
+		{"registry.example.com/image:tag", "registry.example.com"},
+		{"registry.example.com:5000/repo/image", "registry.example.com:5000"},
+		{"Invalid::Image", ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getRegistryHost(tc.image))
+		})
+	}
+}
+
 func TestImagePullTag(t *testing.T) {
 	mockDockerSDK, client, testTime, _, _, done := dockerClientSetup(t)
 	defer done()
@@ -864,6 +979,198 @@ func TestStopContainer(t *testing.T) {
 	assert.Equal(t, "id", metadata.DockerID)
 }
 
+func TestStopContainerSignalEscalation(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StopSignalEscalation = []string{"SIGTERM", "SIGINT"}
+	mockDockerSDK, client, testTime, _, _, done := dockerClientSetupWithConfig(t, cfg)
+	defer done()
+
+	// the container never exits on its own, so every signal is given half of the stop timeout, and
+	// the escalation waits for the container to exit once it's killed
+	intervalElapsed := func() <-chan time.Time {
+		elapsed := make(chan time.Time, 1)
+		elapsed <- time.Now()
+		return elapsed
+	}
+	exited := make(chan dockercontainer.WaitResponse, 1)
+	mockDockerSDK.EXPECT().ContainerWait(gomock.Any(), "id", dockercontainer.WaitConditionNotRunning).
+		Return(exited, make(chan error))
+	gomock.InOrder(
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{ID: "id"},
+			Config:            &dockercontainer.Config{},
+		}, nil),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGTERM").Return(nil),
+		testTime.EXPECT().After(10*time.Second).Return(intervalElapsed()),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGINT").Return(nil),
+		testTime.EXPECT().After(10*time.Second).Return(intervalElapsed()),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGKILL").Do(
+			func(_ context.Context, _ string, _ string) {
+				exited <- dockercontainer.WaitResponse{StatusCode: 137}
+			}).Return(nil),
+		testTime.EXPECT().After(stopSignalEscalationKillTimeout).Return(make(chan time.Time)),
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "id",
+				State: &types.ContainerState{ExitCode: 137},
+			},
+			Config: &dockercontainer.Config{},
+		}, nil),
+	)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	metadata := client.StopContainer(ctx, "id", 20*time.Second)
+	assert.NoError(t, metadata.Error)
+	assert.Equal(t, "id", metadata.DockerID)
+}
+
+func TestStopContainerSignalEscalationKillTimeout(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StopSignalEscalation = []string{"SIGTERM"}
+	mockDockerSDK, client, testTime, _, _, done := dockerClientSetupWithConfig(t, cfg)
+	defer done()
+
+	// the container doesn't exit once it's killed, so the escalation only waits for it until the
+	// kill timeout elapses
+	elapsed := func() <-chan time.Time {
+		elapsed := make(chan time.Time, 1)
+		elapsed <- time.Now()
+		return elapsed
+	}
+	mockDockerSDK.EXPECT().ContainerWait(gomock.Any(), "id", dockercontainer.WaitConditionNotRunning).
+		Return(make(chan dockercontainer.WaitResponse), make(chan error))
+	gomock.InOrder(
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{ID: "id"},
+			Config:            &dockercontainer.Config{},
+		}, nil),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGTERM").Return(nil),
+		testTime.EXPECT().After(20*time.Second).Return(elapsed()),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGKILL").Return(nil),
+		testTime.EXPECT().After(stopSignalEscalationKillTimeout).Return(elapsed()),
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "id",
+				State: &types.ContainerState{Running: true},
+			},
+			Config: &dockercontainer.Config{},
+		}, nil),
+	)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	metadata := client.StopContainer(ctx, "id", 20*time.Second)
+	assert.NoError(t, metadata.Error)
+	assert.Equal(t, "id", metadata.DockerID)
+}
+
+func TestStopContainerSignalEscalationContainerExits(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StopSignalEscalation = []string{"SIGTERM", "SIGINT"}
+	mockDockerSDK, client, testTime, _, _, done := dockerClientSetupWithConfig(t, cfg)
+	defer done()
+
+	// the container exits after the first signal, so no further signals are sent
+	exited := make(chan dockercontainer.WaitResponse, 1)
+	exited <- dockercontainer.WaitResponse{StatusCode: 0}
+	mockDockerSDK.EXPECT().ContainerWait(gomock.Any(), "id", dockercontainer.WaitConditionNotRunning).
+		Return(exited, make(chan error))
+	gomock.InOrder(
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{ID: "id"},
+			Config:            &dockercontainer.Config{},
+		}, nil),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGTERM").Return(nil),
+		testTime.EXPECT().After(10*time.Second).Return(make(chan time.Time)),
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "id",
+				State: &types.ContainerState{},
+			},
+			Config: &dockercontainer.Config{},
+		}, nil),
+	)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	metadata := client.StopContainer(ctx, "id", 20*time.Second)
+	assert.NoError(t, metadata.Error)
+	assert.Equal(t, "id", metadata.DockerID)
+}
+
+func TestStopContainerSignalEscalationContainerStopSignal(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StopSignalEscalation = []string{"SIGTERM", "SIGINT"}
+	mockDockerSDK, client, testTime, _, _, done := dockerClientSetupWithConfig(t, cfg)
+	defer done()
+
+	// the container's own stop signal is sent before the configured ones, and it's only sent once
+	exited := make(chan dockercontainer.WaitResponse, 1)
+	mockDockerSDK.EXPECT().ContainerWait(gomock.Any(), "id", dockercontainer.WaitConditionNotRunning).
+		Return(exited, make(chan error))
+	intervalElapsed := func() <-chan time.Time {
+		elapsed := make(chan time.Time, 1)
+		elapsed <- time.Now()
+		return elapsed
+	}
+	gomock.InOrder(
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{ID: "id"},
+			Config:            &dockercontainer.Config{StopSignal: "SIGINT"},
+		}, nil),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGINT").Return(nil),
+		testTime.EXPECT().After(10*time.Second).Return(intervalElapsed()),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGTERM").Do(
+			func(_ context.Context, _ string, _ string) {
+				exited <- dockercontainer.WaitResponse{StatusCode: 0}
+			}).Return(nil),
+		testTime.EXPECT().After(10*time.Second).Return(make(chan time.Time)),
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "id",
+				State: &types.ContainerState{},
+			},
+			Config: &dockercontainer.Config{},
+		}, nil),
+	)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	metadata := client.StopContainer(ctx, "id", 20*time.Second)
+	assert.NoError(t, metadata.Error)
+}
+
+func TestStopContainerSignalEscalationContainerNotRunning(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StopSignalEscalation = []string{"SIGTERM", "SIGINT"}
+	mockDockerSDK, client, testTime, _, _, done := dockerClientSetupWithConfig(t, cfg)
+	defer done()
+
+	// the container exits between signals, so the next signal fails because it isn't running anymore
+	intervalElapsed := make(chan time.Time, 1)
+	intervalElapsed <- time.Now()
+	mockDockerSDK.EXPECT().ContainerWait(gomock.Any(), "id", dockercontainer.WaitConditionNotRunning).
+		Return(make(chan dockercontainer.WaitResponse), make(chan error))
+	gomock.InOrder(
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{ID: "id"},
+			Config:            &dockercontainer.Config{},
+		}, nil),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGTERM").Return(nil),
+		testTime.EXPECT().After(10*time.Second).Return(intervalElapsed),
+		mockDockerSDK.EXPECT().ContainerKill(gomock.Any(), "id", "SIGINT").Return(
+			errdefs.Conflict(errors.New("Error response from daemon: Container id is not running"))),
+		mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "id").Return(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "id",
+				State: &types.ContainerState{},
+			},
+			Config: &dockercontainer.Config{},
+		}, nil),
+	)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	metadata := client.StopContainer(ctx, "id", 20*time.Second)
+	assert.NoError(t, metadata.Error, "a container that has already exited should count as stopped")
+}
+
 func TestRemoveContainerTimeout(t *testing.T) {
 	mockDockerSDK, client, _, _, _, done := dockerClientSetup(t)
 	defer done()
@@ -904,6 +1211,71 @@ func TestRemoveContainer(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUpdateContainerResources(t *testing.T) {
+	mockDockerSDK, client, _, _, _, done := dockerClientSetup(t)
+	defer done()
+
+	resources := dockercontainer.Resources{CPUShares: 512, Memory: 1024 * 1024 * 1024}
+	mockDockerSDK.EXPECT().ContainerUpdate(gomock.Any(), "id",
+		dockercontainer.UpdateConfig{Resources: resources}).Return(dockercontainer.ContainerUpdateOKBody{}, nil)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	err := client.UpdateContainerResources(ctx, "id", resources, dockerclient.UpdateContainerTimeout)
+	assert.NoError(t, err)
+}
+
+func TestContainerLogs(t *testing.T) {
+	mockDockerSDK, client, _, _, _, done := dockerClientSetup(t)
+	defer done()
+
+	logs := io.NopCloser(strings.NewReader("logs"))
+	mockDockerSDK.EXPECT().ContainerLogs(gomock.Any(), "id", types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	}).Return(logs, nil)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	stream, err := client.ContainerLogs(ctx, "id")
+	require.NoError(t, err)
+	assert.Equal(t, logs, stream)
+}
+
+func TestUpdateContainerResourcesNoSuchContainer(t *testing.T) {
+	mockDockerSDK, client, _, _, _, done := dockerClientSetup(t)
+	defer done()
+
+	mockDockerSDK.EXPECT().ContainerUpdate(gomock.Any(), "id", gomock.Any()).Return(
+		dockercontainer.ContainerUpdateOKBody{}, errors.New("No such container: id"))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	err := client.UpdateContainerResources(ctx, "id", dockercontainer.Resources{}, dockerclient.UpdateContainerTimeout)
+	require.Error(t, err)
+	assert.Equal(t, "CannotUpdateContainerError", err.(apierrors.NamedError).ErrorName())
+	assert.IsType(t, NoSuchContainerError{}, err.(CannotUpdateContainerError).FromError)
+}
+
+func TestUpdateContainerResourcesTimeout(t *testing.T) {
+	mockDockerSDK, client, _, _, _, done := dockerClientSetup(t)
+	defer done()
+
+	wait := &sync.WaitGroup{}
+	wait.Add(1)
+	mockDockerSDK.EXPECT().ContainerUpdate(gomock.Any(), "id", gomock.Any()).Do(func(x, y, z interface{}) {
+		wait.Wait() // wait until timeout happens
+	}).MaxTimes(1)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	err := client.UpdateContainerResources(ctx, "id", dockercontainer.Resources{}, xContainerShortTimeout)
+	assert.Error(t, err, "Expected error for update timeout")
+	assert.Equal(t, "DockerTimeoutError", err.(apierrors.NamedError).ErrorName(), "Wrong error type")
+	wait.Done()
+}
+
 func TestInspectContainerTimeout(t *testing.T) {
 	mockDockerSDK, client, _, _, _, done := dockerClientSetup(t)
 	defer done()
@@ -1028,7 +1400,8 @@ func TestContainerEvents(t *testing.T) {
 			ID: "container_health",
 			State: &types.ContainerState{
 				Health: &types.Health{
-					Status: "healthy",
+					Status:        "healthy",
+					FailingStreak: 2,
 					Log: []*types.HealthcheckResult{
 						{
 							ExitCode: 1,
@@ -1055,6 +1428,7 @@ func TestContainerEvents(t *testing.T) {
 	assert.Equal(t, anEvent.Type, apicontainer.ContainerHealthEvent, "unexpected docker events type received")
 	assert.Equal(t, anEvent.Health.Status, apicontainerstatus.ContainerHealthy)
 	assert.Equal(t, anEvent.Health.Output, "health output")
+	assert.Equal(t, 2, anEvent.Health.FailingStreak)
 
 	// Verify the following events do not translate into our event stream
 
@@ -1136,6 +1510,7 @@ func TestContainerEventsError(t *testing.T) {
 			eventsChan := make(chan events.Message, dockerEventBufferSize)
 			errChan := make(chan error)
 			mockDockerSDK.EXPECT().Events(gomock.Any(), gomock.Any()).Return(eventsChan, errChan).MinTimes(1)
+			mockDockerSDK.EXPECT().Ping(gomock.Any()).Return(types.Ping{}, nil).AnyTimes()
 
 			dockerEvents, err := client.ContainerEvents(context.TODO())
 			require.NoError(t, err, "Could not get container events")
@@ -1151,6 +1526,140 @@ func TestContainerEventsError(t *testing.T) {
 	}
 }
 
+func TestContainerEventsHealthCheckCache(t *testing.T) {
+	mockDockerSDK, client, _, _, _, done := dockerClientSetup(t)
+	defer done()
+
+	eventsChan := make(chan events.Message, dockerEventBufferSize)
+	errChan := make(chan error)
+	mockDockerSDK.EXPECT().Events(gomock.Any(), gomock.Any()).Return(eventsChan, errChan)
+
+	dockerEvents, err := client.ContainerEvents(context.TODO())
+	require.NoError(t, err, "Could not get container events")
+
+	containerWithHealth := func(status string) types.ContainerJSON {
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID: "container_health",
+				State: &types.ContainerState{
+					Health: &types.Health{
+						Status: status,
+						Log:    []*types.HealthcheckResult{{Output: status + " output"}},
+					},
+				},
+			},
+		}
+	}
+	healthEvent := func(status string) events.Message {
+		return events.Message{
+			Type:   "container",
+			ID:     "container_health",
+			Status: "health_status: " + status,
+			Actor:  events.Actor{ID: "container_health"},
+		}
+	}
+
+	// the first health status event inspects the container, a repeated event with the same status
+	// within the cache window reuses the result
+	mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "container_health").
+		Return(containerWithHealth("healthy"), nil).Times(1)
+	for i := 0; i < 2; i++ {
+		eventsChan <- healthEvent("healthy")
+		anEvent := <-dockerEvents
+		assert.Equal(t, apicontainer.ContainerHealthEvent, anEvent.Type)
+		assert.Equal(t, apicontainerstatus.ContainerHealthy, anEvent.Health.Status)
+		assert.Equal(t, "healthy output", anEvent.Health.Output)
+	}
+
+	// a status change is never served from the cache
+	mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "container_health").
+		Return(containerWithHealth("unhealthy"), nil).Times(1)
+	eventsChan <- healthEvent("unhealthy")
+	anEvent := <-dockerEvents
+	assert.Equal(t, apicontainerstatus.ContainerUnhealthy, anEvent.Health.Status)
+
+	// the container is inspected again once the cached result expired
+	client.lock.Lock()
+	cached := client.healthCheckCacheUnsafe["container_health"]
+	cached.inspectedAt = time.Now().Add(-healthCheckCacheTTL)
+	client.healthCheckCacheUnsafe["container_health"] = cached
+	client.lock.Unlock()
+	mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "container_health").
+		Return(containerWithHealth("unhealthy"), nil).Times(1)
+	eventsChan <- healthEvent("unhealthy")
+	anEvent = <-dockerEvents
+	assert.Equal(t, apicontainerstatus.ContainerUnhealthy, anEvent.Health.Status)
+
+	// the cached result is dropped when the container stops
+	mockDockerSDK.EXPECT().ContainerInspect(gomock.Any(), "container_health").
+		Return(containerWithHealth("unhealthy"), nil).Times(1)
+	eventsChan <- events.Message{Type: "container", ID: "container_health", Status: "die"}
+	<-dockerEvents
+	client.lock.Lock()
+	assert.NotContains(t, client.healthCheckCacheUnsafe, "container_health")
+	client.lock.Unlock()
+}
+
+func TestContainerEventsDaemonReconnect(t *testing.T) {
+	mockDockerSDK, client, _, _, _, done := dockerClientSetup(t)
+	defer done()
+	client.daemonReconnectBackoff = retry.NewConstantBackoff(time.Millisecond)
+
+	disconnectedErrChan := make(chan error)
+	initialEventsChan := make(chan events.Message, dockerEventBufferSize)
+	eventsChan := make(chan events.Message, dockerEventBufferSize)
+	lastEventTime := time.Unix(1700000000, 123456789)
+	gomock.InOrder(
+		mockDockerSDK.EXPECT().Events(gomock.Any(), types.EventsOptions{}).Return(initialEventsChan, disconnectedErrChan),
+		// the daemon is unreachable while it restarts
+		mockDockerSDK.EXPECT().Ping(gomock.Any()).DoAndReturn(func(context.Context) (types.Ping, error) {
+			assert.Equal(t, DockerConnectionStatus{
+				State:     DockerConnectionReconnecting,
+				LastError: "unexpected EOF",
+			}, client.ConnectionStatus())
+			return types.Ping{}, errors.New("connection refused")
+		}).Times(2),
+		mockDockerSDK.EXPECT().Ping(gomock.Any()).Return(types.Ping{}, nil),
+		// the stream resumes from the last event received
+		mockDockerSDK.EXPECT().Events(gomock.Any(), types.EventsOptions{Since: "1700000000.123456789"}).
+			Return(eventsChan, make(chan error)),
+	)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	assert.Equal(t, DockerConnectionConnected, client.ConnectionStatus().State)
+	dockerEvents, err := client.ContainerEvents(ctx)
+	require.NoError(t, err, "Could not get container events")
+	initialEventsChan <- events.Message{Type: "container", ID: "previousId", Status: "create", TimeNano: lastEventTime.UnixNano()}
+	event := <-dockerEvents
+	assert.Equal(t, "previousId", event.DockerID)
+
+	disconnectedErrChan <- errors.New("unexpected EOF")
+	eventsChan <- events.Message{Type: "container", ID: "containerId", Status: "create"}
+
+	event = <-dockerEvents
+	assert.Equal(t, "containerId", event.DockerID)
+	assert.Equal(t, apicontainerstatus.ContainerCreated, event.Status)
+	assert.Equal(t, DockerConnectionStatus{
+		State:     DockerConnectionConnected,
+		LastError: "unexpected EOF",
+	}, client.ConnectionStatus())
+}
+
+func TestWaitForDaemonContextCanceled(t *testing.T) {
+	mockDockerSDK, client, _, _, _, done := dockerClientSetup(t)
+	defer done()
+	client.daemonReconnectBackoff = retry.NewConstantBackoff(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	mockDockerSDK.EXPECT().Ping(gomock.Any()).DoAndReturn(func(context.Context) (types.Ping, error) {
+		cancel()
+		return types.Ping{}, errors.New("connection refused")
+	})
+
+	assert.False(t, client.waitForDaemon(ctx, mockDockerSDK))
+}
+
 func TestSetExitCodeFromEvent(t *testing.T) {
 	var (
 		exitCodeInt    = 42
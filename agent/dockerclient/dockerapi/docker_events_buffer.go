@@ -16,6 +16,7 @@ package dockerapi
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types/events"
 )
@@ -43,6 +44,8 @@ type InfiniteBuffer struct {
 	empty        bool
 	waitForEvent sync.WaitGroup
 	lock         sync.RWMutex
+	// lastEventTimeNano is the timestamp of the newest event read from the stream
+	lastEventTimeNano int64
 }
 
 // NewInfiniteBuffer returns an InfiniteBuffer object
@@ -70,6 +73,12 @@ func (buffer *InfiniteBuffer) StartListening(ctx context.Context, eventChan <-ch
 
 // CopyEvents copies the event into the buffer
 func (buffer *InfiniteBuffer) CopyEvents(event *events.Message) {
+	buffer.lock.Lock()
+	if event.TimeNano > buffer.lastEventTimeNano {
+		buffer.lastEventTimeNano = event.TimeNano
+	}
+	buffer.lock.Unlock()
+
 	if event.ID == "" || event.Type != containerTypeEvent {
 		return
 	}
@@ -93,6 +102,18 @@ func (buffer *InfiniteBuffer) CopyEvents(event *events.Message) {
 	}
 }
 
+// LastEventTime returns the timestamp of the newest event read from the stream, or the
+// zero time if no event has been read yet
+func (buffer *InfiniteBuffer) LastEventTime() time.Time {
+	buffer.lock.RLock()
+	defer buffer.lock.RUnlock()
+
+	if buffer.lastEventTimeNano == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, buffer.lastEventTimeNano)
+}
+
 // Consume reads the buffer and write to a listener channel
 func (buffer *InfiniteBuffer) Consume(in chan<- *events.Message) {
 	for {
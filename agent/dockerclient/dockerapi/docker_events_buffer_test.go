@@ -20,6 +20,7 @@ import (
 	"context"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types/events"
 	"github.com/stretchr/testify/assert"
@@ -71,3 +72,14 @@ func TestIgnoreEvents(t *testing.T) {
 	defer buffer.lock.Unlock()
 	assert.Len(t, buffer.events, 0)
 }
+
+func TestLastEventTime(t *testing.T) {
+	buffer := NewInfiniteBuffer()
+	assert.True(t, buffer.LastEventTime().IsZero())
+
+	newest := time.Unix(1700000000, 500)
+	buffer.CopyEvents(&events.Message{ID: "id", Type: containerTypeEvent, Status: "start", TimeNano: newest.UnixNano()})
+	// an older event copied out of order doesn't move the timestamp back
+	buffer.CopyEvents(&events.Message{ID: "id", Type: containerTypeEvent, Status: "create", TimeNano: newest.UnixNano() - 1})
+	assert.True(t, newest.Equal(buffer.LastEventTime()))
+}
@@ -285,6 +285,20 @@ func (err CannotRemoveContainerError) ErrorName() string {
 	return "CannotRemoveContainerError"
 }
 
+// CannotUpdateContainerError indicates any error when trying to update the resources of a container
+type CannotUpdateContainerError struct {
+	FromError error
+}
+
+func (err CannotUpdateContainerError) Error() string {
+	return err.FromError.Error()
+}
+
+// ErrorName returns name of the CannotUpdateContainerError
+func (err CannotUpdateContainerError) ErrorName() string {
+	return "CannotUpdateContainerError"
+}
+
 // CannotDescribeContainerError indicates any error when trying to describe a container
 type CannotDescribeContainerError struct {
 	FromError error
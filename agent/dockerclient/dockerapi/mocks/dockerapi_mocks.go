@@ -34,6 +34,7 @@ import (
 	filters "github.com/docker/docker/api/types/filters"
 	registry "github.com/docker/docker/api/types/registry"
 	gomock "github.com/golang/mock/gomock"
+	digest "github.com/opencontainers/go-digest"
 )
 
 // MockDockerClient is a mock of DockerClient interface.
@@ -74,6 +75,20 @@ func (mr *MockDockerClientMockRecorder) APIVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIVersion", reflect.TypeOf((*MockDockerClient)(nil).APIVersion))
 }
 
+// ConnectionStatus mocks base method.
+func (m *MockDockerClient) ConnectionStatus() dockerapi.DockerConnectionStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectionStatus")
+	ret0, _ := ret[0].(dockerapi.DockerConnectionStatus)
+	return ret0
+}
+
+// ConnectionStatus indicates an expected call of ConnectionStatus.
+func (mr *MockDockerClientMockRecorder) ConnectionStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectionStatus", reflect.TypeOf((*MockDockerClient)(nil).ConnectionStatus))
+}
+
 // ContainerEvents mocks base method.
 func (m *MockDockerClient) ContainerEvents(arg0 context.Context) (<-chan dockerapi.DockerContainerChangeEvent, error) {
 	m.ctrl.T.Helper()
@@ -89,6 +104,21 @@ func (mr *MockDockerClientMockRecorder) ContainerEvents(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerEvents", reflect.TypeOf((*MockDockerClient)(nil).ContainerEvents), arg0)
 }
 
+// ContainerLogs mocks base method.
+func (m *MockDockerClient) ContainerLogs(arg0 context.Context, arg1 string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainerLogs", arg0, arg1)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerLogs indicates an expected call of ContainerLogs.
+func (mr *MockDockerClientMockRecorder) ContainerLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerLogs", reflect.TypeOf((*MockDockerClient)(nil).ContainerLogs), arg0, arg1)
+}
+
 // CreateContainer mocks base method.
 func (m *MockDockerClient) CreateContainer(arg0 context.Context, arg1 *container0.Config, arg2 *container0.HostConfig, arg3 string, arg4 time.Duration) dockerapi.DockerContainerMetadata {
 	m.ctrl.T.Helper()
@@ -320,6 +350,21 @@ func (mr *MockDockerClientMockRecorder) PullImage(arg0, arg1, arg2, arg3 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullImage", reflect.TypeOf((*MockDockerClient)(nil).PullImage), arg0, arg1, arg2, arg3)
 }
 
+// PullImageLayerMediaTypes mocks base method.
+func (m *MockDockerClient) PullImageLayerMediaTypes(arg0 context.Context, arg1 string, arg2 digest.Digest, arg3 *container.RegistryAuthenticationData) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullImageLayerMediaTypes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullImageLayerMediaTypes indicates an expected call of PullImageLayerMediaTypes.
+func (mr *MockDockerClientMockRecorder) PullImageLayerMediaTypes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullImageLayerMediaTypes", reflect.TypeOf((*MockDockerClient)(nil).PullImageLayerMediaTypes), arg0, arg1, arg2, arg3)
+}
+
 // PullImageManifest mocks base method.
 func (m *MockDockerClient) PullImageManifest(arg0 context.Context, arg1 string, arg2 *container.RegistryAuthenticationData) (registry.DistributionInspect, errors.NamedError) {
 	m.ctrl.T.Helper()
@@ -476,6 +521,20 @@ func (mr *MockDockerClientMockRecorder) TagImage(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagImage", reflect.TypeOf((*MockDockerClient)(nil).TagImage), arg0, arg1, arg2)
 }
 
+// UpdateContainerResources mocks base method.
+func (m *MockDockerClient) UpdateContainerResources(arg0 context.Context, arg1 string, arg2 container0.Resources, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContainerResources", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateContainerResources indicates an expected call of UpdateContainerResources.
+func (mr *MockDockerClientMockRecorder) UpdateContainerResources(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContainerResources", reflect.TypeOf((*MockDockerClient)(nil).UpdateContainerResources), arg0, arg1, arg2, arg3)
+}
+
 // Version mocks base method.
 func (m *MockDockerClient) Version(arg0 context.Context, arg1 time.Duration) (string, error) {
 	m.ctrl.T.Helper()
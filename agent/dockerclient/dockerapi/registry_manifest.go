@@ -0,0 +1,291 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dockerapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"runtime"
+	"strings"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// mediaTypeDockerManifest is the media type of docker v2 schema 2 manifests
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	// mediaTypeDockerManifestList is the media type of docker v2 schema 2 manifest lists
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	// dockerHubDomain is the domain docker normalizes image references without a registry to
+	dockerHubDomain = "docker.io"
+	// dockerHubRegistryHost is the host serving the registry API of docker hub
+	dockerHubRegistryHost = "registry-1.docker.io"
+	// maxManifestSize bounds the size of the manifests read from registries
+	maxManifestSize = 4 * 1024 * 1024
+)
+
+// authChallengeParamRegex matches the key="value" parameters of a WWW-Authenticate challenge.
+var authChallengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// PullImageLayerMediaTypes fetches the manifest with the given digest from the registry of the image
+// and returns the media types of the layers it references. Manifest lists and image indexes are
+// resolved to the manifest of the platform the agent runs on.
+func (dg *dockerGoClient) PullImageLayerMediaTypes(ctx context.Context, imageRef string,
+	manifestDigest digest.Digest, authData *apicontainer.RegistryAuthenticationData) ([]string, error) {
+	authConfig, err := dg.getAuthdata(imageRef, authData)
+	if err != nil {
+		return nil, wrapManifestPullErrorAsNamedError(imageRef, err)
+	}
+	fetcher, err := newManifestFetcher(imageRef, authConfig)
+	if err != nil {
+		return nil, wrapManifestPullErrorAsNamedError(imageRef, err)
+	}
+	layerMediaTypes, err := fetcher.layerMediaTypes(ctx, manifestDigest)
+	if err != nil {
+		return nil, wrapManifestPullErrorAsNamedError(imageRef, err)
+	}
+	return layerMediaTypes, nil
+}
+
+// manifestFetcher reads the manifests of a repository through the registry HTTP API.
+type manifestFetcher struct {
+	httpClient *http.Client
+	baseURL    string
+	repository string
+	authConfig types.AuthConfig
+	// authorization is the Authorization header of the requests, set once the registry challenged
+	// the fetcher
+	authorization string
+}
+
+func newManifestFetcher(imageRef string, authConfig types.AuthConfig) (*manifestFetcher, error) {
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return nil, err
+	}
+	host := reference.Domain(named)
+	if host == dockerHubDomain {
+		host = dockerHubRegistryHost
+	}
+	return &manifestFetcher{
+		httpClient: http.DefaultClient,
+		baseURL:    "https://" + host,
+		repository: reference.Path(named),
+		authConfig: authConfig,
+	}, nil
+}
+
+// layerMediaTypes returns the media types of the layers of the manifest with the given digest.
+func (f *manifestFetcher) layerMediaTypes(ctx context.Context, manifestDigest digest.Digest) ([]string, error) {
+	mediaType, body, err := f.fetch(ctx, manifestDigest)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType == ocispec.MediaTypeImageIndex || mediaType == mediaTypeDockerManifestList {
+		var index ocispec.Index
+		if err := json.Unmarshal(body, &index); err != nil {
+			return nil, fmt.Errorf("unable to decode manifest list %s: %w", manifestDigest, err)
+		}
+		platformDigest, ok := platformManifestDigest(index)
+		if !ok {
+			return nil, fmt.Errorf("manifest list %s has no manifest for %s/%s",
+				manifestDigest, runtime.GOOS, runtime.GOARCH)
+		}
+		if _, body, err = f.fetch(ctx, platformDigest); err != nil {
+			return nil, err
+		}
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("unable to decode manifest %s: %w", manifestDigest, err)
+	}
+	layerMediaTypes := make([]string, 0, len(manifest.Layers))
+	for _, layer := range manifest.Layers {
+		layerMediaTypes = append(layerMediaTypes, layer.MediaType)
+	}
+	return layerMediaTypes, nil
+}
+
+// platformManifestDigest returns the digest of the manifest of the platform the agent runs on.
+func platformManifestDigest(index ocispec.Index) (digest.Digest, bool) {
+	for _, manifest := range index.Manifests {
+		if manifest.Platform != nil && manifest.Platform.OS == runtime.GOOS &&
+			manifest.Platform.Architecture == runtime.GOARCH {
+			return manifest.Digest, true
+		}
+	}
+	return "", false
+}
+
+// fetch returns the media type and the content of the manifest with the given digest, authorizing
+// against the registry when it challenges the request.
+func (f *manifestFetcher) fetch(ctx context.Context, manifestDigest digest.Digest) (string, []byte, error) {
+	if err := manifestDigest.Validate(); err != nil {
+		return "", nil, err
+	}
+	resp, err := f.get(ctx, manifestDigest)
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && f.authorization == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := f.authorize(ctx, challenge); err != nil {
+			return "", nil, err
+		}
+		if resp, err = f.get(ctx, manifestDigest); err != nil {
+			return "", nil, err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("unable to fetch manifest %s: registry responded with %s",
+			manifestDigest, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to read manifest %s: %w", manifestDigest, err)
+	}
+	if manifestDigest.Algorithm().FromBytes(body) != manifestDigest {
+		return "", nil, fmt.Errorf("manifest %s doesn't match its digest", manifestDigest)
+	}
+	var versioned struct {
+		MediaType string `json:"mediaType"`
+	}
+	if err := json.Unmarshal(body, &versioned); err != nil {
+		return "", nil, fmt.Errorf("unable to decode manifest %s: %w", manifestDigest, err)
+	}
+	mediaType := versioned.MediaType
+	if mediaType == "" {
+		mediaType = strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	}
+	return mediaType, body, nil
+}
+
+func (f *manifestFetcher) get(ctx context.Context, manifestDigest digest.Digest) (*http.Response, error) {
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", f.baseURL, f.repository, manifestDigest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join([]string{
+		ocispec.MediaTypeImageManifest,
+		ocispec.MediaTypeImageIndex,
+		mediaTypeDockerManifest,
+		mediaTypeDockerManifestList,
+	}, ", "))
+	if f.authorization != "" {
+		req.Header.Set("Authorization", f.authorization)
+	}
+	return f.httpClient.Do(req)
+}
+
+// authorize sets the Authorization header answering the given WWW-Authenticate challenge, requesting
+// a bearer token from the registry's token service when needed.
+func (f *manifestFetcher) authorize(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if f.authConfig.Username == "" {
+			return fmt.Errorf("registry requires credentials to fetch manifests of %s", f.repository)
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(f.authConfig.Username, f.authConfig.Password)
+		f.authorization = req.Header.Get("Authorization")
+		return nil
+	case "bearer":
+		if f.authConfig.RegistryToken != "" {
+			f.authorization = "Bearer " + f.authConfig.RegistryToken
+			return nil
+		}
+		token, err := f.bearerToken(ctx, params)
+		if err != nil {
+			return err
+		}
+		f.authorization = "Bearer " + token
+		return nil
+	default:
+		return fmt.Errorf("registry challenged manifest fetch of %s with unsupported scheme %q",
+			f.repository, scheme)
+	}
+}
+
+// bearerToken requests a token from the token service named by the parameters of a bearer challenge.
+func (f *manifestFetcher) bearerToken(ctx context.Context, challengeParams string) (string, error) {
+	params := map[string]string{}
+	for _, match := range authChallengeParamRegex.FindAllStringSubmatch(challengeParams, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	realm, ok := params["realm"]
+	if !ok {
+		return "", fmt.Errorf("registry bearer challenge for %s has no realm", f.repository)
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("registry bearer challenge for %s has an invalid realm: %w", f.repository, err)
+	}
+	query := tokenURL.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	scope, ok := params["scope"]
+	if !ok {
+		scope = fmt.Sprintf("repository:%s:pull", f.repository)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if f.authConfig.Username != "" {
+		req.SetBasicAuth(f.authConfig.Username, f.authConfig.Password)
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to get registry token for %s: token service responded with %s",
+			f.repository, resp.Status)
+	}
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("unable to decode registry token for %s: %w", f.repository, err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", fmt.Errorf("token service returned no registry token for %s", f.repository)
+}
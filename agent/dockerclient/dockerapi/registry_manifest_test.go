@@ -0,0 +1,195 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dockerapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRegistryRepository = "library/busybox"
+
+// testRegistry serves manifests by digest, challenging unauthorized requests with the given scheme.
+type testRegistry struct {
+	t             *testing.T
+	manifests     map[digest.Digest][]byte
+	challenge     string
+	authorization string
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		username, password, ok := req.BasicAuth()
+		assert.True(r.t, ok)
+		assert.Equal(r.t, "user", username)
+		assert.Equal(r.t, "pass", password)
+		assert.Equal(r.t, "repository:"+testRegistryRepository+":pull", req.URL.Query().Get("scope"))
+		json.NewEncoder(w).Encode(map[string]string{"token": "registry-token"})
+		return
+	}
+	if req.Header.Get("Authorization") != r.authorization {
+		w.Header().Set("WWW-Authenticate", r.challenge)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	prefix := "/v2/" + testRegistryRepository + "/manifests/"
+	require.True(r.t, strings.HasPrefix(req.URL.Path, prefix))
+	manifest, ok := r.manifests[digest.Digest(strings.TrimPrefix(req.URL.Path, prefix))]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write(manifest)
+}
+
+func (r *testRegistry) add(t *testing.T, manifest interface{}) digest.Digest {
+	content, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	manifestDigest := digest.FromBytes(content)
+	r.manifests[manifestDigest] = content
+	return manifestDigest
+}
+
+func TestManifestFetcherLayerMediaTypes(t *testing.T) {
+	layers := []ocispec.Descriptor{
+		{MediaType: ocispec.MediaTypeImageLayerZstd},
+		{MediaType: ocispec.MediaTypeImageLayerGzip},
+	}
+	expectedLayerMediaTypes := []string{ocispec.MediaTypeImageLayerZstd, ocispec.MediaTypeImageLayerGzip}
+
+	tcs := []struct {
+		name          string
+		challenge     string
+		authorization string
+		index         bool
+	}{
+		{
+			name:          "manifest behind basic auth",
+			challenge:     `Basic realm="registry"`,
+			authorization: "Basic dXNlcjpwYXNz",
+		},
+		{
+			name:          "manifest list behind bearer auth",
+			authorization: "Bearer registry-token",
+			index:         true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := &testRegistry{
+				t:             t,
+				manifests:     map[digest.Digest][]byte{},
+				challenge:     tc.challenge,
+				authorization: tc.authorization,
+			}
+			server := httptest.NewTLSServer(registry)
+			defer server.Close()
+			if registry.challenge == "" {
+				registry.challenge = fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL)
+			}
+
+			manifestDigest := registry.add(t, ocispec.Manifest{
+				MediaType: ocispec.MediaTypeImageManifest,
+				Layers:    layers,
+			})
+			if tc.index {
+				otherDigest := registry.add(t, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest})
+				manifestDigest = registry.add(t, ocispec.Index{
+					MediaType: ocispec.MediaTypeImageIndex,
+					Manifests: []ocispec.Descriptor{
+						{
+							MediaType: ocispec.MediaTypeImageManifest,
+							Digest:    otherDigest,
+							Platform:  &ocispec.Platform{OS: "plan9", Architecture: runtime.GOARCH},
+						},
+						{
+							MediaType: ocispec.MediaTypeImageManifest,
+							Digest:    manifestDigest,
+							Platform:  &ocispec.Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH},
+						},
+					},
+				})
+			}
+
+			fetcher := &manifestFetcher{
+				httpClient: server.Client(),
+				baseURL:    server.URL,
+				repository: testRegistryRepository,
+				authConfig: types.AuthConfig{Username: "user", Password: "pass"},
+			}
+			layerMediaTypes, err := fetcher.layerMediaTypes(context.Background(), manifestDigest)
+			require.NoError(t, err)
+			assert.Equal(t, expectedLayerMediaTypes, layerMediaTypes)
+		})
+	}
+}
+
+func TestManifestFetcherLayerMediaTypesDigestMismatch(t *testing.T) {
+	registry := &testRegistry{t: t, manifests: map[digest.Digest][]byte{}}
+	server := httptest.NewTLSServer(registry)
+	defer server.Close()
+
+	manifestDigest := digest.FromString("some other manifest")
+	registry.manifests[manifestDigest] = []byte(`{"mediaType":"` + ocispec.MediaTypeImageManifest + `"}`)
+
+	fetcher := &manifestFetcher{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		repository: testRegistryRepository,
+	}
+	_, err := fetcher.layerMediaTypes(context.Background(), manifestDigest)
+	assert.ErrorContains(t, err, "doesn't match its digest")
+}
+
+func TestNewManifestFetcher(t *testing.T) {
+	tcs := []struct {
+		imageRef           string
+		expectedBaseURL    string
+		expectedRepository string
+	}{
+		{
+			imageRef:           "busybox",
+			expectedBaseURL:    "https://registry-1.docker.io",
+			expectedRepository: "library/busybox",
+		},
+		{
+			imageRef:           "123456789012.dkr.ecr.us-west-2.amazonaws.com/my/app:latest",
+			expectedBaseURL:    "https://123456789012.dkr.ecr.us-west-2.amazonaws.com",
+			expectedRepository: "my/app",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.imageRef, func(t *testing.T) {
+			fetcher, err := newManifestFetcher(tc.imageRef, types.AuthConfig{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedBaseURL, fetcher.baseURL)
+			assert.Equal(t, tc.expectedRepository, fetcher.repository)
+		})
+	}
+}
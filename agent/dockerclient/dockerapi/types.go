@@ -76,6 +76,9 @@ type DockerContainerMetadata struct {
 	NetworkMode string
 	// NetworksUnsafe denotes the Docker Network Settings in the container
 	NetworkSettings *types.NetworkSettings
+	// LayerCacheStats counts the image layers that were found in the layer cache and the ones that were
+	// downloaded by a successful image pull
+	LayerCacheStats *apicontainer.LayerCacheStats
 }
 
 // ListContainersResponse encapsulates the response from the docker client for the
@@ -98,6 +101,23 @@ type ListImagesResponse struct {
 	Error error
 }
 
+const (
+	// DockerConnectionConnected indicates that the docker daemon is reachable
+	DockerConnectionConnected = "connected"
+	// DockerConnectionReconnecting indicates that the connection to the docker
+	// daemon was lost and the client is waiting for the daemon to come back
+	DockerConnectionReconnecting = "reconnecting"
+)
+
+// DockerConnectionStatus describes the health of the client's connection to the
+// docker daemon
+type DockerConnectionStatus struct {
+	// State is either DockerConnectionConnected or DockerConnectionReconnecting
+	State string
+	// LastError is the last error that caused the connection to be lost, if any
+	LastError string
+}
+
 type PingResponse struct {
 	Response *types.Ping
 	Error    error
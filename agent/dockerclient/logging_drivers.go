@@ -41,3 +41,11 @@ var LoggingDriverMinimumVersion = map[LoggingDriver]DockerVersion{
 	SumoLogicDriver:  Version_1_29,
 	NoneDriver:       Version_1_19,
 }
+
+// NonBlockingLogModeMinimumVersion is the minimum docker API version that supports the
+// `mode` and `max-buffer-size` log options.
+const NonBlockingLogModeMinimumVersion = Version_1_28
+
+// JournaldTagMinimumVersion is the minimum docker API version that supports the `tag`
+// log option of the journald logging driver.
+const JournaldTagMinimumVersion = Version_1_23
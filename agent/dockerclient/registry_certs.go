@@ -0,0 +1,81 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dockerclient
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RegistryClientCertFile is the name of the client certificate file that docker
+	// presents to a registry requiring mutual TLS. It is looked up in the
+	// <certsDir>/<registry host> directory, following docker's certs.d layout.
+	RegistryClientCertFile = "client.cert"
+	// RegistryClientKeyFile is the name of the private key file matching RegistryClientCertFile.
+	RegistryClientKeyFile = "client.key"
+)
+
+// RegistriesWithClientCerts returns the registry hosts under certsDir that have
+// both a client certificate and a client key configured.
+func RegistriesWithClientCerts(certsDir string) ([]string, error) {
+	entries, err := os.ReadDir(certsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var registries []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if hasRegistryClientCert(certsDir, entry.Name()) {
+			registries = append(registries, entry.Name())
+		}
+	}
+	return registries, nil
+}
+
+// LoadRegistryClientCert loads the client certificate configured for the registry.
+// It returns a nil certificate and no error when the registry has no client
+// certificate configured.
+func LoadRegistryClientCert(certsDir, registry string) (*tls.Certificate, error) {
+	if certsDir == "" || registry == "" || !hasRegistryClientCert(certsDir, registry) {
+		return nil, nil
+	}
+	registryDir := filepath.Join(certsDir, registry)
+	cert, err := tls.LoadX509KeyPair(filepath.Join(registryDir, RegistryClientCertFile),
+		filepath.Join(registryDir, RegistryClientKeyFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid client certificate configured for registry %s", registry)
+	}
+	return &cert, nil
+}
+
+func hasRegistryClientCert(certsDir, registry string) bool {
+	registryDir := filepath.Join(certsDir, registry)
+	for _, file := range []string{RegistryClientCertFile, RegistryClientKeyFile} {
+		fileInfo, err := os.Stat(filepath.Join(registryDir, file))
+		if err != nil || fileInfo.IsDir() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package dockerclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRegistryClientCert(t *testing.T, certsDir, registry string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ecs-agent"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	registryDir := filepath.Join(certsDir, registry)
+	require.NoError(t, os.MkdirAll(registryDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, RegistryClientCertFile),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, RegistryClientKeyFile),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+func TestRegistriesWithClientCerts(t *testing.T) {
+	certsDir := t.TempDir()
+	writeRegistryClientCert(t, certsDir, "registry.example.com")
+	// A registry with only a CA configured should not be reported.
+	require.NoError(t, os.MkdirAll(filepath.Join(certsDir, "ca-only.example.com"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(certsDir, "ca-only.example.com", "ca.crt"), []byte("ca"), 0600))
+
+	registries, err := RegistriesWithClientCerts(certsDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"registry.example.com"}, registries)
+}
+
+func TestRegistriesWithClientCertsMissingDir(t *testing.T) {
+	registries, err := RegistriesWithClientCerts(filepath.Join(t.TempDir(), "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, registries)
+}
+
+func TestLoadRegistryClientCert(t *testing.T) {
+	certsDir := t.TempDir()
+	writeRegistryClientCert(t, certsDir, "registry.example.com")
+
+	cert, err := LoadRegistryClientCert(certsDir, "registry.example.com")
+	require.NoError(t, err)
+	assert.NotNil(t, cert)
+}
+
+func TestLoadRegistryClientCertNotConfigured(t *testing.T) {
+	cert, err := LoadRegistryClientCert(t.TempDir(), "registry.example.com")
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+}
+
+func TestLoadRegistryClientCertInvalid(t *testing.T) {
+	certsDir := t.TempDir()
+	registryDir := filepath.Join(certsDir, "registry.example.com")
+	require.NoError(t, os.MkdirAll(registryDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, RegistryClientCertFile), []byte("invalid"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(registryDir, RegistryClientKeyFile), []byte("invalid"), 0600))
+
+	cert, err := LoadRegistryClientCert(certsDir, "registry.example.com")
+	assert.Error(t, err)
+	assert.Nil(t, cert)
+}
@@ -45,6 +45,8 @@ const (
 	StopContainerTimeout = 30 * time.Second
 	// RemoveContainerTimeout is the timeout for the RemoveContainer API.
 	RemoveContainerTimeout = 5 * time.Minute
+	// UpdateContainerTimeout is the timeout for the UpdateContainer API.
+	UpdateContainerTimeout = 1 * time.Minute
 
 	// CreateVolumeTimeout is the timeout for CreateVolume API.
 	CreateVolumeTimeout = 5 * time.Minute
@@ -22,6 +22,10 @@ import (
 
 type DockerVersion string
 
+// ExecAgentMinimumVersion is the minimum docker API version that reports the pid of an
+// exec process, which the agent needs to track the ECS Exec agent.
+const ExecAgentMinimumVersion = Version_1_25
+
 var (
 	// MinDockerAPIVersion is the min Docker API version supported by agent
 	MinDockerAPIVersion   = Version_1_21
@@ -183,6 +183,9 @@ func validateContainerRunWorkflow(t *testing.T,
 	manifestPullClient.EXPECT().
 		PullImageManifest(gomock.Any(), container.Image, container.RegistryAuthentication).
 		Return(registry.DistributionInspect{Descriptor: ocispec.Descriptor{Digest: testDigest}}, nil)
+	manifestPullClient.EXPECT().
+		PullImageLayerMediaTypes(gomock.Any(), container.Image, gomock.Any(), container.RegistryAuthentication).
+		Return(nil, nil)
 
 	imageManager.EXPECT().AddAllImageStates(gomock.Any()).AnyTimes()
 	client.EXPECT().
@@ -108,6 +108,13 @@ const (
 
 	defaultMonitorExecAgentsInterval = 15 * time.Minute
 
+	// customInitContainerPath is where the custom init binary is mounted in containers
+	customInitContainerPath = "/sbin/ecs-custom-init"
+
+	// logDriverInitErrorMessage is part of the error returned by docker when a container's log driver
+	// can't be initialized while starting the container
+	logDriverInitErrorMessage = "failed to initialize logging driver"
+
 	defaultStopContainerBackoffMin = time.Second
 	defaultStopContainerBackoffMax = time.Second * 5
 	stopContainerBackoffJitter     = 0.2
@@ -118,10 +125,20 @@ const (
 	mediaTypeManifestV1 = "application/vnd.docker.distribution.manifest.v1+json"
 	// mediaTypeSignedManifestV1 specifies the media type for signed v1 manifest
 	mediaTypeSignedManifestV1 = "application/vnd.docker.distribution.manifest.v1+prettyjws"
+	// imageCompressionGzip is the compression of docker and OCI layers with a .tar.gzip or +gzip media type
+	imageCompressionGzip = "gzip"
+	// imageCompressionZstd is the compression of OCI layers with a +zstd media type
+	imageCompressionZstd = "zstd"
 )
 
 var newExponentialBackoff = retry.NewExponentialBackoff
 
+// appArmorProfilesFilePath lists the loaded AppArmor profiles, can be overridden in tests.
+var appArmorProfilesFilePath = utils.AppArmorProfilesFilePath
+
+// logDriverThrottlingErrorMessages are parts of log driver errors that mean the log backend throttled the log driver
+var logDriverThrottlingErrorMessages = []string{"ThrottlingException", "Rate exceeded"}
+
 // DockerTaskEngine is a state machine for managing a task and its containers
 // in ECS.
 //
@@ -189,6 +206,16 @@ type DockerTaskEngine struct {
 	daemonTasksLock sync.RWMutex
 	daemonTasks     map[string]*apitask.Task
 
+	// awslogsEndpoint is the endpoint applied to new awslogs containers that
+	// don't specify one. It's initialized from config and can be reloaded at
+	// runtime via SetAWSLogsEndpoint
+	awslogsEndpointLock sync.RWMutex
+	awslogsEndpoint     string
+
+	// stopContainerSlots bounds the number of containers stopped concurrently.
+	// It's nil when parallel stops aren't limited
+	stopContainerSlots chan struct{}
+
 	// taskSteadyStatePollInterval is the duration that a managed task waits
 	// once the task gets into steady state before polling the state of all of
 	// the task's containers to re-evaluate if the task is still in steady state
@@ -258,6 +285,10 @@ func NewDockerTaskEngine(cfg *config.Config,
 		stopContainerBackoffMax:           defaultStopContainerBackoffMax,
 		namespaceHelper:                   ecscni.NewNamespaceHelper(client),
 		daemonTasks:                       make(map[string]*apitask.Task),
+		awslogsEndpoint:                   cfg.AWSLogsEndpoint,
+	}
+	if cfg.ParallelTaskStopLimit > 0 {
+		dockerTaskEngine.stopContainerSlots = make(chan struct{}, cfg.ParallelTaskStopLimit)
 	}
 
 	dockerTaskEngine.initializeContainerStatusToTransitionFunction()
@@ -296,6 +327,20 @@ func (engine *DockerTaskEngine) reconcileHostResources() {
 	}
 }
 
+// SetAWSLogsEndpoint updates the endpoint applied to awslogs containers
+// created from now on. Containers that are already running are unaffected.
+func (engine *DockerTaskEngine) SetAWSLogsEndpoint(endpoint string) {
+	engine.awslogsEndpointLock.Lock()
+	defer engine.awslogsEndpointLock.Unlock()
+	engine.awslogsEndpoint = endpoint
+}
+
+func (engine *DockerTaskEngine) getAWSLogsEndpoint() string {
+	engine.awslogsEndpointLock.RLock()
+	defer engine.awslogsEndpointLock.RUnlock()
+	return engine.awslogsEndpoint
+}
+
 func (engine *DockerTaskEngine) initializeContainerStatusToTransitionFunction() {
 	containerStatusToTransitionFunction := map[apicontainerstatus.ContainerStatus]transitionApplyFunc{
 		apicontainerstatus.ContainerManifestPulled:       engine.pullContainerManifest,
@@ -928,6 +973,16 @@ func (engine *DockerTaskEngine) deleteTask(task *apitask.Task) {
 		}
 	}
 
+	if task.HasSecretFiles() {
+		// cleanup the files of secrets delivered as files
+		if err := removeAll(task.GetSecretFilesDir(engine.cfg.DataDir)); err != nil {
+			logger.Warn("Unable to remove secret files for task", logger.Fields{
+				field.TaskID: tID,
+				field.Error:  err,
+			})
+		}
+	}
+
 	if task.IsServiceConnectEnabled() {
 		serviceconnectConfig := task.GetServiceConnectRuntimeConfig()
 		if err := removeAll(filepath.Dir(serviceconnectConfig.AdminSocketPath)); err != nil {
@@ -1101,7 +1156,8 @@ func (engine *DockerTaskEngine) handleDockerEvent(event dockerapi.DockerContaine
 	}
 
 	// Container health status change does not affect the container status
-	// no need to process this in task manager
+	// no need to process this in task manager unless the task status is gated
+	// on container health
 	if event.Type == apicontainer.ContainerHealthEvent {
 		if cont.Container.HealthStatusShouldBeReported() {
 			logger.Debug("Updating container health status", logger.Fields{
@@ -1114,7 +1170,9 @@ func (engine *DockerTaskEngine) handleDockerEvent(event dockerapi.DockerContaine
 			})
 			cont.Container.SetHealthStatus(event.DockerContainerMetadata.Health)
 		}
-		return
+		if !task.IsHealthGatingEnabled() {
+			return
+		}
 	}
 
 	engine.tasksLock.RLock()
@@ -1383,6 +1441,17 @@ func (engine *DockerTaskEngine) pullContainerManifest(
 				return dockerapi.DockerContainerMetadata{}
 			}
 			imageManifestDigest = distInspect.Descriptor.Digest
+			layerMediaTypes, layersErr := client.PullImageLayerMediaTypes(
+				ctx, container.Image, imageManifestDigest, container.RegistryAuthentication)
+			if layersErr != nil {
+				logger.Warn("Unable to fetch image layers from registry, image compression is unknown", logger.Fields{
+					field.TaskARN:       task.Arn,
+					field.ContainerName: container.Name,
+					field.Image:         container.Image,
+					field.Error:         layersErr,
+				})
+			}
+			container.SetImageCompression(imageCompression(layerMediaTypes))
 			logger.Info("Fetched image manifest digest for container from registry", logger.Fields{
 				field.TaskARN:       task.Arn,
 				field.ContainerName: container.Name,
@@ -1402,6 +1471,30 @@ func (engine *DockerTaskEngine) pullContainerManifest(
 	return dockerapi.DockerContainerMetadata{}
 }
 
+// imageCompression returns the compression shared by the image layers with the given media types, gzip
+// for docker '.tar.gzip' and OCI '+gzip' layers or zstd for OCI '+zstd' layers. It returns an empty
+// string when there are no layers, when a layer isn't compressed or when the layers are compressed
+// differently.
+func imageCompression(layerMediaTypes []string) string {
+	compression := ""
+	for _, mediaType := range layerMediaTypes {
+		var layerCompression string
+		switch {
+		case strings.HasSuffix(mediaType, ".tar.gzip"), strings.HasSuffix(mediaType, "+gzip"):
+			layerCompression = imageCompressionGzip
+		case strings.HasSuffix(mediaType, "+zstd"):
+			layerCompression = imageCompressionZstd
+		default:
+			return ""
+		}
+		if compression != "" && compression != layerCompression {
+			return ""
+		}
+		compression = layerCompression
+	}
+	return compression
+}
+
 func (engine *DockerTaskEngine) pullContainer(task *apitask.Task, container *apicontainer.Container) dockerapi.DockerContainerMetadata {
 	switch container.Type {
 	case apicontainer.ContainerCNIPause, apicontainer.ContainerNamespacePause, apicontainer.ContainerServiceConnectRelay, apicontainer.ContainerManagedDaemon:
@@ -1418,6 +1511,7 @@ func (engine *DockerTaskEngine) pullContainer(task *apitask.Task, container *api
 		defer func() {
 			timestamp := engine.time().Now()
 			task.SetPullStoppedAt(timestamp)
+			container.SetPullStoppedAt(timestamp)
 		}()
 		logger.Info("Pulling image for container concurrently", logger.Fields{
 			field.TaskID:    task.GetID(),
@@ -1518,6 +1612,7 @@ func (engine *DockerTaskEngine) concurrentPull(task *apitask.Task, container *ap
 		})
 
 	}
+	container.SetPullStartedAt(pullStart)
 	metadata := engine.pullAndUpdateContainerReference(task, container)
 	if metadata.Error == nil {
 		elapsed := time.Since(pullStart)
@@ -1600,6 +1695,9 @@ func (engine *DockerTaskEngine) pullAndUpdateContainerReference(task *apitask.Ta
 		return metadata
 	}
 	pullSucceeded := metadata.Error == nil
+	if pullSucceeded && metadata.LayerCacheStats != nil {
+		container.SetLayerCacheStats(*metadata.LayerCacheStats)
+	}
 
 	if pullSucceeded && imageRef != container.Image && !referenceutil.DigestExists(container.Image) {
 		// Resolved image manifest digest was used to pull the image.
@@ -1833,7 +1931,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 		if firelensConfig.Type == firelens.FirelensConfigTypeFluentd {
 			// For fluentd router, needs to specify FLUENT_UID to root in order for the fluentd process to access
 			// the socket created by Docker.
-			container.MergeEnvironmentVariables(map[string]string{
+			container.MergeEnvironmentVariablesFromAgent(map[string]string{
 				"FLUENT_UID": "0",
 			})
 		}
@@ -1847,7 +1945,7 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 	if hostConfig.LogConfig.Type == logDriverTypeFirelens {
 		hostConfig.LogConfig = getFirelensLogConfig(task, container, hostConfig, engine.cfg)
 		if task.IsNetworkModeAWSVPC() {
-			container.MergeEnvironmentVariables(map[string]string{
+			container.MergeEnvironmentVariablesFromAgent(map[string]string{
 				fluentNetworkHost: FluentAWSVPCHostValue,
 				fluentNetworkPort: FluentNetworkPortValue,
 			})
@@ -1878,17 +1976,25 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 				err := apierrors.DockerClientConfigError{Msg: "unable to get BridgeIP for task in bridge mode"}
 				return dockerapi.DockerContainerMetadata{Error: apierrors.NamedError(&err)}
 			}
-			container.MergeEnvironmentVariables(map[string]string{
+			container.MergeEnvironmentVariablesFromAgent(map[string]string{
 				fluentNetworkHost: ipAddress,
 				fluentNetworkPort: FluentNetworkPortValue,
 			})
 		}
 	}
 
-	// This is a short term solution only for specific regions
 	if hostConfig.LogConfig.Type == logDriverTypeAwslogs {
 		region := engine.cfg.AWSRegion
-		if region == "us-isob-east-1" || region == "us-iso-east-1" || region == "us-iso-west-1" || region == "eu-isoe-west-1" || region == "us-isof-south-1" || region == "us-isof-east-1" {
+		// A configured endpoint is applied only if the container doesn't set its own.
+		// Otherwise, as a short term solution, resolve the endpoint for specific regions
+		if endpoint := engine.getAWSLogsEndpoint(); endpoint != "" {
+			if _, ok := hostConfig.LogConfig.Config["awslogs-endpoint"]; !ok {
+				if hostConfig.LogConfig.Config == nil {
+					hostConfig.LogConfig.Config = make(map[string]string)
+				}
+				hostConfig.LogConfig.Config["awslogs-endpoint"] = endpoint
+			}
+		} else if region == "us-isob-east-1" || region == "us-iso-east-1" || region == "us-iso-west-1" || region == "eu-isoe-west-1" || region == "us-isof-south-1" || region == "us-isof-east-1" {
 			endpoint := ""
 			dnsSuffix := ""
 			partition, ok := ep.PartitionForRegion(ep.DefaultPartitions(), region)
@@ -1925,6 +2031,17 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 		}
 	}
 
+	// Write the secrets delivered as files and mount them into the container
+	hasSecretAsFile := func(s apicontainer.Secret) bool {
+		return s.Type == apicontainer.SecretTypeMount
+	}
+	if container.HasSecret(hasSecretAsFile) {
+		err := task.AddSecretFileMounts(hostConfig, container, engine.cfg)
+		if err != nil {
+			return dockerapi.DockerContainerMetadata{Error: apierrors.NamedError(err)}
+		}
+	}
+
 	// Populate credentialspec resource
 	if container.RequiresAnyCredentialSpec() {
 		logger.Debug("Obtained container with credentialspec resource requirement for task", logger.Fields{
@@ -2006,6 +2123,28 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 	config.Labels[labelTaskDefinitionVersion] = task.Version
 	config.Labels[labelCluster] = engine.cfg.Cluster
 
+	if engine.cfg.ContainerInitPath != "" && hostConfig.Init != nil && *hostConfig.Init {
+		if err := engine.applyCustomInit(client, container, config, hostConfig); err != nil {
+			logger.Error("Error applying custom init to container", logger.Fields{
+				field.TaskID:    task.GetID(),
+				field.Container: container.Name,
+				field.Error:     err,
+			})
+			return dockerapi.DockerContainerMetadata{Error: apierrors.NamedError(err)}
+		}
+	}
+
+	if appArmorNamedProfileSupported(engine.cfg) {
+		if err := verifyAppArmorProfile(hostConfig); err != nil {
+			logger.Error("Error verifying the AppArmor profile of container", logger.Fields{
+				field.TaskID:    task.GetID(),
+				field.Container: container.Name,
+				field.Error:     err,
+			})
+			return dockerapi.DockerContainerMetadata{Error: apierrors.NamedError(err)}
+		}
+	}
+
 	if dockerContainerName == "" {
 		// only alphanumeric and hyphen characters are allowed
 		reInvalidChars := regexp.MustCompile("[^A-Za-z0-9-]+")
@@ -2070,6 +2209,71 @@ func (engine *DockerTaskEngine) createContainer(task *apitask.Task, container *a
 	return metadata
 }
 
+// applyCustomInit replaces Docker's built-in init process with the custom init binary configured
+// on the instance. The binary is bind mounted read-only into the container and prepended to the
+// container's entrypoint, so that it runs as '<init> -- <entrypoint> <command>'.
+func (engine *DockerTaskEngine) applyCustomInit(client dockerapi.DockerClient, container *apicontainer.Container,
+	config *dockercontainer.Config, hostConfig *dockercontainer.HostConfig) *apierrors.DockerClientConfigError {
+	entrypoint := config.Entrypoint
+	cmd := config.Cmd
+	if len(entrypoint) == 0 {
+		// Overriding the entrypoint makes Docker ignore the image's default command as well,
+		// so both have to be resolved from the image
+		image, err := client.InspectImage(container.Image)
+		if err != nil {
+			return &apierrors.DockerClientConfigError{
+				Msg: fmt.Sprintf("unable to inspect image to apply custom init: %v", err)}
+		}
+		if image.Config != nil {
+			entrypoint = image.Config.Entrypoint
+			if len(cmd) == 0 {
+				cmd = image.Config.Cmd
+			}
+		}
+	}
+
+	config.Entrypoint = append([]string{customInitContainerPath, "--"}, entrypoint...)
+	config.Cmd = cmd
+	hostConfig.Init = aws.Bool(false)
+	hostConfig.Binds = append(hostConfig.Binds,
+		fmt.Sprintf("%s:%s:ro", engine.cfg.ContainerInitPath, customInitContainerPath))
+	return nil
+}
+
+// appArmorNamedProfileSupported mirrors the condition under which the apparmor.named-profile
+// capability is advertised: the agent is AppArmor capable and the loaded profiles can be listed.
+func appArmorNamedProfileSupported(cfg *config.Config) bool {
+	if !cfg.AppArmorCapable.Enabled() {
+		return false
+	}
+	_, err := os.Stat(appArmorProfilesFilePath)
+	return err == nil
+}
+
+// verifyAppArmorProfile checks that the AppArmor profile a container refers to by name through its
+// 'apparmor=<profile>' security option is loaded on the instance, so that the container fails early
+// with a meaningful error instead of failing to start. If the loaded profiles can't be read the
+// profile is left for docker to validate.
+func verifyAppArmorProfile(hostConfig *dockercontainer.HostConfig) *apierrors.DockerClientConfigError {
+	profile := apicontainer.AppArmorProfileFromSecurityOpts(hostConfig.SecurityOpt)
+	if profile == "" {
+		return nil
+	}
+	loaded, err := utils.AppArmorProfileLoaded(appArmorProfilesFilePath, profile)
+	if err != nil {
+		logger.Warn("Unable to verify apparmor profile, leaving it to docker", logger.Fields{
+			"profile":   profile,
+			field.Error: err,
+		})
+		return nil
+	}
+	if !loaded {
+		return &apierrors.DockerClientConfigError{
+			Msg: fmt.Sprintf("apparmor profile %s is not loaded on the instance", profile)}
+	}
+	return nil
+}
+
 func getFirelensLogConfig(task *apitask.Task, container *apicontainer.Container, hostConfig *dockercontainer.HostConfig, cfg *config.Config) dockercontainer.LogConfig {
 	fields := strings.Split(task.Arn, "/")
 	taskID := fields[len(fields)-1]
@@ -2094,6 +2298,24 @@ func getFirelensLogConfig(task *apitask.Task, container *apicontainer.Container,
 	return logConfig
 }
 
+// logDeliveryStatusFromStartError derives the state of a container's log driver from the result of starting
+// the container. Docker initializes the log driver when starting the container, so a successful start means
+// the log driver is delivering logs. It returns an empty string if the start failed for another reason.
+func logDeliveryStatusFromStartError(err apierrors.NamedError) string {
+	if err == nil {
+		return apicontainer.LogDeliveryStatusOK
+	}
+	if !strings.Contains(err.Error(), logDriverInitErrorMessage) {
+		return ""
+	}
+	for _, throttlingErrorMessage := range logDriverThrottlingErrorMessages {
+		if strings.Contains(err.Error(), throttlingErrorMessage) {
+			return apicontainer.LogDeliveryStatusThrottled
+		}
+	}
+	return apicontainer.LogDeliveryStatusBlocked
+}
+
 func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *apicontainer.Container) dockerapi.DockerContainerMetadata {
 	logger.Info("Starting container", logger.Fields{
 		field.TaskID:    task.GetID(),
@@ -2124,6 +2346,9 @@ func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *ap
 
 	startContainerBegin := time.Now()
 	dockerContainerMD := client.StartContainer(engine.ctx, dockerID, engine.cfg.ContainerStartTimeout)
+	if status := logDeliveryStatusFromStartError(dockerContainerMD.Error); status != "" {
+		container.SetLogDeliveryStatus(status)
+	}
 	if dockerContainerMD.Error != nil {
 		return dockerContainerMD
 	}
@@ -2158,6 +2383,11 @@ func (engine *DockerTaskEngine) startContainer(task *apitask.Task, container *ap
 		}()
 	}
 
+	// Tee the logs of the container to the agent's stdout for local debugging
+	if engine.cfg.LogTeeStdout.Enabled() && !container.IsInternal() {
+		go engine.teeContainerLogs(task, container, dockerID)
+	}
+
 	// If container is a firelens container, fluent host is needed to be added to the environment variable for the task.
 	// For the supported network mode - bridge and awsvpc, the awsvpc take the host 127.0.0.1 but in bridge mode,
 	// there is a need to wait for the IP to be present before the container using the firelens can be created.
@@ -2592,9 +2822,18 @@ func (engine *DockerTaskEngine) stopContainer(task *apitask.Task, container *api
 		}
 	}
 
+	// Docker sends the container's stop signal first, and SIGKILL if the container is still running once
+	// the stop timeout has passed
+	apiTimeoutStopContainer := container.GetStopTimeout()
+	if apiTimeoutStopContainer <= 0 {
+		apiTimeoutStopContainer = engine.cfg.DockerStopTimeout
+	}
+
 	logger.Info("Stopping container", logger.Fields{
 		field.TaskID:    task.GetID(),
 		field.Container: container.Name,
+		"stopSignal":    container.GetStopSignal(),
+		"stopTimeout":   apiTimeoutStopContainer,
 	})
 	dockerID, err := engine.getDockerID(task, container)
 	if err != nil {
@@ -2619,9 +2858,18 @@ func (engine *DockerTaskEngine) stopContainer(task *apitask.Task, container *api
 		}
 	}
 
-	apiTimeoutStopContainer := container.GetStopTimeout()
-	if apiTimeoutStopContainer <= 0 {
-		apiTimeoutStopContainer = engine.cfg.DockerStopTimeout
+	// Bound the number of containers stopped in parallel, e.g. when all tasks are stopped during a drain
+	if engine.stopContainerSlots != nil {
+		select {
+		case engine.stopContainerSlots <- struct{}{}:
+			defer func() { <-engine.stopContainerSlots }()
+		case <-engine.ctx.Done():
+			return dockerapi.DockerContainerMetadata{
+				Error: dockerapi.CannotStopContainerError{
+					FromError: engine.ctx.Err(),
+				},
+			}
+		}
 	}
 
 	return engine.stopDockerContainer(dockerID, container.Name, apiTimeoutStopContainer)
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -50,6 +51,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/firelens"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource/ssmsecret"
 	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
+	"github.com/aws/amazon-ecs-agent/agent/utils"
 	mock_ioutilwrapper "github.com/aws/amazon-ecs-agent/agent/utils/ioutilwrapper/mocks"
 	mock_appnet "github.com/aws/amazon-ecs-agent/ecs-agent/api/appnet/mocks"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/attachment"
@@ -134,6 +136,9 @@ func TestResourceContainerProgression(t *testing.T) {
 				Return(registry.DistributionInspect{
 					Descriptor: ocispec.Descriptor{Digest: testDigest},
 				}, nil),
+			manifestPullClient.EXPECT().
+				PullImageLayerMediaTypes(gomock.Any(), sleepContainer.Image, gomock.Any(), nil).
+				Return(nil, nil),
 			client.EXPECT().
 				PullImage(gomock.Any(), expectedCanonicalRef, nil, gomock.Any()).
 				Return(dockerapi.DockerContainerMetadata{}),
@@ -176,6 +181,9 @@ func TestResourceContainerProgression(t *testing.T) {
 				Return(registry.DistributionInspect{
 					Descriptor: ocispec.Descriptor{Digest: testDigest},
 				}, nil),
+			manifestPullClient.EXPECT().
+				PullImageLayerMediaTypes(gomock.Any(), sleepContainer.Image, gomock.Any(), nil).
+				Return(nil, nil),
 			client.EXPECT().
 				PullImage(gomock.Any(), expectedCanonicalRef, nil, gomock.Any()).
 				Return(dockerapi.DockerContainerMetadata{}),
@@ -710,6 +718,9 @@ func TestTaskWithSteadyStateResourcesProvisioned(t *testing.T) {
 	manifestPullClient.EXPECT().
 		PullImageManifest(gomock.Any(), sleepContainer.Image, sleepContainer.RegistryAuthentication).
 		Return(registry.DistributionInspect{Descriptor: ocispec.Descriptor{Digest: testDigest}}, nil)
+	manifestPullClient.EXPECT().
+		PullImageLayerMediaTypes(gomock.Any(), sleepContainer.Image, gomock.Any(), sleepContainer.RegistryAuthentication).
+		Return(nil, nil)
 
 	expectedCanonicalRef := sleepContainer.Image + "@" + testDigest.String()
 	client.EXPECT().
@@ -925,6 +936,10 @@ func TestPauseContainerHappyPath(t *testing.T) {
 			assertPauseContainerIsRunning() // Ensure that pause container is already RUNNING
 		}).
 		Return(registry.DistributionInspect{}, nil)
+	manifestPullClient.EXPECT().
+		PullImageLayerMediaTypes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(nil, nil)
 	dockerClient.EXPECT().
 		PullImage(gomock.Any(), gomock.Any(), nil, gomock.Any()).
 		Do(func(context.Context, string, *apicontainer.RegistryAuthenticationData, time.Duration) {
@@ -1148,6 +1163,9 @@ func TestContainersWithServiceConnect(t *testing.T) {
 	manifestPullClient.EXPECT().
 		PullImageManifest(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
 		Return(registry.DistributionInspect{}, nil)
+	manifestPullClient.EXPECT().
+		PullImageLayerMediaTypes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		Return(nil, nil)
 	dockerClient.EXPECT().PullImage(gomock.Any(), gomock.Any(), nil, gomock.Any()).Return(dockerapi.DockerContainerMetadata{}).Times(2)
 	imageManager.EXPECT().RecordContainerReference(gomock.Any()).Return(nil).Times(2)
 	imageManager.EXPECT().GetImageStateFromImageName(gomock.Any()).Return(nil, false).Times(2)
@@ -1351,6 +1369,9 @@ func TestContainersWithServiceConnect_BridgeMode(t *testing.T) {
 	manifestPullClient.EXPECT().
 		PullImageManifest(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(registry.DistributionInspect{}, nil)
+	manifestPullClient.EXPECT().
+		PullImageLayerMediaTypes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, nil)
 	dockerClient.EXPECT().PullImage(gomock.Any(), gomock.Any(), nil, gomock.Any()).Return(dockerapi.DockerContainerMetadata{}).Times(1)
 	imageManager.EXPECT().RecordContainerReference(gomock.Any()).Return(nil).Times(1)
 	imageManager.EXPECT().GetImageStateFromImageName(gomock.Any()).Return(nil, false).Times(1)
@@ -1619,3 +1640,129 @@ func TestCredentialSpecResourceTaskFile(t *testing.T) {
 	ret := taskEngine.(*DockerTaskEngine).createContainer(testTask, testTask.Containers[0])
 	assert.Nil(t, ret.Error)
 }
+
+func TestCreateContainerAppArmorNamedProfile(t *testing.T) {
+	profilesFile := filepath.Join(t.TempDir(), "profiles")
+	require.NoError(t, os.WriteFile(profilesFile, []byte("ecs-custom (enforce)\n"), 0644))
+	defer func() {
+		appArmorProfilesFilePath = utils.AppArmorProfilesFilePath
+	}()
+	appArmorProfilesFilePath = profilesFile
+
+	testCases := []struct {
+		name        string
+		securityOpt []string
+		expectErr   bool
+	}{
+		{
+			name:        "loaded profile",
+			securityOpt: []string{"apparmor=ecs-custom"},
+		},
+		{
+			name:        "unconfined",
+			securityOpt: []string{"apparmor=unconfined"},
+		},
+		{
+			name:        "absent profile",
+			securityOpt: []string{"apparmor=ecs-missing"},
+			expectErr:   true,
+		},
+		{
+			name:        "absent profile in deprecated form",
+			securityOpt: []string{"apparmor:ecs-missing"},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			cfg := defaultConfig
+			cfg.AppArmorCapable = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+			ctrl, client, _, taskEngine, _, _, _, _ := mocks(t, ctx, &cfg)
+			defer ctrl.Finish()
+			dockerTaskEngine := taskEngine.(*DockerTaskEngine)
+
+			rawHostConfig, err := json.Marshal(&dockercontainer.HostConfig{SecurityOpt: tc.securityOpt})
+			require.NoError(t, err)
+			container := &apicontainer.Container{
+				Name:  "test-container",
+				Image: "test-image",
+				DockerConfig: apicontainer.DockerConfig{
+					HostConfig: aws.String(string(rawHostConfig)),
+				},
+			}
+			testTask := &apitask.Task{
+				Arn:        "arn:aws:ecs:region:account-id:task/test-task-arn",
+				Containers: []*apicontainer.Container{container},
+			}
+
+			client.EXPECT().APIVersion().Return(defaultDockerClientAPIVersion, nil).AnyTimes()
+			if !tc.expectErr {
+				client.EXPECT().CreateContainer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			}
+
+			metadata := dockerTaskEngine.createContainer(testTask, container)
+			if tc.expectErr {
+				assert.Error(t, metadata.Error)
+			} else {
+				assert.NoError(t, metadata.Error)
+			}
+		})
+	}
+}
+
+func TestCreateContainerAppArmorNamedProfileUnverifiable(t *testing.T) {
+	profilesDir := t.TempDir()
+	defer func() {
+		appArmorProfilesFilePath = utils.AppArmorProfilesFilePath
+	}()
+
+	testCases := []struct {
+		name         string
+		profilesFile string
+	}{
+		{
+			name:         "profiles file missing",
+			profilesFile: filepath.Join(profilesDir, "missing"),
+		},
+		{
+			// a directory exists but can't be read as the profiles list
+			name:         "profiles file unreadable",
+			profilesFile: profilesDir,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			appArmorProfilesFilePath = tc.profilesFile
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			cfg := defaultConfig
+			cfg.AppArmorCapable = config.BooleanDefaultFalse{Value: config.ExplicitlyEnabled}
+			ctrl, client, _, taskEngine, _, _, _, _ := mocks(t, ctx, &cfg)
+			defer ctrl.Finish()
+
+			rawHostConfig, err := json.Marshal(&dockercontainer.HostConfig{SecurityOpt: []string{"apparmor=ecs-custom"}})
+			require.NoError(t, err)
+			container := &apicontainer.Container{
+				Name:  "test-container",
+				Image: "test-image",
+				DockerConfig: apicontainer.DockerConfig{
+					HostConfig: aws.String(string(rawHostConfig)),
+				},
+			}
+			testTask := &apitask.Task{
+				Arn:        "arn:aws:ecs:region:account-id:task/test-task-arn",
+				Containers: []*apicontainer.Container{container},
+			}
+
+			client.EXPECT().APIVersion().Return(defaultDockerClientAPIVersion, nil).AnyTimes()
+			client.EXPECT().CreateContainer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+
+			metadata := taskEngine.(*DockerTaskEngine).createContainer(testTask, container)
+			assert.NoError(t, metadata.Error)
+		})
+	}
+}
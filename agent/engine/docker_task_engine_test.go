@@ -114,6 +114,7 @@ const (
 	containerNetworkMode        = "none"
 	serviceConnectContainerName = "service-connect"
 	mediaTypeManifestV2         = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeLayerV2            = "application/vnd.docker.image.rootfs.diff.tar.gzip"
 )
 
 var (
@@ -454,6 +455,9 @@ func TestStartTimeoutThenStart(t *testing.T) {
 			Return(registry.DistributionInspect{
 				Descriptor: ocispec.Descriptor{Digest: testDigest},
 			}, nil)
+		manifestPullClient.EXPECT().
+			PullImageLayerMediaTypes(gomock.Any(), container.Image, gomock.Any(), container.RegistryAuthentication).
+			Return(nil, nil)
 		client.EXPECT().
 			PullImage(gomock.Any(), container.Image+"@"+testDigest.String(), nil, gomock.Any()).
 			Return(dockerapi.DockerContainerMetadata{})
@@ -603,6 +607,9 @@ func TestStopWithPendingStops(t *testing.T) {
 	manifestPullClient.EXPECT().
 		PullImageManifest(gomock.Any(), gomock.Any(), gomock.Any()).MaxTimes(2).
 		Return(registry.DistributionInspect{}, nil)
+	manifestPullClient.EXPECT().
+		PullImageLayerMediaTypes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).MaxTimes(2).
+		Return(nil, nil)
 
 	pullDone := make(chan bool)
 	pullInvoked := make(chan bool)
@@ -808,6 +815,9 @@ func TestTaskTransitionWhenStopContainerTimesout(t *testing.T) {
 		manifestPullClient.EXPECT().
 			PullImageManifest(gomock.Any(), container.Image, container.RegistryAuthentication).
 			Return(registry.DistributionInspect{Descriptor: ocispec.Descriptor{Digest: testDigest}}, nil)
+		manifestPullClient.EXPECT().
+			PullImageLayerMediaTypes(gomock.Any(), container.Image, gomock.Any(), container.RegistryAuthentication).
+			Return(nil, nil)
 		expectedCanonicalRef := container.Image + "@" + testDigest.String()
 		client.EXPECT().
 			PullImage(gomock.Any(), expectedCanonicalRef, nil, gomock.Any()).
@@ -887,6 +897,9 @@ func TestTaskTransitionWhenStopContainerReturnsUnretriableError(t *testing.T) {
 				Return(
 					registry.DistributionInspect{Descriptor: ocispec.Descriptor{Digest: testDigest}},
 					nil),
+			manifestPullClient.EXPECT().
+				PullImageLayerMediaTypes(gomock.Any(), container.Image, gomock.Any(), container.RegistryAuthentication).
+				Return(nil, nil),
 			client.EXPECT().
 				PullImage(gomock.Any(), expectedCanonicalRef, nil, gomock.Any()).
 				Return(dockerapi.DockerContainerMetadata{}),
@@ -975,6 +988,9 @@ func TestTaskTransitionWhenStopContainerReturnsTransientErrorBeforeSucceeding(t
 			manifestPullClient.EXPECT().
 				PullImageManifest(gomock.Any(), container.Image, container.RegistryAuthentication).
 				Return(registry.DistributionInspect{Descriptor: ocispec.Descriptor{Digest: testDigest}}, nil),
+			manifestPullClient.EXPECT().
+				PullImageLayerMediaTypes(gomock.Any(), container.Image, gomock.Any(), container.RegistryAuthentication).
+				Return(nil, nil),
 			client.EXPECT().
 				PullImage(gomock.Any(), expectedCanonicalRef, nil, gomock.Any()).
 				Return(dockerapi.DockerContainerMetadata{}),
@@ -2137,6 +2153,10 @@ func TestPullStartedStoppedAtWasSetCorrectly(t *testing.T) {
 
 	assert.Equal(t, testTask.PullStartedAtUnsafe, startTime1)
 	assert.Equal(t, testTask.PullStoppedAtUnsafe, stopTime3)
+	// The container records the timestamps of its most recent pull
+	pullDuration, ok := container.GetImagePullDuration()
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, pullDuration)
 }
 
 // TestPullStoppedAtWasSetCorrectlyWhenPullFail tests the PullStoppedAt was set
@@ -2489,6 +2509,9 @@ func TestContainerProgressParallize(t *testing.T) {
 	manifestPullClient.EXPECT().
 		PullImageManifest(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
 		Return(registry.DistributionInspect{}, nil)
+	manifestPullClient.EXPECT().
+		PullImageLayerMediaTypes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		Return(nil, nil)
 	client.EXPECT().PullImage(gomock.Any(), fastPullImage, gomock.Any(), gomock.Any())
 	client.EXPECT().PullImage(gomock.Any(), slowPullImage, gomock.Any(), gomock.Any()).Do(
 		func(ctx interface{}, image interface{}, auth interface{}, timeout interface{}) {
@@ -3036,6 +3059,352 @@ func TestCreateContainerAwslogsLogDriver(t *testing.T) {
 
 }
 
+// TestStopContainerParallelTaskStopLimit simulates a drain stopping many tasks at once and
+// asserts that no more than the configured number of containers are stopped concurrently.
+func TestStopContainerParallelTaskStopLimit(t *testing.T) {
+	const (
+		parallelTaskStopLimit = 2
+		numTasks              = 6
+	)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	cfg := defaultConfig
+	cfg.ParallelTaskStopLimit = parallelTaskStopLimit
+	ctrl, client, _, taskEngine, _, _, _, _ := mocks(t, ctx, &cfg)
+	defer ctrl.Finish()
+	dockerTaskEngine := taskEngine.(*DockerTaskEngine)
+
+	var (
+		lock             sync.Mutex
+		inFlight         int
+		maxInFlight      int
+		limitReached     = make(chan struct{})
+		releaseStops     = make(chan struct{})
+		limitReachedOnce sync.Once
+	)
+	client.EXPECT().StopContainer(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, dockerID string, timeout time.Duration) dockerapi.DockerContainerMetadata {
+			lock.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			if inFlight == parallelTaskStopLimit {
+				limitReachedOnce.Do(func() { close(limitReached) })
+			}
+			lock.Unlock()
+
+			<-releaseStops
+
+			lock.Lock()
+			inFlight--
+			lock.Unlock()
+			return dockerapi.DockerContainerMetadata{DockerID: dockerID}
+		}).Times(numTasks)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numTasks; i++ {
+		task := &apitask.Task{Arn: fmt.Sprintf("arn:aws:ecs:region:account-id:task/task-%d", i)}
+		container := &apicontainer.Container{Name: "container"}
+		container.SetRuntimeID(fmt.Sprintf("docker-id-%d", i))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, dockerTaskEngine.stopContainer(task, container).Error)
+		}()
+	}
+
+	<-limitReached
+	// Give the remaining stops a chance to exceed the limit if they weren't bounded
+	time.Sleep(50 * time.Millisecond)
+	lock.Lock()
+	assert.Equal(t, parallelTaskStopLimit, inFlight)
+	lock.Unlock()
+
+	close(releaseStops)
+	wg.Wait()
+	assert.Equal(t, parallelTaskStopLimit, maxInFlight)
+}
+
+// TestStopContainerParallelTaskStopLimitContextCanceled asserts that a container stop waiting for a slot
+// returns once the engine context is canceled while all slots are held.
+func TestStopContainerParallelTaskStopLimitContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	cfg := defaultConfig
+	cfg.ParallelTaskStopLimit = 1
+	ctrl, _, _, taskEngine, _, _, _, _ := mocks(t, ctx, &cfg)
+	defer ctrl.Finish()
+	dockerTaskEngine := taskEngine.(*DockerTaskEngine)
+
+	// Hold the only slot, as a stop that never returns would
+	dockerTaskEngine.stopContainerSlots <- struct{}{}
+
+	task := &apitask.Task{Arn: "arn:aws:ecs:region:account-id:task/task-id"}
+	container := &apicontainer.Container{Name: "container"}
+	container.SetRuntimeID("docker-id")
+	stopped := make(chan dockerapi.DockerContainerMetadata)
+	go func() {
+		stopped <- dockerTaskEngine.stopContainer(task, container)
+	}()
+
+	cancel()
+	select {
+	case metadata := <-stopped:
+		require.Error(t, metadata.Error)
+		assert.ErrorIs(t, metadata.Error.(dockerapi.CannotStopContainerError).FromError, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("container stop did not return after the engine context was canceled")
+	}
+}
+
+func TestContainerStopSignalAndStopTimeout(t *testing.T) {
+	testCases := []struct {
+		name                string
+		stopTimeout         uint
+		expectedStopTimeout time.Duration
+	}{
+		{
+			name:                "stop timeout from container definition",
+			stopTimeout:         120,
+			expectedStopTimeout: 120 * time.Second,
+		},
+		{
+			name:                "default stop timeout",
+			expectedStopTimeout: defaultConfig.DockerStopTimeout,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			ctrl, client, _, taskEngine, _, _, _, _ := mocks(t, ctx, &defaultConfig)
+			defer ctrl.Finish()
+			dockerTaskEngine := taskEngine.(*DockerTaskEngine)
+
+			container := &apicontainer.Container{
+				Name:        "test-container",
+				Image:       "test-image",
+				StopTimeout: tc.stopTimeout,
+				DockerConfig: apicontainer.DockerConfig{
+					Config: aws.String(`{"StopSignal":"SIGQUIT"}`),
+				},
+			}
+			testTask := &apitask.Task{
+				Arn:        "arn:aws:ecs:region:account-id:task/test-task-arn",
+				Containers: []*apicontainer.Container{container},
+			}
+
+			// Docker sends the configured stop signal when stopping the container, and SIGKILL
+			// once the stop timeout has passed, so both have to reach docker.
+			client.EXPECT().APIVersion().Return(defaultDockerClientAPIVersion, nil).AnyTimes()
+			gomock.InOrder(
+				client.EXPECT().CreateContainer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(
+					func(ctx context.Context,
+						config *dockercontainer.Config,
+						hostConfig *dockercontainer.HostConfig,
+						name string,
+						timeout time.Duration) {
+						assert.Equal(t, "SIGQUIT", config.StopSignal)
+					}).Return(dockerapi.DockerContainerMetadata{DockerID: containerID}),
+				client.EXPECT().StopContainer(gomock.Any(), containerID, tc.expectedStopTimeout).
+					Return(dockerapi.DockerContainerMetadata{DockerID: containerID}),
+			)
+
+			require.NoError(t, dockerTaskEngine.createContainer(testTask, container).Error)
+			container.SetRuntimeID(containerID)
+			assert.NoError(t, dockerTaskEngine.stopContainer(testTask, container).Error)
+		})
+	}
+}
+
+func TestCreateContainerCustomInit(t *testing.T) {
+	const initPath = "/usr/local/bin/tini"
+	customInitBind := initPath + ":" + customInitContainerPath + ":ro"
+
+	testCases := []struct {
+		name               string
+		initEnabled        bool
+		entrypoint         []string
+		cmd                []string
+		imageInspect       *types.ImageInspect
+		imageInspectErr    error
+		expectedEntrypoint []string
+		expectedCmd        []string
+		expectedInit       bool
+		expectedBinds      []string
+		expectErr          bool
+	}{
+		{
+			name:               "init not enabled",
+			entrypoint:         []string{"/app"},
+			expectedEntrypoint: []string{"/app"},
+		},
+		{
+			name:               "entrypoint from container definition",
+			initEnabled:        true,
+			entrypoint:         []string{"/app"},
+			cmd:                []string{"--flag"},
+			expectedEntrypoint: []string{customInitContainerPath, "--", "/app"},
+			expectedCmd:        []string{"--flag"},
+			expectedBinds:      []string{customInitBind},
+		},
+		{
+			name:        "entrypoint and command from image",
+			initEnabled: true,
+			imageInspect: &types.ImageInspect{
+				Config: &dockercontainer.Config{
+					Entrypoint: []string{"/image-entrypoint"},
+					Cmd:        []string{"image-cmd"},
+				},
+			},
+			expectedEntrypoint: []string{customInitContainerPath, "--", "/image-entrypoint"},
+			expectedCmd:        []string{"image-cmd"},
+			expectedBinds:      []string{customInitBind},
+		},
+		{
+			name:        "command from container definition overrides image",
+			initEnabled: true,
+			cmd:         []string{"container-cmd"},
+			imageInspect: &types.ImageInspect{
+				Config: &dockercontainer.Config{
+					Cmd: []string{"image-cmd"},
+				},
+			},
+			expectedEntrypoint: []string{customInitContainerPath, "--"},
+			expectedCmd:        []string{"container-cmd"},
+			expectedBinds:      []string{customInitBind},
+		},
+		{
+			name:            "image inspect fails",
+			initEnabled:     true,
+			imageInspectErr: errors.New("inspect failed"),
+			expectErr:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			cfg := defaultConfig
+			cfg.ContainerInitPath = initPath
+			ctrl, client, _, taskEngine, _, _, _, _ := mocks(t, ctx, &cfg)
+			defer ctrl.Finish()
+			dockerTaskEngine := taskEngine.(*DockerTaskEngine)
+
+			rawHostConfig, err := json.Marshal(&dockercontainer.HostConfig{Init: aws.Bool(tc.initEnabled)})
+			require.NoError(t, err)
+			container := &apicontainer.Container{
+				Name:    "test-container",
+				Image:   "test-image",
+				Command: tc.cmd,
+				DockerConfig: apicontainer.DockerConfig{
+					HostConfig: aws.String(string(rawHostConfig)),
+				},
+			}
+			if tc.entrypoint != nil {
+				container.EntryPoint = &tc.entrypoint
+			}
+			testTask := &apitask.Task{
+				Arn:        "arn:aws:ecs:region:account-id:task/test-task-arn",
+				Containers: []*apicontainer.Container{container},
+			}
+
+			client.EXPECT().APIVersion().Return(defaultDockerClientAPIVersion, nil).AnyTimes()
+			if tc.initEnabled && tc.entrypoint == nil {
+				client.EXPECT().InspectImage("test-image").Return(tc.imageInspect, tc.imageInspectErr)
+			}
+			if !tc.expectErr {
+				client.EXPECT().CreateContainer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(
+					func(ctx context.Context,
+						config *dockercontainer.Config,
+						hostConfig *dockercontainer.HostConfig,
+						name string,
+						timeout time.Duration) {
+						assert.Equal(t, tc.expectedEntrypoint, []string(config.Entrypoint))
+						assert.Equal(t, tc.expectedCmd, []string(config.Cmd))
+						require.NotNil(t, hostConfig.Init)
+						assert.Equal(t, tc.expectedInit, *hostConfig.Init)
+						assert.Equal(t, tc.expectedBinds, hostConfig.Binds)
+					})
+			}
+
+			metadata := dockerTaskEngine.createContainer(testTask, container)
+			if tc.expectErr {
+				assert.Error(t, metadata.Error)
+			} else {
+				assert.NoError(t, metadata.Error)
+			}
+		})
+	}
+}
+
+// TestCreateContainerAwslogsEndpointReload tests that a configured awslogs endpoint is
+// applied to new containers, that reloading it affects containers created afterwards,
+// and that an endpoint set by the container itself is left untouched.
+func TestCreateContainerAwslogsEndpointReload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	cfg := defaultConfig
+	cfg.AWSLogsEndpoint = "https://logs.initial.example.com"
+	ctrl, client, _, taskEngine, _, _, _, _ := mocks(t, ctx, &cfg)
+	defer ctrl.Finish()
+	dockerTaskEngine := taskEngine.(*DockerTaskEngine)
+
+	newTask := func(logOptions map[string]string) *apitask.Task {
+		rawHostConfig, err := json.Marshal(&dockercontainer.HostConfig{
+			LogConfig: dockercontainer.LogConfig{
+				Type:   "awslogs",
+				Config: logOptions,
+			},
+		})
+		require.NoError(t, err)
+		return &apitask.Task{
+			Arn: "arn:aws:ecs:region:account-id:task/test-task-arn",
+			Containers: []*apicontainer.Container{
+				{
+					Name: "test-container",
+					DockerConfig: apicontainer.DockerConfig{
+						HostConfig: func() *string {
+							s := string(rawHostConfig)
+							return &s
+						}(),
+					},
+				},
+			},
+		}
+	}
+
+	var endpoints []string
+	client.EXPECT().APIVersion().Return(defaultDockerClientAPIVersion, nil).AnyTimes()
+	client.EXPECT().CreateContainer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx context.Context,
+			config *dockercontainer.Config,
+			hostConfig *dockercontainer.HostConfig,
+			name string,
+			timeout time.Duration) {
+			endpoints = append(endpoints, hostConfig.LogConfig.Config["awslogs-endpoint"])
+		}).Times(3)
+
+	testTask := newTask(nil)
+	assert.NoError(t, dockerTaskEngine.createContainer(testTask, testTask.Containers[0]).Error)
+
+	dockerTaskEngine.SetAWSLogsEndpoint("https://logs.reloaded.example.com")
+	testTask = newTask(map[string]string{})
+	assert.NoError(t, dockerTaskEngine.createContainer(testTask, testTask.Containers[0]).Error)
+
+	testTask = newTask(map[string]string{"awslogs-endpoint": "https://logs.container.example.com"})
+	assert.NoError(t, dockerTaskEngine.createContainer(testTask, testTask.Containers[0]).Error)
+
+	assert.Equal(t, []string{
+		"https://logs.initial.example.com",
+		"https://logs.reloaded.example.com",
+		"https://logs.container.example.com",
+	}, endpoints)
+}
+
 // TestCreateContainerAddFirelensLogDriverConfig tests that in createContainer, when the
 // container is using firelens log driver, its logConfig is properly set.
 func TestCreateContainerAddFirelensLogDriverConfig(t *testing.T) {
@@ -3570,6 +3939,61 @@ func TestStartFirelensContainerRetryForContainerIP(t *testing.T) {
 
 }
 
+func TestStartContainerLogDeliveryStatus(t *testing.T) {
+	testCases := []struct {
+		name           string
+		startError     error
+		expectedStatus string
+	}{
+		{
+			name:           "container started",
+			expectedStatus: apicontainer.LogDeliveryStatusOK,
+		},
+		{
+			name: "log backend throttled the log driver",
+			startError: errors.New("failed to initialize logging driver: failed to create Cloudwatch log stream: " +
+				"ThrottlingException: Rate exceeded"),
+			expectedStatus: apicontainer.LogDeliveryStatusThrottled,
+		},
+		{
+			name:           "log driver unable to deliver logs",
+			startError:     errors.New("failed to initialize logging driver: dial tcp 127.0.0.1:24224: connect: connection refused"),
+			expectedStatus: apicontainer.LogDeliveryStatusBlocked,
+		},
+		{
+			name:           "container failed to start for another reason",
+			startError:     errors.New("OCI runtime create failed"),
+			expectedStatus: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			ctrl, client, _, taskEngine, _, _, _, _ := mocks(t, ctx, &defaultConfig)
+			defer ctrl.Finish()
+
+			task := testdata.LoadTask("sleep5")
+			taskEngine.(*DockerTaskEngine).state.AddTask(task)
+			taskEngine.(*DockerTaskEngine).state.AddContainer(&apicontainer.DockerContainer{
+				Container:  task.Containers[0],
+				DockerName: dockerContainerName,
+				DockerID:   containerID,
+			}, task)
+
+			dockerContainerMD := dockerapi.DockerContainerMetadata{DockerID: containerID}
+			if tc.startError != nil {
+				dockerContainerMD.Error = dockerapi.CannotStartContainerError{FromError: tc.startError}
+			}
+			client.EXPECT().StartContainer(gomock.Any(), containerID, gomock.Any()).Return(dockerContainerMD)
+
+			taskEngine.(*DockerTaskEngine).startContainer(task, task.Containers[0])
+			assert.Equal(t, tc.expectedStatus, task.Containers[0].GetLogDeliveryStatus())
+		})
+	}
+}
+
 func TestStartExecAgent(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.TODO())
 	defer cancel()
@@ -4131,6 +4555,7 @@ func TestPullContainerManifest(t *testing.T) {
 		setDockerClientExpectations func(c *gomock.Controller, d *mock_dockerapi.MockDockerClient)
 		expectedResult              dockerapi.DockerContainerMetadata
 		expectedDigest              string
+		expectedCompression         string
 	}
 
 	someError := errors.New("some error")
@@ -4269,6 +4694,57 @@ func TestPullContainerManifest(t *testing.T) {
 							},
 						},
 						nil)
+				versioned.EXPECT().
+					PullImageLayerMediaTypes(gomock.Any(), "myimage", testDigest, nil).
+					Return([]string{mediaTypeLayerV2, mediaTypeLayerV2}, nil)
+				d.EXPECT().WithVersion(dockerclient.Version_1_35).Return(versioned, nil)
+			},
+			expectedDigest:      testDigest.String(),
+			expectedCompression: "gzip",
+		},
+		{
+			name:              "image pull required - zstd compressed OCI manifest",
+			image:             "myimage",
+			imagePullBehavior: config.ImagePullAlwaysBehavior,
+			setDockerClientExpectations: func(c *gomock.Controller, d *mock_dockerapi.MockDockerClient) {
+				versioned := mock_dockerapi.NewMockDockerClient(c)
+				versioned.EXPECT().
+					PullImageManifest(gomock.Any(), "myimage", nil).
+					Return(
+						registry.DistributionInspect{
+							Descriptor: ocispec.Descriptor{
+								MediaType: ocispec.MediaTypeImageManifest,
+								Digest:    testDigest,
+							},
+						},
+						nil)
+				versioned.EXPECT().
+					PullImageLayerMediaTypes(gomock.Any(), "myimage", testDigest, nil).
+					Return([]string{ocispec.MediaTypeImageLayerZstd}, nil)
+				d.EXPECT().WithVersion(dockerclient.Version_1_35).Return(versioned, nil)
+			},
+			expectedDigest:      testDigest.String(),
+			expectedCompression: "zstd",
+		},
+		{
+			name:              "image pull required - compression unknown when layers can't be fetched",
+			image:             "myimage",
+			imagePullBehavior: config.ImagePullAlwaysBehavior,
+			setDockerClientExpectations: func(c *gomock.Controller, d *mock_dockerapi.MockDockerClient) {
+				versioned := mock_dockerapi.NewMockDockerClient(c)
+				versioned.EXPECT().
+					PullImageManifest(gomock.Any(), "myimage", nil).
+					Return(
+						registry.DistributionInspect{
+							Descriptor: ocispec.Descriptor{
+								MediaType: ocispec.MediaTypeImageManifest,
+								Digest:    testDigest,
+							},
+						},
+						nil)
+				versioned.EXPECT().
+					PullImageLayerMediaTypes(gomock.Any(), "myimage", testDigest, nil).
+					Return(nil, errors.New("some error"))
 				d.EXPECT().WithVersion(dockerclient.Version_1_35).Return(versioned, nil)
 			},
 			expectedDigest: testDigest.String(),
@@ -4305,9 +4781,13 @@ func TestPullContainerManifest(t *testing.T) {
 								},
 							},
 							nil)
+					versioned.EXPECT().
+						PullImageLayerMediaTypes(gomock.Any(), "myimage", testDigest, expectedRegistryAuthData).
+						Return([]string{mediaTypeLayerV2}, nil)
 					d.EXPECT().WithVersion(dockerclient.Version_1_35).Return(versioned, nil)
 				},
-				expectedDigest: testDigest.String(),
+				expectedDigest:      testDigest.String(),
+				expectedCompression: "gzip",
 			}
 		}(),
 	}
@@ -4336,6 +4816,47 @@ func TestPullContainerManifest(t *testing.T) {
 			result := engine.pullContainerManifest(task, container)
 			assert.Equal(t, tc.expectedResult, result)
 			assert.Equal(t, tc.expectedDigest, container.GetImageDigest())
+			assert.Equal(t, tc.expectedCompression, container.GetImageCompression())
+		})
+	}
+}
+
+func TestImageCompression(t *testing.T) {
+	tcs := []struct {
+		name                string
+		layerMediaTypes     []string
+		expectedCompression string
+	}{
+		{
+			name:                "docker gzip layers",
+			layerMediaTypes:     []string{mediaTypeLayerV2, "application/vnd.docker.image.rootfs.foreign.diff.tar.gzip"},
+			expectedCompression: "gzip",
+		},
+		{
+			name:                "OCI gzip layers",
+			layerMediaTypes:     []string{ocispec.MediaTypeImageLayerGzip, ocispec.MediaTypeImageLayerGzip},
+			expectedCompression: "gzip",
+		},
+		{
+			name:                "OCI zstd layers",
+			layerMediaTypes:     []string{ocispec.MediaTypeImageLayerZstd, "application/vnd.oci.image.layer.nondistributable.v1.tar+zstd"},
+			expectedCompression: "zstd",
+		},
+		{
+			name:            "mixed compression",
+			layerMediaTypes: []string{ocispec.MediaTypeImageLayerGzip, ocispec.MediaTypeImageLayerZstd},
+		},
+		{
+			name:            "uncompressed layer",
+			layerMediaTypes: []string{ocispec.MediaTypeImageLayerGzip, ocispec.MediaTypeImageLayer},
+		},
+		{
+			name: "no layers",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedCompression, imageCompression(tc.layerMediaTypes))
 		})
 	}
 }
@@ -4374,6 +4895,9 @@ func TestManifestPullTaskShouldContinue(t *testing.T) {
 						Return(
 							registry.DistributionInspect{Descriptor: ocispec.Descriptor{Digest: testDigest}},
 							nil),
+					manifestPullClient.EXPECT().
+						PullImageLayerMediaTypes(gomock.Any(), testImage, gomock.Any(), nil).
+						Return(nil, nil),
 				}
 			},
 			shouldPullImage: true,
@@ -4456,6 +4980,9 @@ func TestManifestPullTaskShouldContinue(t *testing.T) {
 						Return(
 							registry.DistributionInspect{Descriptor: ocispec.Descriptor{Digest: testDigest}},
 							nil),
+					manifestPullClient.EXPECT().
+						PullImageLayerMediaTypes(gomock.Any(), testImage, gomock.Any(), nil).
+						Return(nil, nil),
 				}
 			},
 			shouldPullImage: true,
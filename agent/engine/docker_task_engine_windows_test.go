@@ -331,6 +331,9 @@ func TestTaskWithSteadyStateResourcesProvisioned(t *testing.T) {
 		Return(registry.DistributionInspect{
 			Descriptor: ocispec.Descriptor{Digest: testDigest},
 		}, nil)
+	manifestPullClient.EXPECT().
+		PullImageLayerMediaTypes(gomock.Any(), sleepContainer.Image, gomock.Any(), nil).
+		Return(nil, nil)
 	client.EXPECT().
 		PullImage(gomock.Any(), expectedCanonicalRef, nil, gomock.Any()).
 		Return(dockerapi.DockerContainerMetadata{})
@@ -572,6 +575,10 @@ func TestPauseContainerHappyPath(t *testing.T) {
 			assertPauseContainerIsRunning() // Ensure that pause container is already RUNNING
 		}).
 		Return(registry.DistributionInspect{}, nil)
+	manifestPullClient.EXPECT().
+		PullImageLayerMediaTypes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(nil, nil)
 	dockerClient.EXPECT().
 		PullImage(gomock.Any(), gomock.Any(), nil, gomock.Any()).
 		Do(func(context.Context, string, *apicontainer.RegistryAuthenticationData, time.Duration) {
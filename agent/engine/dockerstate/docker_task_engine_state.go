@@ -48,6 +48,8 @@ type TaskEngineState interface {
 	PulledContainerMapByArn(arn string) (map[string]*apicontainer.DockerContainer, bool)
 	// TaskByShortID retrieves the task of a given docker short container id
 	TaskByShortID(cid string) ([]*apitask.Task, bool)
+	// DockerIDsByPrefix returns the docker ids of all containers whose id starts with the given prefix
+	DockerIDsByPrefix(prefix string) []string
 	// TaskByID returns an apitask.Task for a given container ID
 	TaskByID(cid string) (*apitask.Task, bool)
 	// TaskByArn returns a task for a given ARN
@@ -439,6 +441,18 @@ func (state *DockerTaskEngineState) TaskByShortID(cid string) ([]*apitask.Task,
 	return tasks, len(tasks) > 0
 }
 
+// DockerIDsByPrefix returns the docker ids of all containers whose id starts with the given prefix,
+// such as a docker short container id
+func (state *DockerTaskEngineState) DockerIDsByPrefix(prefix string) []string {
+	var ids []string
+	for _, id := range state.GetAllContainerIDs() {
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // TaskByID retrieves the task of a given docker container id
 func (state *DockerTaskEngineState) TaskByID(cid string) (*apitask.Task, bool) {
 	state.lock.RLock()
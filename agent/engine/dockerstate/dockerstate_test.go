@@ -99,6 +99,22 @@ func TestAddTask(t *testing.T) {
 	}
 }
 
+func TestDockerIDsByPrefix(t *testing.T) {
+	state := NewTaskEngineState()
+	testTask := &apitask.Task{Arn: "test"}
+	state.AddTask(testTask)
+	for _, dockerID := range []string{"0123456789ab0000", "0123456789ab1111", "fedcba9876540000"} {
+		state.AddContainer(&apicontainer.DockerContainer{
+			DockerID:  dockerID,
+			Container: &apicontainer.Container{Name: "container-" + dockerID},
+		}, testTask)
+	}
+
+	assert.Equal(t, []string{"fedcba9876540000"}, state.DockerIDsByPrefix("fedcba987654"))
+	assert.ElementsMatch(t, []string{"0123456789ab0000", "0123456789ab1111"}, state.DockerIDsByPrefix("0123456789ab"))
+	assert.Empty(t, state.DockerIDsByPrefix("aaaaaaaaaaaa"))
+}
+
 func TestAddRemoveENIAttachment(t *testing.T) {
 	state := NewTaskEngineState()
 
@@ -222,6 +222,20 @@ func (mr *MockTaskEngineStateMockRecorder) ContainerMapByArn(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerMapByArn", reflect.TypeOf((*MockTaskEngineState)(nil).ContainerMapByArn), arg0)
 }
 
+// DockerIDsByPrefix mocks base method.
+func (m *MockTaskEngineState) DockerIDsByPrefix(arg0 string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DockerIDsByPrefix", arg0)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// DockerIDsByPrefix indicates an expected call of DockerIDsByPrefix.
+func (mr *MockTaskEngineStateMockRecorder) DockerIDsByPrefix(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DockerIDsByPrefix", reflect.TypeOf((*MockTaskEngineState)(nil).DockerIDsByPrefix), arg0)
+}
+
 // DockerIDByV3EndpointID mocks base method.
 func (m *MockTaskEngineState) DockerIDByV3EndpointID(arg0 string) (string, bool) {
 	m.ctrl.T.Helper()
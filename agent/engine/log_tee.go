@@ -0,0 +1,130 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+	"github.com/aws/aws-sdk-go/aws"
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+var (
+	// logTeeOutput is where container logs are teed to when ECS_LOG_TEE_STDOUT is enabled, can be
+	// overridden in tests
+	logTeeOutput io.Writer = os.Stdout
+	// logTeeLock serializes the lines written by the containers whose logs are teed at the same time
+	logTeeLock sync.Mutex
+)
+
+// teeContainerLogs writes the logs of the container to the agent's stdout until the container stops or the
+// engine is stopped. Each line is prefixed with the task ID and the container name.
+func (engine *DockerTaskEngine) teeContainerLogs(task *apitask.Task, container *apicontainer.Container,
+	dockerID string) {
+	logs, err := engine.client.ContainerLogs(engine.ctx, dockerID)
+	if err != nil {
+		logger.Warn("Failed to get logs of container to tee to stdout", logger.Fields{
+			field.TaskID:    task.GetID(),
+			field.Container: container.Name,
+			field.Error:     err,
+		})
+		return
+	}
+	defer logs.Close()
+
+	out := &linePrefixWriter{
+		out:    logTeeOutput,
+		prefix: fmt.Sprintf("[%s/%s] ", task.GetID(), container.Name),
+	}
+	// The logs of a container with a TTY aren't multiplexed, as stdout and stderr are the same stream
+	if containerUsesTTY(container) {
+		_, err = io.Copy(out, logs)
+	} else {
+		_, err = stdcopy.StdCopy(out, out, logs)
+	}
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil && engine.ctx.Err() == nil {
+		logger.Warn("Failed to tee logs of container to stdout", logger.Fields{
+			field.TaskID:    task.GetID(),
+			field.Container: container.Name,
+			field.Error:     err,
+		})
+	}
+}
+
+// containerUsesTTY returns whether the container was created with a TTY attached.
+func containerUsesTTY(container *apicontainer.Container) bool {
+	if container.DockerConfig.Config == nil {
+		return false
+	}
+	var config dockercontainer.Config
+	if err := json.Unmarshal([]byte(aws.StringValue(container.DockerConfig.Config)), &config); err != nil {
+		return false
+	}
+	return config.Tty
+}
+
+// linePrefixWriter writes each line written to it to the underlying writer with the prefix prepended. Partial
+// lines are buffered until they're completed or the writer is flushed.
+type linePrefixWriter struct {
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *linePrefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	start := 0
+	for {
+		i := bytes.IndexByte(w.buf[start:], '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[start : start+i+1]); err != nil {
+			return 0, err
+		}
+		start += i + 1
+	}
+	w.buf = append(w.buf[:0], w.buf[start:]...)
+	return len(p), nil
+}
+
+// Flush writes the buffered partial line, if any, as a complete line.
+func (w *linePrefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.writeLine(append(w.buf, '\n'))
+	w.buf = nil
+	return err
+}
+
+func (w *linePrefixWriter) writeLine(line []byte) error {
+	logTeeLock.Lock()
+	defer logTeeLock.Unlock()
+	_, err := fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+	return err
+}
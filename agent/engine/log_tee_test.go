@@ -0,0 +1,127 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	mock_dockerapi "github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const logTeeTaskARN = "arn:aws:ecs:us-west-2:123456789012:task/cluster/abc"
+
+func setLogTeeOutput(out io.Writer) func() {
+	logTeeOutput = out
+	return func() {
+		logTeeOutput = os.Stdout
+	}
+}
+
+func TestTeeContainerLogs(t *testing.T) {
+	var multiplexed bytes.Buffer
+	_, err := stdcopy.NewStdWriter(&multiplexed, stdcopy.Stdout).Write([]byte("hello\nwor"))
+	require.NoError(t, err)
+	_, err = stdcopy.NewStdWriter(&multiplexed, stdcopy.Stderr).Write([]byte("ld\nerror\n"))
+	require.NoError(t, err)
+	_, err = stdcopy.NewStdWriter(&multiplexed, stdcopy.Stdout).Write([]byte("no newline"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		config   *string
+		logs     []byte
+		expected string
+	}{
+		{
+			name:     "multiplexed logs",
+			logs:     multiplexed.Bytes(),
+			expected: "[abc/web] hello\n[abc/web] world\n[abc/web] error\n[abc/web] no newline\n",
+		},
+		{
+			name:     "tty logs",
+			config:   aws.String(`{"Tty":true}`),
+			logs:     []byte("hello\r\nworld"),
+			expected: "[abc/web] hello\r\n[abc/web] world\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			defer setLogTeeOutput(&out)()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			client := mock_dockerapi.NewMockDockerClient(ctrl)
+			ctx := context.Background()
+			engine := &DockerTaskEngine{ctx: ctx, client: client}
+			client.EXPECT().ContainerLogs(ctx, "dockerID").Return(io.NopCloser(bytes.NewReader(tc.logs)), nil)
+
+			container := &apicontainer.Container{Name: "web"}
+			container.DockerConfig.Config = tc.config
+			engine.teeContainerLogs(&apitask.Task{Arn: logTeeTaskARN}, container, "dockerID")
+
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestTeeContainerLogsError(t *testing.T) {
+	var out bytes.Buffer
+	defer setLogTeeOutput(&out)()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_dockerapi.NewMockDockerClient(ctrl)
+	ctx := context.Background()
+	engine := &DockerTaskEngine{ctx: ctx, client: client}
+	client.EXPECT().ContainerLogs(ctx, "dockerID").Return(nil, errors.New("no such container"))
+
+	engine.teeContainerLogs(&apitask.Task{Arn: logTeeTaskARN}, &apicontainer.Container{Name: "web"}, "dockerID")
+	assert.Empty(t, out.String())
+}
+
+func TestLinePrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := &linePrefixWriter{out: &out, prefix: "> "}
+
+	n, err := w.Write([]byte("a\nb"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "> a\n", out.String())
+
+	_, err = w.Write([]byte("c\n\nd"))
+	require.NoError(t, err)
+	assert.Equal(t, "> a\n> bc\n> \n", out.String())
+
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "> a\n> bc\n> \n> d\n", out.String())
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "> a\n> bc\n> \n> d\n", out.String())
+}
@@ -60,6 +60,7 @@ const (
 	stoppedSentWaitInterval                  = 30 * time.Second
 	maxStoppedWaitTimes                      = 72 * time.Hour / stoppedSentWaitInterval
 	taskUnableToTransitionToStoppedReason    = "TaskStateError: Agent could not progress task's state to stopped"
+	taskHealthGatingTimeoutReason            = "TaskHealthGatingError: Essential containers did not become healthy"
 	// unstage retries are ultimately limited by successful unstage or by the unstageVolumeTimeout
 	unstageVolumeTimeout = 30 * time.Second
 	// substantial min/max accommodate a csi-driver outage
@@ -468,6 +469,11 @@ func (mtask *managedTask) handleContainerChange(containerChange dockerContainerC
 		return
 	}
 
+	if event.Type == apicontainer.ContainerHealthEvent {
+		mtask.handleContainerHealthChange()
+		return
+	}
+
 	// If this is a backwards transition stopped->running, the first time set it
 	// to be known running so it will be stopped. Subsequently, ignore these backward transitions
 	mtask.handleStoppedToRunningContainerTransition(event.Status, container)
@@ -560,6 +566,15 @@ func (mtask *managedTask) handleContainerChange(containerChange dockerContainerC
 	}
 }
 
+// handleContainerHealthChange re-evaluates the task status after the health of one of its containers
+// changed, as the task RUNNING status may be gated on the health of its essential containers
+func (mtask *managedTask) handleContainerHealthChange() {
+	if mtask.UpdateStatus() {
+		mtask.emitTaskEvent(mtask.Task, "")
+		mtask.engine.saveTaskData(mtask.Task)
+	}
+}
+
 // handleResourceStateChange attempts to update resource's known status depending on
 // the current status and errors during transition
 func (mtask *managedTask) handleResourceStateChange(resChange resourceStateChange) {
@@ -1077,6 +1092,25 @@ func (mtask *managedTask) progressTask() {
 
 	blockedByOrderingDependencies := len(blockedDependencies) > 0
 
+	// If no transitions happened because all containers are running but the task is held back by health gating, wait
+	// for the health of its essential containers to change, and stop the task once the gating timeout expired.
+	if !atLeastOneTransitionStarted && mtask.IsHeldByHealthGating() {
+		if mtask.IsHealthGatingTimedOut(mtask.cfg.TaskHealthGatingTimeout, mtask.time().Now()) {
+			logger.Warn("Essential containers did not become healthy within the health gating timeout; stopping task",
+				logger.Fields{
+					field.TaskID: mtask.GetID(),
+					"timeout":    mtask.cfg.TaskHealthGatingTimeout.String(),
+				})
+			mtask.SetTerminalReason(taskHealthGatingTimeoutReason)
+			mtask.handleDesiredStatusChange(apitaskstatus.TaskStopped, 0)
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), transitionPollTime)
+		defer cancel()
+		mtask.waitEvent(ctx.Done())
+		return
+	}
+
 	// If no transitions happened, and we aren't blocked by ordering dependencies, then we are possibly in a state where
 	// its impossible for containers to move forward. We will do an additional check to see if we are waiting for ACS
 	// execution credentials. If not, then we will abort the task progression.
@@ -57,6 +57,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandleEventError(t *testing.T) {
@@ -1108,6 +1109,40 @@ func TestOnContainersUnableToTransitionStateForDesiredRunningTask(t *testing.T)
 	}
 }
 
+// TestProgressTaskHealthGatingTimeout tests that a task held back from running by health gating is stopped
+// once its essential container has not become healthy within the health gating timeout
+func TestProgressTaskHealthGatingTimeout(t *testing.T) {
+	container := &apicontainer.Container{
+		Name:                "container1",
+		KnownStatusUnsafe:   apicontainerstatus.ContainerRunning,
+		DesiredStatusUnsafe: apicontainerstatus.ContainerRunning,
+		Essential:           true,
+		HealthCheckType:     apicontainer.DockerHealthCheckType,
+	}
+	container.SetHealthStatus(apicontainer.HealthStatus{Status: apicontainerstatus.ContainerHealthUnknown})
+	container.SetStartedAt(time.Now().Add(-time.Hour))
+	mTask := &managedTask{
+		Task: &apitask.Task{
+			Arn:                 "arn:aws:ecs:us-west-2:123456789012:task/test-cluster/task-id",
+			Containers:          []*apicontainer.Container{container},
+			KnownStatusUnsafe:   apitaskstatus.TaskCreated,
+			DesiredStatusUnsafe: apitaskstatus.TaskRunning,
+		},
+		engine: &DockerTaskEngine{
+			dataClient: data.NewNoopClient(),
+		},
+		cfg: &config.Config{TaskHealthGatingTimeout: time.Minute},
+		ctx: context.TODO(),
+	}
+	mTask.SetHealthGatingEnabled(true)
+
+	mTask.progressTask()
+	assert.Equal(t, apitaskstatus.TaskCreated, mTask.GetKnownStatus())
+	assert.Equal(t, apitaskstatus.TaskStopped, mTask.GetDesiredStatus())
+	assert.Equal(t, apicontainerstatus.ContainerStopped, container.GetDesiredStatus())
+	assert.Equal(t, taskHealthGatingTimeoutReason, mTask.GetTerminalReason())
+}
+
 // TODO: Test progressContainers workflow
 
 func TestHandleStoppedToSteadyStateTransition(t *testing.T) {
@@ -1854,6 +1889,46 @@ func TestHandleContainerChangeUpdateMetadataRedundant(t *testing.T) {
 	assert.Equal(t, timeNow, containerCreateTime)
 }
 
+func TestHandleContainerChangeHealthEventWithHealthGating(t *testing.T) {
+	mTask := &managedTask{
+		Task:              testdata.LoadTask("sleep5TaskCgroup"),
+		stateChangeEvents: make(chan statechange.Event, 1),
+		ctx:               context.TODO(),
+		engine: &DockerTaskEngine{
+			dataClient: data.NewNoopClient(),
+		},
+	}
+	mTask.SetHealthGatingEnabled(true)
+	mTask.SetKnownStatus(apitaskstatus.TaskCreated)
+	mTask.SetSentStatus(apitaskstatus.TaskCreated)
+	container := mTask.Containers[0]
+	container.Essential = true
+	container.HealthCheckType = apicontainer.DockerHealthCheckType
+	container.SetKnownStatus(apicontainerstatus.ContainerRunning)
+	container.SetHealthStatus(apicontainer.HealthStatus{Status: apicontainerstatus.ContainerHealthUnknown})
+
+	healthEvent := dockerContainerChange{
+		container: container,
+		event: dockerapi.DockerContainerChangeEvent{
+			Status: apicontainerstatus.ContainerRunning,
+			Type:   apicontainer.ContainerHealthEvent,
+		},
+	}
+
+	// Task should stay CREATED while the essential container's health is unknown
+	mTask.handleContainerChange(healthEvent)
+	assert.Equal(t, apitaskstatus.TaskCreated, mTask.GetKnownStatus())
+	assert.Len(t, mTask.stateChangeEvents, 0)
+
+	// Task should move to RUNNING once the essential container is healthy
+	container.SetHealthStatus(apicontainer.HealthStatus{Status: apicontainerstatus.ContainerHealthy})
+	mTask.handleContainerChange(healthEvent)
+	assert.Equal(t, apitaskstatus.TaskRunning, mTask.GetKnownStatus())
+	require.Len(t, mTask.stateChangeEvents, 1)
+	event := (<-mTask.stateChangeEvents).(api.TaskStateChange)
+	assert.Equal(t, apitaskstatus.TaskRunning, event.Status)
+}
+
 func waitForTaskDesiredStatus(mTask *managedTask, status apitaskstatus.TaskStatus) {
 	for i := 0; i < 40; i++ {
 		taskStatus := mTask.GetDesiredStatus()
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
 	"github.com/aws/amazon-ecs-agent/agent/engine"
 	handlersutils "github.com/aws/amazon-ecs-agent/agent/handlers/utils"
 	v1 "github.com/aws/amazon-ecs-agent/agent/handlers/v1"
@@ -36,6 +37,10 @@ type rootResponse struct {
 }
 
 const (
+	// IntrospectionAPIVersion is the version of the introspection API served by the agent. Bump it whenever
+	// handlers are added to or changed on the introspection server.
+	IntrospectionAPIVersion = "1"
+
 	// With pprof we need to increase the timeout so that there is enough time to do the profiling. Since the profiling
 	// time window for CPU is configurable in the request, this timeout effectively means the CPU profiling will be
 	// capped to 5 min.
@@ -56,7 +61,8 @@ var (
 	pprofTraceHandler   = pprof.Trace
 )
 
-func introspectionServerSetup(containerInstanceArn *string, taskEngine handlersutils.DockerStateResolver, cfg *config.Config) *http.Server {
+func introspectionServerSetup(containerInstanceArn *string, taskEngine handlersutils.DockerStateResolver,
+	dockerConnection v1.DockerConnectionStatusProvider, cfg *config.Config) *http.Server {
 	paths := []string{v1.AgentMetadataPath, v1.TaskContainerMetadataPath, v1.LicensePath}
 
 	if cfg.EnableRuntimeStats.Enabled() {
@@ -77,7 +83,7 @@ func introspectionServerSetup(containerInstanceArn *string, taskEngine handlersu
 	serverMux := http.NewServeMux()
 	serverMux.HandleFunc("/", defaultHandler)
 
-	v1HandlersSetup(serverMux, containerInstanceArn, taskEngine, cfg)
+	v1HandlersSetup(serverMux, containerInstanceArn, taskEngine, dockerConnection, cfg)
 	pprofHandlerSetup(serverMux, cfg)
 
 	// Log all requests and then pass through to serverMux
@@ -102,8 +108,9 @@ func introspectionServerSetup(containerInstanceArn *string, taskEngine handlersu
 func v1HandlersSetup(serverMux *http.ServeMux,
 	containerInstanceArn *string,
 	taskEngine handlersutils.DockerStateResolver,
+	dockerConnection v1.DockerConnectionStatusProvider,
 	cfg *config.Config) {
-	serverMux.HandleFunc(v1.AgentMetadataPath, v1.AgentMetadataHandler(containerInstanceArn, cfg))
+	serverMux.HandleFunc(v1.AgentMetadataPath, v1.AgentMetadataHandler(containerInstanceArn, cfg, dockerConnection))
 	serverMux.HandleFunc(v1.TaskContainerMetadataPath, v1.TaskContainerMetadataHandler(taskEngine))
 	serverMux.HandleFunc(v1.LicensePath, v1.LicenseHandler)
 }
@@ -122,12 +129,13 @@ func pprofHandlerSetup(serverMux *http.ServeMux, cfg *config.Config) {
 // ServeIntrospectionHTTPEndpoint serves information about this agent/containerInstance and tasks
 // running on it. "V1" here indicates the hostname version of this server instead
 // of the handler versions, i.e. "V1" server can include "V1" and "V2" handlers.
-func ServeIntrospectionHTTPEndpoint(ctx context.Context, containerInstanceArn *string, taskEngine engine.TaskEngine, cfg *config.Config) {
+func ServeIntrospectionHTTPEndpoint(ctx context.Context, containerInstanceArn *string, taskEngine engine.TaskEngine,
+	dockerClient dockerapi.DockerClient, cfg *config.Config) {
 	// Is this the right level to type assert, assuming we'd abstract multiple taskengines here?
 	// Revisit if we ever add another type..
 	dockerTaskEngine := taskEngine.(*engine.DockerTaskEngine)
 
-	server := introspectionServerSetup(containerInstanceArn, dockerTaskEngine, cfg)
+	server := introspectionServerSetup(containerInstanceArn, dockerTaskEngine, dockerClient, cfg)
 
 	go func() {
 		<-ctx.Done()
@@ -27,6 +27,8 @@ import (
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
+	mock_dockerapi "github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi/mocks"
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	mock_utils "github.com/aws/amazon-ecs-agent/agent/handlers/mocks"
 	v1 "github.com/aws/amazon-ecs-agent/agent/handlers/v1"
@@ -47,7 +49,7 @@ const (
 var runtimeStatsConfigForTest = config.BooleanDefaultFalse{}
 
 func TestMetadataHandler(t *testing.T) {
-	metadataHandler := v1.AgentMetadataHandler(utils.Strptr(testContainerInstanceArn), &config.Config{Cluster: testClusterArn})
+	metadataHandler := v1.AgentMetadataHandler(utils.Strptr(testContainerInstanceArn), &config.Config{Cluster: testClusterArn}, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "http://localhost:"+strconv.Itoa(config.AgentIntrospectionPort), nil)
@@ -64,6 +66,46 @@ func TestMetadataHandler(t *testing.T) {
 	}
 }
 
+func TestMetadataHandlerDockerConnection(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   dockerapi.DockerConnectionStatus
+		expected *v1.DockerConnectionResponse
+	}{
+		{
+			name:     "connected",
+			status:   dockerapi.DockerConnectionStatus{State: dockerapi.DockerConnectionConnected},
+			expected: &v1.DockerConnectionResponse{State: "connected"},
+		},
+		{
+			name: "reconnecting",
+			status: dockerapi.DockerConnectionStatus{
+				State:     dockerapi.DockerConnectionReconnecting,
+				LastError: "unexpected EOF",
+			},
+			expected: &v1.DockerConnectionResponse{State: "reconnecting", LastError: "unexpected EOF"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+			dockerClient.EXPECT().ConnectionStatus().Return(tc.status)
+
+			metadataHandler := v1.AgentMetadataHandler(utils.Strptr(testContainerInstanceArn),
+				&config.Config{Cluster: testClusterArn}, dockerClient)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "http://localhost:"+strconv.Itoa(config.AgentIntrospectionPort), nil)
+			metadataHandler(w, req)
+
+			var resp v1.MetadataResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tc.expected, resp.DockerConnection)
+		})
+	}
+}
+
 func TestListMultipleTasks(t *testing.T) {
 	recorder := performMockRequest(t, "/v1/tasks")
 
@@ -481,7 +523,7 @@ func performMockRequest(t *testing.T, path string) *httptest.ResponseRecorder {
 		mockStateResolver.EXPECT().State().Return(state)
 	}
 
-	requestHandler := introspectionServerSetup(utils.Strptr(testContainerInstanceArn), mockStateResolver, &config.Config{
+	requestHandler := introspectionServerSetup(utils.Strptr(testContainerInstanceArn), mockStateResolver, nil, &config.Config{
 		Cluster:            testClusterArn,
 		EnableRuntimeStats: runtimeStatsConfigForTest,
 	})
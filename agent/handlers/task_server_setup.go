@@ -15,12 +15,17 @@ package handlers
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	tpfactory "github.com/aws/amazon-ecs-agent/agent/handlers/agentapi/taskprotection"
+	"github.com/aws/amazon-ecs-agent/agent/handlers/utils"
 	v2 "github.com/aws/amazon-ecs-agent/agent/handlers/v2"
 	v3 "github.com/aws/amazon-ecs-agent/agent/handlers/v3"
 	v4 "github.com/aws/amazon-ecs-agent/agent/handlers/v4"
@@ -53,6 +58,13 @@ const (
 
 	// Timeout for ECS calls. Must be lower than server write timeout defined above.
 	ecsCallTimeout = 4 * time.Second
+
+	// FaultInjectionStatusEnabled specifies whether the task metadata endpoint serves the route listing
+	// the active fault injections of a task.
+	FaultInjectionStatusEnabled = true
+	// tmdsSocketMode is the file mode of the Unix domain socket the task metadata endpoint
+	// is served on, which restricts access to the socket's owner.
+	tmdsSocketMode = 0700
 )
 
 func taskServerSetup(
@@ -62,8 +74,12 @@ func taskServerSetup(
 	ecsClient ecs.ECSClient,
 	cluster string,
 	statsEngine stats.Engine,
+	dockerClient dockerapi.DockerClient,
 	steadyStateRate int,
 	burstRate int,
+	maxConcurrentRequests int,
+	metadataFieldStyle config.MetadataFieldStyleType,
+	metadataAuthEnabled bool,
 	availabilityZone string,
 	vpcID string,
 	containerInstanceArn string,
@@ -81,19 +97,51 @@ func taskServerSetup(
 
 	tmdsAgentState := v4.NewTMDSAgentState(state, statsEngine, ecsClient, cluster, availabilityZone, vpcID, containerInstanceArn)
 	metricsFactory := metrics.NewNopEntryFactory()
+	// Container metadata handlers across versions share one bound on concurrent requests
+	containerMetadataLimiter := utils.NewConcurrencyLimiter(maxConcurrentRequests)
+	// v3 and v4 handlers require the token of the requesting task when metadata authentication is enabled
+	metadataAuthenticator := utils.NewMetadataAuthenticator(state, metadataAuthEnabled,
+		v3.V3EndpointIDMuxName, tmdsv4.EndpointContainerIDMuxName)
 
 	v2HandlersSetup(muxRouter, state, ecsClient, statsEngine, cluster, credentialsManager, auditLogger, availabilityZone, containerInstanceArn)
 
-	v3HandlersSetup(muxRouter, state, ecsClient, statsEngine, cluster, availabilityZone, containerInstanceArn)
+	v3HandlersSetup(muxRouter, state, ecsClient, statsEngine, dockerClient, cluster, availabilityZone,
+		containerInstanceArn, containerMetadataLimiter, metadataAuthenticator, metadataFieldStyle)
 
-	v4HandlersSetup(muxRouter, state, ecsClient, statsEngine, cluster, availabilityZone, vpcID, containerInstanceArn,
-		tmdsAgentState, metricsFactory)
+	v4HandlersSetup(muxRouter, state, ecsClient, statsEngine, dockerClient, cluster, availabilityZone, vpcID,
+		containerInstanceArn, tmdsAgentState, metricsFactory, containerMetadataLimiter, metadataAuthenticator)
 
 	agentAPIV1HandlersSetup(muxRouter, state, credentialsManager, cluster, tmdsAgentState,
 		taskProtectionClientFactory, metricsFactory)
 
-	// TODO: Future PR to pass in TMDS server router once all of the handlers have been implemented.
-	registerFaultHandlers(nil, tmdsAgentState, metricsFactory)
+	// TODO: Future PR to pass in TMDS server router for the network fault handlers once all of them have been
+	// implemented.
+	registerFaultHandlers(muxRouter, nil, tmdsAgentState, metricsFactory, metadataAuthenticator)
+
+	return tmds.NewServer(auditLogger,
+		tmds.WithHandler(muxRouter),
+		tmds.WithListenAddress(tmds.AddressIPv4()),
+		tmds.WithReadTimeout(readTimeout),
+		tmds.WithWriteTimeout(writeTimeout),
+		tmds.WithSteadyStateRate(float64(steadyStateRate)),
+		tmds.WithBurstRate(burstRate))
+}
+
+// credentialsServerSetup sets up a server that only serves the IAM role credentials handlers. It listens
+// on the link-local address tasks get their credentials from when the rest of the task metadata endpoint
+// is served on a Unix domain socket.
+func credentialsServerSetup(
+	credentialsManager credentials.Manager,
+	auditLogger auditinterface.AuditLogger,
+	steadyStateRate int,
+	burstRate int,
+) (*http.Server, error) {
+	muxRouter := mux.NewRouter()
+	muxRouter.SkipClean(false)
+
+	muxRouter.HandleFunc(tmdsv1.CredentialsPath,
+		tmdsv1.CredentialsHandler(credentialsManager, auditLogger))
+	muxRouter.HandleFunc(tmdsv2.CredentialsPath, tmdsv2.CredentialsHandler(credentialsManager, auditLogger))
 
 	return tmds.NewServer(auditLogger,
 		tmds.WithHandler(muxRouter),
@@ -130,17 +178,26 @@ func v3HandlersSetup(muxRouter *mux.Router,
 	state dockerstate.TaskEngineState,
 	ecsClient ecs.ECSClient,
 	statsEngine stats.Engine,
+	dockerClient dockerapi.DockerClient,
 	cluster string,
 	availabilityZone string,
-	containerInstanceArn string) {
-	muxRouter.HandleFunc(v3.ContainerMetadataPath, v3.ContainerMetadataHandler(state))
-	muxRouter.HandleFunc(v3.TaskMetadataPath, v3.TaskMetadataHandler(state, ecsClient, cluster, availabilityZone, containerInstanceArn, false))
-	muxRouter.HandleFunc(v3.TaskWithTagsMetadataPath, v3.TaskMetadataHandler(state, ecsClient, cluster, availabilityZone, containerInstanceArn, true))
-	muxRouter.HandleFunc(v3.ContainerStatsPath, v3.ContainerStatsHandler(state, statsEngine))
-	muxRouter.HandleFunc(v3.TaskStatsPath, v3.TaskStatsHandler(state, statsEngine))
-	muxRouter.HandleFunc(v3.ContainerAssociationsPath, v3.ContainerAssociationsHandler(state))
-	muxRouter.HandleFunc(v3.ContainerAssociationPathWithSlash, v3.ContainerAssociationHandler(state))
-	muxRouter.HandleFunc(v3.ContainerAssociationPath, v3.ContainerAssociationHandler(state))
+	containerInstanceArn string,
+	containerMetadataLimiter *utils.ConcurrencyLimiter,
+	metadataAuthenticator *utils.MetadataAuthenticator,
+	metadataFieldStyle config.MetadataFieldStyleType) {
+	auth := metadataAuthenticator.Authenticate
+	muxRouter.HandleFunc(v3.HealthPath, v3.HealthHandler(state))
+	muxRouter.HandleFunc(v3.ContainerMetadataPath, auth(containerMetadataLimiter.Limit(v3.ContainerMetadataHandler(state, metadataFieldStyle))))
+	muxRouter.HandleFunc(v3.TaskMetadataPath, auth(v3.TaskMetadataHandler(state, ecsClient, cluster, availabilityZone, containerInstanceArn, false)))
+	muxRouter.HandleFunc(v3.TaskWithTagsMetadataPath, auth(v3.TaskMetadataHandler(state, ecsClient, cluster, availabilityZone, containerInstanceArn, true)))
+	muxRouter.HandleFunc(v3.ContainerStatsPath, auth(v3.ContainerStatsHandler(state, statsEngine)))
+	muxRouter.HandleFunc(v3.ContainerLogConfigPath, auth(v3.ContainerLogConfigHandler(state)))
+	muxRouter.HandleFunc(v3.ContainerResourcesPath, auth(v3.ContainerResourcesHandler(state, dockerClient))).Methods("PUT")
+	muxRouter.HandleFunc(v3.TaskStatsPath, auth(v3.TaskStatsHandler(state, statsEngine)))
+	muxRouter.HandleFunc(v3.TaskENIPath, auth(v3.TaskENIHandler(state)))
+	muxRouter.HandleFunc(v3.ContainerAssociationsPath, auth(v3.ContainerAssociationsHandler(state)))
+	muxRouter.HandleFunc(v3.ContainerAssociationPathWithSlash, auth(v3.ContainerAssociationHandler(state)))
+	muxRouter.HandleFunc(v3.ContainerAssociationPath, auth(v3.ContainerAssociationHandler(state)))
 }
 
 // v4HandlerSetup adda all handlers in v4 package to the mux router
@@ -148,21 +205,27 @@ func v4HandlersSetup(muxRouter *mux.Router,
 	state dockerstate.TaskEngineState,
 	ecsClient ecs.ECSClient,
 	statsEngine stats.Engine,
+	dockerClient dockerapi.DockerClient,
 	cluster string,
 	availabilityZone string,
 	vpcID string,
 	containerInstanceArn string,
 	tmdsAgentState *v4.TMDSAgentState,
 	metricsFactory metrics.EntryFactory,
+	containerMetadataLimiter *utils.ConcurrencyLimiter,
+	metadataAuthenticator *utils.MetadataAuthenticator,
 ) {
-	muxRouter.HandleFunc(tmdsv4.ContainerMetadataPath(), tmdsv4.ContainerMetadataHandler(tmdsAgentState, metricsFactory))
-	muxRouter.HandleFunc(tmdsv4.TaskMetadataPath(), tmdsv4.TaskMetadataHandler(tmdsAgentState, metricsFactory))
-	muxRouter.HandleFunc(tmdsv4.TaskMetadataWithTagsPath(), tmdsv4.TaskMetadataWithTagsHandler(tmdsAgentState, metricsFactory))
-	muxRouter.HandleFunc(tmdsv4.ContainerStatsPath(), tmdsv4.ContainerStatsHandler(tmdsAgentState, metricsFactory))
-	muxRouter.HandleFunc(tmdsv4.TaskStatsPath(), tmdsv4.TaskStatsHandler(tmdsAgentState, metricsFactory))
-	muxRouter.HandleFunc(v4.ContainerAssociationsPath, v4.ContainerAssociationsHandler(state))
-	muxRouter.HandleFunc(v4.ContainerAssociationPathWithSlash, v4.ContainerAssociationHandler(state))
-	muxRouter.HandleFunc(v4.ContainerAssociationPath, v4.ContainerAssociationHandler(state))
+	auth := metadataAuthenticator.Authenticate
+	muxRouter.HandleFunc(tmdsv4.ContainerMetadataPath(),
+		auth(containerMetadataLimiter.Limit(tmdsv4.ContainerMetadataHandler(tmdsAgentState, metricsFactory))))
+	muxRouter.HandleFunc(tmdsv4.TaskMetadataPath(), auth(tmdsv4.TaskMetadataHandler(tmdsAgentState, metricsFactory)))
+	muxRouter.HandleFunc(tmdsv4.TaskMetadataWithTagsPath(), auth(tmdsv4.TaskMetadataWithTagsHandler(tmdsAgentState, metricsFactory)))
+	muxRouter.HandleFunc(tmdsv4.ContainerStatsPath(), auth(tmdsv4.ContainerStatsHandler(tmdsAgentState, metricsFactory)))
+	muxRouter.HandleFunc(tmdsv4.TaskStatsPath(), auth(tmdsv4.TaskStatsHandler(tmdsAgentState, metricsFactory)))
+	muxRouter.HandleFunc(v4.ContainerAssociationsPath, auth(v4.ContainerAssociationsHandler(state)))
+	muxRouter.HandleFunc(v4.ContainerAssociationPathWithSlash, auth(v4.ContainerAssociationHandler(state)))
+	muxRouter.HandleFunc(v4.ContainerAssociationPath, auth(v4.ContainerAssociationHandler(state)))
+	muxRouter.HandleFunc(v4.ContainerResizePath, auth(v4.ContainerResizeHandler(state, dockerClient))).Methods("PUT")
 }
 
 // agentAPIV1HandlersSetup adds handlers for Agent API V1
@@ -189,18 +252,30 @@ func agentAPIV1HandlersSetup(
 		Methods("GET")
 }
 
-// registerFaultHandlers adds handlers for fault endpoints
-// TODO: Will need to be called in taskServerSetup once all of the handlers have been implemented
+// registerFaultHandlers adds handlers for fault endpoints. The fault injection status endpoint is added to
+// statusMuxRouter, and the network fault endpoints are added to networkFaultMuxRouter if it's not nil.
+// TODO: Pass the TMDS server router as networkFaultMuxRouter once all of the handlers have been implemented
 func registerFaultHandlers(
-	muxRouter *mux.Router,
+	statusMuxRouter *mux.Router,
+	networkFaultMuxRouter *mux.Router,
 	agentState *v4.TMDSAgentState,
 	metricsFactory metrics.EntryFactory,
+	metadataAuthenticator *utils.MetadataAuthenticator,
 ) {
-	handler := fault.FaultHandler{
+	handler := &fault.FaultHandler{
 		AgentState:     agentState,
 		MetricsFactory: metricsFactory,
 	}
 
+	// Setting up handler endpoint listing the active fault injections of a task
+	if FaultInjectionStatusEnabled {
+		statusMuxRouter.HandleFunc(
+			fault.TaskFaultsStatusPath(),
+			metadataAuthenticator.Authenticate(handler.CheckTaskFaultsStatus()),
+		).Methods("GET")
+	}
+
+	muxRouter := networkFaultMuxRouter
 	if muxRouter == nil {
 		return
 	}
@@ -260,6 +335,7 @@ func ServeTaskHTTPEndpoint(
 	containerInstanceArn string,
 	cfg *config.Config,
 	statsEngine stats.Engine,
+	dockerClient dockerapi.DockerClient,
 	availabilityZone string,
 	vpcID string) {
 	// Create and initialize the audit log
@@ -276,13 +352,34 @@ func ServeTaskHTTPEndpoint(
 		Region: cfg.AWSRegion, Endpoint: cfg.APIEndpoint, AcceptInsecureCert: cfg.AcceptInsecureCert,
 	}
 	server, err := taskServerSetup(credentialsManager, auditLogger, state, ecsClient, cfg.Cluster,
-		statsEngine, cfg.TaskMetadataSteadyStateRate, cfg.TaskMetadataBurstRate,
-		availabilityZone, vpcID, containerInstanceArn, taskProtectionClientFactory)
+		statsEngine, dockerClient, cfg.TaskMetadataSteadyStateRate, cfg.TaskMetadataBurstRate, cfg.TMDSMaxConcurrentRequests,
+		cfg.MetadataFieldStyle, cfg.TMDSAuthEnabled.Enabled(), availabilityZone, vpcID, containerInstanceArn,
+		taskProtectionClientFactory)
 	if err != nil {
 		seelog.Criticalf("Failed to set up Task Metadata Server: %v", err)
 		return
 	}
 
+	if cfg.TMDSSocketPath != "" {
+		// Tasks aren't given access to the socket, so they keep getting their credentials
+		// from the link-local address
+		credentialsServer, err := credentialsServerSetup(credentialsManager, auditLogger,
+			cfg.TaskMetadataSteadyStateRate, cfg.TaskMetadataBurstRate)
+		if err != nil {
+			seelog.Criticalf("Failed to set up Task Metadata Server credentials endpoint: %v", err)
+			return
+		}
+		go serveTaskServer(ctx, credentialsServer, credentialsServer.ListenAndServe)
+	}
+
+	serveTaskServer(ctx, server, func() error {
+		return listenAndServe(server, cfg.TMDSSocketPath)
+	})
+}
+
+// serveTaskServer runs serve until the server is closed, retrying with backoff on errors, and shuts the
+// server down once ctx is done.
+func serveTaskServer(ctx context.Context, server *http.Server, serve func() error) {
 	go func() {
 		<-ctx.Done()
 		if err := server.Shutdown(context.Background()); err != nil {
@@ -293,7 +390,7 @@ func ServeTaskHTTPEndpoint(
 
 	for {
 		retry.RetryWithBackoff(retry.NewExponentialBackoff(time.Second, time.Minute, 0.2, 2), func() error {
-			if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			if err := serve(); err != http.ErrServerClosed {
 				seelog.Errorf("Error running task api: %v", err)
 				return err
 			}
@@ -302,3 +399,50 @@ func ServeTaskHTTPEndpoint(
 		})
 	}
 }
+
+// listenAndServe serves the task metadata endpoint on the Unix domain socket at socketPath,
+// or on the server's TCP address if socketPath is empty.
+func listenAndServe(server *http.Server, socketPath string) error {
+	if socketPath == "" {
+		return server.ListenAndServe()
+	}
+	listener, err := listenUnixSocket(socketPath)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// listenUnixSocket listens on the Unix domain socket at socketPath, replacing any socket left
+// behind by a previous run, and restricts access to the socket's owner. The socket is created
+// in a private directory and only moved to socketPath once its mode is set, so that it's never
+// reachable with the default permissions.
+func listenUnixSocket(socketPath string) (net.Listener, error) {
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	// os.MkdirTemp creates the directory with mode 0700, next to socketPath so that the socket
+	// can be renamed into place
+	dir, err := os.MkdirTemp(filepath.Dir(socketPath), ".tmds")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	privateSocketPath := filepath.Join(dir, "s")
+	listener, err := net.Listen("unix", privateSocketPath)
+	if err != nil {
+		return nil, err
+	}
+	// the socket is moved away from the path it was created at, which must not be unlinked on close
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(privateSocketPath, tmdsSocketMode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(privateSocketPath, socketPath); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
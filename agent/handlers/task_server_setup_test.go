@@ -18,14 +18,18 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -33,6 +37,7 @@ import (
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	"github.com/aws/amazon-ecs-agent/agent/config"
 	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	handlersutils "github.com/aws/amazon-ecs-agent/agent/handlers/utils"
 	v3 "github.com/aws/amazon-ecs-agent/agent/handlers/v3"
 	agentV4 "github.com/aws/amazon-ecs-agent/agent/handlers/v4"
 	mock_stats "github.com/aws/amazon-ecs-agent/agent/stats/mock"
@@ -208,7 +213,8 @@ var (
 		taskARN: pulledDockerContainer,
 	}
 	labels = map[string]string{
-		"foo": "bar",
+		"foo":                        "bar",
+		"com.amazonaws.ecs.task-arn": taskARN,
 	}
 	expectedContainerResponse = v2.ContainerResponse{
 		ID:            containerID,
@@ -222,8 +228,9 @@ var (
 			CPU:    aws.Float64(cpu),
 			Memory: aws.Int64(memory),
 		},
-		Type:   containerType,
-		Labels: labels,
+		Type:               containerType,
+		RestartPolicyState: apicontainer.RestartPolicyStateDisabled,
+		Labels:             labels,
 		Ports: []tmdsresponse.PortResponse{
 			{
 				ContainerPort: containerPort,
@@ -248,7 +255,8 @@ var (
 			CPU:    aws.Float64(cpu),
 			Memory: aws.Int64(memory),
 		},
-		Type: containerType,
+		Type:               containerType,
+		RestartPolicyState: apicontainer.RestartPolicyStateDisabled,
 	}
 	expectedTaskResponseNoContainers = stripContainersFromV2TaskResponse(expectedTaskResponse())
 	expectedAssociationsResponse     = v3.AssociationsResponse{
@@ -317,8 +325,9 @@ var (
 			CPU:    aws.Float64(cpu),
 			Memory: aws.Int64(memory),
 		},
-		Type:   containerType,
-		Labels: labels,
+		Type:               containerType,
+		RestartPolicyState: apicontainer.RestartPolicyStateDisabled,
+		Labels:             labels,
 		Ports: []tmdsresponse.PortResponse{
 			{
 				ContainerPort: containerPort,
@@ -364,8 +373,9 @@ var (
 				CPU:    aws.Float64(cpu),
 				Memory: aws.Int64(memory),
 			},
-			Type:   containerType,
-			Labels: labels,
+			Type:               containerType,
+			RestartPolicyState: apicontainer.RestartPolicyStateDisabled,
+			Labels:             labels,
 			Ports: []tmdsresponse.PortResponse{
 				{
 					ContainerPort: containerPort,
@@ -400,7 +410,8 @@ var (
 				CPU:    aws.Float64(cpu),
 				Memory: aws.Int64(memory),
 			},
-			Type: containerType,
+			Type:               containerType,
+			RestartPolicyState: apicontainer.RestartPolicyStateDisabled,
 		},
 	}
 	expectedV4BridgeContainerResponse = v4ContainerResponseFromV2(expectedBridgeContainerResponse, []v4.Network{{
@@ -465,13 +476,21 @@ func expectedTaskResponse() v2.TaskResponse {
 			CPU:    aws.Float64(cpu),
 			Memory: aws.Int64(memory),
 		},
-		PullStartedAt:      aws.Time(now.UTC()),
-		PullStoppedAt:      aws.Time(now.UTC()),
-		ExecutionStoppedAt: aws.Time(now.UTC()),
-		AvailabilityZone:   availabilityzone,
+		PullStartedAt:             aws.Time(now.UTC()),
+		PullStoppedAt:             aws.Time(now.UTC()),
+		ExecutionStoppedAt:        aws.Time(now.UTC()),
+		AvailabilityZone:          availabilityzone,
+		SupportedMetadataVersions: []string{"v2"},
 	}
 }
 
+// Returns the v3 container response for a v2 container response. The v3 container endpoint also
+// serves the container's docker labels, without the labels set by ECS. Labels are left unchanged.
+func expectedV3ContainerResponse(containerResponse v2.ContainerResponse) v2.ContainerResponse {
+	containerResponse.DockerLabels = map[string]string{"foo": "bar"}
+	return containerResponse
+}
+
 // Creates a v4 ContainerResponse given a v2 ContainerResponse and v4 networks
 func v4ContainerResponseFromV2(
 	v2ContainerResponse v2.ContainerResponse, networks []v4.Network) v4.ContainerResponse {
@@ -497,11 +516,12 @@ func expectedV4TaskResponse() v4.TaskResponse {
 				CPU:    aws.Float64(cpu),
 				Memory: aws.Int64(memory),
 			},
-			PullStartedAt:      aws.Time(now.UTC()),
-			PullStoppedAt:      aws.Time(now.UTC()),
-			ExecutionStoppedAt: aws.Time(now.UTC()),
-			AvailabilityZone:   availabilityzone,
-			LaunchType:         "EC2",
+			PullStartedAt:             aws.Time(now.UTC()),
+			PullStoppedAt:             aws.Time(now.UTC()),
+			ExecutionStoppedAt:        aws.Time(now.UTC()),
+			AvailabilityZone:          availabilityzone,
+			LaunchType:                "EC2",
+			SupportedMetadataVersions: []string{"v2"},
 		},
 		[]v4.ContainerResponse{expectedV4ContainerResponse},
 		vpcID,
@@ -523,11 +543,12 @@ func expectedV4PulledTaskResponse() v4.TaskResponse {
 				CPU:    aws.Float64(cpu),
 				Memory: aws.Int64(memory),
 			},
-			PullStartedAt:      aws.Time(now.UTC()),
-			PullStoppedAt:      aws.Time(now.UTC()),
-			ExecutionStoppedAt: aws.Time(now.UTC()),
-			AvailabilityZone:   availabilityzone,
-			LaunchType:         "EC2",
+			PullStartedAt:             aws.Time(now.UTC()),
+			PullStoppedAt:             aws.Time(now.UTC()),
+			ExecutionStoppedAt:        aws.Time(now.UTC()),
+			AvailabilityZone:          availabilityzone,
+			LaunchType:                "EC2",
+			SupportedMetadataVersions: []string{"v2"},
 		},
 		[]v4.ContainerResponse{expectedV4ContainerResponse, expectedV4PulledContainerResponse},
 		vpcID,
@@ -790,8 +811,9 @@ func testErrorResponsesFromServer(t *testing.T, path string, expectedErrorMessag
 	credentialsManager := mock_credentials.NewMockManager(ctrl)
 	auditLog := mock_audit.NewMockAuditLogger(ctrl)
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
-	server, err := taskServerSetup(credentialsManager, auditLog, nil, ecsClient, "", nil,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentialsManager, auditLog, nil, ecsClient, "", nil, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 
@@ -827,8 +849,9 @@ func getResponseForCredentialsRequest(t *testing.T, expectedStatus int,
 	credentialsManager := mock_credentials.NewMockManager(ctrl)
 	auditLog := mock_audit.NewMockAuditLogger(ctrl)
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
-	server, err := taskServerSetup(credentialsManager, auditLog, nil, ecsClient, "", nil,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentialsManager, auditLog, nil, ecsClient, "", nil, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 
@@ -886,8 +909,9 @@ func TestV3ContainerAssociations(t *testing.T) {
 		state.EXPECT().ContainerByID(containerID).Return(dockerContainer, true),
 		state.EXPECT().TaskByArn(taskARN).Return(standardTask(), true),
 	)
-	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 	recorder := httptest.NewRecorder()
@@ -918,8 +942,9 @@ func TestV3ContainerAssociation(t *testing.T) {
 		state.EXPECT().TaskARNByV3EndpointID(v3EndpointID).Return(taskARN, true),
 		state.EXPECT().TaskByArn(taskARN).Return(task, true),
 	)
-	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 	recorder := httptest.NewRecorder()
@@ -949,8 +974,9 @@ func TestV4ContainerAssociations(t *testing.T) {
 		state.EXPECT().ContainerByID(containerID).Return(dockerContainer, true),
 		state.EXPECT().TaskByArn(taskARN).Return(task, true),
 	)
-	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 	recorder := httptest.NewRecorder()
@@ -981,8 +1007,9 @@ func TestV4ContainerAssociation(t *testing.T) {
 		state.EXPECT().TaskARNByV3EndpointID(v3EndpointID).Return(taskARN, true),
 		state.EXPECT().TaskByArn(taskARN).Return(task, true),
 	)
-	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 	recorder := httptest.NewRecorder()
@@ -1008,8 +1035,9 @@ func TestTaskHTTPEndpoint301Redirect(t *testing.T) {
 	statsEngine := mock_stats.NewMockEngine(ctrl)
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
-	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 
@@ -1051,8 +1079,9 @@ func TestTaskHTTPEndpointErrorCode404(t *testing.T) {
 	statsEngine := mock_stats.NewMockEngine(ctrl)
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
-	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 
@@ -1091,8 +1120,9 @@ func TestTaskHTTPEndpointErrorCode400(t *testing.T) {
 	statsEngine := mock_stats.NewMockEngine(ctrl)
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
-	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 
@@ -1114,11 +1144,47 @@ func TestTaskHTTPEndpointErrorCode400(t *testing.T) {
 }
 
 func TestTaskHTTPEndpointErrorCode500(t *testing.T) {
+	testPaths := []string{
+		"/v3/wrong-v3-endpoint-id/task",
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	auditLog := mock_audit.NewMockAuditLogger(ctrl)
+	statsEngine := mock_stats.NewMockEngine(ctrl)
+	ecsClient := mock_ecs.NewMockECSClient(ctrl)
+
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
+		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
+	require.NoError(t, err)
+
+	for _, testPath := range testPaths {
+		t.Run(fmt.Sprintf("Test path: %s", testPath), func(t *testing.T) {
+			// Make every possible call to state fail
+			state.EXPECT().TaskARNByV3EndpointID(gomock.Any()).Return("", false).AnyTimes()
+			state.EXPECT().DockerIDByV3EndpointID(gomock.Any()).Return("", false).AnyTimes()
+			state.EXPECT().TaskARNByV3EndpointID(gomock.Any()).Return("", false).AnyTimes()
+			state.EXPECT().GetTaskByIPAddress(gomock.Any()).Return("", false).AnyTimes()
+
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", testPath, nil)
+			req.RemoteAddr = remoteIP + ":" + remotePort
+			server.Handler.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		})
+	}
+}
+
+// Tests that v3 container metadata endpoints return a 404 error when the container cannot be found.
+func TestTaskHTTPEndpointContainerNotFoundErrorCode404(t *testing.T) {
 	testPaths := []string{
 		"/v3/wrong-v3-endpoint-id",
 		"/v3/",
 		"/v3/stats",
-		"/v3/wrong-v3-endpoint-id/task",
 		"/v3/task",
 	}
 
@@ -1130,8 +1196,9 @@ func TestTaskHTTPEndpointErrorCode500(t *testing.T) {
 	statsEngine := mock_stats.NewMockEngine(ctrl)
 	ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
-	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 		containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 	require.NoError(t, err)
 
@@ -1147,7 +1214,7 @@ func TestTaskHTTPEndpointErrorCode500(t *testing.T) {
 			req, _ := http.NewRequest("GET", testPath, nil)
 			req.RemoteAddr = remoteIP + ":" + remotePort
 			server.Handler.ServeHTTP(recorder, req)
-			assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			assert.Equal(t, http.StatusNotFound, recorder.Code)
 		})
 	}
 }
@@ -1200,8 +1267,9 @@ func TestV4TaskNotFoundError404(t *testing.T) {
 			statsEngine := mock_stats.NewMockEngine(ctrl)
 			ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
-			server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-				config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+			server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+				config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+				config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 				containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 			require.NoError(t, err)
 
@@ -1256,8 +1324,9 @@ func TestV4Unexpected500Error(t *testing.T) {
 			statsEngine := mock_stats.NewMockEngine(ctrl)
 			ecsClient := mock_ecs.NewMockECSClient(ctrl)
 
-			server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine,
-				config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, "", vpcID,
+			server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+				config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+				config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, "", vpcID,
 				containerInstanceArn, tp.NewMockTaskProtectionClientFactoryInterface(ctrl))
 			require.NoError(t, err)
 
@@ -1296,6 +1365,8 @@ type TMDSResponse interface {
 		v4.StatsResponse |
 		map[string]*types.StatsJSON |
 		map[string]*v4.StatsResponse |
+		v3.MetadataErrorResponse |
+		v3.HealthResponse |
 		string
 }
 
@@ -1364,8 +1435,9 @@ func testTMDSRequest[R TMDSResponse](t *testing.T, tc TMDSTestCase[R]) {
 
 	// Initialize server
 	server, err := taskServerSetup(credsManager, auditLog, state, ecsClient,
-		clusterName, statsEngine,
-		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate, availabilityzone, vpcID,
+		clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, availabilityzone, vpcID,
 		containerInstanceArn, taskProtectionClientFactory)
 	require.NoError(t, err)
 
@@ -1540,21 +1612,24 @@ func TestV3ContainerMetadata(t *testing.T) {
 	task := standardTask()
 
 	t.Run("v3EndpointID invalid", func(t *testing.T) {
-		testTMDSRequest(t, TMDSTestCase[string]{
+		testTMDSRequest(t, TMDSTestCase[v3.MetadataErrorResponse]{
 			path: v3BasePath + v3EndpointID,
 			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
 				gomock.InOrder(
 					state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return("", false),
 				)
 			},
-			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponseBody: fmt.Sprintf(
-				"V3 container metadata handler: unable to get container ID from request: unable to get docker ID from v3 endpoint ID: %s",
-				v3EndpointID),
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponseBody: v3.MetadataErrorResponse{
+				Code: v3.ErrorCodeContainerNotFound,
+				Message: fmt.Sprintf(
+					"V3 container metadata handler: unable to get container ID from request: unable to get docker ID from v3 endpoint ID: %s",
+					v3EndpointID),
+			},
 		})
 	})
 	t.Run("container not found but ID is valid", func(t *testing.T) {
-		testTMDSRequest(t, TMDSTestCase[string]{
+		testTMDSRequest(t, TMDSTestCase[v3.MetadataErrorResponse]{
 			path: v3BasePath + v3EndpointID,
 			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
 				gomock.InOrder(
@@ -1562,9 +1637,28 @@ func TestV3ContainerMetadata(t *testing.T) {
 					state.EXPECT().ContainerByID(containerID).Return(nil, false),
 				)
 			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponseBody: v3.MetadataErrorResponse{
+				Code:    v3.ErrorCodeContainerNotFound,
+				Message: fmt.Sprintf("V3 container metadata handler: container '%s' not found", containerID),
+			},
+		})
+	})
+	t.Run("task not found for container", func(t *testing.T) {
+		testTMDSRequest(t, TMDSTestCase[v3.MetadataErrorResponse]{
+			path: v3BasePath + v3EndpointID,
+			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
+				gomock.InOrder(
+					state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(containerID, true),
+					state.EXPECT().ContainerByID(containerID).Return(dockerContainer, true),
+					state.EXPECT().TaskByID(containerID).Return(nil, false),
+				)
+			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponseBody: fmt.Sprintf(
-				"Unable to generate metadata for container '%s'", containerID),
+			expectedResponseBody: v3.MetadataErrorResponse{
+				Code:    v3.ErrorCodeInternal,
+				Message: fmt.Sprintf("Unable to generate metadata for container '%s'", containerID),
+			},
 		})
 	})
 	t.Run("happy case", func(t *testing.T) {
@@ -1578,11 +1672,28 @@ func TestV3ContainerMetadata(t *testing.T) {
 				)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: expectedContainerResponse,
+			expectedResponseBody: expectedV3ContainerResponse(expectedContainerResponse),
+		})
+	})
+	t.Run("fields projection", func(t *testing.T) {
+		testTMDSRequest(t, TMDSTestCase[v2.ContainerResponse]{
+			path: v3BasePath + v3EndpointID + "?fields=DockerId,Name,Unknown",
+			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
+				gomock.InOrder(
+					state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(containerID, true),
+					state.EXPECT().ContainerByID(containerID).Return(dockerContainer, true),
+					state.EXPECT().TaskByID(containerID).Return(task, true),
+				)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: v2.ContainerResponse{
+				ID:   expectedContainerResponse.ID,
+				Name: expectedContainerResponse.Name,
+			},
 		})
 	})
 	t.Run("bridge mode container not found when looking up network settings", func(t *testing.T) {
-		testTMDSRequest(t, TMDSTestCase[string]{
+		testTMDSRequest(t, TMDSTestCase[v3.MetadataErrorResponse]{
 			path: v3BasePath + v3EndpointID,
 			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
 				gomock.InOrder(
@@ -1592,12 +1703,15 @@ func TestV3ContainerMetadata(t *testing.T) {
 					state.EXPECT().ContainerByID(containerID).Return(nil, false),
 				)
 			},
-			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: fmt.Sprintf("Unable to find container '%s'", containerID),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponseBody: v3.MetadataErrorResponse{
+				Code:    v3.ErrorCodeInternal,
+				Message: fmt.Sprintf("Unable to find container '%s'", containerID),
+			},
 		})
 	})
 	t.Run("bridge mode container no network settings", func(t *testing.T) {
-		testTMDSRequest(t, TMDSTestCase[string]{
+		testTMDSRequest(t, TMDSTestCase[v3.MetadataErrorResponse]{
 			path: v3BasePath + v3EndpointID,
 			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
 				gomock.InOrder(
@@ -1608,8 +1722,10 @@ func TestV3ContainerMetadata(t *testing.T) {
 				)
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponseBody: fmt.Sprintf(
-				"Unable to generate network response for container '%s'", containerID),
+			expectedResponseBody: v3.MetadataErrorResponse{
+				Code:    v3.ErrorCodeInternal,
+				Message: fmt.Sprintf("Unable to generate network response for container '%s'", containerID),
+			},
 		})
 	})
 	t.Run("happy case bridge mode", func(t *testing.T) {
@@ -1624,11 +1740,108 @@ func TestV3ContainerMetadata(t *testing.T) {
 				)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: expectedBridgeContainerResponse,
+			expectedResponseBody: expectedV3ContainerResponse(expectedBridgeContainerResponse),
 		})
 	})
 }
 
+// Tests that the v3 container metadata endpoint can be served over a Unix domain socket
+func TestV3ContainerMetadataOverUnixSocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	task := standardTask()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	gomock.InOrder(
+		state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(containerID, true),
+		state.EXPECT().ContainerByID(containerID).Return(dockerContainer, true),
+		state.EXPECT().TaskByID(containerID).Return(task, true),
+	)
+
+	router := mux.NewRouter()
+	router.HandleFunc(v3.ContainerMetadataPath,
+		v3.ContainerMetadataHandler(state, config.MetadataFieldStyleDefault))
+
+	// Keep the directory name short, socket paths are limited in length
+	dir, err := os.MkdirTemp("", "tmds")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "tmds.sock")
+
+	listener, err := listenUnixSocket(socketPath)
+	require.NoError(t, err)
+	server := &http.Server{Handler: router}
+	go server.Serve(listener)
+	defer server.Close()
+
+	info, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(tmdsSocketMode), info.Mode().Perm())
+	// the private directory the socket was created in is removed once it's moved into place
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "tmds.sock", entries[0].Name())
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	resp, err := client.Get("http://unix" + v3BasePath + v3EndpointID)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var actualResponseBody v2.ContainerResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&actualResponseBody))
+	assert.Equal(t, expectedV3ContainerResponse(expectedContainerResponse), actualResponseBody)
+}
+
+// Tests that the server set up for the link-local address when the task metadata endpoint is served on a
+// Unix domain socket serves credentials and nothing else
+func TestCredentialsServerSetup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	credentialsManager := mock_credentials.NewMockManager(ctrl)
+	auditLog := mock_audit.NewMockAuditLogger(ctrl)
+	server, err := credentialsServerSetup(credentialsManager, auditLog,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate)
+	require.NoError(t, err)
+
+	creds := taskRoleCredentials()
+	for _, path := range []string{
+		credentials.V1CredentialsPath + "?id=" + credentialsID,
+		credentials.V2CredentialsPath + "/" + credentialsID,
+	} {
+		t.Run(path, func(t *testing.T) {
+			credentialsManager.EXPECT().GetTaskCredentials(credentialsID).Return(creds, true)
+			auditLog.EXPECT().Log(gomock.Any(), gomock.Any(), gomock.Any())
+
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", path, nil)
+			server.Handler.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+
+	for _, path := range []string{
+		v2BaseMetadataPath,
+		v3BasePath + v3EndpointID,
+		v4BasePath + v3EndpointID,
+	} {
+		t.Run(path, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", path, nil)
+			server.Handler.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusNotFound, recorder.Code)
+		})
+	}
+}
+
 func TestV3TaskMetadata(t *testing.T) {
 	task := standardTask()
 
@@ -1691,6 +1904,27 @@ func TestV3TaskMetadata(t *testing.T) {
 			expectedResponseBody: expectedTaskResponse(),
 		})
 	})
+	t.Run("pull timestamps omitted before pull starts", func(t *testing.T) {
+		notPulledTask := standardTask()
+		notPulledTask.PullStartedAtUnsafe = time.Time{}
+		notPulledTask.PullStoppedAtUnsafe = time.Time{}
+		expectedResponse := expectedTaskResponse()
+		expectedResponse.PullStartedAt = nil
+		expectedResponse.PullStoppedAt = nil
+		testTMDSRequest(t, TMDSTestCase[v2.TaskResponse]{
+			path: v3BasePath + v3EndpointID + "/task",
+			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
+				gomock.InOrder(
+					state.EXPECT().TaskARNByV3EndpointID(v3EndpointID).Return(taskARN, true),
+					state.EXPECT().TaskByArn(taskARN).Return(notPulledTask, true),
+					state.EXPECT().ContainerMapByArn(taskARN).Return(containerNameToDockerContainer, true),
+					state.EXPECT().TaskByArn(taskARN).Return(notPulledTask, true),
+				)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: expectedResponse,
+		})
+	})
 	t.Run("bridge mode container not found", func(t *testing.T) {
 		testTMDSRequest(t, TMDSTestCase[string]{
 			path: v3BasePath + v3EndpointID + "/task",
@@ -2744,6 +2978,18 @@ func TestV3ContainerStats(t *testing.T) {
 	})
 }
 
+// Tests for the v3 health endpoint of the metadata server
+func TestV3Health(t *testing.T) {
+	t.Run("healthy without tracked containers", func(t *testing.T) {
+		// No expectations are set on the task engine state, so any lookup of tasks or containers fails the test.
+		testTMDSRequest(t, TMDSTestCase[v3.HealthResponse]{
+			path:                 v3.HealthPath,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: v3.HealthResponse{Status: v3.HealthStatusOK},
+		})
+	})
+}
+
 func TestV3TaskStats(t *testing.T) {
 	path := v3BasePath + v3EndpointID + "/task/stats"
 	t.Run("task not found", func(t *testing.T) {
@@ -3726,7 +3972,8 @@ func testRegisterFaultHandler(t *testing.T, tcs []blackholePortFaultTestCase, me
 			}
 
 			router := mux.NewRouter()
-			registerFaultHandlers(router, agentState, metricsFactory)
+			registerFaultHandlers(router, router, agentState, metricsFactory,
+				handlersutils.NewMetadataAuthenticator(state, false))
 			var requestBody io.Reader
 			if tc.requestBody != "" {
 				reqBodyBytes, err := json.Marshal(tc.requestBody)
@@ -3751,3 +3998,40 @@ func testRegisterFaultHandler(t *testing.T, tcs []blackholePortFaultTestCase, me
 		})
 	}
 }
+
+func TestTaskFaultsStatusServed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	auditLog := mock_audit.NewMockAuditLogger(ctrl)
+	statsEngine := mock_stats.NewMockEngine(ctrl)
+	ecsClient := mock_ecs.NewMockECSClient(ctrl)
+
+	task := standardTask()
+	gomock.InOrder(
+		state.EXPECT().TaskARNByV3EndpointID(v3EndpointID).Return(taskARN, true),
+		state.EXPECT().TaskByArn(taskARN).Return(task, true).AnyTimes(),
+	)
+	state.EXPECT().ContainerMapByArn(taskARN).Return(containerNameToDockerContainer, true).AnyTimes()
+	state.EXPECT().PulledContainerMapByArn(taskARN).Return(nil, true).AnyTimes()
+	state.EXPECT().ContainerByID(gomock.Any()).Return(nil, false).AnyTimes()
+	ecsClient.EXPECT().GetResourceTags(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	server, err := taskServerSetup(credentials.NewManager(), auditLog, state, ecsClient, clusterName, statsEngine, nil,
+		config.DefaultTaskMetadataSteadyStateRate, config.DefaultTaskMetadataBurstRate,
+		config.DefaultTMDSMaxConcurrentRequests, config.MetadataFieldStyleDefault, false, availabilityzone, vpcID,
+		containerInstanceArn, nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/%s/fault/v1/status", v3EndpointID), nil)
+	require.NoError(t, err)
+	req.RemoteAddr = remoteIP + ":" + remotePort
+	server.Handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	var response faulttype.TaskFaultsStatusResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Empty(t, response.ActiveFaults)
+}
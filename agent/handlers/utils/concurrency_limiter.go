@@ -0,0 +1,60 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package utils
+
+import (
+	"net/http"
+	"strconv"
+
+	tmdsutils "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+	"github.com/cihub/seelog"
+)
+
+// concurrencyLimitRetryAfterSeconds is the value of the Retry-After header sent
+// with requests rejected by the ConcurrencyLimiter
+const concurrencyLimitRetryAfterSeconds = 1
+
+// ConcurrencyLimiter bounds the number of requests served concurrently by the
+// handlers it wraps. Requests beyond the bound are rejected with a 503 rather
+// than queued, so that heavy polling can't pile up goroutines reading state.
+type ConcurrencyLimiter struct {
+	semaphore chan struct{}
+}
+
+// NewConcurrencyLimiter returns a ConcurrencyLimiter that allows at most
+// maxConcurrentRequests requests to be served at once
+func NewConcurrencyLimiter(maxConcurrentRequests int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		semaphore: make(chan struct{}, maxConcurrentRequests),
+	}
+}
+
+// Limit wraps the handler so that it's only invoked while a slot is available.
+// The slot is released once the handler returns, whichever path it returns on.
+func (limiter *ConcurrencyLimiter) Limit(
+	handler func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case limiter.semaphore <- struct{}{}:
+		default:
+			seelog.Warnf("Too many concurrent requests, rejecting request for %s", r.URL.Path)
+			w.Header().Set("Retry-After", strconv.Itoa(concurrencyLimitRetryAfterSeconds))
+			tmdsutils.WriteJSONResponse(w, http.StatusServiceUnavailable,
+				"Too many concurrent requests, please retry", tmdsutils.RequestTypeContainerMetadata)
+			return
+		}
+		defer func() { <-limiter.semaphore }()
+		handler(w, r)
+	}
+}
@@ -0,0 +1,92 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimiterRejectsRequestsOverLimit(t *testing.T) {
+	const limit = 2
+	const numRequests = 5
+	limiter := NewConcurrencyLimiter(limit)
+
+	// Block handlers until all requests have been sent so that they overlap
+	started := make(chan struct{}, numRequests)
+	release := make(chan struct{})
+	handler := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	recorders := make([]*httptest.ResponseRecorder, numRequests)
+	var admitted, overLimit sync.WaitGroup
+	send := func(i int, wg *sync.WaitGroup) {
+		recorders[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(recorder *httptest.ResponseRecorder) {
+			defer wg.Done()
+			handler(recorder, httptest.NewRequest("GET", "/v3/container", nil))
+		}(recorders[i])
+	}
+	for i := 0; i < limit; i++ {
+		send(i, &admitted)
+	}
+	for i := 0; i < limit; i++ {
+		<-started
+	}
+	// With every slot held, the remaining requests are rejected without waiting
+	for i := limit; i < numRequests; i++ {
+		send(i, &overLimit)
+	}
+	overLimit.Wait()
+	close(release)
+	admitted.Wait()
+
+	var ok, rejected int
+	for _, recorder := range recorders {
+		switch recorder.Code {
+		case http.StatusOK:
+			ok++
+		case http.StatusServiceUnavailable:
+			rejected++
+			assert.Equal(t, "1", recorder.Header().Get("Retry-After"))
+		}
+	}
+	assert.Equal(t, limit, ok)
+	assert.Equal(t, numRequests-limit, rejected)
+}
+
+func TestConcurrencyLimiterReleasesOnErrorPath(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1)
+	handler := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	// A limit of one would reject the second request if the slot leaked
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		handler(recorder, httptest.NewRequest("GET", "/v3/container", nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	}
+}
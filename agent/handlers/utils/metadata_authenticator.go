@@ -0,0 +1,89 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package utils
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	tmdsutils "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+	"github.com/cihub/seelog"
+)
+
+// MetadataAuthTokenHeader is the request header carrying the metadata endpoint token of a task
+const MetadataAuthTokenHeader = "X-ECS-Metadata-Token"
+
+// MetadataAuthenticator restricts the handlers it wraps to requests carrying the token issued to
+// the task of the container the request is made for, so that containers on a shared host can't
+// read each other's metadata.
+type MetadataAuthenticator struct {
+	state              dockerstate.TaskEngineState
+	enabled            bool
+	endpointIDMuxNames []string
+}
+
+// NewMetadataAuthenticator returns a MetadataAuthenticator that looks up the task of a request by
+// the v3 endpoint ID found under one of endpointIDMuxNames. Handlers are left as they are unless
+// it's enabled.
+func NewMetadataAuthenticator(state dockerstate.TaskEngineState, enabled bool,
+	endpointIDMuxNames ...string) *MetadataAuthenticator {
+	return &MetadataAuthenticator{
+		state:              state,
+		enabled:            enabled,
+		endpointIDMuxNames: endpointIDMuxNames,
+	}
+}
+
+// Authenticate wraps the handler so that it's only invoked for requests carrying the token of the
+// task the requested endpoint ID belongs to. Other requests are rejected with a 401.
+func (authenticator *MetadataAuthenticator) Authenticate(
+	handler func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+	if !authenticator.enabled {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authenticator.authorized(r) {
+			seelog.Warnf("Rejecting unauthorized task metadata request for %s", r.URL.Path)
+			tmdsutils.WriteJSONResponse(w, http.StatusUnauthorized,
+				"Missing or invalid metadata token", tmdsutils.RequestTypeTaskMetadata)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (authenticator *MetadataAuthenticator) authorized(r *http.Request) bool {
+	token := r.Header.Get(MetadataAuthTokenHeader)
+	if token == "" {
+		return false
+	}
+	for _, muxName := range authenticator.endpointIDMuxNames {
+		endpointID, ok := tmdsutils.GetMuxValueFromRequest(r, muxName)
+		if !ok {
+			continue
+		}
+		taskARN, ok := authenticator.state.TaskARNByV3EndpointID(endpointID)
+		if !ok {
+			return false
+		}
+		task, ok := authenticator.state.TaskByArn(taskARN)
+		if !ok {
+			return false
+		}
+		expected := task.GetMetadataAuthToken()
+		return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+	}
+	return false
+}
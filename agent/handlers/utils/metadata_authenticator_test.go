@@ -0,0 +1,128 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testEndpointIDMuxName = "endpointIDMuxName"
+	testEndpointID        = "endpoint-id"
+	testTaskARN           = "arn:aws:ecs:us-west-2:123456789012:task/cluster/task-id"
+	testToken             = "task-token"
+)
+
+func serveAuthenticatedRequest(authenticator *MetadataAuthenticator, token string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/v3/{"+testEndpointIDMuxName+"}",
+		authenticator.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	req := httptest.NewRequest("GET", "/v3/"+testEndpointID, nil)
+	if token != "" {
+		req.Header.Set(MetadataAuthTokenHeader, token)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestMetadataAuthenticator(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		token                string
+		setStateExpectations func(state *mock_dockerstate.MockTaskEngineState)
+		expectedStatusCode   int
+	}{
+		{
+			name:  "authorized",
+			token: testToken,
+			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
+				state.EXPECT().TaskARNByV3EndpointID(testEndpointID).Return(testTaskARN, true)
+				state.EXPECT().TaskByArn(testTaskARN).Return(
+					&apitask.Task{Arn: testTaskARN, MetadataAuthTokenUnsafe: testToken}, true)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "missing token",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:  "token of another task",
+			token: "other-task-token",
+			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
+				state.EXPECT().TaskARNByV3EndpointID(testEndpointID).Return(testTaskARN, true)
+				state.EXPECT().TaskByArn(testTaskARN).Return(
+					&apitask.Task{Arn: testTaskARN, MetadataAuthTokenUnsafe: testToken}, true)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:  "task without a token",
+			token: testToken,
+			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
+				state.EXPECT().TaskARNByV3EndpointID(testEndpointID).Return(testTaskARN, true)
+				state.EXPECT().TaskByArn(testTaskARN).Return(&apitask.Task{Arn: testTaskARN}, true)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:  "unknown endpoint id",
+			token: testToken,
+			setStateExpectations: func(state *mock_dockerstate.MockTaskEngineState) {
+				state.EXPECT().TaskARNByV3EndpointID(testEndpointID).Return("", false)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+			if tc.setStateExpectations != nil {
+				tc.setStateExpectations(state)
+			}
+
+			authenticator := NewMetadataAuthenticator(state, true, testEndpointIDMuxName)
+			recorder := serveAuthenticatedRequest(authenticator, tc.token)
+			assert.Equal(t, tc.expectedStatusCode, recorder.Code)
+		})
+	}
+}
+
+func TestMetadataAuthenticatorDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+
+	// requests aren't checked against the state when authentication is disabled
+	authenticator := NewMetadataAuthenticator(state, false, testEndpointIDMuxName)
+	recorder := serveAuthenticatedRequest(authenticator, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -18,6 +18,7 @@ import (
 	"net/http"
 
 	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
 	agentversion "github.com/aws/amazon-ecs-agent/agent/version"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
 )
@@ -25,14 +26,28 @@ import (
 // AgentMetadataPath is the Agent metadata path for v1 handler.
 const AgentMetadataPath = "/v1/metadata"
 
-// AgentMetadataHandler creates response for 'v1/metadata' API.
-func AgentMetadataHandler(containerInstanceArn *string, cfg *config.Config) func(http.ResponseWriter, *http.Request) {
+// DockerConnectionStatusProvider provides the health of the agent's connection to the docker daemon.
+type DockerConnectionStatusProvider interface {
+	ConnectionStatus() dockerapi.DockerConnectionStatus
+}
+
+// AgentMetadataHandler creates response for 'v1/metadata' API. The docker connection
+// health is only reported if dockerConnection is not nil.
+func AgentMetadataHandler(containerInstanceArn *string, cfg *config.Config,
+	dockerConnection DockerConnectionStatusProvider) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		resp := &MetadataResponse{
 			Cluster:              cfg.Cluster,
 			ContainerInstanceArn: containerInstanceArn,
 			Version:              agentversion.String(),
 		}
+		if dockerConnection != nil {
+			status := dockerConnection.ConnectionStatus()
+			resp.DockerConnection = &DockerConnectionResponse{
+				State:     status.State,
+				LastError: status.LastError,
+			}
+		}
 		responseJSON, err := json.Marshal(resp)
 		if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
 			return
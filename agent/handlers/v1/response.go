@@ -26,9 +26,17 @@ import (
 
 // MetadataResponse is the schema for the metadata response JSON object
 type MetadataResponse struct {
-	Cluster              string  `json:"Cluster"`
-	ContainerInstanceArn *string `json:"ContainerInstanceArn"`
-	Version              string  `json:"Version"`
+	Cluster              string                    `json:"Cluster"`
+	ContainerInstanceArn *string                   `json:"ContainerInstanceArn"`
+	Version              string                    `json:"Version"`
+	DockerConnection     *DockerConnectionResponse `json:"DockerConnection,omitempty"`
+}
+
+// DockerConnectionResponse is the schema for the health of the agent's connection
+// to the docker daemon
+type DockerConnectionResponse struct {
+	State     string `json:"State"`
+	LastError string `json:"LastError,omitempty"`
 }
 
 // TaskResponse is the schema for the task response JSON object
@@ -146,6 +154,7 @@ func NewPortBindingsResponse(dockerContainer *apicontainer.DockerContainer, eni
 		port := tmdsresponse.PortResponse{
 			ContainerPort: binding.ContainerPort,
 			Protocol:      binding.Protocol.String(),
+			Name:          binding.Name,
 		}
 
 		if eni == nil {
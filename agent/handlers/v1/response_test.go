@@ -273,6 +273,29 @@ func TestPortBindingsResponse(t *testing.T) {
 	assert.Equal(t, expectedPortResponse, PortBindingsResponse[0])
 }
 
+func TestPortBindingsResponseNamedPort(t *testing.T) {
+	container := &apicontainer.Container{
+		Name: containerName,
+		Ports: []apicontainer.PortBinding{
+			{
+				ContainerPort: 80,
+				HostPort:      80,
+				Protocol:      apicontainer.TransportProtocolTCP,
+				Name:          "http",
+			},
+		},
+	}
+
+	dockerContainer := &apicontainer.DockerContainer{
+		Container: container,
+	}
+
+	PortBindingsResponse := NewPortBindingsResponse(dockerContainer, nil)
+	expected := expectedPortResponse
+	expected.Name = "http"
+	assert.Equal(t, expected, PortBindingsResponse[0])
+}
+
 func TestVolumesResponse(t *testing.T) {
 	container := &apicontainer.Container{
 		Name: containerName,
@@ -17,8 +17,11 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	v1 "github.com/aws/amazon-ecs-agent/agent/handlers/v1"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource/firelens"
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs"
 	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
@@ -34,6 +37,13 @@ import (
 // are passed to Docker as two CPU shares
 const minimumCPUUnit = 2
 
+// Task metadata endpoint versions listed in the SupportedMetadataVersions of a task response
+const (
+	metadataVersionV2 = "v2"
+	metadataVersionV3 = "v3"
+	metadataVersionV4 = "v4"
+)
+
 // NewTaskResponse creates a new response object for the task
 func NewTaskResponse(
 	taskARN string,
@@ -62,6 +72,9 @@ func NewTaskResponse(
 	if includeV4Metadata {
 		resp.LaunchType = task.LaunchType
 	}
+	if task.HealthStatusShouldBeReported() {
+		resp.HealthStatus = task.GetHealthStatus().String()
+	}
 
 	taskCPU := task.CPU
 	taskMemory := task.Memory
@@ -85,6 +98,7 @@ func NewTaskResponse(
 	if timestamp := task.GetExecutionStoppedAt(); !timestamp.IsZero() {
 		resp.ExecutionStoppedAt = aws.Time(timestamp.UTC())
 	}
+	resp.SupportedMetadataVersions = supportedMetadataVersions(task)
 	containerNameToDockerContainer, ok := state.ContainerMapByArn(task.Arn)
 	if !ok {
 		seelog.Warnf("V2 task response: unable to get container name mapping for task '%s'",
@@ -104,6 +118,23 @@ func NewTaskResponse(
 	return resp, nil
 }
 
+// supportedMetadataVersions returns the metadata endpoint versions the containers of the task can use.
+// The v2 endpoint looks up tasks by their ENI's IP address, so it's only available to awsvpc tasks, while
+// the v3 and v4 endpoints are available once the containers have been assigned their v3 endpoint ID.
+func supportedMetadataVersions(task *apitask.Task) []string {
+	var versions []string
+	if task.IsNetworkModeAWSVPC() {
+		versions = append(versions, metadataVersionV2)
+	}
+	for _, container := range task.Containers {
+		if container.GetV3EndpointID() != "" {
+			versions = append(versions, metadataVersionV3, metadataVersionV4)
+			break
+		}
+	}
+	return versions
+}
+
 // propagateTagsToMetadata retrieves container instance and task tags from ECS
 func propagateTagsToMetadata(ecsClient ecs.ECSClient, containerInstanceARN, taskARN string, resp *tmdsv2.TaskResponse, includeV4Metadata bool) {
 	containerInstanceTags, err := ecsClient.GetResourceTags(containerInstanceARN)
@@ -188,6 +219,13 @@ func NewContainerResponse(
 		resp.ContainerARN = container.ContainerArn
 	}
 
+	if firelensConfig := container.GetFirelensConfig(); firelensConfig != nil {
+		resp.FirelensConfigType = firelensConfig.Options[firelens.ExternalConfigTypeOption]
+	}
+	if container.GetLogDriver() == string(dockerclient.AWSFirelensDriver) {
+		resp.LogDestinations = firelens.OutputNames(container.GetLogOptions())
+	}
+
 	// Write the container health status inside the container
 	if dockerContainer.Container.HealthStatusShouldBeReported() {
 		health := dockerContainer.Container.GetHealthStatus()
@@ -206,11 +244,42 @@ func NewContainerResponse(
 		finishedAt = finishedAt.UTC()
 		resp.FinishedAt = &finishedAt
 	}
+	if pullDuration, ok := container.GetImagePullDuration(); ok {
+		resp.ImagePullDurationMs = pullDuration.Milliseconds()
+	}
+	resp.EnvironmentSources = container.GetEnvironmentSources()
+	resp.LogDeliveryStatus = container.GetLogDeliveryStatus()
+	resp.SELinuxLabel = container.GetSELinuxLabel()
+	resp.AppArmorProfile = container.GetAppArmorProfile()
+	resp.CPU, resp.Memory, resp.MemoryReservation = container.GetHostConfigResources()
+	resp.RestartPolicyState = container.GetRestartPolicyState()
+	if cacheStats, ok := container.GetLayerCacheStats(); ok {
+		resp.LayerCacheStats = &tmdsv2.LayerCacheStats{
+			HitLayers:  cacheStats.HitLayers,
+			MissLayers: cacheStats.MissLayers,
+		}
+	}
+	resp.ImageCompression = container.GetImageCompression()
+	for _, volume := range container.VolumesFrom {
+		volumeFrom := volume.SourceContainer
+		if volume.ReadOnly {
+			volumeFrom += ":ro"
+		}
+		resp.VolumesFrom = append(resp.VolumesFrom, volumeFrom)
+	}
+	for _, event := range container.GetResizeHistory() {
+		resp.ResizeHistory = append(resp.ResizeHistory, tmdsv2.ResizeEvent{
+			Timestamp: event.Timestamp.UTC(),
+			CPU:       event.CPU,
+			Memory:    event.Memory,
+		})
+	}
 
 	for _, binding := range container.GetKnownPortBindings() {
 		port := tmdsresponse.PortResponse{
 			ContainerPort: binding.ContainerPort,
 			Protocol:      binding.Protocol.String(),
+			Name:          binding.Name,
 		}
 		if eni == nil {
 			port.HostPort = binding.HostPort
@@ -246,10 +315,11 @@ func dockerContainerHealthToV2Health(health apicontainer.HealthStatus) *tmdsv2.H
 		status = ""
 	}
 	return &tmdsv2.HealthStatus{
-		Status:   status,
-		Since:    health.Since,
-		ExitCode: health.ExitCode,
-		Output:   health.Output,
+		Status:                   status,
+		Since:                    health.Since,
+		ExitCode:                 health.ExitCode,
+		Output:                   health.Output,
+		HealthCheckFailingStreak: health.FailingStreak,
 	}
 }
 
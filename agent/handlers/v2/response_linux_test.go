@@ -0,0 +1,69 @@
+//go:build linux && unit
+// +build linux,unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v2
+
+import (
+	"encoding/json"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerResponseLogDestinations(t *testing.T) {
+	container := &apicontainer.Container{
+		Name: containerName,
+		DockerConfig: apicontainer.DockerConfig{
+			HostConfig: aws.String(`{"LogConfig":{"Type":"awsfirelens","Config":{` +
+				`"Name":"cloudwatch_logs","log_group_name":"my-group",` +
+				`"output.archive.Name":"s3","output.archive.bucket":"my-bucket"}}}`),
+		},
+	}
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  container,
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Equal(t, []string{"cloudwatch_logs", "s3"}, containerResponse.LogDestinations)
+
+	// Only the output plugin names are reported, never their options
+	containerResponseJSON, err := json.Marshal(containerResponse)
+	require.NoError(t, err)
+	assert.NotContains(t, string(containerResponseJSON), "my-group")
+	assert.NotContains(t, string(containerResponseJSON), "my-bucket")
+}
+
+func TestContainerResponseLogDestinationsNonFirelens(t *testing.T) {
+	container := &apicontainer.Container{
+		Name: containerName,
+		DockerConfig: apicontainer.DockerConfig{
+			HostConfig: aws.String(`{"LogConfig":{"Type":"awslogs","Config":{"awslogs-group":"myLogGroup"}}}`),
+		},
+	}
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  container,
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Empty(t, containerResponse.LogDestinations)
+}
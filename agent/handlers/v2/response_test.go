@@ -25,6 +25,7 @@ import (
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
 	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/api/container/restart"
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
 	mock_ecs "github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs/mocks"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/api/ecs/model/ecs"
@@ -333,6 +334,257 @@ func TestContainerResponse(t *testing.T) {
 	}
 }
 
+func TestContainerResponseFirelensConfigType(t *testing.T) {
+	testCases := []struct {
+		name                       string
+		firelensConfig             *apicontainer.FirelensConfig
+		expectedFirelensConfigType string
+	}{
+		{
+			name: "file config",
+			firelensConfig: &apicontainer.FirelensConfig{
+				Type: "fluentbit",
+				Options: map[string]string{
+					"config-file-type":  "file",
+					"config-file-value": "/tmp/dummy.conf",
+				},
+			},
+			expectedFirelensConfigType: "file",
+		},
+		{
+			name: "s3 config",
+			firelensConfig: &apicontainer.FirelensConfig{
+				Type: "fluentd",
+				Options: map[string]string{
+					"config-file-type":  "s3",
+					"config-file-value": "arn:aws:s3:::bucket/fluent.conf",
+				},
+			},
+			expectedFirelensConfigType: "s3",
+		},
+		{
+			name: "no external config",
+			firelensConfig: &apicontainer.FirelensConfig{
+				Type: "fluentbit",
+			},
+		},
+		{
+			name: "not a firelens container",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dockerContainer := &apicontainer.DockerContainer{
+				DockerID:   containerID,
+				DockerName: containerName,
+				Container: &apicontainer.Container{
+					Name:           containerName,
+					FirelensConfig: tc.firelensConfig,
+				},
+			}
+
+			containerResponse := NewContainerResponse(dockerContainer, nil, false)
+			assert.Equal(t, tc.expectedFirelensConfigType, containerResponse.FirelensConfigType)
+
+			containerResponseJSON, err := json.Marshal(containerResponse)
+			assert.NoError(t, err)
+			containerResponseMap := make(map[string]interface{})
+			json.Unmarshal(containerResponseJSON, &containerResponseMap)
+			if tc.expectedFirelensConfigType == "" {
+				assert.NotContains(t, containerResponseMap, "FirelensConfigType")
+			} else {
+				assert.Equal(t, tc.expectedFirelensConfigType, containerResponseMap["FirelensConfigType"])
+			}
+		})
+	}
+}
+
+func TestContainerResponseNamedPorts(t *testing.T) {
+	container := &apicontainer.Container{
+		Name: containerName,
+		Ports: []apicontainer.PortBinding{
+			{ContainerPort: 80, Protocol: apicontainer.TransportProtocolTCP, Name: "http"},
+		},
+	}
+	container.SetKnownPortBindings([]apicontainer.PortBinding{
+		{ContainerPort: 80, HostPort: 32768, Protocol: apicontainer.TransportProtocolTCP},
+		{ContainerPort: 9090, HostPort: 32769, Protocol: apicontainer.TransportProtocolTCP},
+	})
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  container,
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	require.Len(t, containerResponse.Ports, 2)
+	assert.Equal(t, "http", containerResponse.Ports[0].Name)
+	assert.Empty(t, containerResponse.Ports[1].Name)
+}
+
+func TestContainerResponseImagePullDuration(t *testing.T) {
+	pullStartedAt := time.Now()
+	testCases := []struct {
+		name             string
+		pullStartedAt    time.Time
+		pullStoppedAt    time.Time
+		expectedDuration int64
+	}{
+		{
+			name:             "pull recorded",
+			pullStartedAt:    pullStartedAt,
+			pullStoppedAt:    pullStartedAt.Add(1500 * time.Millisecond),
+			expectedDuration: 1500,
+		},
+		{
+			name:          "pull in progress",
+			pullStartedAt: pullStartedAt,
+		},
+		{
+			name: "pull not started",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			container := &apicontainer.Container{Name: containerName}
+			container.SetPullStartedAt(tc.pullStartedAt)
+			container.SetPullStoppedAt(tc.pullStoppedAt)
+			dockerContainer := &apicontainer.DockerContainer{
+				DockerID:   containerID,
+				DockerName: containerName,
+				Container:  container,
+			}
+
+			containerResponse := NewContainerResponse(dockerContainer, nil, false)
+			assert.Equal(t, tc.expectedDuration, containerResponse.ImagePullDurationMs)
+
+			containerResponseJSON, err := json.Marshal(containerResponse)
+			require.NoError(t, err)
+			containerResponseMap := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+			if tc.expectedDuration == 0 {
+				assert.NotContains(t, containerResponseMap, "ImagePullDurationMs")
+			} else {
+				assert.Equal(t, float64(tc.expectedDuration), containerResponseMap["ImagePullDurationMs"])
+			}
+		})
+	}
+}
+
+func TestContainerResponseEnvironmentSources(t *testing.T) {
+	container := &apicontainer.Container{
+		Name:        containerName,
+		Environment: map[string]string{"TASK_DEF": "taskdef-value"},
+	}
+	container.MergeEnvironmentVariablesFromSecrets(map[string]string{"SECRET": "secret-value"})
+	require.NoError(t, container.MergeEnvironmentVariablesFromEnvfiles([]map[string]string{
+		{"ENVFILE": "envfile-value", "SECRET": "envfile-value"},
+	}))
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  container,
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Equal(t, map[string]string{
+		"TASK_DEF": apicontainer.EnvironmentSourceTaskDef,
+		"SECRET":   apicontainer.EnvironmentSourceSecret,
+		"ENVFILE":  apicontainer.EnvironmentSourceEnvfile,
+	}, containerResponse.EnvironmentSources)
+
+	containerResponseJSON, err := json.Marshal(containerResponse)
+	require.NoError(t, err)
+	assert.NotContains(t, string(containerResponseJSON), "-value")
+}
+
+func TestTaskResponseHealthStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	ecsClient := mock_ecs.NewMockECSClient(ctrl)
+	unhealthyContainer := &apicontainer.Container{
+		Name:              containerName,
+		Essential:         true,
+		KnownStatusUnsafe: apicontainerstatus.ContainerRunning,
+		HealthCheckType:   apicontainer.DockerHealthCheckType,
+	}
+	unhealthyContainer.SetHealthStatus(apicontainer.HealthStatus{Status: apicontainerstatus.ContainerUnhealthy})
+	healthyContainer := &apicontainer.Container{
+		Name:              "healthy",
+		Essential:         true,
+		KnownStatusUnsafe: apicontainerstatus.ContainerRunning,
+		HealthCheckType:   apicontainer.DockerHealthCheckType,
+	}
+	healthyContainer.SetHealthStatus(apicontainer.HealthStatus{Status: apicontainerstatus.ContainerHealthy})
+	task := &apitask.Task{
+		Arn:               taskARN,
+		KnownStatusUnsafe: apitaskstatus.TaskRunning,
+		Containers:        []*apicontainer.Container{unhealthyContainer, healthyContainer},
+	}
+	containerNameToDockerContainer := map[string]*apicontainer.DockerContainer{
+		taskARN: {
+			DockerID:   containerID,
+			DockerName: containerName,
+			Container:  unhealthyContainer,
+		},
+	}
+	state.EXPECT().TaskByArn(taskARN).Return(task, true).Times(2)
+	state.EXPECT().ContainerMapByArn(taskARN).Return(containerNameToDockerContainer, true).Times(2)
+
+	taskResponse, err := NewTaskResponse(taskARN, state, ecsClient, cluster, availabilityZone, containerInstanceArn, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, "UNHEALTHY", taskResponse.HealthStatus)
+
+	// health status should not be populated when no essential container has a health check
+	unhealthyContainer.HealthCheckType = ""
+	healthyContainer.HealthCheckType = ""
+	taskResponse, err = NewTaskResponse(taskARN, state, ecsClient, cluster, availabilityZone, containerInstanceArn, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, "", taskResponse.HealthStatus)
+}
+
+func TestTaskResponseSupportedMetadataVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	ecsClient := mock_ecs.NewMockECSClient(ctrl)
+	container := &apicontainer.Container{
+		Name:              containerName,
+		KnownStatusUnsafe: apicontainerstatus.ContainerRunning,
+		V3EndpointID:      "v3-endpoint-id",
+	}
+	task := &apitask.Task{
+		Arn:               taskARN,
+		KnownStatusUnsafe: apitaskstatus.TaskRunning,
+		NetworkMode:       apitask.AWSVPCNetworkMode,
+		Containers:        []*apicontainer.Container{container},
+	}
+	containerNameToDockerContainer := map[string]*apicontainer.DockerContainer{
+		taskARN: {
+			DockerID:   containerID,
+			DockerName: containerName,
+			Container:  container,
+		},
+	}
+	state.EXPECT().TaskByArn(taskARN).Return(task, true).Times(2)
+	state.EXPECT().ContainerMapByArn(taskARN).Return(containerNameToDockerContainer, true).Times(2)
+
+	taskResponse, err := NewTaskResponse(taskARN, state, ecsClient, cluster, availabilityZone, containerInstanceArn, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v2", "v3", "v4"}, taskResponse.SupportedMetadataVersions)
+
+	// the v2 endpoint is only served to awsvpc tasks
+	task.NetworkMode = apitask.BridgeNetworkMode
+	taskResponse, err = NewTaskResponse(taskARN, state, ecsClient, cluster, availabilityZone, containerInstanceArn, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v3", "v4"}, taskResponse.SupportedMetadataVersions)
+}
+
 func TestTaskResponseMarshal(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -368,7 +620,8 @@ func TestTaskResponseMarshal(t *testing.T) {
 					"CPU":    float64(2),
 					"Memory": float64(0),
 				},
-				"Type": "NORMAL",
+				"Type":               "NORMAL",
+				"RestartPolicyState": "disabled",
 				"Networks": []interface{}{
 					map[string]interface{}{
 						"IPv4Addresses": []interface{}{
@@ -524,8 +777,9 @@ func TestContainerResponseMarshal(t *testing.T) {
 			"CPU":    float64(cpu),
 			"Memory": float64(memory),
 		},
-		"CreatedAt": timeRFC3339.Format(time.RFC3339),
-		"Type":      "NORMAL",
+		"CreatedAt":          timeRFC3339.Format(time.RFC3339),
+		"Type":               "NORMAL",
+		"RestartPolicyState": "disabled",
 		"Networks": []interface{}{
 			map[string]interface{}{
 				"NetworkMode": "awsvpc",
@@ -757,3 +1011,277 @@ func TestDockerContainerHealthToV2HealthJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerResponseLogDeliveryStatus(t *testing.T) {
+	container := &apicontainer.Container{Name: containerName}
+	container.SetLogDeliveryStatus(apicontainer.LogDeliveryStatusThrottled)
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  container,
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Equal(t, apicontainer.LogDeliveryStatusThrottled, containerResponse.LogDeliveryStatus)
+
+	containerResponseJSON, err := json.Marshal(containerResponse)
+	require.NoError(t, err)
+	containerResponseMap := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+	assert.Equal(t, apicontainer.LogDeliveryStatusThrottled, containerResponseMap["LogDeliveryStatus"])
+}
+
+func TestContainerResponseSELinuxLabel(t *testing.T) {
+	hostConfig := `{"SecurityOpt":["label=type:svirt_apache_t"]}`
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container: &apicontainer.Container{
+			Name:         containerName,
+			DockerConfig: apicontainer.DockerConfig{HostConfig: &hostConfig},
+		},
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Equal(t, "type:svirt_apache_t", containerResponse.SELinuxLabel)
+
+	// the label is omitted for containers without SELinux security options
+	containerResponseJSON, err := json.Marshal(NewContainerResponse(&apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  &apicontainer.Container{Name: containerName},
+	}, nil, false))
+	require.NoError(t, err)
+	containerResponseMap := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+	assert.NotContains(t, containerResponseMap, "SELinuxLabel")
+}
+
+func TestContainerResponseHostConfigResources(t *testing.T) {
+	hostConfig := `{"CpuShares":512,"Memory":1073741824,"MemoryReservation":536870912}`
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container: &apicontainer.Container{
+			Name:         containerName,
+			DockerConfig: apicontainer.DockerConfig{HostConfig: &hostConfig},
+		},
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Equal(t, int64(512), containerResponse.CPU)
+	assert.Equal(t, int64(1073741824), containerResponse.Memory)
+	assert.Equal(t, int64(536870912), containerResponse.MemoryReservation)
+
+	// the resources are omitted for containers without them in their host config
+	containerResponseJSON, err := json.Marshal(NewContainerResponse(&apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  &apicontainer.Container{Name: containerName},
+	}, nil, false))
+	require.NoError(t, err)
+	containerResponseMap := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+	assert.NotContains(t, containerResponseMap, "CPU")
+	assert.NotContains(t, containerResponseMap, "Memory")
+	assert.NotContains(t, containerResponseMap, "MemoryReservation")
+}
+
+func TestContainerResponseLayerCacheStats(t *testing.T) {
+	container := &apicontainer.Container{Name: containerName}
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  container,
+	}
+
+	// the stats are omitted until the agent pulled the container's image
+	containerResponseJSON, err := json.Marshal(NewContainerResponse(dockerContainer, nil, false))
+	require.NoError(t, err)
+	containerResponseMap := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+	assert.NotContains(t, containerResponseMap, "LayerCacheStats")
+
+	// a partially cached pull
+	container.SetLayerCacheStats(apicontainer.LayerCacheStats{HitLayers: 3, MissLayers: 2})
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Equal(t, &tmdsv2.LayerCacheStats{HitLayers: 3, MissLayers: 2}, containerResponse.LayerCacheStats)
+}
+
+func TestContainerResponseImageCompression(t *testing.T) {
+	container := &apicontainer.Container{Name: containerName}
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  container,
+	}
+
+	// the compression is omitted while it's unknown
+	containerResponseJSON, err := json.Marshal(NewContainerResponse(dockerContainer, nil, false))
+	require.NoError(t, err)
+	containerResponseMap := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+	assert.NotContains(t, containerResponseMap, "ImageCompression")
+
+	container.SetImageCompression("gzip")
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Equal(t, "gzip", containerResponse.ImageCompression)
+}
+
+func TestContainerResponseVolumesFrom(t *testing.T) {
+	container := &apicontainer.Container{Name: containerName}
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  container,
+	}
+
+	// the field is omitted when the container doesn't mount volumes from other containers
+	containerResponseJSON, err := json.Marshal(NewContainerResponse(dockerContainer, nil, false))
+	require.NoError(t, err)
+	containerResponseMap := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+	assert.NotContains(t, containerResponseMap, "VolumesFrom")
+
+	container.VolumesFrom = []apicontainer.VolumeFrom{
+		{SourceContainer: "data", ReadOnly: true},
+		{SourceContainer: "scratch"},
+	}
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Equal(t, []string{"data:ro", "scratch"}, containerResponse.VolumesFrom)
+}
+
+func TestContainerResponseRestartPolicyStateExhausted(t *testing.T) {
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container: &apicontainer.Container{
+			Name:              containerName,
+			KnownStatusUnsafe: apicontainerstatus.ContainerStopped,
+			RestartPolicy: &restart.RestartPolicy{
+				Enabled:              true,
+				IgnoredExitCodes:     []int{0},
+				RestartAttemptPeriod: 60,
+			},
+			RestartTracker: restart.NewRestartTracker(restart.RestartPolicy{
+				Enabled:              true,
+				IgnoredExitCodes:     []int{0},
+				RestartAttemptPeriod: 60,
+			}),
+		},
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Equal(t, apicontainer.RestartPolicyStateExhausted, containerResponse.RestartPolicyState)
+}
+
+func TestContainerResponseAppArmorProfile(t *testing.T) {
+	testCases := []struct {
+		name            string
+		securityOpt     string
+		expectedProfile string
+	}{
+		{
+			name:            "named profile",
+			securityOpt:     "apparmor=ecs-custom",
+			expectedProfile: "ecs-custom",
+		},
+		{
+			name:        "unconfined",
+			securityOpt: "apparmor=unconfined",
+		},
+		{
+			name:        "docker default",
+			securityOpt: "apparmor=docker-default",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hostConfig := `{"SecurityOpt":["` + tc.securityOpt + `"]}`
+			dockerContainer := &apicontainer.DockerContainer{
+				DockerID:   containerID,
+				DockerName: containerName,
+				Container: &apicontainer.Container{
+					Name:         containerName,
+					DockerConfig: apicontainer.DockerConfig{HostConfig: &hostConfig},
+				},
+			}
+
+			containerResponseJSON, err := json.Marshal(NewContainerResponse(dockerContainer, nil, false))
+			require.NoError(t, err)
+			containerResponseMap := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+			if tc.expectedProfile == "" {
+				assert.NotContains(t, containerResponseMap, "AppArmorProfile")
+			} else {
+				assert.Equal(t, tc.expectedProfile, containerResponseMap["AppArmorProfile"])
+			}
+		})
+	}
+}
+
+func TestContainerResponseHealthCheckFailingStreak(t *testing.T) {
+	container := &apicontainer.Container{
+		Name:            containerName,
+		HealthCheckType: apicontainer.DockerHealthCheckType,
+	}
+	// The container's last two health checks failed, but it hasn't used up its retries yet
+	container.SetHealthStatus(apicontainer.HealthStatus{
+		Status:        apicontainerstatus.ContainerHealthy,
+		ExitCode:      1,
+		FailingStreak: 2,
+	})
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container:  container,
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	require.NotNil(t, containerResponse.Health)
+	assert.Equal(t, "HEALTHY", containerResponse.Health.Status)
+	assert.Equal(t, 2, containerResponse.Health.HealthCheckFailingStreak)
+
+	containerResponseJSON, err := json.Marshal(containerResponse)
+	require.NoError(t, err)
+	containerResponseMap := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+	health, ok := containerResponseMap["Health"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, float64(2), health["healthCheckFailingStreak"])
+}
+
+func TestContainerResponseResizeHistory(t *testing.T) {
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   containerID,
+		DockerName: containerName,
+		Container: &apicontainer.Container{
+			Name:   containerName,
+			CPU:    256,
+			Memory: 512,
+		},
+	}
+
+	containerResponse := NewContainerResponse(dockerContainer, nil, false)
+	assert.Empty(t, containerResponse.ResizeHistory, "a container never resized should have no resize history")
+
+	dockerContainer.Container.SetResources(1024, 0)
+	containerResponse = NewContainerResponse(dockerContainer, nil, false)
+	require.Len(t, containerResponse.ResizeHistory, 1)
+	event := containerResponse.ResizeHistory[0]
+	assert.Equal(t, uint(1024), event.CPU)
+	assert.Equal(t, uint(512), event.Memory)
+	assert.False(t, event.Timestamp.IsZero())
+	assert.Equal(t, time.UTC, event.Timestamp.Location())
+
+	containerResponseJSON, err := json.Marshal(containerResponse)
+	require.NoError(t, err)
+	containerResponseMap := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(containerResponseJSON, &containerResponseMap))
+	history := containerResponseMap["ResizeHistory"].([]interface{})
+	require.Len(t, history, 1)
+	assert.Contains(t, history[0], "Timestamp")
+	assert.Equal(t, float64(1024), history[0].(map[string]interface{})["CPU"])
+	assert.Equal(t, float64(512), history[0].(map[string]interface{})["Memory"])
+}
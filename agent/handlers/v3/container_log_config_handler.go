@@ -0,0 +1,95 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+	"github.com/cihub/seelog"
+)
+
+// redactedLogOptionValue replaces the value of sensitive log options in the response.
+const redactedLogOptionValue = "[redacted]"
+
+// ContainerLogConfigPath specifies the relative URI path for serving the container's log configuration.
+var ContainerLogConfigPath = "/v3/" + utils.ConstructMuxVar(V3EndpointIDMuxName, utils.AnythingButSlashRegEx) + "/log-configuration"
+
+// sensitiveLogOptions are log driver options whose values are credentials and must never be served.
+var sensitiveLogOptions = map[string]struct{}{
+	"splunk-token": {},
+}
+
+// ContainerLogConfigResponse is the response of the container log configuration endpoint.
+type ContainerLogConfigResponse struct {
+	Driver  string            `json:"driver"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// ContainerLogConfigHandler returns the handler method for handling container log configuration requests.
+func ContainerLogConfigHandler(state dockerstate.TaskEngineState) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		containerID, err := GetContainerIDByRequest(r, state)
+		if err != nil {
+			writeMetadataErrorResponse(w, http.StatusNotFound, ErrorCodeContainerNotFound,
+				fmt.Sprintf("V3 container log configuration handler: unable to get container ID from request: %s", err.Error()))
+			return
+		}
+		dockerContainer, ok := state.ContainerByID(containerID)
+		if !ok {
+			writeMetadataErrorResponse(w, http.StatusNotFound, ErrorCodeContainerNotFound,
+				fmt.Sprintf("V3 container log configuration handler: container '%s' not found", containerID))
+			return
+		}
+		seelog.Infof("V3 container log configuration handler: writing response for container '%s'", containerID)
+
+		responseJSON, err := json.Marshal(newContainerLogConfigResponse(dockerContainer.Container))
+		if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
+			return
+		}
+		utils.WriteJSONToResponse(w, http.StatusOK, responseJSON, utils.RequestTypeContainerLogConfig)
+	}
+}
+
+// newContainerLogConfigResponse builds the log configuration response of the container, redacting the
+// values of options that are either known credentials or populated from a log driver secret.
+func newContainerLogConfigResponse(container *apicontainer.Container) ContainerLogConfigResponse {
+	response := ContainerLogConfigResponse{Driver: container.GetLogDriver()}
+	options := container.GetLogOptions()
+	if len(options) == 0 {
+		return response
+	}
+
+	response.Options = make(map[string]string, len(options))
+	for name, value := range options {
+		if isSensitiveLogOption(container, name) {
+			value = redactedLogOptionValue
+		}
+		response.Options[name] = value
+	}
+	return response
+}
+
+func isSensitiveLogOption(container *apicontainer.Container, name string) bool {
+	if _, ok := sensitiveLogOptions[name]; ok {
+		return true
+	}
+	return container.HasSecret(func(s apicontainer.Secret) bool {
+		return s.Target == apicontainer.SecretTargetLogDriver && s.Name == name
+	})
+}
@@ -0,0 +1,93 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveContainerLogConfigRequest(t *testing.T, state *mock_dockerstate.MockTaskEngineState) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc(ContainerLogConfigPath, ContainerLogConfigHandler(state))
+	req, err := http.NewRequest(http.MethodGet, "/v3/"+v3EndpointID+"/log-configuration", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestContainerLogConfigHandlerRedactsSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	hostConfig := `{"LogConfig":{"Type":"splunk","Config":{"splunk-url":"https://splunk:8088","splunk-token":"secret-token","splunk-index":"idx"}}}`
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   dockerID,
+		DockerName: dockerName,
+		Container: &apicontainer.Container{
+			Name:         containerName,
+			DockerConfig: apicontainer.DockerConfig{HostConfig: &hostConfig},
+			Secrets: []apicontainer.Secret{
+				{Name: "splunk-index", Target: apicontainer.SecretTargetLogDriver},
+			},
+		},
+	}
+	gomock.InOrder(
+		state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true),
+		state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true),
+	)
+
+	recorder := serveContainerLogConfigRequest(t, state)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response ContainerLogConfigResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "splunk", response.Driver)
+	assert.Equal(t, map[string]string{
+		"splunk-url":   "https://splunk:8088",
+		"splunk-token": redactedLogOptionValue,
+		"splunk-index": redactedLogOptionValue,
+	}, response.Options)
+	assert.NotContains(t, recorder.Body.String(), "secret-token")
+}
+
+func TestContainerLogConfigHandlerUnknownContainer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	gomock.InOrder(
+		state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true),
+		state.EXPECT().ContainerByID(dockerID).Return(nil, false),
+	)
+
+	recorder := serveContainerLogConfigRequest(t, state)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	var errResponse MetadataErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errResponse))
+	assert.Equal(t, ErrorCodeContainerNotFound, errResponse.Code)
+}
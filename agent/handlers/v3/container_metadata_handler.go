@@ -17,7 +17,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/aws/amazon-ecs-agent/agent/config"
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	v2 "github.com/aws/amazon-ecs-agent/agent/handlers/v2"
 	tmdsresponse "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/response"
@@ -30,52 +33,142 @@ import (
 // ContainerMetadataPath specifies the relative URI path for serving container metadata.
 var ContainerMetadataPath = "/v3/" + utils.ConstructMuxVar(V3EndpointIDMuxName, utils.AnythingButSlashRegEx)
 
+// TaskARNQueryParam is the query parameter used to scope a container metadata request to a task.
+// When set, the request fails with a conflict if the container doesn't belong to that task.
+const TaskARNQueryParam = "taskArn"
+
+// PrettyQueryParam is the query parameter used to request an indented container metadata response
+// for humans, e.g. "?pretty=true". Responses are compact by default.
+const PrettyQueryParam = "pretty"
+
+// IncludeInternalLabelsQueryParam is the query parameter used to include the labels set by ECS in the
+// container's docker labels, e.g. "?includeInternalLabels=true". They're excluded by default.
+const IncludeInternalLabelsQueryParam = "includeInternalLabels"
+
+// internalLabelPrefix is the prefix of the docker labels ECS sets on the containers it creates.
+const internalLabelPrefix = "com.amazonaws.ecs."
+
+// errContainerNotFound is returned by GetContainerResponse when the agent doesn't know about the container.
+var errContainerNotFound = errors.New("container not found")
+
+// projectJSONFields marshals the container response, can be overridden in tests.
+var projectJSONFields = utils.ProjectJSONFields
+
 // ContainerMetadataHandler returns the handler method for handling container metadata requests.
-func ContainerMetadataHandler(state dockerstate.TaskEngineState) func(http.ResponseWriter, *http.Request) {
+// With the snake field style the response is re-marshaled with snake_case field names.
+func ContainerMetadataHandler(state dockerstate.TaskEngineState,
+	fieldStyle config.MetadataFieldStyleType) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		containerID, err := GetContainerIDByRequest(r, state)
 		if err != nil {
-			responseJSON, err := json.Marshal(
+			statusCode, errorCode := getContainerIDErrorStatus(err)
+			writeMetadataErrorResponse(w, statusCode, errorCode,
 				fmt.Sprintf("V3 container metadata handler: unable to get container ID from request: %s", err.Error()))
-			if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
-				return
-			}
-			utils.WriteJSONToResponse(w, http.StatusInternalServerError, responseJSON, utils.RequestTypeContainerMetadata)
 			return
 		}
 		containerResponse, err := GetContainerResponse(containerID, state)
 		if err != nil {
-			errResponseJSON, err := json.Marshal(err.Error())
-			if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
+			if errors.Is(err, errContainerNotFound) {
+				writeMetadataErrorResponse(w, http.StatusNotFound, ErrorCodeContainerNotFound,
+					fmt.Sprintf("V3 container metadata handler: container '%s' not found", containerID))
 				return
 			}
-			utils.WriteJSONToResponse(w, http.StatusInternalServerError, errResponseJSON, utils.RequestTypeContainerMetadata)
+			writeMetadataErrorResponse(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
 			return
 		}
+		if taskARN, ok := utils.ValueFromRequest(r, TaskARNQueryParam); ok {
+			if task, found := state.TaskByID(containerID); !found || task.Arn != taskARN {
+				writeMetadataErrorResponse(w, http.StatusConflict, ErrorCodeTaskARNMismatch,
+					fmt.Sprintf("V3 container metadata handler: container '%s' does not belong to task '%s'",
+						containerID, taskARN))
+				return
+			}
+		}
+		includeInternalLabels, _ := utils.ValueFromRequest(r, IncludeInternalLabelsQueryParam)
+		containerResponse.DockerLabels = dockerLabels(containerResponse.Labels, includeInternalLabels == "true")
 		seelog.Infof("V3 container metadata handler: writing response for container '%s'", containerID)
 
-		responseJSON, err := json.Marshal(containerResponse)
+		fields, _ := utils.ValueFromRequest(r, utils.FieldsQueryParam)
+		responseJSON, err := projectJSONFields(containerResponse, fields)
+		if err == nil && fieldStyle == config.MetadataFieldStyleSnake {
+			responseJSON, err = toSnakeCaseJSON(responseJSON)
+		}
+		if pretty, _ := utils.ValueFromRequest(r, PrettyQueryParam); err == nil && pretty == "true" {
+			responseJSON, err = json.MarshalIndent(json.RawMessage(responseJSON), "", "  ")
+		}
 		if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
 			return
 		}
+		if r.Method == http.MethodHead {
+			writeHeadResponse(w, http.StatusOK, responseJSON)
+			return
+		}
 		utils.WriteJSONToResponse(w, http.StatusOK, responseJSON, utils.RequestTypeContainerMetadata)
 	}
 }
 
-// GetContainerResponse gets container response for v3 metadata
+// writeHeadResponse writes the headers of a JSON response, including the length of the body
+// a GET request would have received, without writing the body itself.
+func writeHeadResponse(w http.ResponseWriter, status int, responseJSON []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(responseJSON)))
+	w.WriteHeader(status)
+}
+
+// writeMetadataErrorResponse writes a MetadataErrorResponse with the given status, code and message.
+func writeMetadataErrorResponse(w http.ResponseWriter, status int, code, message string) {
+	errResponseJSON, err := json.Marshal(MetadataErrorResponse{
+		Code:    code,
+		Message: message,
+	})
+	if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
+		return
+	}
+	utils.WriteJSONToResponse(w, status, errResponseJSON, utils.RequestTypeContainerMetadata)
+}
+
+// GetContainerResponse gets container response for v3 metadata. The returned error wraps
+// errContainerNotFound if the agent doesn't know about the container.
 func GetContainerResponse(containerID string, state dockerstate.TaskEngineState) (*tmdsv2.ContainerResponse, error) {
-	containerResponse, err := v2.NewContainerResponseFromState(containerID, state, false)
-	if err != nil {
+	dockerContainer, ok := state.ContainerByID(containerID)
+	if !ok {
+		seelog.Errorf("Unable to find container '%s'", containerID)
+		return nil, errors.Wrapf(errContainerNotFound, "unable to find container '%s'", containerID)
+	}
+	task, ok := state.TaskByID(containerID)
+	if !ok {
 		seelog.Errorf("Unable to get container metadata for container '%s'", containerID)
 		return nil, errors.Errorf("Unable to generate metadata for container '%s'", containerID)
 	}
+	containerResponse := v2.NewContainerResponse(dockerContainer, task.GetPrimaryENI(), false)
 	// fill in network details if not set
 	if containerResponse.Networks == nil {
-		if containerResponse.Networks, err = GetContainerNetworkMetadata(containerID, state); err != nil {
+		networks, err := GetContainerNetworkMetadata(containerID, state)
+		if err != nil {
 			return nil, err
 		}
+		containerResponse.Networks = networks
+	}
+	return &containerResponse, nil
+}
+
+// dockerLabels returns the container's docker labels, leaving out the ones set by ECS unless
+// includeInternal is set.
+func dockerLabels(labels map[string]string, includeInternal bool) map[string]string {
+	if includeInternal {
+		return labels
+	}
+	var filtered map[string]string
+	for key, value := range labels {
+		if strings.HasPrefix(key, internalLabelPrefix) {
+			continue
+		}
+		if filtered == nil {
+			filtered = make(map[string]string)
+		}
+		filtered[key] = value
 	}
-	return containerResponse, nil
+	return filtered
 }
 
 // GetContainerNetworkMetadata returns the network metadata for the container
@@ -0,0 +1,451 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const v3EndpointID = "v3EndpointID"
+
+func serveContainerMetadataRequest(t *testing.T, state *mock_dockerstate.MockTaskEngineState,
+	method string) *httptest.ResponseRecorder {
+	return serveContainerMetadataRequestWithFieldStyle(t, state, method, config.MetadataFieldStyleDefault)
+}
+
+func serveContainerMetadataRequestWithFieldStyle(t *testing.T, state *mock_dockerstate.MockTaskEngineState,
+	method string, fieldStyle config.MetadataFieldStyleType) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc(ContainerMetadataPath, ContainerMetadataHandler(state, fieldStyle))
+	req, err := http.NewRequest(method, "/v3/"+v3EndpointID, nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestContainerMetadataHandlerUnknownEndpointID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return("", false)
+
+	recorder := serveContainerMetadataRequest(t, state, http.MethodGet)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	var errResponse MetadataErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errResponse))
+	assert.Equal(t, ErrorCodeContainerNotFound, errResponse.Code)
+}
+
+func TestContainerMetadataHandlerAmbiguousDockerShortID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	state.EXPECT().DockerIDByV3EndpointID(dockerShortID).Return("", false)
+	state.EXPECT().DockerIDsByPrefix(dockerShortID).Return([]string{dockerShortID + "0000", dockerShortID + "1111"})
+
+	router := mux.NewRouter()
+	router.HandleFunc(ContainerMetadataPath, ContainerMetadataHandler(state, config.MetadataFieldStyleDefault))
+	req, err := http.NewRequest(http.MethodGet, "/v3/"+dockerShortID, nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	var errResponse MetadataErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errResponse))
+	assert.Equal(t, ErrorCodeInvalidRequest, errResponse.Code)
+}
+
+func TestContainerMetadataHandlerMarshalError(t *testing.T) {
+	defer func() {
+		projectJSONFields = utils.ProjectJSONFields
+	}()
+	projectJSONFields = func(response interface{}, fields string) ([]byte, error) {
+		return nil, errors.New("marshal error")
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   dockerID,
+		DockerName: dockerName,
+		Container:  &apicontainer.Container{Name: containerName},
+	}
+	task := &apitask.Task{
+		Arn: taskARN,
+		ENIs: []*ni.NetworkInterface{
+			{IPV4Addresses: []*ni.IPV4Address{{Address: "10.0.0.2"}}},
+		},
+	}
+	gomock.InOrder(
+		state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true),
+		state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true),
+		state.EXPECT().TaskByID(dockerID).Return(task, true),
+	)
+
+	recorder := serveContainerMetadataRequest(t, state, http.MethodGet)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "{}", recorder.Body.String())
+}
+
+func TestContainerMetadataHandlerHead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   dockerID,
+		DockerName: dockerName,
+		Container:  &apicontainer.Container{Name: containerName},
+	}
+	task := &apitask.Task{
+		Arn: taskARN,
+		ENIs: []*ni.NetworkInterface{
+			{IPV4Addresses: []*ni.IPV4Address{{Address: "10.0.0.2"}}},
+		},
+	}
+	state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true).Times(2)
+	state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true).Times(2)
+	state.EXPECT().TaskByID(dockerID).Return(task, true).Times(2)
+
+	getRecorder := serveContainerMetadataRequest(t, state, http.MethodGet)
+	require.Equal(t, http.StatusOK, getRecorder.Code)
+	require.NotZero(t, getRecorder.Body.Len())
+
+	headRecorder := serveContainerMetadataRequest(t, state, http.MethodHead)
+	assert.Equal(t, http.StatusOK, headRecorder.Code)
+	assert.Equal(t, "application/json", headRecorder.Header().Get("Content-Type"))
+	assert.Equal(t, strconv.Itoa(getRecorder.Body.Len()), headRecorder.Header().Get("Content-Length"))
+	assert.Zero(t, headRecorder.Body.Len())
+}
+
+func TestContainerMetadataHandlerFieldStyle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   dockerID,
+		DockerName: dockerName,
+		Container: &apicontainer.Container{
+			Name:   containerName,
+			CPU:    256,
+			Memory: 512,
+		},
+	}
+	dockerContainer.Container.SetLabels(map[string]string{"com.example.TeamName": "payments"})
+	task := &apitask.Task{
+		Arn: taskARN,
+		ENIs: []*ni.NetworkInterface{
+			{IPV4Addresses: []*ni.IPV4Address{{Address: "10.0.0.2"}}},
+		},
+	}
+	state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true).AnyTimes()
+	state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true).AnyTimes()
+	state.EXPECT().TaskByID(dockerID).Return(task, true).AnyTimes()
+
+	expectedResponse, err := GetContainerResponse(dockerID, state)
+	require.NoError(t, err)
+	expectedResponse.DockerLabels = expectedResponse.Labels
+	expectedJSON, err := json.Marshal(expectedResponse)
+	require.NoError(t, err)
+
+	defaultRecorder := serveContainerMetadataRequestWithFieldStyle(t, state, http.MethodGet,
+		config.MetadataFieldStyleDefault)
+	require.Equal(t, http.StatusOK, defaultRecorder.Code)
+	assert.Equal(t, string(expectedJSON), defaultRecorder.Body.String())
+
+	snakeRecorder := serveContainerMetadataRequestWithFieldStyle(t, state, http.MethodGet,
+		config.MetadataFieldStyleSnake)
+	require.Equal(t, http.StatusOK, snakeRecorder.Code)
+	var snakeResponse map[string]interface{}
+	require.NoError(t, json.Unmarshal(snakeRecorder.Body.Bytes(), &snakeResponse))
+	assert.Equal(t, dockerID, snakeResponse["docker_id"])
+	assert.Equal(t, dockerName, snakeResponse["docker_name"])
+	assert.Equal(t, containerName, snakeResponse["name"])
+	assert.Contains(t, snakeResponse, "known_status")
+	assert.NotContains(t, snakeResponse, "DockerId")
+	assert.Equal(t, map[string]interface{}{"cpu": float64(256), "memory": float64(512)}, snakeResponse["limits"])
+	assert.Equal(t, map[string]interface{}{"com.example.TeamName": "payments"}, snakeResponse["labels"],
+		"label names must be served as is")
+	assert.Equal(t, map[string]interface{}{"com.example.TeamName": "payments"}, snakeResponse["docker_labels"],
+		"label names must be served as is")
+	networks := snakeResponse["networks"].([]interface{})
+	require.Len(t, networks, 1)
+	assert.Equal(t, []interface{}{"10.0.0.2"}, networks[0].(map[string]interface{})["ipv4_addresses"])
+}
+
+func TestToSnakeCase(t *testing.T) {
+	testCases := map[string]string{
+		"DockerId":            "docker_id",
+		"ImageID":             "image_id",
+		"ContainerARN":        "container_arn",
+		"CPU":                 "cpu",
+		"ImagePullDurationMs": "image_pull_duration_ms",
+		"IPv4Addresses":       "ipv4_addresses",
+		"IPv6SubnetCIDRBlock": "ipv6_subnet_cidr_block",
+		"SELinuxLabel":        "selinux_label",
+		"statusSince":         "status_since",
+	}
+	for name, expected := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expected, toSnakeCase(name))
+		})
+	}
+}
+
+func TestContainerMetadataHandlerTaskARNQueryParam(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		expectedCode int
+	}{
+		{
+			name:         "no task arn",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "matching task arn",
+			query:        "?" + TaskARNQueryParam + "=" + taskARN,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "mismatched task arn",
+			query:        "?" + TaskARNQueryParam + "=arn:aws:ecs:region:account-id:task/other-task-id",
+			expectedCode: http.StatusConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+			dockerContainer := &apicontainer.DockerContainer{
+				DockerID:   dockerID,
+				DockerName: dockerName,
+				Container:  &apicontainer.Container{Name: containerName},
+			}
+			task := &apitask.Task{
+				Arn: taskARN,
+				ENIs: []*ni.NetworkInterface{
+					{IPV4Addresses: []*ni.IPV4Address{{Address: "10.0.0.2"}}},
+				},
+			}
+			state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true)
+			state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true)
+			state.EXPECT().TaskByID(dockerID).Return(task, true).AnyTimes()
+
+			router := mux.NewRouter()
+			router.HandleFunc(ContainerMetadataPath, ContainerMetadataHandler(state, config.MetadataFieldStyleDefault))
+			req, err := http.NewRequest(http.MethodGet, "/v3/"+v3EndpointID+tc.query, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+			if tc.expectedCode == http.StatusConflict {
+				var errResponse MetadataErrorResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errResponse))
+				assert.Equal(t, ErrorCodeTaskARNMismatch, errResponse.Code)
+			}
+		})
+	}
+}
+
+func TestContainerMetadataHandlerLifecycleTimestamps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+
+	// a running container has been created and started, but hasn't finished yet
+	createdAt := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	startedAt := createdAt.Add(3 * time.Second)
+	container := &apicontainer.Container{Name: containerName}
+	container.SetCreatedAt(createdAt)
+	container.SetStartedAt(startedAt)
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:   dockerID,
+		DockerName: dockerName,
+		Container:  container,
+	}
+	task := &apitask.Task{
+		Arn: taskARN,
+		ENIs: []*ni.NetworkInterface{
+			{IPV4Addresses: []*ni.IPV4Address{{Address: "10.0.0.2"}}},
+		},
+	}
+	state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true)
+	state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true)
+	state.EXPECT().TaskByID(dockerID).Return(task, true)
+
+	recorder := serveContainerMetadataRequest(t, state, http.MethodGet)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, createdAt.Format(time.RFC3339Nano), response["CreatedAt"])
+	assert.Equal(t, startedAt.Format(time.RFC3339Nano), response["StartedAt"])
+	assert.NotContains(t, response, "FinishedAt")
+}
+
+func TestContainerMetadataHandlerPretty(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectIndented bool
+	}{
+		{
+			name: "no pretty param",
+		},
+		{
+			name:           "pretty param set",
+			query:          "?" + PrettyQueryParam + "=true",
+			expectIndented: true,
+		},
+		{
+			name:  "pretty param disabled",
+			query: "?" + PrettyQueryParam + "=false",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+			dockerContainer := &apicontainer.DockerContainer{
+				DockerID:   dockerID,
+				DockerName: dockerName,
+				Container:  &apicontainer.Container{Name: containerName},
+			}
+			task := &apitask.Task{
+				Arn: taskARN,
+				ENIs: []*ni.NetworkInterface{
+					{IPV4Addresses: []*ni.IPV4Address{{Address: "10.0.0.2"}}},
+				},
+			}
+			state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true)
+			state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true)
+			state.EXPECT().TaskByID(dockerID).Return(task, true)
+
+			router := mux.NewRouter()
+			router.HandleFunc(ContainerMetadataPath, ContainerMetadataHandler(state, config.MetadataFieldStyleDefault))
+			req, err := http.NewRequest(http.MethodGet, "/v3/"+v3EndpointID+tc.query, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			body := recorder.Body.String()
+			assert.Equal(t, tc.expectIndented, strings.Contains(body, "\n  \"DockerId\": "), body)
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, dockerID, response["DockerId"])
+		})
+	}
+}
+
+func TestContainerMetadataHandlerDockerLabels(t *testing.T) {
+	labels := map[string]string{
+		"com.example.TeamName":                     "payments",
+		"com.amazonaws.ecs.task-arn":               taskARN,
+		"com.amazonaws.ecs.task-definition-family": "family",
+	}
+	testCases := []struct {
+		name           string
+		query          string
+		expectedLabels map[string]interface{}
+	}{
+		{
+			name:           "internal labels excluded by default",
+			expectedLabels: map[string]interface{}{"com.example.TeamName": "payments"},
+		},
+		{
+			name:           "internal labels excluded",
+			query:          "?" + IncludeInternalLabelsQueryParam + "=false",
+			expectedLabels: map[string]interface{}{"com.example.TeamName": "payments"},
+		},
+		{
+			name:  "internal labels included",
+			query: "?" + IncludeInternalLabelsQueryParam + "=true",
+			expectedLabels: map[string]interface{}{
+				"com.example.TeamName":                     "payments",
+				"com.amazonaws.ecs.task-arn":               taskARN,
+				"com.amazonaws.ecs.task-definition-family": "family",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+			dockerContainer := &apicontainer.DockerContainer{
+				DockerID:   dockerID,
+				DockerName: dockerName,
+				Container:  &apicontainer.Container{Name: containerName},
+			}
+			dockerContainer.Container.SetLabels(labels)
+			task := &apitask.Task{
+				Arn: taskARN,
+				ENIs: []*ni.NetworkInterface{
+					{IPV4Addresses: []*ni.IPV4Address{{Address: "10.0.0.2"}}},
+				},
+			}
+			state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true)
+			state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true)
+			state.EXPECT().TaskByID(dockerID).Return(task, true)
+
+			router := mux.NewRouter()
+			router.HandleFunc(ContainerMetadataPath, ContainerMetadataHandler(state, config.MetadataFieldStyleDefault))
+			req, err := http.NewRequest(http.MethodGet, "/v3/"+v3EndpointID+tc.query, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, tc.expectedLabels, response["DockerLabels"])
+			// Labels are served as they are, with the labels set by ECS
+			assert.Equal(t, map[string]interface{}{
+				"com.example.TeamName":                     "payments",
+				"com.amazonaws.ecs.task-arn":               taskARN,
+				"com.amazonaws.ecs.task-definition-family": "family",
+			}, response["Labels"])
+		})
+	}
+}
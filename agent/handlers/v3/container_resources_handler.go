@@ -0,0 +1,180 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+	"github.com/cihub/seelog"
+	dockercontainer "github.com/docker/docker/api/types/container"
+)
+
+const (
+	// bytesPerMiB converts the memory of a resize request, given in MiB like the task definition, to bytes.
+	bytesPerMiB = 1024 * 1024
+	// minimumContainerMemoryMiB is the smallest memory limit accepted by the docker daemon.
+	minimumContainerMemoryMiB = 6
+	// minimumContainerCPUShares and maximumContainerCPUShares bound the CPU shares accepted by the kernel.
+	minimumContainerCPUShares = 2
+	maximumContainerCPUShares = 262144
+	// cpuUnitsPerVCPU converts the vCPUs of a task to CPU units.
+	cpuUnitsPerVCPU = 1024
+	// containerResizeMinimumDockerVersion is the first docker API version whose container update API
+	// accepts CPU and memory changes of a running container.
+	containerResizeMinimumDockerVersion = dockerclient.Version_1_29
+)
+
+// ContainerResourcesPath specifies the relative URI path for resizing the container's resources in place.
+var ContainerResourcesPath = "/v3/" + utils.ConstructMuxVar(V3EndpointIDMuxName, utils.AnythingButSlashRegEx) + "/resources"
+
+// ContainerResources is both the request and the response of the container resources endpoint. CPU is
+// in CPU units and Memory is in MiB, matching the container definition. A zero value leaves the current
+// limit unchanged.
+type ContainerResources struct {
+	CPU    uint `json:"Cpu,omitempty"`
+	Memory uint `json:"Memory,omitempty"`
+}
+
+// ContainerResourcesHandler returns the handler method for handling requests to update the CPU and memory
+// limits of a running container. The new limits are recorded on the container in the task engine state once
+// docker has applied them.
+func ContainerResourcesHandler(state dockerstate.TaskEngineState,
+	dockerClient dockerapi.DockerClient) func(http.ResponseWriter, *http.Request) {
+	return NewContainerResourcesHandler(state, dockerClient, "V3 container resources handler",
+		utils.RequestTypeContainerResources)
+}
+
+// NewContainerResourcesHandler returns a handler updating the CPU and memory limits of a running container
+// like ContainerResourcesHandler, whose messages are prefixed with handlerName and whose responses are
+// written with requestType.
+func NewContainerResourcesHandler(state dockerstate.TaskEngineState, dockerClient dockerapi.DockerClient,
+	handlerName string, requestType string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !supportsContainerResize(dockerClient) {
+			writeContainerResourcesErrorResponse(w, requestType, http.StatusNotImplemented, ErrorCodeUnsupported,
+				fmt.Sprintf("%s: docker API version %s or greater is required", handlerName,
+					containerResizeMinimumDockerVersion))
+			return
+		}
+
+		containerID, err := GetContainerIDByRequest(r, state)
+		if err != nil {
+			statusCode, errorCode := getContainerIDErrorStatus(err)
+			writeContainerResourcesErrorResponse(w, requestType, statusCode, errorCode,
+				fmt.Sprintf("%s: unable to get container ID from request: %s", handlerName, err.Error()))
+			return
+		}
+		dockerContainer, ok := state.ContainerByID(containerID)
+		if !ok {
+			writeContainerResourcesErrorResponse(w, requestType, http.StatusNotFound, ErrorCodeContainerNotFound,
+				fmt.Sprintf("%s: container '%s' not found", handlerName, containerID))
+			return
+		}
+
+		var resources ContainerResources
+		if err := json.NewDecoder(r.Body).Decode(&resources); err != nil {
+			writeContainerResourcesErrorResponse(w, requestType, http.StatusBadRequest, ErrorCodeInvalidRequest,
+				fmt.Sprintf("%s: unable to decode request body: %s", handlerName, err.Error()))
+			return
+		}
+		if err := validateContainerResources(containerID, resources, state); err != nil {
+			writeContainerResourcesErrorResponse(w, requestType, http.StatusBadRequest, ErrorCodeInvalidRequest,
+				fmt.Sprintf("%s: %s", handlerName, err.Error()))
+			return
+		}
+
+		seelog.Infof("%s: updating container '%s' to cpu %d, memory %d MiB", handlerName,
+			containerID, resources.CPU, resources.Memory)
+		err = dockerClient.UpdateContainerResources(r.Context(), containerID, newDockerResources(resources),
+			dockerclient.UpdateContainerTimeout)
+		if err != nil {
+			writeContainerResourcesErrorResponse(w, requestType, http.StatusInternalServerError, ErrorCodeInternal,
+				fmt.Sprintf("%s: unable to update container '%s': %s", handlerName, containerID, err.Error()))
+			return
+		}
+
+		dockerContainer.Container.SetResources(resources.CPU, resources.Memory)
+
+		cpu, memory := dockerContainer.Container.GetResources()
+		responseJSON, err := json.Marshal(ContainerResources{
+			CPU:    cpu,
+			Memory: memory,
+		})
+		if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
+			return
+		}
+		utils.WriteJSONToResponse(w, http.StatusOK, responseJSON, requestType)
+	}
+}
+
+// validateContainerResources checks that the requested limits are usable by docker and that they stay
+// within the CPU and memory of the container's task, if the task has them.
+func validateContainerResources(containerID string, resources ContainerResources,
+	state dockerstate.TaskEngineState) error {
+	if resources.CPU == 0 && resources.Memory == 0 {
+		return fmt.Errorf("either Cpu or Memory must be set")
+	}
+	if resources.CPU != 0 && (resources.CPU < minimumContainerCPUShares || resources.CPU > maximumContainerCPUShares) {
+		return fmt.Errorf("cpu must be between %d and %d", minimumContainerCPUShares, maximumContainerCPUShares)
+	}
+	if resources.Memory != 0 && resources.Memory < minimumContainerMemoryMiB {
+		return fmt.Errorf("memory must be at least %d MiB", minimumContainerMemoryMiB)
+	}
+	task, ok := state.TaskByID(containerID)
+	if !ok {
+		return fmt.Errorf("unable to find task of container '%s'", containerID)
+	}
+	if task.CPU > 0 && float64(resources.CPU) > task.CPU*cpuUnitsPerVCPU {
+		return fmt.Errorf("cpu %d exceeds the task cpu of %.0f", resources.CPU, task.CPU*cpuUnitsPerVCPU)
+	}
+	if task.Memory > 0 && int64(resources.Memory) > task.Memory {
+		return fmt.Errorf("memory %d MiB exceeds the task memory of %d MiB", resources.Memory, task.Memory)
+	}
+	return nil
+}
+
+// newDockerResources converts the requested limits to the resources of a docker container update.
+func newDockerResources(resources ContainerResources) dockercontainer.Resources {
+	return dockercontainer.Resources{
+		CPUShares: int64(resources.CPU),
+		Memory:    int64(resources.Memory) * bytesPerMiB,
+	}
+}
+
+func supportsContainerResize(dockerClient dockerapi.DockerClient) bool {
+	for _, version := range dockerClient.SupportedVersions() {
+		if version == containerResizeMinimumDockerVersion {
+			return true
+		}
+	}
+	return false
+}
+
+func writeContainerResourcesErrorResponse(w http.ResponseWriter, requestType string, status int, code, message string) {
+	seelog.Error(message)
+	errResponseJSON, err := json.Marshal(MetadataErrorResponse{
+		Code:    code,
+		Message: message,
+	})
+	if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
+		return
+	}
+	utils.WriteJSONToResponse(w, status, errResponseJSON, requestType)
+}
@@ -0,0 +1,225 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
+	mock_dockerapi "github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi/mocks"
+	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var resizeSupportedVersions = []dockerclient.DockerVersion{dockerclient.Version_1_28, dockerclient.Version_1_29}
+
+func newResizeDockerContainer() *apicontainer.DockerContainer {
+	return &apicontainer.DockerContainer{
+		DockerID:  dockerID,
+		Container: &apicontainer.Container{Name: containerName, CPU: 128, Memory: 256},
+	}
+}
+
+func serveContainerResourcesRequest(t *testing.T, state *mock_dockerstate.MockTaskEngineState,
+	dockerClient *mock_dockerapi.MockDockerClient, body string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc(ContainerResourcesPath, ContainerResourcesHandler(state, dockerClient))
+	req, err := http.NewRequest(http.MethodPut, "/v3/"+v3EndpointID+"/resources", bytes.NewBufferString(body))
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestContainerResourcesHandlerResize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	dockerContainer := newResizeDockerContainer()
+	gomock.InOrder(
+		dockerClient.EXPECT().SupportedVersions().Return(resizeSupportedVersions),
+		state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true),
+		state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true),
+		state.EXPECT().TaskByID(dockerID).Return(&apitask.Task{Memory: 1024}, true),
+		dockerClient.EXPECT().UpdateContainerResources(gomock.Any(), dockerID,
+			dockercontainer.Resources{CPUShares: 256, Memory: 512 * 1024 * 1024},
+			dockerclient.UpdateContainerTimeout).Return(nil),
+	)
+
+	recorder := serveContainerResourcesRequest(t, state, dockerClient, `{"Cpu":256,"Memory":512}`)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response ContainerResources
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, ContainerResources{CPU: 256, Memory: 512}, response)
+	cpu, memory := dockerContainer.Container.GetResources()
+	assert.Equal(t, uint(256), cpu, "the new cpu limit should be recorded in state")
+	assert.Equal(t, uint(512), memory, "the new memory limit should be recorded in state")
+	assert.Equal(t, uint(128), dockerContainer.Container.CPU, "the cpu used for host resource accounting should not change")
+	assert.Equal(t, uint(256), dockerContainer.Container.Memory,
+		"the memory used for host resource accounting should not change")
+}
+
+func TestContainerResourcesHandlerCPUOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	gomock.InOrder(
+		dockerClient.EXPECT().SupportedVersions().Return(resizeSupportedVersions),
+		state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true),
+		state.EXPECT().ContainerByID(dockerID).Return(newResizeDockerContainer(), true),
+		state.EXPECT().TaskByID(dockerID).Return(&apitask.Task{Memory: 1024}, true),
+		dockerClient.EXPECT().UpdateContainerResources(gomock.Any(), dockerID,
+			dockercontainer.Resources{CPUShares: 1024}, dockerclient.UpdateContainerTimeout).Return(nil),
+	)
+
+	recorder := serveContainerResourcesRequest(t, state, dockerClient, `{"Cpu":1024}`)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response ContainerResources
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, ContainerResources{CPU: 1024, Memory: 256}, response,
+		"the response should report the memory limit the container already had")
+}
+
+func TestContainerResourcesHandlerUnsupportedDockerVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	dockerClient.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{dockerclient.Version_1_28})
+
+	recorder := serveContainerResourcesRequest(t, state, dockerClient, `{"Cpu":256}`)
+
+	assert.Equal(t, http.StatusNotImplemented, recorder.Code)
+	var errResponse MetadataErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errResponse))
+	assert.Equal(t, ErrorCodeUnsupported, errResponse.Code)
+}
+
+func TestContainerResourcesHandlerInvalidRequest(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		task       *apitask.Task
+		expectTask bool
+	}{
+		{
+			name: "malformed body",
+			body: `{"Cpu":`,
+		},
+		{
+			name: "no resources",
+			body: `{}`,
+		},
+		{
+			name: "memory below docker minimum",
+			body: `{"Memory":4}`,
+		},
+		{
+			name: "cpu below kernel minimum",
+			body: `{"Cpu":1}`,
+		},
+		{
+			name:       "cpu above task cpu",
+			body:       `{"Cpu":2048}`,
+			task:       &apitask.Task{CPU: 1},
+			expectTask: true,
+		},
+		{
+			name:       "memory above task memory",
+			body:       `{"Memory":2048}`,
+			task:       &apitask.Task{Memory: 1024},
+			expectTask: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+			dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+			dockerClient.EXPECT().SupportedVersions().Return(resizeSupportedVersions)
+			state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true)
+			state.EXPECT().ContainerByID(dockerID).Return(newResizeDockerContainer(), true)
+			if tc.expectTask {
+				state.EXPECT().TaskByID(dockerID).Return(tc.task, true)
+			}
+
+			recorder := serveContainerResourcesRequest(t, state, dockerClient, tc.body)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			var errResponse MetadataErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errResponse))
+			assert.Equal(t, ErrorCodeInvalidRequest, errResponse.Code)
+		})
+	}
+}
+
+func TestContainerResourcesHandlerUnknownContainer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	dockerClient.EXPECT().SupportedVersions().Return(resizeSupportedVersions)
+	state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return("", false)
+
+	recorder := serveContainerResourcesRequest(t, state, dockerClient, `{"Cpu":256}`)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	var errResponse MetadataErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errResponse))
+	assert.Equal(t, ErrorCodeContainerNotFound, errResponse.Code)
+}
+
+func TestContainerResourcesHandlerUpdateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	dockerContainer := newResizeDockerContainer()
+	gomock.InOrder(
+		dockerClient.EXPECT().SupportedVersions().Return(resizeSupportedVersions),
+		state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true),
+		state.EXPECT().ContainerByID(dockerID).Return(dockerContainer, true),
+		state.EXPECT().TaskByID(dockerID).Return(&apitask.Task{}, true),
+		dockerClient.EXPECT().UpdateContainerResources(gomock.Any(), dockerID, gomock.Any(),
+			dockerclient.UpdateContainerTimeout).Return(errors.New("update failed")),
+	)
+
+	recorder := serveContainerResourcesRequest(t, state, dockerClient, `{"Memory":512}`)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	var errResponse MetadataErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errResponse))
+	assert.Equal(t, ErrorCodeInternal, errResponse.Code)
+	assert.Empty(t, dockerContainer.Container.GetResizeHistory(), "a failed update should not be recorded in state")
+}
@@ -0,0 +1,104 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// userKeyedFields are response fields whose values are maps keyed by names chosen by the customer, such as
+// docker labels. Their keys are served as is regardless of the field style.
+var userKeyedFields = map[string]struct{}{
+	"Labels":             {},
+	"LogOptions":         {},
+	"EnvironmentSources": {},
+	"DockerLabels":       {},
+}
+
+// toSnakeCaseJSON re-marshals a JSON response with all field names converted to snake_case.
+func toSnakeCaseJSON(responseJSON []byte) ([]byte, error) {
+	var value json.RawMessage
+	if err := json.Unmarshal(responseJSON, &value); err != nil {
+		return nil, err
+	}
+	converted, err := snakeCaseJSONValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(converted)
+}
+
+func snakeCaseJSONValue(value json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return value, nil
+	}
+	switch trimmed[0] {
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &fields); err != nil {
+			return nil, err
+		}
+		convertedFields := make(map[string]json.RawMessage, len(fields))
+		for name, fieldValue := range fields {
+			if _, ok := userKeyedFields[name]; !ok {
+				converted, err := snakeCaseJSONValue(fieldValue)
+				if err != nil {
+					return nil, err
+				}
+				fieldValue = converted
+			}
+			convertedFields[toSnakeCase(name)] = fieldValue
+		}
+		return json.Marshal(convertedFields)
+	case '[':
+		var elements []json.RawMessage
+		if err := json.Unmarshal(trimmed, &elements); err != nil {
+			return nil, err
+		}
+		for i, element := range elements {
+			converted, err := snakeCaseJSONValue(element)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = converted
+		}
+		return json.Marshal(elements)
+	default:
+		return value, nil
+	}
+}
+
+// toSnakeCase converts a PascalCase or camelCase field name to snake_case, keeping acronyms such as
+// "ID" and "ARN" together, e.g. "ContainerARN" becomes "container_arn".
+func toSnakeCase(name string) string {
+	// These names are written in mixed case and would otherwise be split in the middle.
+	name = strings.NewReplacer("IPv4", "Ipv4", "IPv6", "Ipv6", "SELinux", "Selinux").Replace(name)
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				builder.WriteByte('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
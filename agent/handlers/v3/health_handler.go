@@ -0,0 +1,59 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+)
+
+const (
+	// HealthStatusOK is the status reported by the health endpoint when the metadata server is healthy.
+	HealthStatusOK = "ok"
+	// HealthStatusUnavailable is the status reported by the health endpoint when the metadata server
+	// can't serve metadata.
+	HealthStatusUnavailable = "unavailable"
+)
+
+// HealthPath specifies the relative URI path for the health check of the metadata server itself.
+// It must be registered before ContainerMetadataPath, which would otherwise match it.
+var HealthPath = "/v3/health"
+
+// HealthResponse is the response of the metadata server health endpoint.
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// HealthHandler returns the handler method for the metadata server health check. It is meant as a cheap
+// liveness probe, so it only checks that the server has a task engine state to serve metadata from and
+// never calls into Docker.
+func HealthHandler(state dockerstate.TaskEngineState) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		statusCode := http.StatusOK
+		response := HealthResponse{Status: HealthStatusOK}
+		if state == nil {
+			statusCode = http.StatusServiceUnavailable
+			response.Status = HealthStatusUnavailable
+		}
+
+		responseJSON, err := json.Marshal(response)
+		if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
+			return
+		}
+		utils.WriteJSONToResponse(w, statusCode, responseJSON, utils.RequestTypeHealth)
+	}
+}
@@ -0,0 +1,64 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler(t *testing.T) {
+	testCases := []struct {
+		name               string
+		state              dockerstate.TaskEngineState
+		expectedStatusCode int
+		expectedStatus     string
+	}{
+		{
+			name:               "no containers tracked",
+			state:              dockerstate.NewTaskEngineState(),
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     HealthStatusOK,
+		},
+		{
+			name:               "no task engine state",
+			state:              nil,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedStatus:     HealthStatusUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", HealthPath, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			HealthHandler(tc.state)(recorder, req)
+
+			assert.Equal(t, tc.expectedStatusCode, recorder.Code)
+			var response HealthResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, tc.expectedStatus, response.Status)
+		})
+	}
+}
@@ -15,12 +15,19 @@ package v3
 
 import (
 	"net/http"
+	"regexp"
 
 	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
 	"github.com/pkg/errors"
 )
 
+// dockerShortIDRegex matches a docker short container id, which is the first 12 characters of the container id.
+var dockerShortIDRegex = regexp.MustCompile(`^[0-9a-f]{12}$`)
+
+// ErrAmbiguousContainerID is returned when a docker short container id matches more than one container.
+var ErrAmbiguousContainerID = errors.New("ambiguous container ID")
+
 func GetTaskARNByRequest(r *http.Request, state dockerstate.TaskEngineState) (string, error) {
 	v3EndpointID, ok := utils.GetMuxValueFromRequest(r, V3EndpointIDMuxName)
 	if !ok {
@@ -44,11 +51,33 @@ func GetContainerIDByRequest(r *http.Request, state dockerstate.TaskEngineState)
 
 	// Get docker ID from the v3 endpoint ID.
 	dockerID, ok := state.DockerIDByV3EndpointID(v3EndpointID)
-	if !ok {
-		return "", errors.Errorf("unable to get docker ID from v3 endpoint ID: %s", v3EndpointID)
+	if ok {
+		return dockerID, nil
+	}
+
+	// Fall back to resolving the docker ID from a docker short container ID.
+	if dockerShortIDRegex.MatchString(v3EndpointID) {
+		dockerIDs := state.DockerIDsByPrefix(v3EndpointID)
+		switch len(dockerIDs) {
+		case 0:
+		case 1:
+			return dockerIDs[0], nil
+		default:
+			return "", errors.Wrapf(ErrAmbiguousContainerID, "docker short ID %s matches %d containers",
+				v3EndpointID, len(dockerIDs))
+		}
 	}
 
-	return dockerID, nil
+	return "", errors.Errorf("unable to get docker ID from v3 endpoint ID: %s", v3EndpointID)
+}
+
+// getContainerIDErrorStatus returns the status code and error code for a failure to get the container ID
+// from a request. An ambiguous docker short container ID is a bad request rather than an unknown container.
+func getContainerIDErrorStatus(err error) (int, string) {
+	if errors.Is(err, ErrAmbiguousContainerID) {
+		return http.StatusBadRequest, ErrorCodeInvalidRequest
+	}
+	return http.StatusNotFound, ErrorCodeContainerNotFound
 }
 
 func GetAssociationTypeByRequest(r *http.Request) (string, error) {
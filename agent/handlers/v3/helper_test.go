@@ -0,0 +1,110 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"net/http"
+	"testing"
+
+	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dockerShortID = "0123456789ab"
+
+func newContainerIDRequest(t *testing.T, id string) *http.Request {
+	req, err := http.NewRequest(http.MethodGet, "/v3/"+id, nil)
+	require.NoError(t, err)
+	return mux.SetURLVars(req, map[string]string{V3EndpointIDMuxName: id})
+}
+
+func TestGetContainerIDByRequestEndpointID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	state.EXPECT().DockerIDByV3EndpointID(v3EndpointID).Return(dockerID, true)
+
+	containerID, err := GetContainerIDByRequest(newContainerIDRequest(t, v3EndpointID), state)
+	require.NoError(t, err)
+	assert.Equal(t, dockerID, containerID)
+}
+
+func TestGetContainerIDByRequestDockerShortID(t *testing.T) {
+	testCases := []struct {
+		name                string
+		id                  string
+		matchingDockerIDs   []string
+		expectPrefixLookup  bool
+		expectedContainerID string
+		expectedAmbiguous   bool
+	}{
+		{
+			name:                "exact prefix",
+			id:                  dockerShortID,
+			matchingDockerIDs:   []string{dockerShortID + "cdef0000"},
+			expectPrefixLookup:  true,
+			expectedContainerID: dockerShortID + "cdef0000",
+		},
+		{
+			name:               "ambiguous prefix",
+			id:                 dockerShortID,
+			matchingDockerIDs:  []string{dockerShortID + "cdef0000", dockerShortID + "cdef1111"},
+			expectPrefixLookup: true,
+			expectedAmbiguous:  true,
+		},
+		{
+			name:               "no match",
+			id:                 dockerShortID,
+			expectPrefixLookup: true,
+		},
+		{
+			name: "not a docker short id",
+			id:   "0123456789AB",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+			state.EXPECT().DockerIDByV3EndpointID(tc.id).Return("", false)
+			if tc.expectPrefixLookup {
+				state.EXPECT().DockerIDsByPrefix(tc.id).Return(tc.matchingDockerIDs)
+			}
+
+			containerID, err := GetContainerIDByRequest(newContainerIDRequest(t, tc.id), state)
+			if tc.expectedContainerID != "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedContainerID, containerID)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tc.expectedAmbiguous, errors.Is(err, ErrAmbiguousContainerID))
+			statusCode, _ := getContainerIDErrorStatus(err)
+			if tc.expectedAmbiguous {
+				assert.Equal(t, http.StatusBadRequest, statusCode)
+			} else {
+				assert.Equal(t, http.StatusNotFound, statusCode)
+			}
+		})
+	}
+}
@@ -18,6 +18,30 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	// ErrorCodeContainerNotFound is the error code returned when the container of a request cannot be found.
+	ErrorCodeContainerNotFound = "CONTAINER_NOT_FOUND"
+	// ErrorCodeTaskNotFound is the error code returned when the task of a request cannot be found.
+	ErrorCodeTaskNotFound = "TASK_NOT_FOUND"
+	// ErrorCodeENINotFound is the error code returned when the task of a request has no ENI.
+	ErrorCodeENINotFound = "ENI_NOT_FOUND"
+	// ErrorCodeTaskARNMismatch is the error code returned when the container of a request doesn't
+	// belong to the task requested with the taskArn query parameter.
+	ErrorCodeTaskARNMismatch = "TASK_ARN_MISMATCH"
+	// ErrorCodeInvalidRequest is the error code returned when the body of a request is malformed or not allowed.
+	ErrorCodeInvalidRequest = "INVALID_REQUEST"
+	// ErrorCodeInternal is the error code returned when the response for a request cannot be generated.
+	ErrorCodeInternal = "INTERNAL"
+	// ErrorCodeUnsupported is the error code returned when the request isn't supported by the docker daemon.
+	ErrorCodeUnsupported = "UNSUPPORTED"
+)
+
+// MetadataErrorResponse defines the schema for the error response JSON object
+type MetadataErrorResponse struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
 // AssociationsResponse defines the schema for the associations response JSON object
 type AssociationsResponse struct {
 	Associations []string `json:"Associations"`
@@ -104,3 +104,20 @@ func TestContainerAssociationResponse(t *testing.T) {
 	// the response is expected to be the same as the association value
 	assert.Equal(t, associationResponse, associationValue)
 }
+
+func TestMetadataErrorResponse(t *testing.T) {
+	expectedErrorResponseMap := map[string]interface{}{
+		"Code":    ErrorCodeContainerNotFound,
+		"Message": "unable to get docker ID from v3 endpoint ID",
+	}
+
+	errorResponseJSON, err := json.Marshal(MetadataErrorResponse{
+		Code:    ErrorCodeContainerNotFound,
+		Message: "unable to get docker ID from v3 endpoint ID",
+	})
+	assert.NoError(t, err)
+
+	errorResponseMap := make(map[string]interface{})
+	json.Unmarshal(errorResponseJSON, &errorResponseMap)
+	assert.Equal(t, expectedErrorResponseMap, errorResponseMap)
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+	"github.com/cihub/seelog"
+)
+
+// TaskENIPath specifies the relative URI path for serving the ENI of an awsvpc task.
+var TaskENIPath = "/v3/" + utils.ConstructMuxVar(V3EndpointIDMuxName, utils.AnythingButSlashRegEx) + "/task/eni"
+
+// TaskENIResponse is the response of the task ENI endpoint.
+type TaskENIResponse struct {
+	ID                       string   `json:"ID"`
+	AttachmentARN            string   `json:"AttachmentARN,omitempty"`
+	MACAddress               string   `json:"MACAddress"`
+	IPv4Addresses            []string `json:"IPv4Addresses,omitempty"`
+	IPv6Addresses            []string `json:"IPv6Addresses,omitempty"`
+	IPv4SubnetCIDRBlock      string   `json:"IPv4SubnetCIDRBlock,omitempty"`
+	IPv6SubnetCIDRBlock      string   `json:"IPv6SubnetCIDRBlock,omitempty"`
+	SubnetGatewayIPv4Address string   `json:"SubnetGatewayIpv4Address,omitempty"`
+	PrivateDNSName           string   `json:"PrivateDNSName,omitempty"`
+	DomainNameServers        []string `json:"DomainNameServers,omitempty"`
+	DomainNameSearchList     []string `json:"DomainNameSearchList,omitempty"`
+}
+
+// TaskENIHandler returns the handler method for handling task ENI requests.
+func TaskENIHandler(state dockerstate.TaskEngineState) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskARN, err := GetTaskARNByRequest(r, state)
+		if err != nil {
+			writeMetadataErrorResponse(w, http.StatusNotFound, ErrorCodeTaskNotFound,
+				fmt.Sprintf("V3 task ENI handler: unable to get task arn from request: %s", err.Error()))
+			return
+		}
+		task, ok := state.TaskByArn(taskARN)
+		if !ok {
+			writeMetadataErrorResponse(w, http.StatusNotFound, ErrorCodeTaskNotFound,
+				fmt.Sprintf("V3 task ENI handler: task '%s' not found", taskARN))
+			return
+		}
+		eni := task.GetPrimaryENI()
+		if eni == nil {
+			writeMetadataErrorResponse(w, http.StatusNotFound, ErrorCodeENINotFound,
+				fmt.Sprintf("V3 task ENI handler: task '%s' has no ENI", taskARN))
+			return
+		}
+		seelog.Infof("V3 task ENI handler: writing response for task '%s'", taskARN)
+
+		response := newTaskENIResponse(eni)
+		if attachment, ok := state.ENIByMac(eni.MacAddress); ok {
+			response.AttachmentARN = attachment.GetAttachmentARN()
+		}
+		responseJSON, err := json.Marshal(response)
+		if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
+			return
+		}
+		utils.WriteJSONToResponse(w, http.StatusOK, responseJSON, utils.RequestTypeTaskENI)
+	}
+}
+
+func newTaskENIResponse(eni *ni.NetworkInterface) TaskENIResponse {
+	return TaskENIResponse{
+		ID:                       eni.ID,
+		MACAddress:               eni.MacAddress,
+		IPv4Addresses:            eni.GetIPV4Addresses(),
+		IPv6Addresses:            eni.GetIPV6Addresses(),
+		IPv4SubnetCIDRBlock:      eni.GetIPv4SubnetCIDRBlock(),
+		IPv6SubnetCIDRBlock:      eni.GetIPv6SubnetCIDRBlock(),
+		SubnetGatewayIPv4Address: eni.GetSubnetGatewayIPv4Address(),
+		PrivateDNSName:           eni.PrivateDNSName,
+		DomainNameServers:        eni.DomainNameServers,
+		DomainNameSearchList:     eni.DomainNameSearchList,
+	}
+}
@@ -0,0 +1,127 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/api/attachment"
+	ni "github.com/aws/amazon-ecs-agent/ecs-agent/netlib/model/networkinterface"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	taskENITaskARN       = "arn:aws:ecs:us-west-2:123456789012:task/default/abc"
+	taskENIMACAddress    = "06:96:9a:ce:a6:ce"
+	taskENIAttachmentARN = "arn:aws:ecs:us-west-2:123456789012:attachment/abc"
+)
+
+func serveTaskENIRequest(t *testing.T, state *mock_dockerstate.MockTaskEngineState) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc(TaskENIPath, TaskENIHandler(state))
+	req, err := http.NewRequest(http.MethodGet, "/v3/"+v3EndpointID+"/task/eni", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestTaskENIHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	task := &apitask.Task{
+		Arn: taskENITaskARN,
+		ENIs: []*ni.NetworkInterface{
+			{
+				ID:                       "eni-abc",
+				MacAddress:               taskENIMACAddress,
+				IPV4Addresses:            []*ni.IPV4Address{{Primary: true, Address: "10.0.0.2"}},
+				IPV6Addresses:            []*ni.IPV6Address{{Address: "2001:db8::2"}},
+				SubnetGatewayIPV4Address: "10.0.0.1/24",
+				PrivateDNSName:           "ip-10-0-0-2.us-west-2.compute.internal",
+				DomainNameServers:        []string{"10.0.0.2"},
+			},
+		},
+	}
+	eniAttachment := &ni.ENIAttachment{
+		AttachmentInfo: attachment.AttachmentInfo{AttachmentARN: taskENIAttachmentARN},
+		MACAddress:     taskENIMACAddress,
+	}
+	gomock.InOrder(
+		state.EXPECT().TaskARNByV3EndpointID(v3EndpointID).Return(taskENITaskARN, true),
+		state.EXPECT().TaskByArn(taskENITaskARN).Return(task, true),
+		state.EXPECT().ENIByMac(taskENIMACAddress).Return(eniAttachment, true),
+	)
+
+	recorder := serveTaskENIRequest(t, state)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response TaskENIResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, TaskENIResponse{
+		ID:                       "eni-abc",
+		AttachmentARN:            taskENIAttachmentARN,
+		MACAddress:               taskENIMACAddress,
+		IPv4Addresses:            []string{"10.0.0.2"},
+		IPv6Addresses:            []string{"2001:db8::2"},
+		IPv4SubnetCIDRBlock:      "10.0.0.0/24",
+		IPv6SubnetCIDRBlock:      "2001:db8::/64",
+		SubnetGatewayIPv4Address: "10.0.0.1",
+		PrivateDNSName:           "ip-10-0-0-2.us-west-2.compute.internal",
+		DomainNameServers:        []string{"10.0.0.2"},
+	}, response)
+}
+
+func TestTaskENIHandlerNoENI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	gomock.InOrder(
+		state.EXPECT().TaskARNByV3EndpointID(v3EndpointID).Return(taskENITaskARN, true),
+		state.EXPECT().TaskByArn(taskENITaskARN).Return(&apitask.Task{Arn: taskENITaskARN}, true),
+	)
+
+	recorder := serveTaskENIRequest(t, state)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	var response MetadataErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, ErrorCodeENINotFound, response.Code)
+}
+
+func TestTaskENIHandlerUnknownTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	state.EXPECT().TaskARNByV3EndpointID(v3EndpointID).Return("", false)
+
+	recorder := serveTaskENIRequest(t, state)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	var response MetadataErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, ErrorCodeTaskNotFound, response.Code)
+}
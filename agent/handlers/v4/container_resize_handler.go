@@ -0,0 +1,37 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v4
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi"
+	"github.com/aws/amazon-ecs-agent/agent/engine/dockerstate"
+	v3 "github.com/aws/amazon-ecs-agent/agent/handlers/v3"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/utils"
+)
+
+// Container resize endpoint: /v4/<v3 endpoint id>/resize
+var ContainerResizePath = fmt.Sprintf("/v4/%s/resize",
+	utils.ConstructMuxVar(v3.V3EndpointIDMuxName, utils.AnythingButSlashRegEx))
+
+// ContainerResizeHandler returns the handler method for handling requests to update the CPU and memory
+// limits of a running container in place. It validates and applies the new limits the same way as the
+// v3 container resources endpoint.
+func ContainerResizeHandler(state dockerstate.TaskEngineState,
+	dockerClient dockerapi.DockerClient) func(http.ResponseWriter, *http.Request) {
+	return v3.NewContainerResourcesHandler(state, dockerClient, "V4 container resize handler",
+		utils.RequestTypeContainerResize)
+}
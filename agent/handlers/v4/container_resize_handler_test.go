@@ -0,0 +1,155 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v4
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	apitask "github.com/aws/amazon-ecs-agent/agent/api/task"
+	"github.com/aws/amazon-ecs-agent/agent/dockerclient"
+	mock_dockerapi "github.com/aws/amazon-ecs-agent/agent/dockerclient/dockerapi/mocks"
+	mock_dockerstate "github.com/aws/amazon-ecs-agent/agent/engine/dockerstate/mocks"
+	v3 "github.com/aws/amazon-ecs-agent/agent/handlers/v3"
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const resizeV3EndpointID = "v3EndpointID"
+
+var resizeSupportedVersions = []dockerclient.DockerVersion{dockerclient.Version_1_28, dockerclient.Version_1_29}
+
+func serveContainerResizeRequest(t *testing.T, state *mock_dockerstate.MockTaskEngineState,
+	dockerClient *mock_dockerapi.MockDockerClient, body string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc(ContainerResizePath, ContainerResizeHandler(state, dockerClient))
+	req, err := http.NewRequest(http.MethodPut, "/v4/"+resizeV3EndpointID+"/resize", bytes.NewBufferString(body))
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestContainerResizeHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	dockerContainer := &apicontainer.DockerContainer{
+		DockerID:  containerID,
+		Container: &apicontainer.Container{Name: containerName, CPU: 256, Memory: 256},
+	}
+	gomock.InOrder(
+		dockerClient.EXPECT().SupportedVersions().Return(resizeSupportedVersions),
+		state.EXPECT().DockerIDByV3EndpointID(resizeV3EndpointID).Return(containerID, true),
+		state.EXPECT().ContainerByID(containerID).Return(dockerContainer, true),
+		state.EXPECT().TaskByID(containerID).Return(&apitask.Task{CPU: 1, Memory: 1024}, true),
+		dockerClient.EXPECT().UpdateContainerResources(gomock.Any(), containerID,
+			dockercontainer.Resources{Memory: 768 * 1024 * 1024}, dockerclient.UpdateContainerTimeout).Return(nil),
+	)
+
+	recorder := serveContainerResizeRequest(t, state, dockerClient, `{"Memory":768}`)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response v3.ContainerResources
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, v3.ContainerResources{CPU: 256, Memory: 768}, response)
+	cpu, memory := dockerContainer.Container.GetResources()
+	assert.Equal(t, uint(256), cpu)
+	assert.Equal(t, uint(768), memory, "the new memory limit should be recorded in state")
+	assert.Equal(t, uint(256), dockerContainer.Container.Memory,
+		"the memory used for host resource accounting should not change")
+}
+
+func TestContainerResizeHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "malformed body",
+			body: `{"Memory":`,
+		},
+		{
+			name: "no resources",
+			body: `{}`,
+		},
+		{
+			name: "cpu above kernel maximum",
+			body: `{"Cpu":300000}`,
+		},
+		{
+			name: "cpu above task cpu",
+			body: `{"Cpu":2048}`,
+		},
+		{
+			name: "memory below docker minimum",
+			body: `{"Memory":1}`,
+		},
+		{
+			name: "memory above task memory",
+			body: `{"Memory":4096}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+			dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+			dockerContainer := &apicontainer.DockerContainer{
+				DockerID:  containerID,
+				Container: &apicontainer.Container{Name: containerName, CPU: 256, Memory: 256},
+			}
+			dockerClient.EXPECT().SupportedVersions().Return(resizeSupportedVersions)
+			state.EXPECT().DockerIDByV3EndpointID(resizeV3EndpointID).Return(containerID, true)
+			state.EXPECT().ContainerByID(containerID).Return(dockerContainer, true)
+			state.EXPECT().TaskByID(containerID).Return(&apitask.Task{CPU: 1, Memory: 1024}, true).AnyTimes()
+
+			recorder := serveContainerResizeRequest(t, state, dockerClient, tc.body)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			var errResponse v3.MetadataErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errResponse))
+			assert.Equal(t, v3.ErrorCodeInvalidRequest, errResponse.Code)
+			assert.Contains(t, errResponse.Message, "V4 container resize handler")
+			cpu, memory := dockerContainer.Container.GetResources()
+			assert.Equal(t, uint(256), cpu)
+			assert.Equal(t, uint(256), memory)
+		})
+	}
+}
+
+func TestContainerResizeHandlerUnsupportedDockerVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	state := mock_dockerstate.NewMockTaskEngineState(ctrl)
+	dockerClient := mock_dockerapi.NewMockDockerClient(ctrl)
+	dockerClient.EXPECT().SupportedVersions().Return([]dockerclient.DockerVersion{dockerclient.Version_1_28})
+
+	recorder := serveContainerResizeRequest(t, state, dockerClient, `{"Cpu":512}`)
+
+	assert.Equal(t, http.StatusNotImplemented, recorder.Code)
+}
@@ -0,0 +1,62 @@
+//go:build !windows
+// +build !windows
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+package sighandlers
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
+)
+
+// AWSLogsEndpointSetter is implemented by components that apply the awslogs
+// endpoint to new containers
+type AWSLogsEndpointSetter interface {
+	SetAWSLogsEndpoint(endpoint string)
+}
+
+// loadAWSLogsEndpoint is a variable so that tests can stub out config loading
+var loadAWSLogsEndpoint = config.LoadAWSLogsEndpoint
+
+// StartLogEndpointReloadHandler re-reads the awslogs endpoint configuration
+// whenever the agent receives a SIGHUP and applies it to containers created
+// afterwards, without restarting running tasks
+func StartLogEndpointReloadHandler(setter AWSLogsEndpointSetter) {
+	signalChannel := make(chan os.Signal, 1)
+	signal.Notify(signalChannel, syscall.SIGHUP)
+	go func() {
+		for range signalChannel {
+			reloadAWSLogsEndpoint(setter)
+		}
+	}()
+}
+
+func reloadAWSLogsEndpoint(setter AWSLogsEndpointSetter) {
+	endpoint, err := loadAWSLogsEndpoint()
+	if err != nil {
+		logger.Error("Unable to reload awslogs endpoint, keeping the current one", logger.Fields{
+			field.Error: err,
+		})
+		return
+	}
+	logger.Info("Reloaded awslogs endpoint", logger.Fields{
+		"awslogsEndpoint": endpoint,
+	})
+	setter.SetAWSLogsEndpoint(endpoint)
+}
@@ -0,0 +1,74 @@
+//go:build !windows && unit
+// +build !windows,unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+package sighandlers
+
+import (
+	"errors"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ecs-agent/agent/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEndpointSetter struct {
+	lock      sync.Mutex
+	endpoints []string
+}
+
+func (s *fakeEndpointSetter) SetAWSLogsEndpoint(endpoint string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.endpoints = append(s.endpoints, endpoint)
+}
+
+func (s *fakeEndpointSetter) get() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.endpoints...)
+}
+
+func TestReloadAWSLogsEndpoint(t *testing.T) {
+	defer func() { loadAWSLogsEndpoint = config.LoadAWSLogsEndpoint }()
+	setter := &fakeEndpointSetter{}
+
+	loadAWSLogsEndpoint = func() (string, error) { return "https://logs.example.com", nil }
+	reloadAWSLogsEndpoint(setter)
+	assert.Equal(t, []string{"https://logs.example.com"}, setter.get())
+
+	// A failed reload keeps the current endpoint
+	loadAWSLogsEndpoint = func() (string, error) { return "", errors.New("bad config") }
+	reloadAWSLogsEndpoint(setter)
+	assert.Equal(t, []string{"https://logs.example.com"}, setter.get())
+}
+
+func TestLogEndpointReloadHandlerOnSIGHUP(t *testing.T) {
+	defer func() { loadAWSLogsEndpoint = config.LoadAWSLogsEndpoint }()
+	loadAWSLogsEndpoint = func() (string, error) { return "https://logs.reloaded.example.com", nil }
+	setter := &fakeEndpointSetter{}
+
+	StartLogEndpointReloadHandler(setter)
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+
+	assert.Eventually(t, func() bool {
+		return len(setter.get()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"https://logs.reloaded.example.com"}, setter.get())
+}
@@ -19,6 +19,10 @@
 // SIGUSR1:
 //
 //	Print a dump of goroutines to the logger and DON'T exit
+//
+// SIGHUP:
+//
+//	Reload the awslogs endpoint configuration and DON'T exit
 package sighandlers
 
 import (
@@ -19,4 +19,5 @@ import (
 
 type SSMClient interface {
 	GetParameters(*ssm.GetParametersInput) (*ssm.GetParametersOutput, error)
+	GetParametersByPath(*ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error)
 }
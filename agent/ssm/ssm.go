@@ -34,6 +34,38 @@ func GetParametersFromSSM(names []string, client SSMClient) (map[string]string,
 	return getParameters(names, client, false)
 }
 
+// GetSecretsByPathFromSSM makes the api call to the AWS SSM parameter store to
+// retrieve every parameter under the given path hierarchy. The returned map is
+// keyed by the parameter name relative to the path.
+func GetSecretsByPathFromSSM(path string, client SSMClient) (map[string]string, error) {
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	parameterValues := make(map[string]string)
+	in := &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+	}
+	for {
+		out, err := client.GetParametersByPath(in)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			return nil, errors.New("empty response")
+		}
+		for _, parameter := range out.Parameters {
+			name := strings.TrimPrefix(aws.StringValue(parameter.Name), prefix)
+			parameterValues[name] = aws.StringValue(parameter.Value)
+		}
+		if aws.StringValue(out.NextToken) == "" {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+
+	return parameterValues, nil
+}
+
 func getParameters(names []string, client SSMClient, withDecryption bool) (map[string]string, error) {
 	var params []*string
 	for _, name := range names {
@@ -17,6 +17,7 @@
 package ssm
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -126,3 +127,59 @@ func TestGetParametersFromSSM(t *testing.T) {
 		})
 	}
 }
+
+type mockGetParametersByPath struct {
+	SSMClient
+	Pages []ssm.GetParametersByPathOutput
+	calls int
+}
+
+func (m *mockGetParametersByPath) GetParametersByPath(input *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+	if m.calls > 0 && aws.StringValue(input.NextToken) == "" {
+		return nil, errors.New("missing next token")
+	}
+	page := m.Pages[m.calls]
+	m.calls++
+	return &page, nil
+}
+
+func TestGetSecretsByPathFromSSM(t *testing.T) {
+	ssmClient := &mockGetParametersByPath{
+		Pages: []ssm.GetParametersByPathOutput{
+			{
+				Parameters: []*ssm.Parameter{
+					{Name: aws.String("/app/prod/DB_HOST"), Value: aws.String("db.example.com")},
+					{Name: aws.String("/app/prod/db/PASSWORD"), Value: aws.String("secret")},
+				},
+				NextToken: aws.String("token"),
+			},
+			{
+				Parameters: []*ssm.Parameter{
+					{Name: aws.String("/app/prod/API_KEY"), Value: aws.String("key")},
+				},
+			},
+		},
+	}
+
+	values, err := GetSecretsByPathFromSSM("/app/prod/", ssmClient)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ssmClient.calls)
+	assert.Equal(t, map[string]string{
+		"DB_HOST":     "db.example.com",
+		"db/PASSWORD": "secret",
+		"API_KEY":     "key",
+	}, values)
+}
+
+type mockGetParametersByPathError struct {
+	SSMClient
+}
+
+func (m mockGetParametersByPathError) GetParametersByPath(input *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+	return nil, errors.New("access denied")
+}
+
+func TestGetSecretsByPathFromSSMError(t *testing.T) {
+	_, err := GetSecretsByPathFromSSM("/app/prod", mockGetParametersByPathError{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,458 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package appconfigsecret
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cihub/seelog"
+	"github.com/pkg/errors"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	"github.com/aws/amazon-ecs-agent/agent/appconfig"
+	"github.com/aws/amazon-ecs-agent/agent/appconfig/factory"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource"
+	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
+	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
+)
+
+const (
+	// ResourceName is the name of the appconfigsecret resource
+	ResourceName = "appconfigsecret"
+)
+
+// AppConfigSecretResource represents secrets as a task resource.
+// The secrets are stored as AppConfig configuration profiles.
+type AppConfigSecretResource struct {
+	taskARN             string
+	createdAt           time.Time
+	desiredStatusUnsafe resourcestatus.ResourceStatus
+	knownStatusUnsafe   resourcestatus.ResourceStatus
+	// appliedStatus is the status that has been "applied" (e.g., we've called some
+	// operation such as 'Create' on the resource) but we don't yet know that the
+	// application was successful, which may then change the known status. This is
+	// used while progressing resource states in progressTask() of task manager
+	appliedStatus                      resourcestatus.ResourceStatus
+	resourceStatusToTransitionFunction map[resourcestatus.ResourceStatus]func() error
+	credentialsManager                 credentials.Manager
+	executionCredentialsID             string
+
+	// required for store appconfig secrets value, key is region of secret
+	requiredSecrets map[string][]apicontainer.Secret
+	// map to store secret values, key is a combination of valueFrom and region
+	secretData map[string]string
+
+	// appConfigClientCreator is a factory interface that creates new AppConfig clients. This is
+	// needed mostly for testing.
+	appConfigClientCreator factory.AppConfigClientCreator
+
+	// terminalReason should be set for resource creation failures. This ensures
+	// the resource object carries some context for why provisioning failed.
+	terminalReason     string
+	terminalReasonOnce sync.Once
+
+	// lock is used for fields that are accessed and updated concurrently
+	lock sync.RWMutex
+}
+
+// NewAppConfigSecretResource creates a new AppConfigSecretResource object
+func NewAppConfigSecretResource(taskARN string,
+	appConfigSecrets map[string][]apicontainer.Secret,
+	executionCredentialsID string,
+	credentialsManager credentials.Manager,
+	appConfigClientCreator factory.AppConfigClientCreator) *AppConfigSecretResource {
+
+	s := &AppConfigSecretResource{
+		taskARN:                taskARN,
+		requiredSecrets:        appConfigSecrets,
+		credentialsManager:     credentialsManager,
+		executionCredentialsID: executionCredentialsID,
+		appConfigClientCreator: appConfigClientCreator,
+	}
+
+	s.initStatusToTransition()
+	return s
+}
+
+func (secret *AppConfigSecretResource) initStatusToTransition() {
+	resourceStatusToTransitionFunction := map[resourcestatus.ResourceStatus]func() error{
+		resourcestatus.ResourceStatus(AppConfigSecretCreated): secret.Create,
+	}
+	secret.resourceStatusToTransitionFunction = resourceStatusToTransitionFunction
+}
+
+func (secret *AppConfigSecretResource) setTerminalReason(reason string) {
+	secret.terminalReasonOnce.Do(func() {
+		seelog.Infof("appconfig secret resource: setting terminal reason for appconfig secret resource in task: [%s]", secret.taskARN)
+		secret.terminalReason = reason
+	})
+}
+
+// GetTerminalReason returns an error string to propagate up through to task
+// state change messages
+func (secret *AppConfigSecretResource) GetTerminalReason() string {
+	return secret.terminalReason
+}
+
+// SetDesiredStatus safely sets the desired status of the resource
+func (secret *AppConfigSecretResource) SetDesiredStatus(status resourcestatus.ResourceStatus) {
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	secret.desiredStatusUnsafe = status
+}
+
+// GetDesiredStatus safely returns the desired status of the task
+func (secret *AppConfigSecretResource) GetDesiredStatus() resourcestatus.ResourceStatus {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.desiredStatusUnsafe
+}
+
+// GetName safely returns the name of the resource
+func (secret *AppConfigSecretResource) GetName() string {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return ResourceName
+}
+
+// DesiredTerminal returns true if the secret's desired status is REMOVED
+func (secret *AppConfigSecretResource) DesiredTerminal() bool {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.desiredStatusUnsafe == resourcestatus.ResourceStatus(AppConfigSecretRemoved)
+}
+
+// KnownCreated returns true if the secret's known status is CREATED
+func (secret *AppConfigSecretResource) KnownCreated() bool {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.knownStatusUnsafe == resourcestatus.ResourceStatus(AppConfigSecretCreated)
+}
+
+// TerminalStatus returns the last transition state of cgroup
+func (secret *AppConfigSecretResource) TerminalStatus() resourcestatus.ResourceStatus {
+	return resourcestatus.ResourceStatus(AppConfigSecretRemoved)
+}
+
+// NextKnownState returns the state that the resource should
+// progress to based on its `KnownState`.
+func (secret *AppConfigSecretResource) NextKnownState() resourcestatus.ResourceStatus {
+	return secret.GetKnownStatus() + 1
+}
+
+// ApplyTransition calls the function required to move to the specified status
+func (secret *AppConfigSecretResource) ApplyTransition(nextState resourcestatus.ResourceStatus) error {
+	transitionFunc, ok := secret.resourceStatusToTransitionFunction[nextState]
+	if !ok {
+		return errors.Errorf("resource [%s]: transition to %s impossible", secret.GetName(),
+			secret.StatusString(nextState))
+	}
+	return transitionFunc()
+}
+
+// SteadyState returns the transition state of the resource defined as "ready"
+func (secret *AppConfigSecretResource) SteadyState() resourcestatus.ResourceStatus {
+	return resourcestatus.ResourceStatus(AppConfigSecretCreated)
+}
+
+// SetKnownStatus safely sets the currently known status of the resource
+func (secret *AppConfigSecretResource) SetKnownStatus(status resourcestatus.ResourceStatus) {
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	secret.knownStatusUnsafe = status
+	secret.updateAppliedStatusUnsafe(status)
+}
+
+// updateAppliedStatusUnsafe updates the resource transitioning status
+func (secret *AppConfigSecretResource) updateAppliedStatusUnsafe(knownStatus resourcestatus.ResourceStatus) {
+	if secret.appliedStatus == resourcestatus.ResourceStatus(AppConfigSecretStatusNone) {
+		return
+	}
+
+	// Check if the resource transition has already finished
+	if secret.appliedStatus <= knownStatus {
+		secret.appliedStatus = resourcestatus.ResourceStatus(AppConfigSecretStatusNone)
+	}
+}
+
+// SetAppliedStatus sets the applied status of resource and returns whether
+// the resource is already in a transition
+func (secret *AppConfigSecretResource) SetAppliedStatus(status resourcestatus.ResourceStatus) bool {
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	if secret.appliedStatus != resourcestatus.ResourceStatus(AppConfigSecretStatusNone) {
+		// return false to indicate the set operation failed
+		return false
+	}
+
+	secret.appliedStatus = status
+	return true
+}
+
+// GetKnownStatus safely returns the currently known status of the task
+func (secret *AppConfigSecretResource) GetKnownStatus() resourcestatus.ResourceStatus {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.knownStatusUnsafe
+}
+
+// StatusString returns the string of the cgroup resource status
+func (secret *AppConfigSecretResource) StatusString(status resourcestatus.ResourceStatus) string {
+	return AppConfigSecretStatus(status).String()
+}
+
+// SetCreatedAt sets the timestamp for resource's creation time
+func (secret *AppConfigSecretResource) SetCreatedAt(createdAt time.Time) {
+	if createdAt.IsZero() {
+		return
+	}
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	secret.createdAt = createdAt
+}
+
+// GetCreatedAt sets the timestamp for resource's creation time
+func (secret *AppConfigSecretResource) GetCreatedAt() time.Time {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.createdAt
+}
+
+// Create fetches the configurations referenced by the secrets from AppConfig. It spins up a
+// goroutine per region in order to retrieve values in parallel.
+func (secret *AppConfigSecretResource) Create() error {
+
+	// To fail fast, check execution role first
+	executionCredentials, ok := secret.credentialsManager.GetTaskCredentials(secret.getExecutionCredentialsID())
+	if !ok {
+		// No need to log here. managedTask.applyResourceState already does that
+		err := errors.New("appconfig secret resource: unable to find execution role credentials")
+		secret.setTerminalReason(err.Error())
+		return err
+	}
+	iamCredentials := executionCredentials.GetIAMRoleCredentials()
+
+	var wg sync.WaitGroup
+
+	requiredSecrets := secret.getRequiredSecrets()
+	errorEvents := make(chan error, len(requiredSecrets))
+
+	seelog.Infof("appconfig secret resource: retrieving secrets for containers in task: [%s]", secret.taskARN)
+	secret.lock.Lock()
+	secret.secretData = make(map[string]string)
+	secret.lock.Unlock()
+
+	for region, secrets := range requiredSecrets {
+		wg.Add(1)
+		// Spin up goroutine each region to speed up processing time
+		go secret.retrieveAppConfigSecretValuesByRegion(region, secrets, iamCredentials, &wg, errorEvents)
+	}
+
+	wg.Wait()
+
+	// Get the first error returned and set as terminal reason
+	select {
+	case err := <-errorEvents:
+		secret.setTerminalReason(err.Error())
+		return err
+	default:
+		return nil
+	}
+}
+
+// retrieveAppConfigSecretValuesByRegion retrieves the configurations referenced by the secrets of a
+// region from AppConfig and caches them into memory. It stops at the first failure of the region.
+func (secret *AppConfigSecretResource) retrieveAppConfigSecretValuesByRegion(region string, secrets []apicontainer.Secret,
+	iamCredentials credentials.IAMRoleCredentials, wg *sync.WaitGroup, errorEvents chan error) {
+	seelog.Infof("appconfig secret resource: retrieving secrets for region %s in task: [%s]", region, secret.taskARN)
+	defer wg.Done()
+
+	appConfigClient := secret.appConfigClientCreator.NewAppConfigClient(region, iamCredentials)
+	for _, s := range secrets {
+		secretKey := s.GetSecretResourceCacheKey()
+		if _, ok := secret.GetCachedSecretValue(secretKey); ok {
+			continue
+		}
+		secretValue, err := appconfig.GetConfiguration(s.ValueFrom, appConfigClient)
+		if err != nil {
+			errorEvents <- fmt.Errorf("fetching secret data from AppConfig in %s: %v", region, err)
+			return
+		}
+		secret.SetCachedSecretValue(secretKey, secretValue)
+	}
+}
+
+// getRequiredSecrets returns the requiredSecrets field of appconfigsecret task resource
+func (secret *AppConfigSecretResource) getRequiredSecrets() map[string][]apicontainer.Secret {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.requiredSecrets
+}
+
+// getExecutionCredentialsID returns the execution role's credential ID
+func (secret *AppConfigSecretResource) getExecutionCredentialsID() string {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.executionCredentialsID
+}
+
+// Cleanup removes the secret value created for the task
+func (secret *AppConfigSecretResource) Cleanup() error {
+	secret.clearAppConfigSecretValue()
+	return nil
+}
+
+// clearAppConfigSecretValue cycles through the collection of secret value data and
+// removes them from the task
+func (secret *AppConfigSecretResource) clearAppConfigSecretValue() {
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	for key := range secret.secretData {
+		delete(secret.secretData, key)
+	}
+}
+
+// GetCachedSecretValue retrieves the secret value from secretData field
+func (secret *AppConfigSecretResource) GetCachedSecretValue(secretKey string) (string, bool) {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	s, ok := secret.secretData[secretKey]
+	return s, ok
+}
+
+// SetCachedSecretValue set the secret value in the secretData field given the key and value
+func (secret *AppConfigSecretResource) SetCachedSecretValue(secretKey string, secretValue string) {
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	if secret.secretData == nil {
+		secret.secretData = make(map[string]string)
+	}
+
+	secret.secretData[secretKey] = secretValue
+}
+
+func (secret *AppConfigSecretResource) Initialize(resourceFields *taskresource.ResourceFields,
+	taskKnownStatus status.TaskStatus,
+	taskDesiredStatus status.TaskStatus) {
+	secret.initStatusToTransition()
+	secret.credentialsManager = resourceFields.CredentialsManager
+	secret.appConfigClientCreator = resourceFields.AppConfigClientCreator
+
+	// if task hasn't turn to 'created' status, and it's desire status is 'running'
+	// the resource status needs to be reset to 'NONE' status so the secret value
+	// will be retrieved again
+	if taskKnownStatus < status.TaskCreated &&
+		taskDesiredStatus <= status.TaskRunning {
+		secret.SetKnownStatus(resourcestatus.ResourceStatusNone)
+	}
+}
+
+type AppConfigSecretResourceJSON struct {
+	TaskARN                string                           `json:"taskARN"`
+	CreatedAt              *time.Time                       `json:"createdAt,omitempty"`
+	DesiredStatus          *AppConfigSecretStatus           `json:"desiredStatus"`
+	KnownStatus            *AppConfigSecretStatus           `json:"knownStatus"`
+	RequiredSecrets        map[string][]apicontainer.Secret `json:"secretResources"`
+	ExecutionCredentialsID string                           `json:"executionCredentialsID"`
+}
+
+// MarshalJSON serialises the AppConfigSecretResource struct to JSON
+func (secret *AppConfigSecretResource) MarshalJSON() ([]byte, error) {
+	if secret == nil {
+		return nil, errors.New("appconfigsecret resource is nil")
+	}
+	createdAt := secret.GetCreatedAt()
+	return json.Marshal(AppConfigSecretResourceJSON{
+		TaskARN:   secret.taskARN,
+		CreatedAt: &createdAt,
+		DesiredStatus: func() *AppConfigSecretStatus {
+			desiredState := secret.GetDesiredStatus()
+			s := AppConfigSecretStatus(desiredState)
+			return &s
+		}(),
+		KnownStatus: func() *AppConfigSecretStatus {
+			knownState := secret.GetKnownStatus()
+			s := AppConfigSecretStatus(knownState)
+			return &s
+		}(),
+		RequiredSecrets:        secret.getRequiredSecrets(),
+		ExecutionCredentialsID: secret.getExecutionCredentialsID(),
+	})
+}
+
+// UnmarshalJSON deserialises the raw JSON to a AppConfigSecretResource struct
+func (secret *AppConfigSecretResource) UnmarshalJSON(b []byte) error {
+	temp := AppConfigSecretResourceJSON{}
+
+	if err := json.Unmarshal(b, &temp); err != nil {
+		return err
+	}
+
+	if temp.DesiredStatus != nil {
+		secret.SetDesiredStatus(resourcestatus.ResourceStatus(*temp.DesiredStatus))
+	}
+	if temp.KnownStatus != nil {
+		secret.SetKnownStatus(resourcestatus.ResourceStatus(*temp.KnownStatus))
+	}
+	if temp.CreatedAt != nil && !temp.CreatedAt.IsZero() {
+		secret.SetCreatedAt(*temp.CreatedAt)
+	}
+	if temp.RequiredSecrets != nil {
+		secret.requiredSecrets = temp.RequiredSecrets
+	}
+	secret.taskARN = temp.TaskARN
+	secret.executionCredentialsID = temp.ExecutionCredentialsID
+
+	return nil
+}
+
+// GetAppliedStatus safely returns the currently applied status of the resource
+func (secret *AppConfigSecretResource) GetAppliedStatus() resourcestatus.ResourceStatus {
+	secret.lock.RLock()
+	defer secret.lock.RUnlock()
+
+	return secret.appliedStatus
+}
+
+func (secret *AppConfigSecretResource) DependOnTaskNetwork() bool {
+	return false
+}
+
+func (secret *AppConfigSecretResource) BuildContainerDependency(containerName string, satisfied apicontainerstatus.ContainerStatus,
+	dependent resourcestatus.ResourceStatus) {
+}
+
+func (secret *AppConfigSecretResource) GetContainerDependencies(dependent resourcestatus.ResourceStatus) []apicontainer.ContainerDependency {
+	return nil
+}
@@ -0,0 +1,228 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package appconfigsecret
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
+	mock_factory "github.com/aws/amazon-ecs-agent/agent/appconfig/factory/mocks"
+	mock_appconfig "github.com/aws/amazon-ecs-agent/agent/appconfig/mocks"
+	"github.com/aws/amazon-ecs-agent/agent/taskresource"
+	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
+	apitaskstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/task/status"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
+	mock_credentials "github.com/aws/amazon-ecs-agent/ecs-agent/credentials/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/appconfigdata"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	executionCredentialsID = "exec-creds-id"
+	region1                = "us-west-2"
+	region2                = "us-east-1"
+	secretName1            = "FEATURE_FLAGS"
+	valueFrom1             = "my-app/prod/flags"
+	secretKeyWest1         = "my-app/prod/flags_us-west-2"
+	secretKeyEast1         = "my-app/prod/flags_us-east-1"
+	secretValue            = `{"feature":true}`
+	sessionToken           = "token"
+	taskARN                = "task1"
+)
+
+func TestCreateAndGetAcrossRegions(t *testing.T) {
+	requiredSecretData := map[string][]apicontainer.Secret{
+		region1: {
+			{
+				Name:      secretName1,
+				ValueFrom: valueFrom1,
+				Region:    region1,
+				Provider:  apicontainer.SecretProviderAppConfig,
+			},
+		},
+		region2: {
+			{
+				Name:      secretName1,
+				ValueFrom: valueFrom1,
+				Region:    region2,
+				Provider:  apicontainer.SecretProviderAppConfig,
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	credentialsManager := mock_credentials.NewMockManager(ctrl)
+	appConfigClientCreator := mock_factory.NewMockAppConfigClientCreator(ctrl)
+	mockAppConfigClient := mock_appconfig.NewMockAppConfigClient(ctrl)
+
+	iamRoleCreds := credentials.IAMRoleCredentials{}
+	creds := credentials.TaskIAMRoleCredentials{
+		IAMRoleCredentials: iamRoleCreds,
+	}
+
+	credentialsManager.EXPECT().GetTaskCredentials(executionCredentialsID).Return(creds, true)
+	appConfigClientCreator.EXPECT().NewAppConfigClient(region1, iamRoleCreds).Return(mockAppConfigClient)
+	appConfigClientCreator.EXPECT().NewAppConfigClient(region2, iamRoleCreds).Return(mockAppConfigClient)
+	mockAppConfigClient.EXPECT().StartConfigurationSession(gomock.Any()).Return(
+		&appconfigdata.StartConfigurationSessionOutput{InitialConfigurationToken: aws.String(sessionToken)}, nil).Times(2)
+	mockAppConfigClient.EXPECT().GetLatestConfiguration(gomock.Any()).Return(
+		&appconfigdata.GetLatestConfigurationOutput{Configuration: []byte(secretValue)}, nil).Times(2)
+
+	appConfigRes := &AppConfigSecretResource{
+		executionCredentialsID: executionCredentialsID,
+		requiredSecrets:        requiredSecretData,
+		credentialsManager:     credentialsManager,
+		appConfigClientCreator: appConfigClientCreator,
+	}
+	require.NoError(t, appConfigRes.Create())
+
+	value1, ok := appConfigRes.GetCachedSecretValue(secretKeyWest1)
+	require.True(t, ok)
+	assert.Equal(t, secretValue, value1)
+
+	value2, ok := appConfigRes.GetCachedSecretValue(secretKeyEast1)
+	require.True(t, ok)
+	assert.Equal(t, secretValue, value2)
+}
+
+func TestCreateReturnError(t *testing.T) {
+	requiredSecretData := map[string][]apicontainer.Secret{
+		region1: {
+			{
+				Name:      secretName1,
+				ValueFrom: valueFrom1,
+				Region:    region1,
+				Provider:  apicontainer.SecretProviderAppConfig,
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	credentialsManager := mock_credentials.NewMockManager(ctrl)
+	appConfigClientCreator := mock_factory.NewMockAppConfigClientCreator(ctrl)
+	mockAppConfigClient := mock_appconfig.NewMockAppConfigClient(ctrl)
+
+	iamRoleCreds := credentials.IAMRoleCredentials{}
+	creds := credentials.TaskIAMRoleCredentials{
+		IAMRoleCredentials: iamRoleCreds,
+	}
+
+	credentialsManager.EXPECT().GetTaskCredentials(executionCredentialsID).Return(creds, true)
+	appConfigClientCreator.EXPECT().NewAppConfigClient(region1, iamRoleCreds).Return(mockAppConfigClient)
+	mockAppConfigClient.EXPECT().StartConfigurationSession(gomock.Any()).Return(nil, errors.New("error"))
+
+	appConfigRes := &AppConfigSecretResource{
+		executionCredentialsID: executionCredentialsID,
+		requiredSecrets:        requiredSecretData,
+		credentialsManager:     credentialsManager,
+		appConfigClientCreator: appConfigClientCreator,
+	}
+	assert.Error(t, appConfigRes.Create())
+	assert.Contains(t, appConfigRes.GetTerminalReason(), "fetching secret data from AppConfig in us-west-2")
+}
+
+func TestCreateWithoutExecutionCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	credentialsManager := mock_credentials.NewMockManager(ctrl)
+	credentialsManager.EXPECT().GetTaskCredentials(executionCredentialsID).Return(
+		credentials.TaskIAMRoleCredentials{}, false)
+
+	appConfigRes := &AppConfigSecretResource{
+		executionCredentialsID: executionCredentialsID,
+		credentialsManager:     credentialsManager,
+	}
+	assert.Error(t, appConfigRes.Create())
+	assert.NotEmpty(t, appConfigRes.GetTerminalReason())
+}
+
+func TestMarshalUnmarshalJSON(t *testing.T) {
+	requiredSecretData := map[string][]apicontainer.Secret{
+		region1: {
+			{
+				Name:      secretName1,
+				ValueFrom: valueFrom1,
+				Region:    region1,
+				Provider:  apicontainer.SecretProviderAppConfig,
+			},
+		},
+	}
+
+	appConfigResIn := &AppConfigSecretResource{
+		taskARN:                taskARN,
+		executionCredentialsID: executionCredentialsID,
+		createdAt:              time.Now(),
+		knownStatusUnsafe:      resourcestatus.ResourceCreated,
+		desiredStatusUnsafe:    resourcestatus.ResourceCreated,
+		requiredSecrets:        requiredSecretData,
+	}
+
+	bytes, err := json.Marshal(appConfigResIn)
+	require.NoError(t, err)
+
+	appConfigResOut := &AppConfigSecretResource{}
+	err = json.Unmarshal(bytes, appConfigResOut)
+	require.NoError(t, err)
+	assert.Equal(t, appConfigResIn.taskARN, appConfigResOut.taskARN)
+	assert.WithinDuration(t, appConfigResIn.createdAt, appConfigResOut.createdAt, time.Microsecond)
+	assert.Equal(t, appConfigResIn.desiredStatusUnsafe, appConfigResOut.desiredStatusUnsafe)
+	assert.Equal(t, appConfigResIn.knownStatusUnsafe, appConfigResOut.knownStatusUnsafe)
+	assert.Equal(t, appConfigResIn.executionCredentialsID, appConfigResOut.executionCredentialsID)
+	assert.Equal(t, appConfigResIn.requiredSecrets, appConfigResOut.requiredSecrets)
+}
+
+func TestInitialize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	credentialsManager := mock_credentials.NewMockManager(ctrl)
+	appConfigClientCreator := mock_factory.NewMockAppConfigClientCreator(ctrl)
+	appConfigRes := &AppConfigSecretResource{
+		knownStatusUnsafe:   resourcestatus.ResourceCreated,
+		desiredStatusUnsafe: resourcestatus.ResourceCreated,
+	}
+	appConfigRes.Initialize(&taskresource.ResourceFields{
+		ResourceFieldsCommon: &taskresource.ResourceFieldsCommon{
+			AppConfigClientCreator: appConfigClientCreator,
+			CredentialsManager:     credentialsManager,
+		},
+	}, apitaskstatus.TaskStatusNone, apitaskstatus.TaskRunning)
+	assert.Equal(t, resourcestatus.ResourceStatusNone, appConfigRes.GetKnownStatus())
+	assert.Equal(t, resourcestatus.ResourceCreated, appConfigRes.GetDesiredStatus())
+	assert.Equal(t, appConfigClientCreator, appConfigRes.appConfigClientCreator)
+}
+
+func TestClearAppConfigSecretValue(t *testing.T) {
+	appConfigRes := &AppConfigSecretResource{
+		secretData: map[string]string{
+			secretKeyWest1: secretValue,
+		},
+	}
+	appConfigRes.clearAppConfigSecretValue()
+	assert.Equal(t, 0, len(appConfigRes.secretData))
+}
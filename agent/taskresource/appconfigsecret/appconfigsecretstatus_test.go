@@ -0,0 +1,158 @@
+//go:build unit
+// +build unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package appconfigsecret
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusString(t *testing.T) {
+	cases := []struct {
+		Name                     string
+		InAppConfigSecretStatus  AppConfigSecretStatus
+		OutAppConfigSecretStatus string
+	}{
+		{
+			Name:                     "ToStringAppConfigSecretStatusNone",
+			InAppConfigSecretStatus:  AppConfigSecretStatusNone,
+			OutAppConfigSecretStatus: "NONE",
+		},
+		{
+			Name:                     "ToStringAppConfigSecretCreated",
+			InAppConfigSecretStatus:  AppConfigSecretCreated,
+			OutAppConfigSecretStatus: "CREATED",
+		},
+		{
+			Name:                     "ToStringAppConfigSecretRemoved",
+			InAppConfigSecretStatus:  AppConfigSecretRemoved,
+			OutAppConfigSecretStatus: "REMOVED",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			assert.Equal(t, c.OutAppConfigSecretStatus, c.InAppConfigSecretStatus.String())
+		})
+	}
+}
+
+func TestMarshalNilAppConfigSecretStatus(t *testing.T) {
+	var status *AppConfigSecretStatus
+	bytes, err := status.MarshalJSON()
+
+	assert.Nil(t, bytes)
+	assert.Error(t, err)
+}
+
+func TestMarshalAppConfigSecretStatus(t *testing.T) {
+	cases := []struct {
+		Name                     string
+		InAppConfigSecretStatus  AppConfigSecretStatus
+		OutAppConfigSecretStatus string
+	}{
+		{
+			Name:                     "MarshallAppConfigSecretStatusNone",
+			InAppConfigSecretStatus:  AppConfigSecretStatusNone,
+			OutAppConfigSecretStatus: "\"NONE\"",
+		},
+		{
+			Name:                     "MarshallAppConfigSecretCreated",
+			InAppConfigSecretStatus:  AppConfigSecretCreated,
+			OutAppConfigSecretStatus: "\"CREATED\"",
+		},
+		{
+			Name:                     "MarshallAppConfigSecretRemoved",
+			InAppConfigSecretStatus:  AppConfigSecretRemoved,
+			OutAppConfigSecretStatus: "\"REMOVED\"",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			bytes, err := c.InAppConfigSecretStatus.MarshalJSON()
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.OutAppConfigSecretStatus, string(bytes[:]))
+		})
+	}
+
+}
+
+func TestUnmarshalAppConfigSecretStatus(t *testing.T) {
+	cases := []struct {
+		Name                     string
+		InAppConfigSecretStatus  string
+		OutAppConfigSecretStatus AppConfigSecretStatus
+		ShouldError              bool
+	}{
+		{
+			Name:                     "UnmarshallAppConfigSecretStatusNone",
+			InAppConfigSecretStatus:  "\"NONE\"",
+			OutAppConfigSecretStatus: AppConfigSecretStatusNone,
+			ShouldError:              false,
+		},
+		{
+			Name:                     "UnmarshallAppConfigSecretCreated",
+			InAppConfigSecretStatus:  "\"CREATED\"",
+			OutAppConfigSecretStatus: AppConfigSecretCreated,
+			ShouldError:              false,
+		},
+		{
+			Name:                     "UnmarshallAppConfigSecretRemoved",
+			InAppConfigSecretStatus:  "\"REMOVED\"",
+			OutAppConfigSecretStatus: AppConfigSecretRemoved,
+			ShouldError:              false,
+		},
+		{
+			Name:                     "UnmarshallAppConfigSecretStatusNull",
+			InAppConfigSecretStatus:  "null",
+			OutAppConfigSecretStatus: AppConfigSecretStatusNone,
+			ShouldError:              false,
+		},
+		{
+			Name:                     "UnmarshallAppConfigSecretStatusNonString",
+			InAppConfigSecretStatus:  "1",
+			OutAppConfigSecretStatus: AppConfigSecretStatusNone,
+			ShouldError:              true,
+		},
+		{
+			Name:                     "UnmarshallAppConfigSecretStatusUnmappedStatus",
+			InAppConfigSecretStatus:  "\"LOL\"",
+			OutAppConfigSecretStatus: AppConfigSecretStatusNone,
+			ShouldError:              true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+
+			var status AppConfigSecretStatus
+			err := json.Unmarshal([]byte(c.InAppConfigSecretStatus), &status)
+
+			if c.ShouldError {
+				assert.Error(t, err)
+			} else {
+
+				assert.NoError(t, err)
+				assert.Equal(t, c.OutAppConfigSecretStatus, status)
+			}
+		})
+	}
+}
@@ -25,6 +25,8 @@ import (
 	apicontainer "github.com/aws/amazon-ecs-agent/agent/api/container"
 	"github.com/aws/amazon-ecs-agent/agent/s3"
 	"github.com/aws/amazon-ecs-agent/agent/s3/factory"
+	"github.com/aws/amazon-ecs-agent/agent/ssm"
+	ssmfactory "github.com/aws/amazon-ecs-agent/agent/ssm/factory"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource"
 	resourcestatus "github.com/aws/amazon-ecs-agent/agent/taskresource/status"
 	"github.com/aws/amazon-ecs-agent/agent/utils/bufiowrapper"
@@ -35,6 +37,7 @@ import (
 	"github.com/aws/amazon-ecs-agent/ecs-agent/credentials"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/cihub/seelog"
 	"github.com/pkg/errors"
 )
@@ -47,6 +50,13 @@ const (
 	commentIndicator     = "#"
 	envVariableDelimiter = "="
 
+	// envFileTypeSSM is the type of an env file stored as a parameter in SSM parameter store
+	envFileTypeSSM = "ssm"
+	// ssmEnvFileDirPath is the directory under the resource directory that env files from SSM are saved to
+	ssmEnvFileDirPath = "ssm"
+	// ssmParameterResourcePrefix is the prefix of the resource section of an SSM parameter ARN
+	ssmParameterResourcePrefix = "parameter"
+
 	renameBackoffMin      = 500 * time.Millisecond
 	renameBackoffMax      = 5 * time.Second
 	renameBackoffJitter   = 0.0
@@ -57,7 +67,7 @@ const (
 )
 
 // EnvironmentFileResource represents envfile as a task resource
-// these environment files are retrieved from s3
+// these environment files are retrieved from s3 or SSM parameter store
 type EnvironmentFileResource struct {
 	cluster       string
 	taskARN       string
@@ -71,6 +81,7 @@ type EnvironmentFileResource struct {
 	executionCredentialsID string
 	credentialsManager     credentials.Manager
 	s3ClientCreator        factory.S3ClientCreator
+	ssmClientCreator       ssmfactory.SSMClientCreator
 	ioutil                 ioutilwrapper.IOUtil
 	bufio                  bufiowrapper.Bufio
 
@@ -97,6 +108,7 @@ func NewEnvironmentFileResource(cluster, taskARN, region, dataDir, containerName
 		ioutil:                 ioutilwrapper.NewIOUtil(),
 		bufio:                  bufiowrapper.NewBufio(),
 		s3ClientCreator:        factory.NewS3ClientCreator(),
+		ssmClientCreator:       ssmfactory.NewSSMClientCreator(),
 		executionCredentialsID: executionCredentialsID,
 		credentialsManager:     credentialsManager,
 	}
@@ -119,6 +131,7 @@ func (envfile *EnvironmentFileResource) Initialize(resourceFields *taskresource.
 	envfile.initStatusToTransition()
 	envfile.credentialsManager = resourceFields.CredentialsManager
 	envfile.s3ClientCreator = factory.NewS3ClientCreator()
+	envfile.ssmClientCreator = ssmfactory.NewSSMClientCreator()
 	envfile.ioutil = ioutilwrapper.NewIOUtil()
 	envfile.bufio = bufiowrapper.NewBufio()
 	envfile.lock.Unlock()
@@ -289,7 +302,7 @@ func (envfile *EnvironmentFileResource) setTerminalReason(reason string) {
 }
 
 // Create performs resource creation. This retrieves env file contents concurrently
-// from s3 and writes them to disk
+// from s3 or SSM parameter store and writes them to disk
 func (envfile *EnvironmentFileResource) Create() error {
 	seelog.Debugf("Creating envfile resource.")
 	// make sure it has the task execution role
@@ -306,9 +319,12 @@ func (envfile *EnvironmentFileResource) Create() error {
 	iamCredentials := executionCredentials.GetIAMRoleCredentials()
 	for _, envfileSource := range envfile.environmentFilesSource {
 		wg.Add(1)
-		// if we support types besides S3 ARN, we will need to add filtering before the below method is called
 		// call an additional go routine per env file
-		go envfile.downloadEnvfileFromS3(envfileSource.Value, iamCredentials, &wg, errorEvents)
+		if envfileSource.Type == envFileTypeSSM {
+			go envfile.downloadEnvfileFromSSM(envfileSource.Value, iamCredentials, &wg, errorEvents)
+		} else {
+			go envfile.downloadEnvfileFromS3(envfileSource.Value, iamCredentials, &wg, errorEvents)
+		}
 	}
 
 	wg.Wait()
@@ -381,6 +397,76 @@ func (envfile *EnvironmentFileResource) downloadEnvfileFromS3(envFilePath string
 	seelog.Debugf("Downloaded envfile from s3 and saved to %s", downloadPath)
 }
 
+func (envfile *EnvironmentFileResource) downloadEnvfileFromSSM(envFilePath string, iamCredentials credentials.IAMRoleCredentials,
+	wg *sync.WaitGroup, errorEvents chan error) {
+	defer wg.Done()
+
+	region, parameterName, err := parseSSMParameter(envFilePath, envfile.region)
+	if err != nil {
+		errorEvents <- fmt.Errorf("unable to parse SSM parameter specified in environmentFile %s, error: %v", envFilePath, err)
+		return
+	}
+
+	ssmClient := envfile.ssmClientCreator.NewSSMClient(region, iamCredentials)
+	parameters, err := ssm.GetSecretsFromSSM([]string{envFilePath}, ssmClient)
+	if err != nil {
+		errorEvents <- fmt.Errorf("unable to retrieve env file parameter %s from SSM, error: %v", envFilePath, err)
+		return
+	}
+	if len(parameters) != 1 {
+		errorEvents <- fmt.Errorf("unable to retrieve env file parameter %s from SSM, expected 1 parameter but got %d",
+			envFilePath, len(parameters))
+		return
+	}
+	var value string
+	for _, parameterValue := range parameters {
+		value = parameterValue
+	}
+
+	err = envfile.createEnvfileDirectory(ssmEnvFileDirPath, parameterName)
+	if err != nil {
+		errorEvents <- fmt.Errorf("unable to initialize envfile resource directory, error: %v", err)
+		return
+	}
+
+	// we save envfiles to path: /var/lib/ecs/data/envfiles/cluster_name/task_id/ssm/${parametername}
+	downloadPath := filepath.Join(envfile.resourceDir, ssmEnvFileDirPath, parameterName)
+	err = envfile.writeEnvFile(func(file oswrapper.File) error {
+		_, err := file.Write([]byte(value))
+		return err
+	}, downloadPath)
+	if err != nil {
+		errorEvents <- fmt.Errorf("unable to write env file from SSM parameter %s, error: %v", parameterName, err)
+		return
+	}
+
+	seelog.Debugf("Retrieved envfile from SSM and saved to %s", downloadPath)
+}
+
+// parseSSMParameter returns the region and name of an SSM parameter specified either by its name or by its ARN.
+// The default region is returned when the parameter is specified by name.
+func parseSSMParameter(parameter, defaultRegion string) (string, string, error) {
+	if !arn.IsARN(parameter) {
+		if parameter == "" {
+			return "", "", errors.New("empty parameter name")
+		}
+		return defaultRegion, parameter, nil
+	}
+
+	parsedARN, err := arn.Parse(parameter)
+	if err != nil {
+		return "", "", err
+	}
+	if parsedARN.Service != envFileTypeSSM || !strings.HasPrefix(parsedARN.Resource, ssmParameterResourcePrefix+"/") {
+		return "", "", errors.Errorf("invalid SSM parameter arn: %s", parameter)
+	}
+	region := parsedARN.Region
+	if region == "" {
+		region = defaultRegion
+	}
+	return region, strings.TrimPrefix(parsedARN.Resource, ssmParameterResourcePrefix), nil
+}
+
 var rename = os.Rename
 
 func (envfile *EnvironmentFileResource) writeEnvFile(writeFunc func(file oswrapper.File) error, fullPathName string) error {
@@ -512,6 +598,16 @@ func (envfile *EnvironmentFileResource) convertEnvfileToPath() ([]string, error)
 	var envfileLocations []string
 
 	for _, envfileObj := range envfile.environmentFilesSource {
+		if envfileObj.Type == envFileTypeSSM {
+			_, parameterName, err := parseSSMParameter(envfileObj.Value, envfile.region)
+			if err != nil {
+				seelog.Errorf("unable to parse SSM parameter specified in environmentFile %s", envfileObj.Value)
+				return nil, err
+			}
+			envfileLocations = append(envfileLocations, filepath.Join(envfile.resourceDir, ssmEnvFileDirPath, parameterName))
+			continue
+		}
+
 		bucket, key, err := s3.ParseS3ARN(envfileObj.Value)
 		if err != nil {
 			seelog.Errorf("unable to parse bucket and key from s3 ARN specified in environmentFile %s", envfileObj.Value)
@@ -27,6 +27,8 @@ import (
 	"github.com/aws/amazon-ecs-agent/agent/api/container"
 	mock_factory "github.com/aws/amazon-ecs-agent/agent/s3/factory/mocks"
 	mock_s3 "github.com/aws/amazon-ecs-agent/agent/s3/mocks/s3manager"
+	mock_ssm_factory "github.com/aws/amazon-ecs-agent/agent/ssm/factory/mocks"
+	mock_ssm "github.com/aws/amazon-ecs-agent/agent/ssm/mocks"
 	"github.com/aws/amazon-ecs-agent/agent/taskresource"
 	mock_bufio "github.com/aws/amazon-ecs-agent/agent/utils/bufiowrapper/mocks"
 	mock_ioutilwrapper "github.com/aws/amazon-ecs-agent/agent/utils/ioutilwrapper/mocks"
@@ -38,6 +40,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	ssmsdk "github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -56,6 +59,8 @@ const (
 	s3File                 = "s3key.env"
 	s3Key                  = s3Path + string(filepath.Separator) + s3File
 	tempFile               = "tmp_file"
+	ssmParameterName       = "/path/to/envfile"
+	ssmParameterARN        = "arn:aws:ssm:us-east-1:123456789012:parameter" + ssmParameterName
 )
 
 func setup(t *testing.T) (oswrapper.File, *mock_ioutilwrapper.MockIOUtil,
@@ -269,6 +274,144 @@ func TestCreateRenameFileError(t *testing.T) {
 	assert.Contains(t, envfileResource.GetTerminalReason(), "error response")
 }
 
+func TestCreateWithEnvVarFileFromSSM(t *testing.T) {
+	mockFile, mockIOUtil, mockCredentialsManager, _, _, done := setup(t)
+	defer done()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSSMClientCreator := mock_ssm_factory.NewMockSSMClientCreator(ctrl)
+	mockSSMClient := mock_ssm.NewMockSSMClient(ctrl)
+
+	envfiles := []container.EnvironmentFile{
+		sampleEnvironmentFile(ssmParameterARN, "ssm"),
+	}
+
+	envfileResource := newMockEnvfileResource(envfiles, mockCredentialsManager, nil, mockIOUtil)
+	envfileResource.ssmClientCreator = mockSSMClientCreator
+	creds := credentials.TaskIAMRoleCredentials{
+		ARN: iamRoleARN,
+		IAMRoleCredentials: credentials.IAMRoleCredentials{
+			AccessKeyID:     accessKeyId,
+			SecretAccessKey: secretAccessKey,
+		},
+	}
+
+	var createdDir, renamedTo string
+	mkdirAll = func(path string, perm os.FileMode) error {
+		createdDir = path
+		return nil
+	}
+	rename = func(oldpath, newpath string) error {
+		renamedTo = newpath
+		return nil
+	}
+	defer func() {
+		mkdirAll = os.MkdirAll
+		rename = os.Rename
+	}()
+
+	gomock.InOrder(
+		mockCredentialsManager.EXPECT().GetTaskCredentials(executionCredentialsID).Return(creds, true),
+		mockSSMClientCreator.EXPECT().NewSSMClient("us-east-1", creds.IAMRoleCredentials).Return(mockSSMClient),
+		mockSSMClient.EXPECT().GetParameters(gomock.Any()).Do(func(input *ssmsdk.GetParametersInput) {
+			assert.Equal(t, []string{ssmParameterARN}, aws.StringValueSlice(input.Names))
+			assert.True(t, aws.BoolValue(input.WithDecryption))
+		}).Return(&ssmsdk.GetParametersOutput{
+			Parameters: []*ssmsdk.Parameter{
+				{
+					Name:  aws.String(ssmParameterName),
+					Value: aws.String("key=value"),
+				},
+			},
+		}, nil),
+		mockIOUtil.EXPECT().TempFile(resourceDir, gomock.Any()).Return(mockFile, nil),
+	)
+
+	assert.NoError(t, envfileResource.Create())
+	assert.Equal(t, filepath.Join(resourceDir, "ssm", "path", "to"), createdDir)
+	assert.Equal(t, filepath.Join(resourceDir, "ssm", "path", "to", "envfile"), renamedTo)
+}
+
+func TestCreateUnableToRetrieveDataFromSSM(t *testing.T) {
+	_, mockIOUtil, mockCredentialsManager, _, _, done := setup(t)
+	defer done()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSSMClientCreator := mock_ssm_factory.NewMockSSMClientCreator(ctrl)
+	mockSSMClient := mock_ssm.NewMockSSMClient(ctrl)
+
+	envfiles := []container.EnvironmentFile{
+		sampleEnvironmentFile(ssmParameterName, "ssm"),
+	}
+
+	envfileResource := newMockEnvfileResource(envfiles, mockCredentialsManager, nil, mockIOUtil)
+	envfileResource.ssmClientCreator = mockSSMClientCreator
+	creds := credentials.TaskIAMRoleCredentials{
+		ARN: iamRoleARN,
+		IAMRoleCredentials: credentials.IAMRoleCredentials{
+			AccessKeyID:     accessKeyId,
+			SecretAccessKey: secretAccessKey,
+		},
+	}
+
+	gomock.InOrder(
+		mockCredentialsManager.EXPECT().GetTaskCredentials(executionCredentialsID).Return(creds, true),
+		mockSSMClientCreator.EXPECT().NewSSMClient(region, creds.IAMRoleCredentials).Return(mockSSMClient),
+		mockSSMClient.EXPECT().GetParameters(gomock.Any()).Return(nil, errors.New("error response")),
+	)
+
+	assert.Error(t, envfileResource.Create())
+	assert.Contains(t, envfileResource.GetTerminalReason(), "error response")
+}
+
+func TestParseSSMParameter(t *testing.T) {
+	testCases := []struct {
+		name           string
+		parameter      string
+		expectedRegion string
+		expectedName   string
+		expectError    bool
+	}{
+		{
+			name:           "parameter name",
+			parameter:      ssmParameterName,
+			expectedRegion: region,
+			expectedName:   ssmParameterName,
+		},
+		{
+			name:           "parameter arn",
+			parameter:      ssmParameterARN,
+			expectedRegion: "us-east-1",
+			expectedName:   ssmParameterName,
+		},
+		{
+			name:        "empty parameter",
+			parameter:   "",
+			expectError: true,
+		},
+		{
+			name:        "arn of another service",
+			parameter:   fmt.Sprintf("arn:aws:s3:::%s/%s", s3Bucket, s3Key),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsedRegion, parsedName, err := parseSSMParameter(tc.parameter, region)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedRegion, parsedRegion)
+			assert.Equal(t, tc.expectedName, parsedName)
+		})
+	}
+}
+
 func TestEnvFileCleanupSuccess(t *testing.T) {
 	_, mockIOUtil, mockCredentialsManager, mockS3ClientCreator, _, done := setup(t)
 	defer done()
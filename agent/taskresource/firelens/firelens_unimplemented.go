@@ -57,6 +57,19 @@ func NewFirelensResource(cluster, taskARN, taskDefinition, ec2InstanceID, dataDi
 	return nil, errors.New("not implemented")
 }
 
+// SetLogRateLimit sets the log rate limit of the resource.
+func (firelens *FirelensResource) SetLogRateLimit(limit int) {}
+
+// OutputNames returns the names of the output plugins of a container's firelens log options.
+func OutputNames(logOptions map[string]string) []string {
+	return nil
+}
+
+// ValidateLogOptions validates the log options of a container using the awsfirelens log driver.
+func ValidateLogOptions(firelensConfigType string, logOptions map[string]string) error {
+	return nil
+}
+
 // SetDesiredStatus safely sets the desired status of the resource.
 func (firelens *FirelensResource) SetDesiredStatus(status resourcestatus.ResourceStatus) {}
 
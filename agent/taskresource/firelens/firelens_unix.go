@@ -18,8 +18,10 @@ package firelens
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -60,8 +62,19 @@ const (
 	// ExternalConfigTypeOption is s3, the value for this option should be an s3 arn; when ExternalConfigTypeOption is
 	// file, the value for this option should be a path to the config file inside the firelens container.
 	externalConfigValueOption = "config-file-value"
+	// deadLetterPathOption is the option that specifies a directory inside the fluentbit firelens container where log
+	// chunks that still can't be delivered after the output's retries are exhausted are kept, instead of dropped.
+	deadLetterPathOption = "dead-letter-path"
 
 	s3DownloadTimeout = 30 * time.Second
+
+	// rateLimitFilterWindow is the number of intervals the fluent-bit throttle filter averages the rate over.
+	rateLimitFilterWindow = 5
+	// rateLimitFilterInterval is the length of each interval of the fluent-bit throttle filter.
+	rateLimitFilterInterval = "1s"
+
+	// deadLetterRejectedPath is the directory under the dead letter path that fluentbit moves rejected chunks to.
+	deadLetterRejectedPath = "rejected"
 )
 
 // FirelensResource models fluentd/fluentbit firelens container related resources as a task resource.
@@ -83,6 +96,8 @@ type FirelensResource struct {
 	networkMode            string
 	ioutil                 ioutilwrapper.IOUtil
 	s3ClientCreator        factory.S3ClientCreator
+	logRateLimit           int
+	deadLetterPath         string
 
 	// Fields for the common functionality of task resource. Access to these fields are protected by lock.
 	createdAtUnsafe     time.Time
@@ -127,6 +142,13 @@ func NewFirelensResource(cluster, taskARN, taskDefinition, ec2InstanceID, dataDi
 	return firelensResource, nil
 }
 
+// SetLogRateLimit sets the maximum number of log records per second that each container is allowed to send
+// through the firelens container. A value of 0 disables rate limiting. It must be called before the resource is
+// created.
+func (firelens *FirelensResource) SetLogRateLimit(limit int) {
+	firelens.logRateLimit = limit
+}
+
 func (firelens *FirelensResource) parseOptions(options map[string]string) error {
 	if _, ok := options[ecsLogMetadataEnableOption]; ok {
 		val := options[ecsLogMetadataEnableOption]
@@ -153,6 +175,17 @@ func (firelens *FirelensResource) parseOptions(options map[string]string) error
 		firelens.externalConfigValue = externalConfigValue
 	}
 
+	if deadLetterPath, ok := options[deadLetterPathOption]; ok {
+		if !DeadLetterSupported(firelens.firelensConfigType) {
+			return errors.Errorf("option %s is not supported for firelens config type %s",
+				deadLetterPathOption, firelens.firelensConfigType)
+		}
+		if deadLetterPath == "" {
+			return errors.Errorf("empty value is specified for option %s", deadLetterPathOption)
+		}
+		firelens.deadLetterPath = deadLetterPath
+	}
+
 	return nil
 }
 
@@ -431,6 +464,47 @@ func (firelens *FirelensResource) Create() error {
 
 var mkdirAll = os.MkdirAll
 
+// writeRateLimitFilters appends a fluent-bit throttle filter for each container that sends logs through firelens,
+// so that a single noisy container can't exceed the configured log rate limit. Nothing is written if rate limiting
+// is disabled.
+func (firelens *FirelensResource) writeRateLimitFilters(w io.Writer) error {
+	if firelens.logRateLimit <= 0 {
+		return nil
+	}
+
+	containerNames := make([]string, 0, len(firelens.containerToLogOptions))
+	for containerName := range firelens.containerToLogOptions {
+		containerNames = append(containerNames, containerName)
+	}
+	sort.Strings(containerNames)
+
+	for _, containerName := range containerNames {
+		tag := fmt.Sprintf(fluentTagOutputFormat, containerName, matchAnyWildcardFluentbit)
+		_, err := fmt.Fprintf(w, "\n[FILTER]\n    Name throttle\n    Match %s\n    Rate %d\n    Window %d\n    Interval %s\n",
+			tag, firelens.logRateLimit, rateLimitFilterWindow, rateLimitFilterInterval)
+		if err != nil {
+			return errors.Wrapf(err, "unable to write rate limit filter for container %s", containerName)
+		}
+	}
+	return nil
+}
+
+// writeDeadLetterService appends a fluent-bit service section that keeps the chunks an output rejects after its
+// retries are exhausted under the dead letter path, rather than discarding them. Nothing is written if no dead
+// letter path is configured.
+func (firelens *FirelensResource) writeDeadLetterService(w io.Writer) error {
+	if firelens.deadLetterPath == "" {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(w, "\n[SERVICE]\n    storage.path %s\n    storage.keep.rejected On\n    storage.rejected.path %s\n",
+		firelens.deadLetterPath, deadLetterRejectedPath)
+	if err != nil {
+		return errors.Wrap(err, "unable to write dead letter service section")
+	}
+	return nil
+}
+
 // createDirectories creates two directories:
 //   - $(DATA_DIR)/firelens/$(TASK_ID)/config: used to store firelens config file. The config file under this directory
 //     will be mounted to the firelens container at an expected path.
@@ -470,7 +544,13 @@ func (firelens *FirelensResource) generateConfigFile() error {
 		if firelens.firelensConfigType == FirelensConfigTypeFluentd {
 			return config.WriteFluentdConfig(file)
 		} else {
-			return config.WriteFluentBitConfig(file)
+			if err := config.WriteFluentBitConfig(file); err != nil {
+				return err
+			}
+			if err := firelens.writeRateLimitFilters(file); err != nil {
+				return err
+			}
+			return firelens.writeDeadLetterService(file)
 		}
 	}, confFilePath)
 	if err != nil {
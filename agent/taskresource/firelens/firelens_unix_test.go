@@ -153,6 +153,31 @@ func TestParseOptionsNoValue(t *testing.T) {
 	assert.Error(t, firelensResource.parseOptions(options))
 }
 
+func TestParseOptionsDeadLetterPath(t *testing.T) {
+	options := map[string]string{
+		"dead-letter-path": "/fluent-bit/dead-letter",
+	}
+	firelensResource := FirelensResource{firelensConfigType: FirelensConfigTypeFluentbit}
+	assert.NoError(t, firelensResource.parseOptions(options))
+	assert.Equal(t, "/fluent-bit/dead-letter", firelensResource.deadLetterPath)
+}
+
+func TestParseOptionsDeadLetterPathEmpty(t *testing.T) {
+	options := map[string]string{
+		"dead-letter-path": "",
+	}
+	firelensResource := FirelensResource{firelensConfigType: FirelensConfigTypeFluentbit}
+	assert.Error(t, firelensResource.parseOptions(options))
+}
+
+func TestParseOptionsDeadLetterPathFluentd(t *testing.T) {
+	options := map[string]string{
+		"dead-letter-path": "/fluentd/dead-letter",
+	}
+	firelensResource := FirelensResource{firelensConfigType: FirelensConfigTypeFluentd}
+	assert.Error(t, firelensResource.parseOptions(options))
+}
+
 func TestCreateFirelensResourceFluentdBridgeMode(t *testing.T) {
 	mockFile, mockIOUtil, mockCredentialsManager, mockS3ClientCreator, _, done := setup(t)
 	defer done()
@@ -18,6 +18,8 @@ package firelens
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/cihub/seelog"
 	"github.com/pkg/errors"
@@ -108,6 +110,28 @@ const (
 	// inputPortOptionFluentbit is the key for the log option that specifies port for fluentbit.
 	inputPortOptionFluentbit = "Port"
 
+	// outputNameOTLPFluentbit is the name of the fluentbit output plugin that sends logs to an OpenTelemetry
+	// collector over OTLP/HTTP.
+	outputNameOTLPFluentbit = "opentelemetry"
+
+	// otlpLogsURIOptionFluentbit is the key for specifying the collector path that logs are sent to.
+	otlpLogsURIOptionFluentbit = "logs_uri"
+
+	// otlpLogsURIDefault is the default OTLP/HTTP path for logs.
+	otlpLogsURIDefault = "/v1/logs"
+
+	// additionalOutputOptionPrefix is the prefix of the log options that specify additional output sections
+	// for a container's logs. The options are in the form of "output.<id>.<key>", where id distinguishes the
+	// additional outputs from each other and key is an option of the output plugin.
+	additionalOutputOptionPrefix = "output."
+
+	// inputStorageTypeOptionFluentbit is the key for specifying how fluentbit buffers the chunks of an input.
+	inputStorageTypeOptionFluentbit = "storage.type"
+
+	// inputStorageTypeFilesystem buffers an input's chunks on disk, which is required for chunks rejected by an
+	// output to be kept in the dead letter path.
+	inputStorageTypeFilesystem = "filesystem"
+
 	// bridgeNetworkMode specifies bridge type mode for a task
 	bridgeNetworkMode = "bridge"
 
@@ -115,6 +139,60 @@ const (
 	awsvpcNetworkMode = "awsvpc"
 )
 
+// OTLPOutputSupported returns whether the generated config of the given firelens type can route logs to
+// an OpenTelemetry collector. Only fluentbit ships an OTLP output plugin.
+func OTLPOutputSupported(firelensConfigType string) bool {
+	return firelensConfigType == FirelensConfigTypeFluentbit
+}
+
+// MultipleOutputsSupported returns whether the generated config of the given firelens type can route a container's
+// logs to more than one output. Fluentd only routes a record to the first output that matches its tag.
+func MultipleOutputsSupported(firelensConfigType string) bool {
+	return firelensConfigType == FirelensConfigTypeFluentbit
+}
+
+// DeadLetterSupported returns whether the generated config of the given firelens type can keep the logs that
+// can't be delivered after retries in a dead letter path. Only fluentbit can keep rejected chunks on disk.
+func DeadLetterSupported(firelensConfigType string) bool {
+	return firelensConfigType == FirelensConfigTypeFluentbit
+}
+
+// ValidateLogOptions validates that the log options of a container using the awsfirelens log driver form a valid
+// output configuration for a firelens container of the given config type.
+func ValidateLogOptions(firelensConfigType string, logOptions map[string]string) error {
+	_, err := addOutputSection("validation", firelensConfigType, logOptions, generator.New())
+	return err
+}
+
+// OutputNames returns the names of the output plugins a container's firelens log options route its logs to,
+// the main output first followed by the additional outputs. Plugin options are left out since they may contain
+// credentials or endpoints.
+func OutputNames(logOptions map[string]string) []string {
+	var names []string
+	for _, outputKey := range []string{outputTypeLogOptionKeyFluentbit, outputTypeLogOptionKeyFluentd} {
+		if name, ok := logOptions[outputKey]; ok {
+			names = append(names, name)
+		}
+	}
+
+	additionalOutputNames := make(map[string]string)
+	for key, value := range logOptions {
+		id, option, ok := parseAdditionalOutputOption(key)
+		if ok && (option == outputTypeLogOptionKeyFluentbit || option == outputTypeLogOptionKeyFluentd) {
+			additionalOutputNames[id] = value
+		}
+	}
+	ids := make([]string, 0, len(additionalOutputNames))
+	for id := range additionalOutputNames {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		names = append(names, additionalOutputNames[id])
+	}
+	return names
+}
+
 // generateConfig generates a FluentConfig object that contains all necessary information to construct
 // a fluentd or fluentbit config file for a firelens container.
 func (firelens *FirelensResource) generateConfig() (generator.FluentConfig, error) {
@@ -132,9 +210,11 @@ func (firelens *FirelensResource) generateConfig() (generator.FluentConfig, erro
 		inputPathOption = socketInputPathOptionFluentbit
 		matchAnyWildcard = matchAnyWildcardFluentbit
 	}
-	config.AddInput(inputName, "", map[string]string{
+	socketInputMap := map[string]string{
 		inputPathOption: socketPath,
-	})
+	}
+	firelens.addDeadLetterInputOptions(socketInputMap)
+	config.AddInput(inputName, "", socketInputMap)
 	// Specify log stream input of tcp socket kind that can be used for communication between the Firelens
 	// container and other containers if the network is bridge or awsvpc mode. Also add health check sections to support
 	// doing container health check on firlens container for these two modes.
@@ -159,6 +239,7 @@ func (firelens *FirelensResource) generateConfig() (generator.FluentConfig, erro
 				inputListenOptionFluentbit: inputBindValue,
 			}
 		}
+		firelens.addDeadLetterInputOptions(inputMap)
 		config.AddInput(inputName, "", inputMap)
 
 		firelens.addHealthcheckSections(config)
@@ -202,6 +283,15 @@ func (firelens *FirelensResource) generateConfig() (generator.FluentConfig, erro
 	return config, nil
 }
 
+// addDeadLetterInputOptions makes an input buffer its chunks on disk when a dead letter path is configured, so that
+// chunks an output rejects can be kept there.
+func (firelens *FirelensResource) addDeadLetterInputOptions(inputOptions map[string]string) {
+	if firelens.deadLetterPath == "" {
+		return
+	}
+	inputOptions[inputStorageTypeOptionFluentbit] = inputStorageTypeFilesystem
+}
+
 // addHealthcheckSections adds a health check input section and a health check output section to the config.
 func (firelens *FirelensResource) addHealthcheckSections(config generator.FluentConfig) {
 	// Health check supported is only added for fluentbit.
@@ -230,6 +320,8 @@ func (firelens *FirelensResource) addHealthcheckSections(config generator.Fluent
 //  3. exclude-pattern (optional): a regex specifying the logs to be excluded.
 //  4. All other key-value pairs are customer specified options for the plugin. They are unique for each plugin and
 //     we don't check them.
+//  5. Options of additional outputs the logs are also sent to (optional), in the form of "output.<id>.<key>". Each
+//     additional output requires its own output plugin name, e.g. "output.<id>.Name" for fluentbit.
 func addOutputSection(tag, firelensConfigType string, logOptions map[string]string, config generator.FluentConfig) (generator.FluentConfig, error) {
 	var outputKey string
 	if firelensConfigType == FirelensConfigTypeFluentd {
@@ -239,7 +331,15 @@ func addOutputSection(tag, firelensConfigType string, logOptions map[string]stri
 	}
 
 	outputOptions := make(map[string]string)
+	additionalOutputOptions := make(map[string]map[string]string)
 	for key, value := range logOptions {
+		if id, option, ok := parseAdditionalOutputOption(key); ok {
+			if additionalOutputOptions[id] == nil {
+				additionalOutputOptions[id] = make(map[string]string)
+			}
+			additionalOutputOptions[id][option] = value
+			continue
+		}
 		switch key {
 		case outputKey:
 			continue
@@ -258,11 +358,72 @@ func addOutputSection(tag, firelensConfigType string, logOptions map[string]stri
 		return config, errors.New(
 			fmt.Sprintf("missing output key %s which is required for firelens configuration of type %s",
 				outputKey, firelensConfigType))
-	} else if !ok { // Otherwise it's ok to not generate an output section, since customers may specify the output in external config.
+	}
+	// Otherwise it's ok to not generate an output section, since customers may specify the output in external config.
+	if ok {
+		if output == outputNameOTLPFluentbit && OTLPOutputSupported(firelensConfigType) {
+			addOTLPOutputDefaults(outputOptions)
+		}
+		// Output key is specified. Add an output section.
+		config.AddOutput(output, tag, outputOptions)
+	}
+
+	return addAdditionalOutputSections(tag, firelensConfigType, outputKey, additionalOutputOptions, config)
+}
+
+// addAdditionalOutputSections adds an output section for each additional output of a container's logs, in the
+// order of their ids so that the generated config is stable.
+func addAdditionalOutputSections(tag, firelensConfigType, outputKey string,
+	additionalOutputOptions map[string]map[string]string, config generator.FluentConfig) (generator.FluentConfig, error) {
+	if len(additionalOutputOptions) == 0 {
 		return config, nil
 	}
+	if !MultipleOutputsSupported(firelensConfigType) {
+		return config, errors.Errorf("multiple outputs are not supported for firelens configuration of type %s",
+			firelensConfigType)
+	}
 
-	// Output key is specified. Add an output section.
-	config.AddOutput(output, tag, outputOptions)
+	ids := make([]string, 0, len(additionalOutputOptions))
+	for id := range additionalOutputOptions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		outputOptions := additionalOutputOptions[id]
+		output, ok := outputOptions[outputKey]
+		if !ok {
+			return config, errors.Errorf("missing output key %s%s.%s which is required for additional output %s",
+				additionalOutputOptionPrefix, id, outputKey, id)
+		}
+		delete(outputOptions, outputKey)
+		if output == outputNameOTLPFluentbit && OTLPOutputSupported(firelensConfigType) {
+			addOTLPOutputDefaults(outputOptions)
+		}
+		config.AddOutput(output, tag, outputOptions)
+	}
 	return config, nil
 }
+
+// parseAdditionalOutputOption parses a log option key in the form of "output.<id>.<key>" into the id of the
+// additional output and the key of the option.
+func parseAdditionalOutputOption(logOptionKey string) (string, string, bool) {
+	if !strings.HasPrefix(logOptionKey, additionalOutputOptionPrefix) {
+		return "", "", false
+	}
+	id, option, found := strings.Cut(strings.TrimPrefix(logOptionKey, additionalOutputOptionPrefix), ".")
+	if !found || id == "" || option == "" {
+		return "", "", false
+	}
+	return id, option, true
+}
+
+// addOTLPOutputDefaults sets the options of an OTLP output section that the customer did not specify. Fluentbit
+// option keys are case insensitive.
+func addOTLPOutputDefaults(outputOptions map[string]string) {
+	for key := range outputOptions {
+		if strings.EqualFold(key, otlpLogsURIOptionFluentbit) {
+			return
+		}
+	}
+	outputOptions[otlpLogsURIOptionFluentbit] = otlpLogsURIDefault
+}
@@ -218,6 +218,26 @@ var (
     Match container-firelens*
     deliver_stream_name my-stream
     region us-west-2
+`
+	testFluentbitOTLPOptions = map[string]string{
+		"Name": "opentelemetry",
+		"Host": "otel-collector",
+		"Port": "4318",
+	}
+
+	expectedFluentbitOTLPConfig = `
+[INPUT]
+    Name forward
+    unix_path /var/run/fluent.sock
+
+@INCLUDE /tmp/dummy.conf
+
+[OUTPUT]
+    Name opentelemetry
+    Match container-firelens*
+    Host otel-collector
+    Port 4318
+    logs_uri /v1/logs
 `
 	expectedFluentdConfigWithoutECSMetadata = `
 <source>
@@ -451,3 +471,308 @@ func TestGenerateConfigWithoutOutputSection(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedFluentbitConfigWithoutOutputSection, configBytes.String())
 }
+
+func TestGenerateFluentbitOTLPConfig(t *testing.T) {
+	containerToLogOptions := map[string]map[string]string{
+		"container": testFluentbitOTLPOptions,
+	}
+	testFirelensOptions := map[string]string{
+		"enable-ecs-log-metadata": "false",
+		"config-file-type":        "file",
+		"config-file-value":       "/tmp/dummy.conf",
+	}
+
+	firelensResource, err := NewFirelensResource(testCluster, testTaskARN, testTaskDefinition, testEC2InstanceID,
+		testDataDir, FirelensConfigTypeFluentbit, testRegion, "", testFirelensOptions, containerToLogOptions,
+		nil, testExecutionCredentialsID)
+	require.NoError(t, err)
+
+	config, err := firelensResource.generateConfig()
+	assert.NoError(t, err)
+
+	configBytes := new(bytes.Buffer)
+	err = config.WriteFluentBitConfig(configBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedFluentbitOTLPConfig, configBytes.String())
+}
+
+func TestGenerateFluentbitOTLPConfigWithLogsURI(t *testing.T) {
+	containerToLogOptions := map[string]map[string]string{
+		"container": {
+			"Name":     "opentelemetry",
+			"Host":     "otel-collector",
+			"Port":     "4318",
+			"Logs_uri": "/custom/logs",
+		},
+	}
+
+	firelensResource, err := NewFirelensResource(testCluster, testTaskARN, testTaskDefinition, testEC2InstanceID,
+		testDataDir, FirelensConfigTypeFluentbit, testRegion, "", testFirelensOptionsFile, containerToLogOptions,
+		nil, testExecutionCredentialsID)
+	require.NoError(t, err)
+
+	config, err := firelensResource.generateConfig()
+	assert.NoError(t, err)
+
+	configBytes := new(bytes.Buffer)
+	err = config.WriteFluentBitConfig(configBytes)
+	assert.NoError(t, err)
+	assert.Contains(t, configBytes.String(), "Logs_uri /custom/logs")
+	assert.NotContains(t, configBytes.String(), otlpLogsURIDefault)
+}
+
+func TestGenerateFluentdConfigOTLPOutputUnchanged(t *testing.T) {
+	containerToLogOptions := map[string]map[string]string{
+		"container": {
+			"@type": "opentelemetry",
+		},
+	}
+
+	firelensResource, err := NewFirelensResource(testCluster, testTaskARN, testTaskDefinition, testEC2InstanceID,
+		testDataDir, FirelensConfigTypeFluentd, testRegion, "", testFirelensOptionsFile, containerToLogOptions,
+		nil, testExecutionCredentialsID)
+	require.NoError(t, err)
+
+	config, err := firelensResource.generateConfig()
+	assert.NoError(t, err)
+
+	configBytes := new(bytes.Buffer)
+	err = config.WriteFluentdConfig(configBytes)
+	assert.NoError(t, err)
+	assert.NotContains(t, configBytes.String(), otlpLogsURIOptionFluentbit)
+}
+
+func TestOTLPOutputSupported(t *testing.T) {
+	assert.True(t, OTLPOutputSupported(FirelensConfigTypeFluentbit))
+	assert.False(t, OTLPOutputSupported(FirelensConfigTypeFluentd))
+}
+
+func TestGenerateFluentbitMultiOutputConfig(t *testing.T) {
+	containerToLogOptions := map[string]map[string]string{
+		"container": {
+			"Name":             "cloudwatch_logs",
+			"region":           "us-west-2",
+			"output.s3.Name":   "s3",
+			"output.s3.bucket": "my-bucket",
+			"output.otel.Name": "opentelemetry",
+			"output.otel.Host": "otel-collector",
+		},
+	}
+
+	firelensResource, err := NewFirelensResource(testCluster, testTaskARN, testTaskDefinition, testEC2InstanceID,
+		testDataDir, FirelensConfigTypeFluentbit, testRegion, "", testFirelensOptionsFile, containerToLogOptions,
+		nil, testExecutionCredentialsID)
+	require.NoError(t, err)
+
+	config, err := firelensResource.generateConfig()
+	require.NoError(t, err)
+
+	configBytes := new(bytes.Buffer)
+	require.NoError(t, config.WriteFluentBitConfig(configBytes))
+	assert.Contains(t, configBytes.String(), `
+[OUTPUT]
+    Name cloudwatch_logs
+    Match container-firelens*
+    region us-west-2
+
+[OUTPUT]
+    Name opentelemetry
+    Match container-firelens*
+    Host otel-collector
+    logs_uri /v1/logs
+
+[OUTPUT]
+    Name s3
+    Match container-firelens*
+    bucket my-bucket
+`)
+}
+
+func TestGenerateFluentbitMultiOutputConfigMissingOutputName(t *testing.T) {
+	containerToLogOptions := map[string]map[string]string{
+		"container": {
+			"Name":             "cloudwatch_logs",
+			"output.s3.bucket": "my-bucket",
+		},
+	}
+
+	firelensResource, err := NewFirelensResource(testCluster, testTaskARN, testTaskDefinition, testEC2InstanceID,
+		testDataDir, FirelensConfigTypeFluentbit, testRegion, "", testFirelensOptionsFile, containerToLogOptions,
+		nil, testExecutionCredentialsID)
+	require.NoError(t, err)
+
+	_, err = firelensResource.generateConfig()
+	assert.Error(t, err)
+}
+
+func TestGenerateFluentdMultiOutputConfigUnsupported(t *testing.T) {
+	containerToLogOptions := map[string]map[string]string{
+		"container": {
+			"@type":               "kinesis_firehose",
+			"output.s3.@type":     "s3",
+			"output.s3.s3_bucket": "my-bucket",
+		},
+	}
+
+	firelensResource, err := NewFirelensResource(testCluster, testTaskARN, testTaskDefinition, testEC2InstanceID,
+		testDataDir, FirelensConfigTypeFluentd, testRegion, "", testFirelensOptionsFile, containerToLogOptions,
+		nil, testExecutionCredentialsID)
+	require.NoError(t, err)
+
+	_, err = firelensResource.generateConfig()
+	assert.Error(t, err)
+}
+
+func TestParseAdditionalOutputOption(t *testing.T) {
+	testCases := []struct {
+		key            string
+		expectedID     string
+		expectedOption string
+		expectedOK     bool
+	}{
+		{key: "output.s3.bucket", expectedID: "s3", expectedOption: "bucket", expectedOK: true},
+		{key: "output.s3.storage.total_limit_size", expectedID: "s3", expectedOption: "storage.total_limit_size", expectedOK: true},
+		{key: "net.keepalive"},
+		{key: "output.s3"},
+		{key: "output..Name"},
+		{key: "output.s3."},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			id, option, ok := parseAdditionalOutputOption(tc.key)
+			assert.Equal(t, tc.expectedID, id)
+			assert.Equal(t, tc.expectedOption, option)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
+
+func TestOutputNames(t *testing.T) {
+	assert.Equal(t, []string{"cloudwatch_logs", "opentelemetry", "s3"}, OutputNames(map[string]string{
+		"Name":             "cloudwatch_logs",
+		"region":           "us-west-2",
+		"output.s3.Name":   "s3",
+		"output.s3.bucket": "my-bucket",
+		"output.otel.Name": "opentelemetry",
+		"output.otel.Host": "otel-collector",
+	}))
+	assert.Equal(t, []string{"kinesis_firehose"}, OutputNames(testFluentdOptions))
+	assert.Empty(t, OutputNames(map[string]string{"include-pattern": "*failure*"}))
+}
+
+func TestMultipleOutputsSupported(t *testing.T) {
+	assert.True(t, MultipleOutputsSupported(FirelensConfigTypeFluentbit))
+	assert.False(t, MultipleOutputsSupported(FirelensConfigTypeFluentd))
+}
+
+func TestWriteRateLimitFilters(t *testing.T) {
+	containerToLogOptions := map[string]map[string]string{
+		"container-b": testFluentbitOptions,
+		"container-a": testFluentbitOptions,
+	}
+
+	firelensResource, err := NewFirelensResource(testCluster, testTaskARN, testTaskDefinition, testEC2InstanceID,
+		testDataDir, FirelensConfigTypeFluentbit, testRegion, bridgeNetworkMode, testFirelensOptionsFile, containerToLogOptions,
+		nil, testExecutionCredentialsID)
+	require.NoError(t, err)
+
+	configBytes := new(bytes.Buffer)
+	require.NoError(t, firelensResource.writeRateLimitFilters(configBytes))
+	assert.Empty(t, configBytes.String(), "no filters should be written when rate limiting is disabled")
+
+	firelensResource.SetLogRateLimit(100)
+	require.NoError(t, firelensResource.writeRateLimitFilters(configBytes))
+	assert.Equal(t, `
+[FILTER]
+    Name throttle
+    Match container-a-firelens*
+    Rate 100
+    Window 5
+    Interval 1s
+
+[FILTER]
+    Name throttle
+    Match container-b-firelens*
+    Rate 100
+    Window 5
+    Interval 1s
+`, configBytes.String())
+}
+
+func TestDeadLetterSupported(t *testing.T) {
+	assert.True(t, DeadLetterSupported(FirelensConfigTypeFluentbit))
+	assert.False(t, DeadLetterSupported(FirelensConfigTypeFluentd))
+}
+
+func TestGenerateFluentbitDeadLetterConfig(t *testing.T) {
+	containerToLogOptions := map[string]map[string]string{
+		"container": {
+			"Name":   "cloudwatch",
+			"region": "us-west-2",
+		},
+	}
+	firelensOptions := map[string]string{
+		"enable-ecs-log-metadata": "false",
+		"dead-letter-path":        "/fluent-bit/dead-letter",
+	}
+
+	firelensResource, err := NewFirelensResource(testCluster, testTaskARN, testTaskDefinition, testEC2InstanceID,
+		testDataDir, FirelensConfigTypeFluentbit, testRegion, awsvpcNetworkMode, firelensOptions, containerToLogOptions,
+		nil, testExecutionCredentialsID)
+	require.NoError(t, err)
+
+	config, err := firelensResource.generateConfig()
+	require.NoError(t, err)
+
+	configBytes := new(bytes.Buffer)
+	require.NoError(t, config.WriteFluentBitConfig(configBytes))
+	require.NoError(t, firelensResource.writeDeadLetterService(configBytes))
+	assert.Equal(t, `
+[INPUT]
+    Name forward
+    storage.type filesystem
+    unix_path /var/run/fluent.sock
+
+[INPUT]
+    Name forward
+    Listen 127.0.0.1
+    Port 24224
+    storage.type filesystem
+
+[INPUT]
+    Name tcp
+    Tag firelens-healthcheck
+    Listen 127.0.0.1
+    Port 8877
+
+[OUTPUT]
+    Name null
+    Match firelens-healthcheck
+
+[OUTPUT]
+    Name cloudwatch
+    Match container-firelens*
+    region us-west-2
+
+[SERVICE]
+    storage.path /fluent-bit/dead-letter
+    storage.keep.rejected On
+    storage.rejected.path rejected
+`, configBytes.String())
+}
+
+func TestWriteDeadLetterServiceDisabled(t *testing.T) {
+	firelensResource, err := NewFirelensResource(testCluster, testTaskARN, testTaskDefinition, testEC2InstanceID,
+		testDataDir, FirelensConfigTypeFluentbit, testRegion, bridgeNetworkMode, testFirelensOptionsFile,
+		map[string]map[string]string{"container": testFluentbitOptions}, nil, testExecutionCredentialsID)
+	require.NoError(t, err)
+
+	configBytes := new(bytes.Buffer)
+	require.NoError(t, firelensResource.writeDeadLetterService(configBytes))
+	assert.Empty(t, configBytes.String())
+
+	config, err := firelensResource.generateConfig()
+	require.NoError(t, err)
+	require.NoError(t, config.WriteFluentBitConfig(configBytes))
+	assert.NotContains(t, configBytes.String(), "storage.type")
+}
@@ -38,6 +38,8 @@ type firelensResourceJSON struct {
 	ExecutionCredentialsID string
 	ExternalConfigType     string
 	ExternalConfigValue    string
+	LogRateLimit           int
+	DeadLetterPath         string
 	TerminalReason         string
 
 	CreatedAt     time.Time
@@ -69,6 +71,8 @@ func (firelens *FirelensResource) MarshalJSON() ([]byte, error) {
 		ExecutionCredentialsID: firelens.executionCredentialsID,
 		ExternalConfigType:     firelens.externalConfigType,
 		ExternalConfigValue:    firelens.externalConfigValue,
+		LogRateLimit:           firelens.logRateLimit,
+		DeadLetterPath:         firelens.deadLetterPath,
 		TerminalReason:         firelens.terminalReason,
 		CreatedAt:              firelens.createdAtUnsafe,
 		NetworkMode:            firelens.networkMode,
@@ -116,6 +120,8 @@ func (firelens *FirelensResource) UnmarshalJSON(b []byte) error {
 	firelens.executionCredentialsID = temp.ExecutionCredentialsID
 	firelens.externalConfigType = temp.ExternalConfigType
 	firelens.externalConfigValue = temp.ExternalConfigValue
+	firelens.logRateLimit = temp.LogRateLimit
+	firelens.deadLetterPath = temp.DeadLetterPath
 	firelens.terminalReason = temp.TerminalReason
 	firelens.createdAtUnsafe = temp.CreatedAt
 	firelens.desiredStatusUnsafe = resourcestatus.ResourceStatus(*temp.DesiredStatus)
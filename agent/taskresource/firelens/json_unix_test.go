@@ -46,6 +46,8 @@ func TestMarshalUnmarshalJSON(t *testing.T) {
 		executionCredentialsID: testExecutionCredentialsID,
 		externalConfigType:     testExternalConfigType,
 		externalConfigValue:    testExternalConfigValue,
+		logRateLimit:           1000,
+		deadLetterPath:         "/fluent-bit/dead-letter",
 		terminalReason:         testTerminalResason,
 		createdAtUnsafe:        testCreatedAt,
 		desiredStatusUnsafe:    resourcestatus.ResourceCreated,
@@ -69,6 +71,8 @@ func TestMarshalUnmarshalJSON(t *testing.T) {
 	assert.Equal(t, testExecutionCredentialsID, firelensResOut.executionCredentialsID)
 	assert.Equal(t, testExternalConfigType, firelensResOut.externalConfigType)
 	assert.Equal(t, testExternalConfigValue, firelensResOut.externalConfigValue)
+	assert.Equal(t, 1000, firelensResOut.logRateLimit)
+	assert.Equal(t, "/fluent-bit/dead-letter", firelensResOut.deadLetterPath)
 	assert.Equal(t, testTerminalResason, firelensResOut.terminalReason)
 	// Can't use assert.Equal for time here. See https://github.com/golang/go/issues/22957.
 	assert.True(t, testCreatedAt.Equal(firelensResOut.createdAtUnsafe))
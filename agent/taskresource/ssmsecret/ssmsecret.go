@@ -295,6 +295,11 @@ func (secret *SSMSecretResource) getGoRoutineMaxNum() int {
 	total := 0
 	for _, secrets := range secret.requiredSecrets {
 		total += len(secrets)/MaxBatchNum + 1
+		for _, s := range secrets {
+			if s.IsSSMPathReference() {
+				total++
+			}
+		}
 	}
 	return total
 }
@@ -313,6 +318,11 @@ func (secret *SSMSecretResource) retrieveSSMSecretValuesByRegion(region string,
 		if _, ok := secret.GetCachedSecretValue(secretKey); ok {
 			continue
 		}
+		if s.IsSSMPathReference() {
+			wgPerRegion.Add(1)
+			go secret.retrieveSSMSecretValuesByPath(region, s, iamCredentials, &wgPerRegion, errorEvents)
+			continue
+		}
 		secretNames = append(secretNames, s.ValueFrom)
 		if len(secretNames) == MaxBatchNum {
 			secretNamesTmp := make([]string, MaxBatchNum)
@@ -352,6 +362,32 @@ func (secret *SSMSecretResource) retrieveSSMSecretValues(region string, names []
 	}
 }
 
+// retrieveSSMSecretValuesByPath retrieves all the parameters under the hierarchy path of
+// a path reference secret and caches them into memory as a JSON-encoded map keyed by the
+// parameter name relative to the path
+func (secret *SSMSecretResource) retrieveSSMSecretValuesByPath(region string, pathSecret apicontainer.Secret, iamCredentials credentials.IAMRoleCredentials, wg *sync.WaitGroup, errorEvents chan error) {
+	defer wg.Done()
+
+	ssmClient := secret.ssmClientCreator.NewSSMClient(region, iamCredentials)
+	path := pathSecret.GetSSMPath()
+	seelog.Debugf("ssm secret resource: retrieving resource for secret path %s in region [%s] in task: [%s]", path, region, secret.taskARN)
+	secValueMap, err := ssm.GetSecretsByPathFromSSM(path, ssmClient)
+	if err != nil {
+		errorEvents <- fmt.Errorf("fetching secret data from SSM Parameter Store path %s in %s: %v", path, region, err)
+		return
+	}
+	secValue, err := json.Marshal(secValueMap)
+	if err != nil {
+		errorEvents <- fmt.Errorf("encoding secret data from SSM Parameter Store path %s in %s: %v", path, region, err)
+		return
+	}
+
+	secret.lock.Lock()
+	defer secret.lock.Unlock()
+
+	secret.secretData[pathSecret.GetSecretResourceCacheKey()] = string(secValue)
+}
+
 // getRequiredSecrets returns the requiredSecrets field of ssmsecret task resource
 func (secret *SSMSecretResource) getRequiredSecrets() map[string][]apicontainer.Secret {
 	secret.lock.RLock()
@@ -123,6 +123,90 @@ func TestCreateAndGetWithOneCall(t *testing.T) {
 	assert.Equal(t, secretValue, value2)
 }
 
+func TestCreateAndGetWithPathReference(t *testing.T) {
+	pathSecret := apicontainer.Secret{
+		Name:      "APP_",
+		ValueFrom: apicontainer.SecretSSMPathPrefix + "/app/prod",
+		Region:    region1,
+		Provider:  "ssm",
+	}
+	requiredSecretData := map[string][]apicontainer.Secret{
+		region1: {
+			pathSecret,
+			{
+				Name:      secretName1,
+				ValueFrom: valueFrom1,
+				Region:    region1,
+				Provider:  "ssm",
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	credentialsManager := mock_credentials.NewMockManager(ctrl)
+	ssmClientCreator := mock_factory.NewMockSSMClientCreator(ctrl)
+	mockSSMClient := mock_ssm.NewMockSSMClient(ctrl)
+
+	iamRoleCreds := credentials.IAMRoleCredentials{}
+	creds := credentials.TaskIAMRoleCredentials{
+		IAMRoleCredentials: iamRoleCreds,
+	}
+
+	ssmOutput := &ssm.GetParametersOutput{
+		Parameters: []*ssm.Parameter{
+			{
+				Name:  aws.String(valueFrom1),
+				Value: aws.String(secretValue),
+			},
+		},
+	}
+	ssmPathOutput := &ssm.GetParametersByPathOutput{
+		Parameters: []*ssm.Parameter{
+			{
+				Name:  aws.String("/app/prod/DB_HOST"),
+				Value: aws.String("db.example.com"),
+			},
+			{
+				Name:  aws.String("/app/prod/db/PASSWORD"),
+				Value: aws.String(secretValue),
+			},
+		},
+	}
+
+	credentialsManager.EXPECT().GetTaskCredentials(executionCredentialsID).Return(creds, true)
+	ssmClientCreator.EXPECT().NewSSMClient(region1, iamRoleCreds).Return(mockSSMClient).Times(2)
+	mockSSMClient.EXPECT().GetParameters(gomock.Any()).Do(func(in *ssm.GetParametersInput) {
+		assert.Equal(t, []*string{aws.String(valueFrom1)}, in.Names)
+	}).Return(ssmOutput, nil).Times(1)
+	mockSSMClient.EXPECT().GetParametersByPath(gomock.Any()).Do(func(in *ssm.GetParametersByPathInput) {
+		assert.Equal(t, "/app/prod", aws.StringValue(in.Path))
+		assert.True(t, aws.BoolValue(in.Recursive))
+	}).Return(ssmPathOutput, nil).Times(1)
+
+	ssmRes := &SSMSecretResource{
+		executionCredentialsID: executionCredentialsID,
+		requiredSecrets:        requiredSecretData,
+		credentialsManager:     credentialsManager,
+		ssmClientCreator:       ssmClientCreator,
+	}
+	require.NoError(t, ssmRes.Create())
+
+	value1, ok := ssmRes.GetCachedSecretValue(secretKeyWest1)
+	require.True(t, ok)
+	assert.Equal(t, secretValue, value1)
+
+	pathValue, ok := ssmRes.GetCachedSecretValue(pathSecret.GetSecretResourceCacheKey())
+	require.True(t, ok)
+	var pathValues map[string]string
+	require.NoError(t, json.Unmarshal([]byte(pathValue), &pathValues))
+	assert.Equal(t, map[string]string{
+		"DB_HOST":     "db.example.com",
+		"db/PASSWORD": secretValue,
+	}, pathValues)
+}
+
 func TestCreateAndGetWithTwoCallsAcrossRegions(t *testing.T) {
 	requiredSecretData := make(map[string][]apicontainer.Secret)
 	secretsInRegion1 := []apicontainer.Secret{
@@ -18,6 +18,7 @@ import (
 	"errors"
 
 	"github.com/aws/amazon-ecs-agent/agent/taskresource"
+	appconfigsecretres "github.com/aws/amazon-ecs-agent/agent/taskresource/appconfigsecret"
 	asmauthres "github.com/aws/amazon-ecs-agent/agent/taskresource/asmauth"
 	asmsecretres "github.com/aws/amazon-ecs-agent/agent/taskresource/asmsecret"
 	cgroupres "github.com/aws/amazon-ecs-agent/agent/taskresource/cgroup"
@@ -40,6 +41,8 @@ const (
 	SSMSecretKey = ssmsecretres.ResourceName
 	// ASMSecretKey is the string used in resources map to represent asm secret
 	ASMSecretKey = asmsecretres.ResourceName
+	// AppConfigSecretKey is the string used in resources map to represent appconfig secret
+	AppConfigSecretKey = appconfigsecretres.ResourceName
 	// FirelensKey is the string used in resources map to represent firelens resource
 	FirelensKey = firelens.ResourceName
 	// CredentialSpecKey is the string used in resources map to represent credentialspec resource
@@ -83,6 +86,8 @@ func unmarshalResource(key string, value json.RawMessage, result map[string][]ta
 		return unmarshalSSMSecretKey(key, value, result)
 	case ASMSecretKey:
 		return unmarshalASMSecretKey(key, value, result)
+	case AppConfigSecretKey:
+		return unmarshalAppConfigSecretKey(key, value, result)
 	case FirelensKey:
 		return unmarshalFirelensKey(key, value, result)
 	case CredentialSpecKey:
@@ -166,6 +171,24 @@ func unmarshalSSMSecretKey(key string, value json.RawMessage, result map[string]
 	return nil
 }
 
+func unmarshalAppConfigSecretKey(key string, value json.RawMessage, result map[string][]taskresource.TaskResource) error {
+	var appconfigsecrets []json.RawMessage
+	err := json.Unmarshal(value, &appconfigsecrets)
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range appconfigsecrets {
+		res := &appconfigsecretres.AppConfigSecretResource{}
+		err := res.UnmarshalJSON(secret)
+		if err != nil {
+			return err
+		}
+		result[key] = append(result[key], res)
+	}
+	return nil
+}
+
 func unmarshalASMSecretKey(key string, value json.RawMessage, result map[string][]taskresource.TaskResource) error {
 	var asmsecrets []json.RawMessage
 	err := json.Unmarshal(value, &asmsecrets)
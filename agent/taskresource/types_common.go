@@ -14,6 +14,7 @@
 package taskresource
 
 import (
+	appconfigfactory "github.com/aws/amazon-ecs-agent/agent/appconfig/factory"
 	asmfactory "github.com/aws/amazon-ecs-agent/agent/asm/factory"
 	fsxfactory "github.com/aws/amazon-ecs-agent/agent/fsx/factory"
 	s3factory "github.com/aws/amazon-ecs-agent/agent/s3/factory"
@@ -23,11 +24,12 @@ import (
 )
 
 type ResourceFieldsCommon struct {
-	IOUtil             ioutilwrapper.IOUtil
-	ASMClientCreator   asmfactory.ClientCreator
-	SSMClientCreator   ssmfactory.SSMClientCreator
-	FSxClientCreator   fsxfactory.FSxClientCreator
-	S3ClientCreator    s3factory.S3ClientCreator
-	CredentialsManager credentials.Manager
-	EC2InstanceID      string
+	IOUtil                 ioutilwrapper.IOUtil
+	ASMClientCreator       asmfactory.ClientCreator
+	SSMClientCreator       ssmfactory.SSMClientCreator
+	AppConfigClientCreator appconfigfactory.AppConfigClientCreator
+	FSxClientCreator       fsxfactory.FSxClientCreator
+	S3ClientCreator        s3factory.S3ClientCreator
+	CredentialsManager     credentials.Manager
+	EC2InstanceID          string
 }
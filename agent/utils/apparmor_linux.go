@@ -0,0 +1,54 @@
+//go:build linux
+// +build linux
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AppArmorProfilesFilePath lists the AppArmor profiles loaded in the kernel, one '<name> (<mode>)' per line.
+const AppArmorProfilesFilePath = "/sys/kernel/security/apparmor/profiles"
+
+// AppArmorProfileLoaded checks whether the AppArmor profile with the given name is listed in the
+// profiles file at the provided path.
+func AppArmorProfileLoaded(filePath string, name string) (bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to read loaded apparmor profiles")
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// strip the trailing ' (<mode>)', profile names may contain spaces
+		if i := strings.LastIndex(line, " ("); i >= 0 {
+			line = line[:i]
+		}
+		if line == name {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, errors.Wrap(err, "unable to read loaded apparmor profiles")
+	}
+	return false, nil
+}
@@ -0,0 +1,60 @@
+//go:build linux && unit
+// +build linux,unit
+
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppArmorProfileLoaded(t *testing.T) {
+	profilesFile := filepath.Join(t.TempDir(), "profiles")
+	require.NoError(t, os.WriteFile(profilesFile, []byte(
+		"docker-default (enforce)\n"+
+			"my profile (complain)\n"+
+			"/usr/bin/man (enforce)\n"), 0644))
+
+	testCases := []struct {
+		name           string
+		profile        string
+		expectedLoaded bool
+	}{
+		{name: "loaded profile", profile: "docker-default", expectedLoaded: true},
+		{name: "loaded profile with spaces", profile: "my profile", expectedLoaded: true},
+		{name: "loaded path profile", profile: "/usr/bin/man", expectedLoaded: true},
+		{name: "absent profile", profile: "ecs-custom", expectedLoaded: false},
+		{name: "profile name prefix", profile: "docker", expectedLoaded: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			loaded, err := AppArmorProfileLoaded(profilesFile, tc.profile)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLoaded, loaded)
+		})
+	}
+}
+
+func TestAppArmorProfileLoadedMissingFile(t *testing.T) {
+	loaded, err := AppArmorProfileLoaded(filepath.Join(t.TempDir(), "profiles"), "docker-default")
+	assert.Error(t, err)
+	assert.False(t, loaded)
+}
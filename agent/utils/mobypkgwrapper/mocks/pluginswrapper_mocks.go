@@ -61,3 +61,18 @@ func (mr *MockPluginsMockRecorder) Scan() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockPlugins)(nil).Scan))
 }
+
+// VolumeDriverScope mocks base method.
+func (m *MockPlugins) VolumeDriverScope(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VolumeDriverScope", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VolumeDriverScope indicates an expected call of VolumeDriverScope.
+func (mr *MockPluginsMockRecorder) VolumeDriverScope(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VolumeDriverScope", reflect.TypeOf((*MockPlugins)(nil).VolumeDriverScope), arg0)
+}
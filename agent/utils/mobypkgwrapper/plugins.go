@@ -14,12 +14,31 @@
 package mobypkgwrapper
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
 	mobyplugins "github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/pkg/plugins/transport"
+)
+
+const (
+	// volumeDriverCapabilitiesPath is the volume plugin API endpoint that reports the driver's scope
+	volumeDriverCapabilitiesPath = "/VolumeDriver.Capabilities"
+	// volumeDriverCapabilitiesTimeout bounds the call to a plugin's capabilities endpoint
+	volumeDriverCapabilitiesTimeout = 5 * time.Second
 )
 
 // Plugins wraps moby/pkg/plugins methods for testing
 type Plugins interface {
 	Scan() ([]string, error)
+	// VolumeDriverScope returns the scope, "local" or "global", reported by the named volume plugin
+	VolumeDriverScope(name string) (string, error)
 }
 
 type plugins struct {
@@ -35,3 +54,53 @@ func (*plugins) Scan() ([]string, error) {
 	localRegistry := mobyplugins.NewLocalRegistry()
 	return localRegistry.Scan()
 }
+
+func (*plugins) VolumeDriverScope(name string) (string, error) {
+	localRegistry := mobyplugins.NewLocalRegistry()
+	plugin, err := localRegistry.Plugin(name)
+	if err != nil {
+		return "", err
+	}
+
+	// The moby plugin client retries failed connections for up to 30 seconds, so
+	// call the capabilities endpoint directly with a bounded timeout instead
+	addr, err := url.Parse(plugin.Addr)
+	if err != nil {
+		return "", err
+	}
+	client := &http.Client{Timeout: volumeDriverCapabilitiesTimeout}
+	endpoint := "http://" + addr.Host + volumeDriverCapabilitiesPath
+	if addr.Scheme == "unix" {
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", addr.Path)
+			},
+		}
+		endpoint = "http://plugin" + volumeDriverCapabilitiesPath
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader("{}"))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", transport.VersionMimetype)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("plugin %s returned status %d for capabilities request", name, resp.StatusCode)
+	}
+
+	var capabilitiesResponse struct {
+		Capabilities struct {
+			Scope string
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&capabilitiesResponse); err != nil {
+		return "", err
+	}
+	return capabilitiesResponse.Capabilities.Scope, nil
+}
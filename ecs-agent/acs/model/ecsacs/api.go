@@ -680,6 +680,8 @@ type Container struct {
 	StopTimeout *int64 `locationName:"stopTimeout" type:"integer"`
 
 	VolumesFrom []*VolumeFrom `locationName:"volumesFrom" type:"list"`
+
+	WritablePaths []*string `locationName:"writablePaths" type:"list"`
 }
 
 // String returns the string representation.
@@ -1786,6 +1788,8 @@ type MountPoint struct {
 
 	ReadOnly *bool `locationName:"readOnly" type:"boolean"`
 
+	SelinuxRelabel *string `locationName:"selinuxRelabel" type:"string"`
+
 	SourceVolume *string `locationName:"sourceVolume" type:"string"`
 }
 
@@ -2226,6 +2230,8 @@ type PortMapping struct {
 
 	HostPort *int64 `locationName:"hostPort" type:"integer"`
 
+	Name *string `locationName:"name" type:"string"`
+
 	Protocol *string `locationName:"protocol" type:"string" enum:"TransportProtocol"`
 }
 
@@ -2623,6 +2629,8 @@ type Task struct {
 
 	TaskDefinitionAccountId *string `locationName:"taskDefinitionAccountId" type:"string"`
 
+	Ulimits []*Ulimit `locationName:"ulimits" type:"list"`
+
 	Version *string `locationName:"version" type:"string"`
 
 	Volumes []*Volume `locationName:"volumes" type:"list"`
@@ -2900,6 +2908,34 @@ func (s TaskStopVerificationOutput) GoString() string {
 	return s.String()
 }
 
+type Ulimit struct {
+	_ struct{} `type:"structure"`
+
+	HardLimit *int64 `locationName:"hardLimit" type:"integer"`
+
+	Name *string `locationName:"name" type:"string"`
+
+	SoftLimit *int64 `locationName:"softLimit" type:"integer"`
+}
+
+// String returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s Ulimit) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation.
+//
+// API parameter values that are decorated as "sensitive" in the API will not
+// be included in the string output. The member name will be present, but the
+// value will be replaced with "sensitive".
+func (s Ulimit) GoString() string {
+	return s.String()
+}
+
 type UpdateFailureInput struct {
 	_ struct{} `type:"structure"`
 
@@ -19,13 +19,21 @@ import (
 	"time"
 
 	apicontainerstatus "github.com/aws/amazon-ecs-agent/ecs-agent/api/container/status"
+	"github.com/aws/amazon-ecs-agent/ecs-agent/utils/retry"
 )
 
+// maxRestartAttemptPeriodJitter is the largest fraction of the restart attempt period that's added to it after
+// a restart, so that containers restarted at the same time don't all become eligible to restart again at the
+// same time.
+const maxRestartAttemptPeriodJitter = 0.2
+
 type RestartTracker struct {
 	RestartCount  int           `json:"restartCount,omitempty"`
 	LastRestartAt time.Time     `json:"lastRestartAt,omitempty"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
-	lock          sync.RWMutex
+	// RestartAttemptPeriodJitter is added to the restart attempt period measured from the last restart
+	RestartAttemptPeriodJitter time.Duration `json:"restartAttemptPeriodJitter,omitempty"`
+	lock                       sync.RWMutex
 }
 
 // RestartPolicy represents a policy that contains key information considered when
@@ -63,11 +71,19 @@ func (rt *RestartTracker) RecordRestart() {
 	defer rt.lock.Unlock()
 	rt.RestartCount++
 	rt.LastRestartAt = time.Now()
+	rt.RestartAttemptPeriodJitter = retry.AddJitter(0,
+		time.Duration(float64(rt.restartAttemptPeriod())*maxRestartAttemptPeriodJitter))
+}
+
+// restartAttemptPeriod returns the restart attempt period of the restart policy, without jitter.
+func (rt *RestartTracker) restartAttemptPeriod() time.Duration {
+	return time.Duration(rt.RestartPolicy.RestartAttemptPeriod) * time.Second
 }
 
 // ShouldRestart returns whether the container should restart and a reason string
 // explaining why not. The reset attempt period will be calculated first
 // with LastRestart at, using the passed in startedAt if it does not exist.
+// The period measured from the last restart includes the jitter picked when it was recorded.
 func (rt *RestartTracker) ShouldRestart(exitCode *int, startedAt time.Time,
 	desiredStatus apicontainerstatus.ContainerStatus) (bool, string) {
 	rt.lock.RLock()
@@ -89,10 +105,12 @@ func (rt *RestartTracker) ShouldRestart(exitCode *int, startedAt time.Time,
 	}
 
 	startTime := startedAt
+	attemptPeriod := rt.restartAttemptPeriod()
 	if !rt.LastRestartAt.IsZero() {
 		startTime = rt.LastRestartAt
+		attemptPeriod += rt.RestartAttemptPeriodJitter
 	}
-	if time.Since(startTime).Seconds() < float64(rt.RestartPolicy.RestartAttemptPeriod) {
+	if time.Since(startTime) < attemptPeriod {
 		return false, "attempt reset period has not elapsed"
 	}
 	return true, ""
@@ -184,3 +184,38 @@ func TestRecordRestartPolicy(t *testing.T) {
 	assert.Equal(t, 0, len(rt.RestartPolicy.IgnoredExitCodes))
 	assert.NotNil(t, rt.RestartPolicy)
 }
+
+func TestRecordRestartJitter(t *testing.T) {
+	rt := NewRestartTracker(RestartPolicy{
+		Enabled:              true,
+		RestartAttemptPeriod: 60,
+	})
+	assert.Zero(t, rt.RestartAttemptPeriodJitter)
+
+	maxJitter := time.Duration(float64(60*time.Second) * maxRestartAttemptPeriodJitter)
+	for i := 0; i < 1000; i++ {
+		rt.RecordRestart()
+		assert.GreaterOrEqual(t, rt.RestartAttemptPeriodJitter, time.Duration(0))
+		assert.Less(t, rt.RestartAttemptPeriodJitter, maxJitter)
+	}
+}
+
+func TestShouldRestartWithJitter(t *testing.T) {
+	rt := NewRestartTracker(RestartPolicy{
+		Enabled:              true,
+		RestartAttemptPeriod: 60,
+	})
+	exitCode := 1
+	rt.RecordRestart()
+	rt.RestartAttemptPeriodJitter = 10 * time.Second
+
+	// The attempt period without jitter has elapsed, but the jittered one hasn't
+	rt.LastRestartAt = time.Now().Add(-65 * time.Second)
+	shouldRestart, reason := rt.ShouldRestart(&exitCode, time.Now().Add(-time.Hour), apicontainerstatus.ContainerRunning)
+	assert.False(t, shouldRestart)
+	assert.Equal(t, "attempt reset period has not elapsed", reason)
+
+	rt.LastRestartAt = time.Now().Add(-71 * time.Second)
+	shouldRestart, _ = rt.ShouldRestart(&exitCode, time.Now().Add(-time.Hour), apicontainerstatus.ContainerRunning)
+	assert.True(t, shouldRestart)
+}
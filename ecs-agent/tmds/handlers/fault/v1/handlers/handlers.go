@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"sync"
 
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/field"
@@ -36,13 +38,15 @@ const (
 	startFaultRequestType       = "start %s"
 	stopFaultRequestType        = "stop %s"
 	checkStatusFaultRequestType = "check status %s"
+	taskFaultsStatusRequestType = "check status of task faults"
 )
 
 type FaultHandler struct {
-	// TODO: Mutex will be used in a future PR
-	// mu             sync.Mutex
+	mu             sync.Mutex
 	AgentState     state.AgentState
 	MetricsFactory metrics.EntryFactory
+	// activeFaults maps a task ARN to the network faults currently injected into the task, keyed by fault type
+	activeFaults map[string]map[string]types.NetworkFaultRequest
 }
 
 // NetworkFaultPath will take in a fault type and return the TMDS endpoint path
@@ -51,6 +55,12 @@ func NetworkFaultPath(fault string) string {
 		utils.ConstructMuxVar(v4.EndpointContainerIDMuxName, utils.AnythingButSlashRegEx), fault)
 }
 
+// TaskFaultsStatusPath returns the TMDS endpoint path listing the active faults of a task
+func TaskFaultsStatusPath() string {
+	return fmt.Sprintf("/api/%s/fault/v1/status",
+		utils.ConstructMuxVar(v4.EndpointContainerIDMuxName, utils.AnythingButSlashRegEx))
+}
+
 func (h *FaultHandler) StartNetworkBlackholePort() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var request types.NetworkBlackholePortRequest
@@ -71,11 +81,11 @@ func (h *FaultHandler) StartNetworkBlackholePort() func(http.ResponseWriter, *ht
 		})
 
 		// Obtain the task metadata via the endpoint container ID
-		// TODO: Will be using the returned task metadata in a future PR
-		_, err = validateTaskMetadata(w, h.AgentState, requestType, r)
+		taskMetadata, err := validateTaskMetadata(w, h.AgentState, requestType, r)
 		if err != nil {
 			return
 		}
+		h.addActiveFault(taskMetadata, types.BlackHolePortFaultType, request)
 
 		// TODO: Check status of current fault injection
 		// TODO: Invoke the start fault injection functionality if not running
@@ -116,11 +126,11 @@ func (h *FaultHandler) StopNetworkBlackHolePort() func(http.ResponseWriter, *htt
 		})
 
 		// Obtain the task metadata via the endpoint container ID
-		// TODO: Will be using the returned task metadata in a future PR
-		_, err = validateTaskMetadata(w, h.AgentState, requestType, r)
+		taskMetadata, err := validateTaskMetadata(w, h.AgentState, requestType, r)
 		if err != nil {
 			return
 		}
+		h.removeActiveFault(taskMetadata, types.BlackHolePortFaultType)
 
 		// TODO: Check status of current fault injection
 		// TODO: Invoke the stop fault injection functionality if running
@@ -203,11 +213,11 @@ func (h *FaultHandler) StartNetworkLatency() func(http.ResponseWriter, *http.Req
 		}
 
 		// Obtain the task metadata via the endpoint container ID
-		// TODO: Will be using the returned task metadata in a future PR
-		_, err = validateTaskMetadata(w, h.AgentState, requestType, r)
+		taskMetadata, err := validateTaskMetadata(w, h.AgentState, requestType, r)
 		if err != nil {
 			return
 		}
+		h.addActiveFault(taskMetadata, types.LatencyFaultType, request)
 
 		// TODO: Check status of current fault injection
 		// TODO: Invoke the start fault injection functionality if not running
@@ -245,11 +255,11 @@ func (h *FaultHandler) StopNetworkLatency() func(http.ResponseWriter, *http.Requ
 		}
 
 		// Obtain the task metadata via the endpoint container ID
-		// TODO: Will be using the returned task metadata in a future PR
-		_, err = validateTaskMetadata(w, h.AgentState, requestType, r)
+		taskMetadata, err := validateTaskMetadata(w, h.AgentState, requestType, r)
 		if err != nil {
 			return
 		}
+		h.removeActiveFault(taskMetadata, types.LatencyFaultType)
 
 		// TODO: Check status of current fault injection
 		// TODO: Invoke the stop fault injection functionality if running
@@ -328,6 +338,87 @@ func (h *FaultHandler) CheckNetworkPacketLoss() func(http.ResponseWriter, *http.
 	}
 }
 
+// CheckTaskFaultsStatus lists the network faults that are currently injected into the task of the
+// endpoint container.
+func (h *FaultHandler) CheckTaskFaultsStatus() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestType := taskFaultsStatusRequestType
+		logger.Info(fmt.Sprintf("Received new request for request type: %s", requestType), logger.Fields{
+			field.RequestType: requestType,
+		})
+
+		// Obtain the task metadata via the endpoint container ID
+		taskMetadata, err := validateTaskMetadata(w, h.AgentState, requestType, r)
+		if err != nil {
+			return
+		}
+
+		responseBody := types.NewTaskFaultsStatusResponse(h.getActiveFaults(taskMetadata))
+		logger.Info("Successfully checked status for task faults", logger.Fields{
+			field.RequestType: requestType,
+			field.Response:    responseBody.ToString(),
+		})
+		utils.WriteJSONResponse(
+			w,
+			http.StatusOK,
+			responseBody,
+			requestType,
+		)
+	}
+}
+
+// addActiveFault records the fault as active for the task, replacing any earlier fault of the same type.
+func (h *FaultHandler) addActiveFault(taskMetadata *state.TaskResponse, faultType string, request types.NetworkFaultRequest) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.activeFaults == nil {
+		h.activeFaults = make(map[string]map[string]types.NetworkFaultRequest)
+	}
+	taskARN := getTaskARN(taskMetadata)
+	if h.activeFaults[taskARN] == nil {
+		h.activeFaults[taskARN] = make(map[string]types.NetworkFaultRequest)
+	}
+	h.activeFaults[taskARN][faultType] = request
+}
+
+// removeActiveFault clears the fault of the given type from the active faults of the task.
+func (h *FaultHandler) removeActiveFault(taskMetadata *state.TaskResponse, faultType string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	taskARN := getTaskARN(taskMetadata)
+	delete(h.activeFaults[taskARN], faultType)
+	if len(h.activeFaults[taskARN]) == 0 {
+		delete(h.activeFaults, taskARN)
+	}
+}
+
+// getActiveFaults returns the active faults of the task, sorted by fault type.
+func (h *FaultHandler) getActiveFaults(taskMetadata *state.TaskResponse) []types.ActiveNetworkFault {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	faults := []types.ActiveNetworkFault{}
+	for faultType, request := range h.activeFaults[getTaskARN(taskMetadata)] {
+		faults = append(faults, types.ActiveNetworkFault{
+			Type:    faultType,
+			Request: request,
+		})
+	}
+	sort.Slice(faults, func(i, j int) bool {
+		return faults[i].Type < faults[j].Type
+	})
+	return faults
+}
+
+func getTaskARN(taskMetadata *state.TaskResponse) string {
+	if taskMetadata == nil || taskMetadata.TaskResponse == nil {
+		return ""
+	}
+	return taskMetadata.TaskARN
+}
+
 func decodeRequest(w http.ResponseWriter, request types.NetworkFaultRequest, requestType string, r *http.Request) error {
 	logRequest(requestType, r)
 	jsonDecoder := json.NewDecoder(r.Body)
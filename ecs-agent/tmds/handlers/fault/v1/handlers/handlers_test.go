@@ -28,9 +28,11 @@ import (
 
 	mock_metrics "github.com/aws/amazon-ecs-agent/ecs-agent/metrics/mocks"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/fault/v1/types"
+	v2 "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v2"
 	state "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state"
 	mock_state "github.com/aws/amazon-ecs-agent/ecs-agent/tmds/handlers/v4/state/mocks"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -700,3 +702,121 @@ func TestCheckNetworkLatency(t *testing.T) {
 		})
 	}
 }
+
+func TestFaultTaskFaultsStatusPath(t *testing.T) {
+	assert.Equal(t, "/api/{endpointContainerIDMuxName:[^/]*}/fault/v1/status", TaskFaultsStatusPath())
+}
+
+func TestCheckTaskFaultsStatus(t *testing.T) {
+	const (
+		taskARN      = "arn:aws:ecs:us-west-2:123456789012:task/cluster/abc"
+		otherTaskARN = "arn:aws:ecs:us-west-2:123456789012:task/cluster/def"
+		otherId      = "otherEndpointId"
+	)
+	taskMetadata := state.TaskResponse{TaskResponse: &v2.TaskResponse{TaskARN: taskARN}}
+	otherTaskMetadata := state.TaskResponse{TaskResponse: &v2.TaskResponse{TaskARN: otherTaskARN}}
+	blackHolePortReqBody := map[string]interface{}{
+		"Port":        port,
+		"Protocol":    protocol,
+		"TrafficType": trafficType,
+	}
+	latencyReqBody := map[string]interface{}{
+		"DelayMilliseconds":  delayMilliseconds,
+		"JitterMilliseconds": jitterMilliseconds,
+		"Sources":            ipSources,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agentState := mock_state.NewMockAgentState(ctrl)
+	agentState.EXPECT().GetTaskMetadata(endpointId).Return(taskMetadata, nil).AnyTimes()
+	agentState.EXPECT().GetTaskMetadata(otherId).Return(otherTaskMetadata, nil).AnyTimes()
+
+	handler := FaultHandler{
+		AgentState:     agentState,
+		MetricsFactory: mock_metrics.NewMockEntryFactory(ctrl),
+	}
+	router := mux.NewRouter()
+	router.HandleFunc(NetworkFaultPath(types.BlackHolePortFaultType), handler.StartNetworkBlackholePort()).Methods("PUT")
+	router.HandleFunc(NetworkFaultPath(types.BlackHolePortFaultType), handler.StopNetworkBlackHolePort()).Methods("DELETE")
+	router.HandleFunc(NetworkFaultPath(types.LatencyFaultType), handler.StartNetworkLatency()).Methods("PUT")
+	router.HandleFunc(TaskFaultsStatusPath(), handler.CheckTaskFaultsStatus()).Methods("GET")
+
+	serve := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var requestBody io.Reader
+		if body != nil {
+			reqBodyBytes, err := json.Marshal(body)
+			require.NoError(t, err)
+			requestBody = bytes.NewReader(reqBodyBytes)
+		}
+		req, err := http.NewRequest(method, path, requestBody)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+	checkStatus := func(id string, expected []types.ActiveNetworkFault) {
+		recorder := serve("GET", fmt.Sprintf("/api/%s/fault/v1/status", id), nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		expectedBody, err := json.Marshal(types.NewTaskFaultsStatusResponse(expected))
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expectedBody), recorder.Body.String())
+	}
+	blackHolePortRequest := types.NetworkBlackholePortRequest{
+		Port:        aws.Uint16(port),
+		Protocol:    aws.String(protocol),
+		TrafficType: aws.String(trafficType),
+	}
+	latencyRequest := types.NetworkLatencyRequest{
+		DelayMilliseconds:  aws.Uint64(delayMilliseconds),
+		JitterMilliseconds: aws.Uint64(jitterMilliseconds),
+		Sources:            aws.StringSlice(ipSources),
+	}
+
+	// No faults have been started yet
+	checkStatus(endpointId, []types.ActiveNetworkFault{})
+
+	require.Equal(t, http.StatusOK,
+		serve("PUT", fmt.Sprintf("/api/%s/fault/v1/network-latency", endpointId), latencyReqBody).Code)
+	require.Equal(t, http.StatusOK,
+		serve("PUT", fmt.Sprintf("/api/%s/fault/v1/network-blackhole-port", endpointId), blackHolePortReqBody).Code)
+	checkStatus(endpointId, []types.ActiveNetworkFault{
+		{Type: types.BlackHolePortFaultType, Request: blackHolePortRequest},
+		{Type: types.LatencyFaultType, Request: latencyRequest},
+	})
+	// Faults of one task aren't listed for another task
+	checkStatus(otherId, []types.ActiveNetworkFault{})
+
+	require.Equal(t, http.StatusOK,
+		serve("DELETE", fmt.Sprintf("/api/%s/fault/v1/network-blackhole-port", endpointId), blackHolePortReqBody).Code)
+	checkStatus(endpointId, []types.ActiveNetworkFault{
+		{Type: types.LatencyFaultType, Request: latencyRequest},
+	})
+}
+
+func TestCheckTaskFaultsStatusTaskLookupFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agentState := mock_state.NewMockAgentState(ctrl)
+	agentState.EXPECT().GetTaskMetadata(endpointId).Return(state.TaskResponse{}, state.NewErrorLookupFailure("task lookup failed"))
+
+	handler := FaultHandler{
+		AgentState:     agentState,
+		MetricsFactory: mock_metrics.NewMockEntryFactory(ctrl),
+	}
+	router := mux.NewRouter()
+	router.HandleFunc(TaskFaultsStatusPath(), handler.CheckTaskFaultsStatus()).Methods("GET")
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/%s/fault/v1/status", endpointId), nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var actualResponseBody types.NetworkFaultInjectionResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actualResponseBody))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, types.NewNetworkFaultInjectionErrorResponse(fmt.Sprintf("unable to lookup container: %s", endpointId)),
+		actualResponseBody)
+}
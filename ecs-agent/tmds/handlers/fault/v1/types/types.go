@@ -140,3 +140,29 @@ func NewNetworkFaultInjectionErrorResponse(err string) NetworkFaultInjectionResp
 		Error: err,
 	}
 }
+
+// ActiveNetworkFault is a network fault that's currently injected into a task, along with the
+// request it was started with.
+type ActiveNetworkFault struct {
+	Type    string              `json:"Type"`
+	Request NetworkFaultRequest `json:"Request"`
+}
+
+// TaskFaultsStatusResponse lists the network faults that are currently injected into a task.
+type TaskFaultsStatusResponse struct {
+	ActiveFaults []ActiveNetworkFault `json:"ActiveFaults"`
+}
+
+func NewTaskFaultsStatusResponse(faults []ActiveNetworkFault) TaskFaultsStatusResponse {
+	return TaskFaultsStatusResponse{
+		ActiveFaults: faults,
+	}
+}
+
+func (response TaskFaultsStatusResponse) ToString() string {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Sprintf("Error: Unable to parse task faults status response with error %v.", err)
+	}
+	return string(data)
+}
@@ -28,6 +28,7 @@ type PortResponse struct {
 	Protocol      string `json:"Protocol,omitempty"`
 	HostPort      uint16 `json:"HostPort,omitempty"`
 	HostIp        string `json:"HostIp,omitempty"`
+	Name          string `json:"Name,omitempty"`
 }
 
 // Network is a struct that keeps track of metadata of a network interface
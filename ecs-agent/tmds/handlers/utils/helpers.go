@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit"
 	"github.com/aws/amazon-ecs-agent/ecs-agent/logger/audit/request"
@@ -52,6 +53,21 @@ const (
 	// RequestTypeContainerAssociation specifies the container association request type of ContainerAssociationHandler.
 	RequestTypeContainerAssociation = "container association"
 
+	// RequestTypeHealth specifies the request type of the metadata server HealthHandler.
+	RequestTypeHealth = "health"
+
+	// RequestTypeContainerLogConfig specifies the container log configuration request type of ContainerLogConfigHandler.
+	RequestTypeContainerLogConfig = "container log configuration"
+
+	// RequestTypeContainerResources specifies the container resources request type of ContainerResourcesHandler.
+	RequestTypeContainerResources = "container resources"
+
+	// RequestTypeContainerResize specifies the container resize request type of ContainerResizeHandler.
+	RequestTypeContainerResize = "container resize"
+
+	// RequestTypeTaskENI specifies the task ENI request type of TaskENIHandler.
+	RequestTypeTaskENI = "task eni"
+
 	// AnythingButSlashRegEx is a regex pattern that matches any string without slash.
 	AnythingButSlashRegEx = "[^/]*"
 
@@ -60,6 +76,10 @@ const (
 
 	// AnythingButEmptyRegEx is a regex pattern that matches anything but an empty string.
 	AnythingButEmptyRegEx = ".+"
+
+	// FieldsQueryParam is the query parameter used to request only a subset of the
+	// top-level fields of a metadata response, e.g. ?fields=Networks,DockerId
+	FieldsQueryParam = "fields"
 )
 
 // ErrorMessage is used to store the human-readable error Code and a descriptive Message
@@ -86,6 +106,38 @@ func WriteJSONResponse(
 	WriteJSONToResponse(w, httpStatusCode, responseJSON, requestType)
 }
 
+// ProjectJSONFields marshals the provided response to JSON, keeping only the top-level fields
+// named in the comma-separated fields list. Unknown field names are ignored and an empty
+// fields list returns the full response.
+func ProjectJSONFields(response interface{}, fields string) ([]byte, error) {
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedFields := make(map[string]struct{})
+	for _, field := range strings.Split(fields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			requestedFields[field] = struct{}{}
+		}
+	}
+	if len(requestedFields) == 0 {
+		return responseJSON, nil
+	}
+
+	var allFields map[string]json.RawMessage
+	if err := json.Unmarshal(responseJSON, &allFields); err != nil {
+		return nil, err
+	}
+	projectedFields := make(map[string]json.RawMessage)
+	for field := range requestedFields {
+		if value, ok := allFields[field]; ok {
+			projectedFields[field] = value
+		}
+	}
+	return json.Marshal(projectedFields)
+}
+
 // WriteJSONToResponse writes the header, JSON response to a ResponseWriter, and
 // log the error if necessary.
 func WriteJSONToResponse(w http.ResponseWriter, httpStatusCode int, responseJSON []byte, requestType string) {
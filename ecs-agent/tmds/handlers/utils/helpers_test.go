@@ -111,6 +111,53 @@ func TestWriteJSONResponseError(t *testing.T) {
 	assert.Equal(t, "{}", recorder.Body.String())
 }
 
+func TestProjectJSONFields(t *testing.T) {
+	res := response.PortResponse{
+		ContainerPort: 80,
+		Protocol:      "tcp",
+		HostPort:      8080,
+	}
+	fullJSON, err := json.Marshal(res)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name         string
+		fields       string
+		expectedJSON string
+	}{
+		{
+			name:         "no fields returns full response",
+			fields:       "",
+			expectedJSON: string(fullJSON),
+		},
+		{
+			name:         "blank fields returns full response",
+			fields:       " , ",
+			expectedJSON: string(fullJSON),
+		},
+		{
+			name:         "requested fields only",
+			fields:       "ContainerPort, HostPort",
+			expectedJSON: `{"ContainerPort":80,"HostPort":8080}`,
+		},
+		{
+			name:         "unknown fields are ignored",
+			fields:       "Protocol,Unknown",
+			expectedJSON: `{"Protocol":"tcp"}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actualJSON, err := ProjectJSONFields(res, tc.fields)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expectedJSON, string(actualJSON))
+		})
+	}
+
+	_, err = ProjectJSONFields(func() {}, "")
+	assert.Error(t, err)
+}
+
 func TestValueFromRequest(t *testing.T) {
 	r, _ := http.NewRequest("GET", "/v1/credentials?id=credid", nil)
 	val, ok := ValueFromRequest(r, "id")
@@ -26,6 +26,7 @@ type TaskResponse struct {
 	Revision              string              `json:"Revision"`
 	DesiredStatus         string              `json:"DesiredStatus,omitempty"`
 	KnownStatus           string              `json:"KnownStatus"`
+	HealthStatus          string              `json:"HealthStatus,omitempty"`
 	Containers            []ContainerResponse `json:"Containers,omitempty"`
 	Limits                *LimitsResponse     `json:"Limits,omitempty"`
 	PullStartedAt         *time.Time          `json:"PullStartedAt,omitempty"`
@@ -36,32 +37,86 @@ type TaskResponse struct {
 	ContainerInstanceTags map[string]string   `json:"ContainerInstanceTags,omitempty"`
 	LaunchType            string              `json:"LaunchType,omitempty"`
 	Errors                []ErrorResponse     `json:"Errors,omitempty"`
+	// SupportedMetadataVersions are the task metadata endpoint versions the containers of the task
+	// can use, e.g. v2, v3 and v4
+	SupportedMetadataVersions []string `json:"SupportedMetadataVersions,omitempty"`
 }
 
 // ContainerResponse defines the schema for the container response
 // JSON object
 type ContainerResponse struct {
-	ID            string                    `json:"DockerId"`
-	Name          string                    `json:"Name"`
-	DockerName    string                    `json:"DockerName"`
-	Image         string                    `json:"Image"`
-	ImageID       string                    `json:"ImageID"`
-	Ports         []response.PortResponse   `json:"Ports,omitempty"`
-	Labels        map[string]string         `json:"Labels,omitempty"`
-	DesiredStatus string                    `json:"DesiredStatus"`
-	KnownStatus   string                    `json:"KnownStatus"`
-	ExitCode      *int                      `json:"ExitCode,omitempty"`
-	Limits        LimitsResponse            `json:"Limits"`
-	CreatedAt     *time.Time                `json:"CreatedAt,omitempty"`
-	StartedAt     *time.Time                `json:"StartedAt,omitempty"`
-	FinishedAt    *time.Time                `json:"FinishedAt,omitempty"`
-	Type          string                    `json:"Type"`
-	Networks      []response.Network        `json:"Networks,omitempty"`
-	Health        *HealthStatus             `json:"Health,omitempty"`
-	Volumes       []response.VolumeResponse `json:"Volumes,omitempty"`
-	LogDriver     string                    `json:"LogDriver,omitempty"`
-	LogOptions    map[string]string         `json:"LogOptions,omitempty"`
-	ContainerARN  string                    `json:"ContainerARN,omitempty"`
+	ID            string                  `json:"DockerId"`
+	Name          string                  `json:"Name"`
+	DockerName    string                  `json:"DockerName"`
+	Image         string                  `json:"Image"`
+	ImageID       string                  `json:"ImageID"`
+	Ports         []response.PortResponse `json:"Ports,omitempty"`
+	Labels        map[string]string       `json:"Labels,omitempty"`
+	DesiredStatus string                  `json:"DesiredStatus"`
+	KnownStatus   string                  `json:"KnownStatus"`
+	ExitCode      *int                    `json:"ExitCode,omitempty"`
+	Limits        LimitsResponse          `json:"Limits"`
+	CreatedAt     *time.Time              `json:"CreatedAt,omitempty"`
+	StartedAt     *time.Time              `json:"StartedAt,omitempty"`
+	FinishedAt    *time.Time              `json:"FinishedAt,omitempty"`
+	// ImagePullDurationMs is how long pulling the container's image took, if the agent pulled it
+	ImagePullDurationMs int64                     `json:"ImagePullDurationMs,omitempty"`
+	Type                string                    `json:"Type"`
+	Networks            []response.Network        `json:"Networks,omitempty"`
+	Health              *HealthStatus             `json:"Health,omitempty"`
+	Volumes             []response.VolumeResponse `json:"Volumes,omitempty"`
+	LogDriver           string                    `json:"LogDriver,omitempty"`
+	LogOptions          map[string]string         `json:"LogOptions,omitempty"`
+	ContainerARN        string                    `json:"ContainerARN,omitempty"`
+	FirelensConfigType  string                    `json:"FirelensConfigType,omitempty"`
+	// EnvironmentSources maps each environment variable name to the source whose value won
+	// (secret, taskdef, envfile or agent). Values are never included.
+	EnvironmentSources map[string]string `json:"EnvironmentSources,omitempty"`
+	// LogDeliveryStatus is the last known state of the container's log driver (ok, throttled or blocked)
+	LogDeliveryStatus string `json:"LogDeliveryStatus,omitempty"`
+	// LogDestinations are the names of the output plugins the container's firelens logs are routed to.
+	// Output options are never included.
+	LogDestinations []string `json:"LogDestinations,omitempty"`
+	// SELinuxLabel is the SELinux process label set through the container's security options
+	SELinuxLabel string `json:"SELinuxLabel,omitempty"`
+	// AppArmorProfile is the name of the AppArmor profile set through the container's security options
+	AppArmorProfile string `json:"AppArmorProfile,omitempty"`
+	// ResizeHistory are the most recent in-place updates of the container's CPU and memory limits, oldest first
+	ResizeHistory []ResizeEvent `json:"ResizeHistory,omitempty"`
+	// CPU is the CPU shares set in the container's host config
+	CPU int64 `json:"CPU,omitempty"`
+	// Memory is the memory limit in bytes set in the container's host config
+	Memory int64 `json:"Memory,omitempty"`
+	// MemoryReservation is the memory reservation in bytes set in the container's host config
+	MemoryReservation int64 `json:"MemoryReservation,omitempty"`
+	// RestartPolicyState is the evaluation state of the container's restart policy
+	// (eligible, exhausted or disabled)
+	RestartPolicyState string `json:"RestartPolicyState,omitempty"`
+	// LayerCacheStats counts the image layers that were cached and downloaded when the agent pulled the
+	// container's image
+	LayerCacheStats *LayerCacheStats `json:"LayerCacheStats,omitempty"`
+	// VolumesFrom are the containers whose volumes are mounted in the container, suffixed with ":ro"
+	// when they're mounted read-only
+	VolumesFrom []string `json:"VolumesFrom,omitempty"`
+	// DockerLabels are the docker labels of the container. Labels set by ECS are only included in the
+	// v3 container response when they're requested.
+	DockerLabels map[string]string `json:"DockerLabels,omitempty"`
+	// ImageCompression is the compression of the container's image layers (gzip or zstd), if it's known
+	ImageCompression string `json:"ImageCompression,omitempty"`
+}
+
+// LayerCacheStats counts the layers of an image pull that were found in the layer cache and the ones that
+// had to be downloaded
+type LayerCacheStats struct {
+	HitLayers  int `json:"HitLayers"`
+	MissLayers int `json:"MissLayers"`
+}
+
+// ResizeEvent is the CPU and memory limits of a container after an in-place update of its resources
+type ResizeEvent struct {
+	Timestamp time.Time `json:"Timestamp"`
+	CPU       uint      `json:"CPU"`
+	Memory    uint      `json:"Memory"`
 }
 
 // Container health status
@@ -74,6 +129,8 @@ type HealthStatus struct {
 	ExitCode int `json:"exitCode,omitempty"`
 	// Output is the output of health check
 	Output string `json:"output,omitempty"`
+	// HealthCheckFailingStreak is the number of consecutive failed health checks
+	HealthCheckFailingStreak int `json:"healthCheckFailingStreak,omitempty"`
 }
 
 // LimitsResponse defines the schema for task/cpu limits response
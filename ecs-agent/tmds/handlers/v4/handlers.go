@@ -93,7 +93,12 @@ func ContainerMetadataHandler(
 			field.TMDSEndpointContainerID: endpointContainerID,
 			field.Container:               containerMetadata.ID,
 		})
-		utils.WriteJSONResponse(w, http.StatusOK, containerMetadata, utils.RequestTypeContainerMetadata)
+		fields, _ := utils.ValueFromRequest(r, utils.FieldsQueryParam)
+		responseJSON, err := utils.ProjectJSONFields(containerMetadata, fields)
+		if e := utils.WriteResponseIfMarshalError(w, err); e != nil {
+			return
+		}
+		utils.WriteJSONToResponse(w, http.StatusOK, responseJSON, utils.RequestTypeContainerMetadata)
 	}
 }
 
@@ -161,6 +166,8 @@ func taskMetadataHandler(
 			return
 		}
 
+		taskMetadata.SetEphemeralStorageUsage()
+
 		logger.Info("Writing response for v4 task metadata", logger.Fields{
 			field.TMDSEndpointContainerID: endpointContainerID,
 			field.TaskARN:                 taskMetadata.TaskARN,
@@ -211,6 +211,31 @@ func TestContainerMetadata(t *testing.T) {
 			expectedResponseBody: containerResponse,
 		})
 	})
+	t.Run("empty fields returns full response", func(t *testing.T) {
+		handler, _, agentState, _ := setup(t)
+		agentState.EXPECT().
+			GetContainerMetadata(endpointContainerID).
+			Return(containerResponse, nil)
+		testTMDSRequest(t, handler, TMDSTestCase[state.ContainerResponse]{
+			path:                 "/v4/" + endpointContainerID + "?fields=",
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: containerResponse,
+		})
+	})
+	t.Run("fields projection", func(t *testing.T) {
+		handler, _, agentState, _ := setup(t)
+		agentState.EXPECT().
+			GetContainerMetadata(endpointContainerID).
+			Return(containerResponse, nil)
+		testTMDSRequest(t, handler, TMDSTestCase[map[string]interface{}]{
+			path:               "/v4/" + endpointContainerID + "?fields=DockerId,Name,Unknown",
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: map[string]interface{}{
+				"DockerId": containerID,
+				"Name":     containerName,
+			},
+		})
+	})
 	t.Run("container lookup failed", func(t *testing.T) {
 		handler, _, agentState, _ := setup(t)
 		agentState.EXPECT().
@@ -285,6 +310,7 @@ func TestTaskMetadata(t *testing.T) {
 		expectedTaskResponse := taskResponse()
 		expectedTaskResponse.CredentialsID = ""      // credentials ID not expected
 		expectedTaskResponse.TaskNetworkConfig = nil // TaskNetworkConfig is not expected and would be used internally.
+		expectedTaskResponse.EphemeralStorageUsedBytes = 500 * 1024 * 1024
 		handler, _, agentState, _ := setup(t)
 		agentState.EXPECT().
 			GetTaskMetadata(endpointContainerID).
@@ -302,6 +328,7 @@ func TestTaskMetadata(t *testing.T) {
 		expectedTaskResponse.CredentialsID = ""            // credentials ID not expected
 		expectedTaskResponse.TaskNetworkConfig = nil       // TaskNetworkConfig is not expected and would be used internally
 		expectedTaskResponse.FaultInjectionEnabled = false // FaultInjectionEnabled is not expected and would be used internally
+		expectedTaskResponse.EphemeralStorageUsedBytes = 500 * 1024 * 1024
 
 		handler, _, agentState, _ := setup(t)
 		agentState.EXPECT().
@@ -540,6 +567,7 @@ func TestTaskStats(t *testing.T) {
 
 type TMDSResponse interface {
 	string |
+		map[string]interface{} |
 		state.ContainerResponse |
 		state.TaskResponse |
 		state.StatsResponse |
@@ -27,18 +27,37 @@ const (
 	ClockStatusNotSynchronized = "NOT_SYNCHRONIZED"
 )
 
+const (
+	mibsPerGiB  = 1024
+	bytesPerMiB = 1024 * 1024
+)
+
 // TaskResponse is the v4 Task response. It augments the v4 Container response
 // with the v2 task response object.
 type TaskResponse struct {
 	*v2.TaskResponse
-	Containers              []ContainerResponse      `json:"Containers,omitempty"`
-	VPCID                   string                   `json:"VPCID,omitempty"`
-	ServiceName             string                   `json:"ServiceName,omitempty"`
-	ClockDrift              *ClockDrift              `json:"ClockDrift,omitempty"`
-	EphemeralStorageMetrics *EphemeralStorageMetrics `json:"EphemeralStorageMetrics,omitempty"`
-	CredentialsID           string                   `json:"-"`
-	TaskNetworkConfig       *TaskNetworkConfig       `json:"-"`
-	FaultInjectionEnabled   bool                     `json:"-"`
+	Containers                []ContainerResponse      `json:"Containers,omitempty"`
+	VPCID                     string                   `json:"VPCID,omitempty"`
+	ServiceName               string                   `json:"ServiceName,omitempty"`
+	ClockDrift                *ClockDrift              `json:"ClockDrift,omitempty"`
+	EphemeralStorageMetrics   *EphemeralStorageMetrics `json:"EphemeralStorageMetrics,omitempty"`
+	EphemeralStorageSizeGiB   int                      `json:"EphemeralStorageSizeGiB,omitempty"`
+	EphemeralStorageUsedBytes int64                    `json:"EphemeralStorageUsedBytes,omitempty"`
+	CredentialsID             string                   `json:"-"`
+	TaskNetworkConfig         *TaskNetworkConfig       `json:"-"`
+	FaultInjectionEnabled     bool                     `json:"-"`
+}
+
+// SetEphemeralStorageUsage sets the ephemeral storage size and usage of the task from the
+// disk utilization metrics. Both are left unset when the metrics are disabled.
+func (t *TaskResponse) SetEphemeralStorageUsage() {
+	if t.EphemeralStorageMetrics == nil {
+		t.EphemeralStorageSizeGiB = 0
+		t.EphemeralStorageUsedBytes = 0
+		return
+	}
+	t.EphemeralStorageSizeGiB = int(t.EphemeralStorageMetrics.ReservedMiBs / mibsPerGiB)
+	t.EphemeralStorageUsedBytes = t.EphemeralStorageMetrics.UtilizedMiBs * bytesPerMiB
 }
 
 // TaskNetworkConfig contains required network configurations for network faults injection.
@@ -16,6 +16,7 @@
 package state
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -122,3 +123,28 @@ func TestAsErrorStatsLookupFailure(t *testing.T) {
 		require.False(t, errors.As(errors.New("other error"), &target))
 	})
 }
+
+func TestSetEphemeralStorageUsage(t *testing.T) {
+	t.Run("metrics available", func(t *testing.T) {
+		taskResponse := TaskResponse{
+			EphemeralStorageMetrics: &EphemeralStorageMetrics{
+				UtilizedMiBs: 512,
+				ReservedMiBs: 20 * 1024,
+			},
+		}
+		taskResponse.SetEphemeralStorageUsage()
+		assert.Equal(t, 20, taskResponse.EphemeralStorageSizeGiB)
+		assert.Equal(t, int64(512*1024*1024), taskResponse.EphemeralStorageUsedBytes)
+	})
+	t.Run("metrics disabled", func(t *testing.T) {
+		taskResponse := TaskResponse{}
+		taskResponse.SetEphemeralStorageUsage()
+		assert.Zero(t, taskResponse.EphemeralStorageSizeGiB)
+		assert.Zero(t, taskResponse.EphemeralStorageUsedBytes)
+
+		respJSON, err := json.Marshal(taskResponse)
+		require.NoError(t, err)
+		assert.NotContains(t, string(respJSON), "EphemeralStorageSizeGiB")
+		assert.NotContains(t, string(respJSON), "EphemeralStorageUsedBytes")
+	})
+}